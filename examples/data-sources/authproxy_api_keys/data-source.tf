@@ -0,0 +1,4 @@
+data "authproxy_api_keys" "stale" {
+  tenant       = "acme"
+  expired_only = true
+}
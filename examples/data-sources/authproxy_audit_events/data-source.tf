@@ -0,0 +1,10 @@
+variable "last_apply" {
+  type = string
+}
+
+data "authproxy_audit_events" "manual_changes" {
+  tenant  = "acme"
+  since   = var.last_apply
+  actions = ["role.update", "role.delete"]
+  limit   = 5
+}
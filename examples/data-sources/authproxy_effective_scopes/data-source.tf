@@ -0,0 +1,4 @@
+data "authproxy_effective_scopes" "alice" {
+  tenant  = "acme"
+  user_id = "u1"
+}
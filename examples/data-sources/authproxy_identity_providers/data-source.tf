@@ -0,0 +1,3 @@
+data "authproxy_identity_providers" "acme" {
+  tenant = "acme"
+}
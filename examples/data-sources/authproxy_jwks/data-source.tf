@@ -0,0 +1,7 @@
+data "authproxy_jwks" "acme" {
+  tenant = "acme"
+}
+
+output "acme_jwks" {
+  value = data.authproxy_jwks.acme.jwks_json
+}
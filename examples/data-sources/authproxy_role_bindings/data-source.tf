@@ -0,0 +1,5 @@
+data "authproxy_role_bindings" "admins" {
+  tenant         = "acme"
+  role           = "admin"
+  principal_type = "user"
+}
@@ -0,0 +1,5 @@
+# Fails the plan when one of the scopes does not exist.
+data "authproxy_scopes" "billing" {
+  service = "billing"
+  names   = ["billing:read", "billing:write"]
+}
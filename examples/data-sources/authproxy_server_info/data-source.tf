@@ -0,0 +1,5 @@
+data "authproxy_server_info" "this" {}
+
+output "scim_supported" {
+  value = try(data.authproxy_server_info.this.features["scim"], false)
+}
@@ -0,0 +1,9 @@
+# Fails unless exactly one tenant is named cust-acme*.
+data "authproxy_tenant_search" "acme" {
+  prefix = "cust-acme"
+}
+
+data "authproxy_tenant_search" "customers" {
+  regex      = "^cust-[a-z0-9-]+$"
+  match_mode = "all"
+}
@@ -0,0 +1,7 @@
+data "authproxy_tenant_usage" "acme" {
+  tenant = "acme"
+}
+
+output "acme_users_remaining" {
+  value = data.authproxy_tenant_usage.acme.users_remaining
+}
@@ -0,0 +1,15 @@
+variable "billing_token" {
+  type      = string
+  sensitive = true
+}
+
+data "authproxy_token_info" "billing" {
+  token = var.billing_token
+}
+
+check "billing_token" {
+  assert {
+    condition     = data.authproxy_token_info.billing.active && contains(data.authproxy_token_info.billing.scopes, "billing:read")
+    error_message = "The billing token is inactive or lacks the billing:read scope."
+  }
+}
@@ -0,0 +1,4 @@
+data "authproxy_user" "alice" {
+  tenant = "acme"
+  email  = "alice@acme.io"
+}
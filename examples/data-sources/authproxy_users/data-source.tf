@@ -0,0 +1,21 @@
+data "authproxy_users" "staff" {
+  tenant       = "acme"
+  email_domain = "acme.io"
+  enabled      = true
+}
+
+# Read large tenants in pages of 500 and stop after 10000 users.
+data "authproxy_users" "all" {
+  tenant    = "acme"
+  page_size = 500
+  max_items = 10000
+}
+
+# Give up on reading a slow instance after two minutes.
+data "authproxy_users" "slow" {
+  tenant = "acme"
+
+  timeouts {
+    read = "2m"
+  }
+}
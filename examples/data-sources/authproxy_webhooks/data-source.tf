@@ -0,0 +1,4 @@
+data "authproxy_webhooks" "user_events" {
+  tenant = "acme"
+  event  = "user.created"
+}
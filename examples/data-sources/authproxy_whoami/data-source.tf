@@ -0,0 +1,8 @@
+data "authproxy_whoami" "current" {}
+
+check "service_account" {
+  assert {
+    condition     = data.authproxy_whoami.current.username == "terraform"
+    error_message = "Unexpected authproxy credentials in use."
+  }
+}
@@ -1,3 +1,5 @@
+# endpoint, username and password may also be set with the AUTHPROXY_ENDPOINT,
+# AUTHPROXY_USERNAME and AUTHPROXY_PASSWORD environment variables.
 provider "authproxy" {
   username = "admin"
   password = "adsasd921jdiasmasd"
@@ -0,0 +1,2 @@
+# Access rules are imported by tenant and id.
+terraform import authproxy_access_rule.billing_acme_only acme/0b6d3c1e-5f2a-4e8b-9c7d-1a2b3c4d5e6f
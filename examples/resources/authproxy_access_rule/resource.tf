@@ -0,0 +1,19 @@
+# Only users of the acme tenant may use the billing service.
+resource "authproxy_access_rule" "billing_acme_only" {
+  tenant      = "acme"
+  target_type = "service"
+  target_id   = authproxy_service.billing.id
+  effect      = "allow"
+  principals  = ["tenant:acme"]
+  priority    = 10
+}
+
+# Requests from the scanner network are denied before any other rule applies.
+resource "authproxy_access_rule" "billing_admin_deny_scanners" {
+  tenant      = "acme"
+  target_type = "route"
+  target_id   = authproxy_route.billing_admin.id
+  effect      = "deny"
+  cidrs       = ["203.0.113.0/24"]
+  priority    = 100
+}
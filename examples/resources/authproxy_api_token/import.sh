@@ -0,0 +1,2 @@
+# The token itself cannot be recovered and stays empty after an import.
+terraform import authproxy_api_token.ci acme/7d3f4c2e-5d0b-4b8e-9a43-1f0e6c1b2a9d
@@ -0,0 +1,20 @@
+resource "authproxy_api_token" "ci" {
+  tenant     = "acme"
+  name       = "ci"
+  scopes     = ["users:read"]
+  expires_at = "2030-01-01T00:00:00Z"
+
+  # Rotates the token every 90 days while keeping its ID.
+  rotation = {
+    rotated_at = time_rotating.ci.id
+  }
+}
+
+resource "time_rotating" "ci" {
+  rotation_days = 90
+}
+
+output "ci_token" {
+  value     = authproxy_api_token.ci.token
+  sensitive = true
+}
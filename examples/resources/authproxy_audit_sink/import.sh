@@ -0,0 +1,2 @@
+# The authorization header cannot be read back and must be set in the configuration.
+terraform import authproxy_audit_sink.splunk acme/4c1e7d2a-8b3f-4a6e-9d10-5f2b7c8e0a94
@@ -0,0 +1,14 @@
+resource "authproxy_audit_sink" "splunk" {
+  tenant               = "acme"
+  type                 = "http"
+  endpoint             = "https://splunk.acme.io:8088/services/collector"
+  authorization_header = "Splunk ${var.splunk_hec_token}"
+  event_types          = ["user.login", "user.logout", "role_binding.created"]
+  verify_on_create     = true
+}
+
+resource "authproxy_audit_sink" "syslog" {
+  tenant   = "acme"
+  type     = "syslog"
+  endpoint = "tls://logs.acme.io:6514"
+}
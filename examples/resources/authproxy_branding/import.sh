@@ -0,0 +1,2 @@
+# Branding is imported by tenant name.
+terraform import authproxy_branding.acme acme
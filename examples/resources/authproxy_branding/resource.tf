@@ -0,0 +1,7 @@
+resource "authproxy_branding" "acme" {
+  tenant        = "acme"
+  logo_url      = "https://cdn.acme.io/logo.svg"
+  primary_color = "#1A73E8"
+  support_url   = "https://acme.io/support"
+  custom_css    = file("${path.module}/login.css")
+}
@@ -0,0 +1 @@
+terraform import authproxy_certificate.saml_signing acme/5e8a1f3b-2c4d-4e6f-8a9b-0c1d2e3f4a5b
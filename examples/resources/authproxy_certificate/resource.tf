@@ -0,0 +1,10 @@
+resource "authproxy_certificate" "saml_signing" {
+  tenant          = "acme"
+  usage           = "saml_signing"
+  certificate_pem = file("${path.module}/saml-signing.pem")
+
+  # Upload the new certificate before the old one is deleted when rotating.
+  lifecycle {
+    create_before_destroy = true
+  }
+}
@@ -0,0 +1,2 @@
+# Claim mappings are imported by tenant, identity provider ID and mapping ID.
+terraform import authproxy_claim_mapping.platform_admins acme/9b2f0c4e-1d7a-4f3e-8c55-2a6d9e0b7f13/0e6d3b8a-5c2f-4d71-a9e4-7b1f2c3d4e5f
@@ -0,0 +1,7 @@
+resource "authproxy_claim_mapping" "platform_admins" {
+  tenant       = "acme"
+  idp_id       = authproxy_oidc_identity_provider.google.id
+  claim        = "groups"
+  match_value  = "platform-admins"
+  mapped_roles = ["admin", "auditor"]
+}
@@ -0,0 +1,2 @@
+# CORS policies are imported by tenant name.
+terraform import authproxy_cors_policy.acme acme
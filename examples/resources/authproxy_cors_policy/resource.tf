@@ -0,0 +1,7 @@
+resource "authproxy_cors_policy" "acme" {
+  tenant            = "acme"
+  allowed_origins   = ["https://app.acme.io", "https://admin.acme.io"]
+  allowed_headers   = ["Authorization", "Content-Type"]
+  allow_credentials = true
+  max_age_seconds   = 600
+}
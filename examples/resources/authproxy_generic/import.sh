@@ -0,0 +1,2 @@
+# Objects are imported by their path followed by their identifier.
+terraform import authproxy_generic.dark_mode /tenants/acme/feature-flags/42
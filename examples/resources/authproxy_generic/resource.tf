@@ -0,0 +1,10 @@
+# Feature flags are not modeled by the provider yet.
+resource "authproxy_generic" "dark_mode" {
+  path          = "/tenants/acme/feature-flags"
+  update_method = "PATCH"
+  id_attribute  = "data.id"
+  body = jsonencode({
+    name    = "dark-mode"
+    enabled = true
+  })
+}
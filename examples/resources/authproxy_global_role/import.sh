@@ -0,0 +1,2 @@
+# Global roles are imported by name.
+terraform import authproxy_global_role.platform_operator platform-operator
@@ -0,0 +1,8 @@
+resource "authproxy_global_role" "platform_operator" {
+  name = "platform-operator"
+  scopes = [
+    "tenants:read",
+    "tenants:write",
+    "audit:read",
+  ]
+}
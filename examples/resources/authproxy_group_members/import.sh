@@ -0,0 +1 @@
+terraform import authproxy_group_members.ops acme/ops
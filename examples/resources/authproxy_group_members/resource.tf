@@ -0,0 +1,7 @@
+# Makes alice and bob the only members of the ops group.
+resource "authproxy_group_members" "ops" {
+  tenant    = "acme"
+  group     = "ops"
+  user_ids  = [authproxy_user.alice.id, authproxy_user.bob.id]
+  exclusive = true
+}
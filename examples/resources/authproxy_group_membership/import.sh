@@ -0,0 +1 @@
+terraform import authproxy_group_membership.alice_ops acme/ops/7d3f4c2e-5d0b-4b8e-9a43-1f0e6c1b2a9d
@@ -0,0 +1,5 @@
+resource "authproxy_group_membership" "alice_ops" {
+  tenant  = "acme"
+  group   = "ops"
+  user_id = authproxy_user.alice.id
+}
@@ -0,0 +1,2 @@
+# Header policies are imported by tenant and service name.
+terraform import authproxy_header_policy.billing acme/billing
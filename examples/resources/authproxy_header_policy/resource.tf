@@ -0,0 +1,11 @@
+resource "authproxy_header_policy" "billing" {
+  tenant  = "acme"
+  service = authproxy_service.billing.name
+
+  set_headers = {
+    "X-User-Id"     = "{{ .user.id }}"
+    "X-User-Scopes" = "{{ join .token.scopes \" \" }}"
+  }
+
+  remove_headers = ["Cookie"]
+}
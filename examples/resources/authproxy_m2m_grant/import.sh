@@ -0,0 +1,2 @@
+# M2M grants are imported by tenant and client id.
+terraform import authproxy_m2m_grant.ci_runner acme/ci-runner
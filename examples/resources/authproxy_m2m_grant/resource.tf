@@ -0,0 +1,13 @@
+resource "authproxy_scope" "deployments_write" {
+  name        = "deployments:write"
+  description = "Trigger deployments"
+}
+
+resource "authproxy_m2m_grant" "ci_runner" {
+  tenant    = "acme"
+  client_id = "ci-runner"
+  scopes = [
+    "deployments:read",
+    authproxy_scope.deployments_write.name,
+  ]
+}
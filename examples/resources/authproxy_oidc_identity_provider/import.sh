@@ -0,0 +1,2 @@
+# The client secret cannot be read back and must be set in the configuration.
+terraform import authproxy_oidc_identity_provider.google acme/9b2f0c4e-1d7a-4f3e-8c55-2a6d9e0b7f13
@@ -0,0 +1,13 @@
+resource "authproxy_oidc_identity_provider" "google" {
+  tenant        = "acme"
+  name          = "google"
+  issuer_url    = "https://accounts.google.com"
+  client_id     = "1234567890-acme.apps.googleusercontent.com"
+  client_secret = var.google_client_secret
+  scopes        = ["openid", "email", "profile"]
+
+  claim_mappings = {
+    email = "email"
+    name  = "display_name"
+  }
+}
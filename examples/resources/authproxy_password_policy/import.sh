@@ -0,0 +1,2 @@
+# Password policies are imported by tenant name.
+terraform import authproxy_password_policy.acme acme
@@ -0,0 +1,8 @@
+resource "authproxy_password_policy" "acme" {
+  tenant          = "acme"
+  min_length      = 14
+  require_symbols = true
+  require_numbers = true
+  max_age_days    = 180
+  history_count   = 5
+}
@@ -0,0 +1 @@
+terraform import authproxy_policy.billing acme/billing
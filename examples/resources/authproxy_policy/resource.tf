@@ -0,0 +1,13 @@
+resource "authproxy_policy" "billing" {
+  tenant = "acme"
+  name   = "billing"
+  document = jsonencode({
+    version = 1
+    statements = [
+      {
+        effect  = "allow"
+        actions = ["billing:read"]
+      },
+    ]
+  })
+}
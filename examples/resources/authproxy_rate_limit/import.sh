@@ -0,0 +1 @@
+terraform import authproxy_rate_limit.acme_token acme/3c1d7e52-8a4f-4b60-9f2e-6d0a5b8c1e47
@@ -0,0 +1,7 @@
+# Limit token requests of a noisy tenant, other paths stay unlimited.
+resource "authproxy_rate_limit" "acme_token" {
+  tenant              = "acme"
+  requests_per_minute = 600
+  burst               = 100
+  paths               = ["/oauth/token"]
+}
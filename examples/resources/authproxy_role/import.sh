@@ -0,0 +1,2 @@
+# Roles are imported by tenant and name.
+terraform import authproxy_role.editor acme/editor
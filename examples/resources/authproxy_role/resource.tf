@@ -0,0 +1,5 @@
+resource "authproxy_role" "editor" {
+  tenant      = "acme"
+  name        = "editor"
+  permissions = ["billing:read", "billing:write"]
+}
@@ -0,0 +1,6 @@
+resource "authproxy_role_binding" "alice_admin" {
+  tenant         = "acme"
+  role           = "admin"
+  principal_type = "user"
+  principal_id   = authproxy_user.alice.id
+}
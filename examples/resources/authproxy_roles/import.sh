@@ -0,0 +1,3 @@
+# Importing manages every role of the tenant, remove the roles that should
+# stay unmanaged from the configuration afterwards.
+terraform import authproxy_roles.standard acme
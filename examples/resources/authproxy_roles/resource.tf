@@ -0,0 +1,15 @@
+resource "authproxy_roles" "standard" {
+  tenant = "acme"
+  roles = {
+    viewer = {
+      scopes = ["billing:read"]
+    }
+    editor = {
+      scopes      = ["billing:read", "billing:write"]
+      description = "Edits invoices and payment methods"
+    }
+    auditor = {
+      scopes = ["audit:read"]
+    }
+  }
+}
@@ -0,0 +1,2 @@
+# Routes are imported by tenant and id.
+terraform import authproxy_route.billing acme/6f1c2f4e-8d3b-4c52-9a7e-2b0d1e5f3a91
@@ -0,0 +1,16 @@
+resource "authproxy_route" "billing" {
+  tenant          = "acme"
+  service         = authproxy_service.billing.name
+  path_prefix     = "/api/billing/"
+  methods         = ["GET", "POST"]
+  required_scopes = ["billing:read"]
+  strip_prefix    = true
+}
+
+resource "authproxy_route" "billing_admin" {
+  tenant          = "acme"
+  service         = authproxy_service.billing.id
+  path_prefix     = "/api/billing/admin/"
+  required_scopes = ["billing:admin"]
+  priority        = 10
+}
@@ -0,0 +1,2 @@
+# SAML identity providers are imported by tenant name and identity provider ID.
+terraform import authproxy_saml_identity_provider.okta acme/3d7e9a1c-4b2f-4e6a-8c0d-5f1b9e2a7c34
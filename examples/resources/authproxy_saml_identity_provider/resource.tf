@@ -0,0 +1,21 @@
+resource "authproxy_saml_identity_provider" "okta" {
+  tenant          = "acme"
+  name            = "okta"
+  entity_id       = "http://www.okta.com/exk1fcia6d6EMsf0Y357"
+  sso_url         = "https://acme.okta.com/app/acme_authproxy/exk1fcia6d6EMsf0Y357/sso/saml"
+  certificate_pem = file("${path.module}/okta.pem")
+
+  attribute_mappings = {
+    "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress" = "email"
+    "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name"         = "display_name"
+  }
+}
+
+# Register these with the identity provider.
+output "acs_url" {
+  value = authproxy_saml_identity_provider.okta.acs_url
+}
+
+output "sp_metadata_url" {
+  value = authproxy_saml_identity_provider.okta.sp_metadata_url
+}
@@ -0,0 +1,3 @@
+# The bearer token cannot be read back, change token_rotation_version after
+# importing to obtain a new one.
+terraform import authproxy_scim_config.acme acme
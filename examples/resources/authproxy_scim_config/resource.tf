@@ -0,0 +1,17 @@
+resource "authproxy_scim_config" "acme" {
+  tenant      = "acme"
+  allowed_ips = ["52.5.0.0/16"]
+
+  # Increment to rotate the bearer token.
+  token_rotation_version = 1
+}
+
+# Configure these in the identity provider's provisioning settings.
+output "scim_base_url" {
+  value = authproxy_scim_config.acme.scim_base_url
+}
+
+output "scim_bearer_token" {
+  value     = authproxy_scim_config.acme.bearer_token
+  sensitive = true
+}
@@ -0,0 +1 @@
+terraform import authproxy_scope.billing_read billing:read
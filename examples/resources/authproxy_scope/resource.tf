@@ -0,0 +1,10 @@
+resource "authproxy_scope" "billing_read" {
+  name        = "billing:read"
+  description = "Read invoices and payment methods"
+}
+
+resource "authproxy_scope" "billing_export" {
+  name        = "billing:export"
+  description = "Superseded by billing:read"
+  deprecated  = true
+}
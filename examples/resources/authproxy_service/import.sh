@@ -0,0 +1,2 @@
+# Services are imported by tenant and name.
+terraform import authproxy_service.billing acme/billing
@@ -0,0 +1,7 @@
+resource "authproxy_service" "billing" {
+  tenant            = "acme"
+  name              = "billing"
+  upstream_url      = "http://billing.internal:8080"
+  health_check_path = "/healthz"
+  timeout_seconds   = 10
+}
@@ -0,0 +1,2 @@
+# Session policies are imported by tenant name.
+terraform import authproxy_session_policy.acme acme
@@ -0,0 +1,7 @@
+resource "authproxy_session_policy" "acme" {
+  tenant                  = "acme"
+  idle_timeout_minutes    = 30
+  max_lifetime_hours      = 12
+  max_concurrent_sessions = 5
+  remember_me_enabled     = false
+}
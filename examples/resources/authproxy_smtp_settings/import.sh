@@ -0,0 +1,3 @@
+# The password cannot be read back, set it in the configuration after
+# importing.
+terraform import authproxy_smtp_settings.acme acme
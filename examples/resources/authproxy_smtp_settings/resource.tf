@@ -0,0 +1,15 @@
+resource "authproxy_smtp_settings" "acme" {
+  tenant       = "acme"
+  host         = "smtp.acme.io"
+  username     = "authproxy"
+  password     = var.smtp_password
+  from_address = "noreply@acme.io"
+
+  # Fail the apply when the settings cannot deliver emails.
+  send_test_email_to = "ops@acme.io"
+}
+
+variable "smtp_password" {
+  type      = string
+  sensitive = true
+}
@@ -0,0 +1,2 @@
+# Tenant aliases are imported by tenant and alias.
+terraform import authproxy_tenant_alias.acme_legacy acme/acme-corp
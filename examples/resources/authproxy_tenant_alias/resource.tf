@@ -0,0 +1,5 @@
+# Keep logins through the slug acme used before its rename working.
+resource "authproxy_tenant_alias" "acme_legacy" {
+  tenant = "acme"
+  alias  = "acme-corp"
+}
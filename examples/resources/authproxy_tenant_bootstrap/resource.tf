@@ -0,0 +1,7 @@
+resource "authproxy_tenant_bootstrap" "acme" {
+  tenant                 = "acme"
+  admin_scopes           = ["tenants:admin", "users:write"]
+  admin_username         = "alice"
+  admin_email            = "alice@acme.io"
+  admin_initial_password = var.alice_initial_password
+}
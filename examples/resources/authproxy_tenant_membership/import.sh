@@ -0,0 +1,2 @@
+# Tenant memberships are imported by tenant name and user ID.
+terraform import authproxy_tenant_membership.jane acme/6f0c2a9e-3b41-4d8e-a7f5-1c9d2e8b4a60
@@ -0,0 +1,5 @@
+resource "authproxy_tenant_membership" "jane" {
+  tenant    = "acme"
+  user_id   = authproxy_user.jane.id
+  base_role = "member"
+}
@@ -0,0 +1,2 @@
+# Tenant settings are imported by tenant name.
+terraform import authproxy_tenant_settings.acme acme
@@ -0,0 +1,6 @@
+resource "authproxy_tenant_settings" "acme" {
+  tenant                = "acme"
+  session_length        = "12h"
+  allowed_login_methods = ["password", "oidc"]
+  self_signup           = false
+}
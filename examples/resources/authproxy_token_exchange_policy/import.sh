@@ -0,0 +1,2 @@
+# Token exchange policies are imported by tenant and id.
+terraform import authproxy_token_exchange_policy.checkout acme/3f0c9a52-1d2e-4c47-9a61-0b7f2f1de6a4
@@ -0,0 +1,10 @@
+resource "authproxy_token_exchange_policy" "checkout" {
+  tenant            = "acme"
+  subject_client_id = "checkout-service"
+  allowed_audiences = [
+    "https://orders.acme.io",
+    "https://payments.acme.io",
+  ]
+  allowed_scopes       = ["orders:read", "payments:write"]
+  max_lifetime_seconds = 300
+}
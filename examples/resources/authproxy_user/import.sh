@@ -0,0 +1 @@
+terraform import authproxy_user.alice acme/alice
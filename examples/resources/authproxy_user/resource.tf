@@ -0,0 +1,7 @@
+resource "authproxy_user" "alice" {
+  tenant           = "acme"
+  username         = "alice"
+  email            = "alice@acme.io"
+  display_name     = "Alice Liddell"
+  initial_password = var.alice_initial_password
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package planmodifiers provides plan modifiers shared by the resources of
+// the provider.
+package planmodifiers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// ImmutableString returns a plan modifier for string attributes authproxy
+// cannot change in place. Like stringplanmodifier.RequiresReplace it
+// requires the replacement of the resource when the value changes, and in
+// addition warns with explanation, which should say why the value is
+// immutable, as the plan itself only shows that the attribute "forces
+// replacement". Values of custom types that are semantically equal, such as
+// names differing only in case, do not require replacement.
+func ImmutableString(explanation string) planmodifier.String {
+	description := fmt.Sprintf("Changing the value destroys and recreates the resource, as %s.", explanation)
+
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			if !req.PlanValue.IsUnknown() {
+				equal, diags := semanticallyEqual(ctx, req)
+				resp.Diagnostics.Append(diags...)
+				if equal || diags.HasError() {
+					return
+				}
+			}
+			resp.RequiresReplace = true
+
+			change := fmt.Sprintf("from %s to %s", req.StateValue, req.PlanValue)
+			if req.PlanValue.IsUnknown() {
+				change = fmt.Sprintf("from %s to a value known after apply", req.StateValue)
+			}
+			resp.Diagnostics.AddAttributeWarning(
+				req.Path,
+				"Resource Replacement Required",
+				fmt.Sprintf("Changing %s %s destroys and recreates the resource, as %s.", req.Path, change, explanation),
+			)
+		},
+		description,
+		description,
+	)
+}
+
+// semanticallyEqual reports whether the planned value of a custom string type
+// is semantically equal to the one in the state.
+func semanticallyEqual(ctx context.Context, req planmodifier.StringRequest) (bool, diag.Diagnostics) {
+	var planValue, stateValue attr.Value
+
+	diags := req.Plan.GetAttribute(ctx, req.Path, &planValue)
+	diags.Append(req.State.GetAttribute(ctx, req.Path, &stateValue)...)
+	if diags.HasError() {
+		return false, diags
+	}
+
+	prior, ok := stateValue.(basetypes.StringValuableWithSemanticEquals)
+	if !ok {
+		return false, diags
+	}
+	planned, ok := planValue.(basetypes.StringValuable)
+	if !ok {
+		return false, diags
+	}
+
+	equal, equalDiags := prior.StringSemanticEquals(ctx, planned)
+	diags.Append(equalDiags...)
+	return equal, diags
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package planmodifiers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestImmutableString(t *testing.T) {
+	ctx := context.Background()
+	testSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{Required: true},
+		},
+	}
+	object := func(value tftypes.Value) tftypes.Value {
+		objectType := testSchema.Type().TerraformType(ctx).(tftypes.Object)
+		if value.IsNull() && value.Type() == nil {
+			return tftypes.NewValue(objectType, nil)
+		}
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{"tenant": value})
+	}
+	null := tftypes.Value{}
+
+	for name, tc := range map[string]struct {
+		state    tftypes.Value
+		plan     tftypes.Value
+		replace  bool
+		expected string
+	}{
+		"create": {
+			state: null,
+			plan:  tftypes.NewValue(tftypes.String, "acme"),
+		},
+		"destroy": {
+			state: tftypes.NewValue(tftypes.String, "acme"),
+			plan:  null,
+		},
+		"unchanged": {
+			state: tftypes.NewValue(tftypes.String, "acme"),
+			plan:  tftypes.NewValue(tftypes.String, "acme"),
+		},
+		"changed": {
+			state:    tftypes.NewValue(tftypes.String, "acme"),
+			plan:     tftypes.NewValue(tftypes.String, "lidl"),
+			replace:  true,
+			expected: `Changing tenant from "acme" to "lidl" destroys and recreates the resource, as roles cannot move between tenants.`,
+		},
+		"unknown": {
+			state:    tftypes.NewValue(tftypes.String, "acme"),
+			plan:     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			replace:  true,
+			expected: `Changing tenant from "acme" to a value known after apply destroys and recreates the resource, as roles cannot move between tenants.`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := planmodifier.StringRequest{
+				Path:  path.Root("tenant"),
+				State: tfsdk.State{Schema: testSchema, Raw: object(tc.state)},
+				Plan:  tfsdk.Plan{Schema: testSchema, Raw: object(tc.plan)},
+			}
+			if !req.State.Raw.IsNull() {
+				req.StateValue = testStringValue(t, tc.state)
+			}
+			if !req.Plan.Raw.IsNull() {
+				req.PlanValue = testStringValue(t, tc.plan)
+			}
+			resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+
+			ImmutableString("roles cannot move between tenants").PlanModifyString(ctx, req, resp)
+
+			if resp.RequiresReplace != tc.replace {
+				t.Errorf("expected RequiresReplace %t, got %t", tc.replace, resp.RequiresReplace)
+			}
+			if tc.expected == "" {
+				if len(resp.Diagnostics) != 0 {
+					t.Errorf("expected no diagnostics, got %v", resp.Diagnostics)
+				}
+				return
+			}
+			if len(resp.Diagnostics) != 1 || resp.Diagnostics.WarningsCount() != 1 || resp.Diagnostics[0].Detail() != tc.expected {
+				t.Errorf("expected the warning %q, got %v", tc.expected, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestImmutableString_description(t *testing.T) {
+	modifier := ImmutableString("roles cannot move between tenants")
+	expected := "Changing the value destroys and recreates the resource, as roles cannot move between tenants."
+	if got := modifier.Description(context.Background()); got != expected {
+		t.Errorf("expected the description %q, got %q", expected, got)
+	}
+}
+
+func testStringValue(t *testing.T, value tftypes.Value) types.String {
+	t.Helper()
+	if !value.IsKnown() {
+		return types.StringUnknown()
+	}
+	var s string
+	if err := value.As(&s); err != nil {
+		t.Fatal(err)
+	}
+	return types.StringValue(s)
+}
@@ -0,0 +1,333 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AccessRuleResource{}
+var _ resource.ResourceWithImportState = &AccessRuleResource{}
+var _ resource.ResourceWithConfigValidators = &AccessRuleResource{}
+
+func NewAccessRuleResource() resource.Resource {
+	return &AccessRuleResource{}
+}
+
+// AccessRuleResource defines the resource implementation.
+type AccessRuleResource struct {
+	BaseResource
+}
+
+// AccessRuleResourceModel describes the resource data model.
+type AccessRuleResourceModel struct {
+	ID         types.String    `tfsdk:"id"`
+	Tenant     tenantNameValue `tfsdk:"tenant"`
+	TargetType types.String    `tfsdk:"target_type"`
+	TargetID   types.String    `tfsdk:"target_id"`
+	Effect     types.String    `tfsdk:"effect"`
+	CIDRs      types.Set       `tfsdk:"cidrs"`
+	Principals types.Set       `tfsdk:"principals"`
+	Priority   types.Int64     `tfsdk:"priority"`
+}
+
+type accessRuleRequest struct {
+	TargetType string   `json:"target_type"`
+	TargetID   string   `json:"target_id"`
+	Effect     string   `json:"effect"`
+	CIDRs      []string `json:"cidrs"`
+	Principals []string `json:"principals"`
+	Priority   int64    `json:"priority"`
+}
+
+type accessRuleResponse struct {
+	ID         string   `json:"id"`
+	TargetType string   `json:"target_type"`
+	TargetID   string   `json:"target_id"`
+	Effect     string   `json:"effect"`
+	CIDRs      []string `json:"cidrs"`
+	Principals []string `json:"principals"`
+	Priority   int64    `json:"priority"`
+}
+
+func (r *AccessRuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_access_rule"
+}
+
+func (r *AccessRuleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Allows or denies requests to a route or service by client address or principal, in addition to the scopes the route requires. The rules of a target are evaluated by descending priority and the first matching one decides",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the rule belongs to. Changing it recreates the rule",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"target_type": schema.StringAttribute{
+				MarkdownDescription: "Kind of target the rule applies to, one of `route` and `service`",
+				Required:            true,
+				Validators: []validator.String{
+					stringOneOf("route", "service"),
+				},
+			},
+			"target_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the route or service the rule applies to",
+				Required:            true,
+			},
+			"effect": schema.StringAttribute{
+				MarkdownDescription: "Whether matching requests are let through, one of `allow` and `deny`",
+				Required:            true,
+				Validators: []validator.String{
+					stringOneOf("allow", "deny"),
+				},
+			},
+			"cidrs": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "IP addresses and CIDR ranges of clients the rule matches. At least one of `cidrs` and `principals` must be set",
+				Optional:            true,
+				Validators: []validator.Set{
+					ipRanges(),
+				},
+			},
+			"principals": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Principals the rule matches, such as `user:<id>`, `group:<name>` or `tenant:<name>` for every user of a tenant. At least one of `cidrs` and `principals` must be set",
+				Optional:            true,
+			},
+			"priority": schema.Int64Attribute{
+				MarkdownDescription: "Rules of higher priority are evaluated first",
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (r *AccessRuleResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		atLeastOneOf("cidrs", "principals"),
+	}
+}
+
+// accessRulesPath returns the path of the access rules collection of tenant.
+func accessRulesPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/access-rules", url.PathEscape(tenant))
+}
+
+// accessRulePath returns the path of the access rule of tenant with the given
+// id.
+func accessRulePath(tenant string, id string) string {
+	return accessRulesPath(tenant) + "/" + url.PathEscape(id)
+}
+
+// request builds the create and update request body from data.
+func (data *AccessRuleResourceModel) request(ctx context.Context) (accessRuleRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	rule := accessRuleRequest{
+		TargetType: data.TargetType.ValueString(),
+		TargetID:   data.TargetID.ValueString(),
+		Effect:     data.Effect.ValueString(),
+		CIDRs:      []string{},
+		Principals: []string{},
+		Priority:   data.Priority.ValueInt64(),
+	}
+	if !data.CIDRs.IsNull() {
+		diags.Append(data.CIDRs.ElementsAs(ctx, &rule.CIDRs, false)...)
+		sort.Strings(rule.CIDRs)
+	}
+	if !data.Principals.IsNull() {
+		diags.Append(data.Principals.ElementsAs(ctx, &rule.Principals, false)...)
+		sort.Strings(rule.Principals)
+	}
+
+	return rule, diags
+}
+
+// setAccessRule copies the attributes authproxy returned for a rule into
+// data.
+func (data *AccessRuleResourceModel) setAccessRule(ctx context.Context, rule accessRuleResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(rule.ID)
+	data.TargetType = types.StringValue(rule.TargetType)
+	data.TargetID = types.StringValue(rule.TargetID)
+	data.Effect = types.StringValue(rule.Effect)
+	data.Priority = types.Int64Value(rule.Priority)
+
+	// Empty sets stay null unless they were configured, so leaving cidrs or
+	// principals unset does not produce a diff.
+	if len(rule.CIDRs) > 0 || !data.CIDRs.IsNull() {
+		if rule.CIDRs == nil {
+			rule.CIDRs = []string{}
+		}
+		cidrs, d := types.SetValueFrom(ctx, types.StringType, rule.CIDRs)
+		diags.Append(d...)
+		data.CIDRs = cidrs
+	}
+	if len(rule.Principals) > 0 || !data.Principals.IsNull() {
+		if rule.Principals == nil {
+			rule.Principals = []string{}
+		}
+		principals, d := types.SetValueFrom(ctx, types.StringType, rule.Principals)
+		diags.Append(d...)
+		data.Principals = principals
+	}
+
+	return diags
+}
+
+func (r *AccessRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *AccessRuleResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var rule accessRuleResponse
+	err := r.doJSON(ctx, "POST", accessRulesPath(data.Tenant.ValueString()), body, &rule)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create access rule", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setAccessRule(ctx, rule)...)
+
+	tflog.Trace(ctx, "created an access rule resource", map[string]interface{}{
+		"id": rule.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AccessRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *AccessRuleResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var rule accessRuleResponse
+	err := r.doJSON(ctx, "GET", accessRulePath(data.Tenant.ValueString(), data.ID.ValueString()), nil, &rule)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "access rule no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"id":     data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read access rule", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setAccessRule(ctx, rule)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AccessRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *AccessRuleResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var rule accessRuleResponse
+	err := r.doJSON(ctx, "PATCH", accessRulePath(data.Tenant.ValueString(), data.ID.ValueString()), body, &rule)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update access rule", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setAccessRule(ctx, rule)...)
+
+	tflog.Trace(ctx, "updated an access rule resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AccessRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *AccessRuleResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", accessRulePath(data.Tenant.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete access rule", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted an access rule resource")
+}
+
+func (r *AccessRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, id, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
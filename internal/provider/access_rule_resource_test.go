@@ -0,0 +1,208 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccAccessRuleBackend is an in-memory stand-in for the access rule
+// endpoints of the "acme" tenant.
+type testAccAccessRuleBackend struct {
+	mu     sync.Mutex
+	rules  map[string]accessRuleResponse
+	nextID int
+}
+
+func (b *testAccAccessRuleBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prefix := accessRulesPath("acme")
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	var body accessRuleRequest
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	rule := accessRuleResponse{
+		ID:         id,
+		TargetType: body.TargetType,
+		TargetID:   body.TargetID,
+		Effect:     body.Effect,
+		CIDRs:      body.CIDRs,
+		Principals: body.Principals,
+		Priority:   body.Priority,
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		b.nextID++
+		rule.ID = fmt.Sprintf("ar%d", b.nextID)
+		b.rules[rule.ID] = rule
+		_ = json.NewEncoder(w).Encode(rule)
+	case id != "" && r.Method == http.MethodGet:
+		rule, ok := b.rules[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(rule)
+	case id != "" && r.Method == http.MethodPatch:
+		if _, ok := b.rules[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.rules[id] = rule
+		_ = json.NewEncoder(w).Encode(rule)
+	case id != "" && r.Method == http.MethodDelete:
+		if _, ok := b.rules[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.rules, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (b *testAccAccessRuleBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.rules) != 0 {
+		return fmt.Errorf("expected every access rule to be deleted, %d left", len(b.rules))
+	}
+	return nil
+}
+
+func TestAccAccessRuleResource(t *testing.T) {
+	for effect, matches := range map[string]string{
+		"allow": `principals = ["tenant:acme"]`,
+		"deny":  `cidrs = ["203.0.113.0/24", "198.51.100.7"]`,
+	} {
+		t.Run(effect, func(t *testing.T) {
+			backend := &testAccAccessRuleBackend{rules: map[string]accessRuleResponse{}}
+			server := testBackendServer(t, backend, accessRulesPath("acme"))
+
+			resourcetest.Test(t, resourcetest.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				CheckDestroy:             backend.checkDestroy,
+				Steps: []resourcetest.TestStep{
+					// Create and Read testing
+					{
+						Config: testAccProviderConfig(server.URL) + testAccAccessRuleResourceConfig(effect, matches, 10),
+						Check: resourcetest.ComposeAggregateTestCheckFunc(
+							resourcetest.TestCheckResourceAttr("authproxy_access_rule.test", "id", "ar1"),
+							resourcetest.TestCheckResourceAttr("authproxy_access_rule.test", "effect", effect),
+							resourcetest.TestCheckResourceAttr("authproxy_access_rule.test", "priority", "10"),
+							func(s *terraform.State) error {
+								backend.mu.Lock()
+								defer backend.mu.Unlock()
+								if rule := backend.rules["ar1"]; rule.Effect != effect || rule.TargetID != "rt1" {
+									return fmt.Errorf("expected a %s rule on route rt1, got %+v", effect, rule)
+								}
+								return nil
+							},
+						),
+					},
+					// ImportState testing
+					{
+						ResourceName:      "authproxy_access_rule.test",
+						ImportState:       true,
+						ImportStateId:     "acme/ar1",
+						ImportStateVerify: true,
+					},
+					// Update and Read testing
+					{
+						Config: testAccProviderConfig(server.URL) + testAccAccessRuleResourceConfig(effect, matches, 20),
+						Check: resourcetest.ComposeAggregateTestCheckFunc(
+							resourcetest.TestCheckResourceAttr("authproxy_access_rule.test", "id", "ar1"),
+							resourcetest.TestCheckResourceAttr("authproxy_access_rule.test", "priority", "20"),
+						),
+					},
+					// A rule reordered outside of Terraform is detected as drift
+					{
+						PreConfig: func() {
+							backend.mu.Lock()
+							defer backend.mu.Unlock()
+							rule := backend.rules["ar1"]
+							rule.Priority = 5
+							backend.rules["ar1"] = rule
+						},
+						Config:             testAccProviderConfig(server.URL) + testAccAccessRuleResourceConfig(effect, matches, 20),
+						PlanOnly:           true,
+						ExpectNonEmptyPlan: true,
+					},
+					// and moved back by the next apply
+					{
+						Config: testAccProviderConfig(server.URL) + testAccAccessRuleResourceConfig(effect, matches, 20),
+						Check: func(s *terraform.State) error {
+							backend.mu.Lock()
+							defer backend.mu.Unlock()
+							if priority := backend.rules["ar1"].Priority; priority != 20 {
+								return fmt.Errorf("expected the drift to be corrected, got priority %d", priority)
+							}
+							return nil
+						},
+					},
+					// Delete testing automatically occurs in TestCase
+				},
+			})
+		})
+	}
+}
+
+func TestAccAccessRuleResource_validation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		matches string
+		err     string
+	}{
+		"no matches": {matches: "", err: `At least one of the attributes cidrs, principals must be configured`},
+		"cidr":       {matches: `cidrs = ["203.0.113.0/33"]`, err: `values must be IP addresses or CIDR ranges`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resourcetest.Test(t, resourcetest.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resourcetest.TestStep{
+					{
+						Config:      testAccProviderConfig("http://127.0.0.1:1") + testAccAccessRuleResourceConfig("deny", tc.matches, 0),
+						ExpectError: regexp.MustCompile(tc.err),
+					},
+				},
+			})
+		})
+	}
+}
+
+func testAccAccessRuleResourceConfig(effect string, matches string, priority int) string {
+	return fmt.Sprintf(`
+resource "authproxy_access_rule" "test" {
+  tenant      = "acme"
+  target_type = "route"
+  target_id   = "rt1"
+  effect      = %q
+  %s
+  priority    = %d
+}
+`, effect, matches, priority)
+}
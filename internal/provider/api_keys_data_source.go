@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &APIKeysDataSource{}
+
+func NewAPIKeysDataSource() datasource.DataSource {
+	return &APIKeysDataSource{}
+}
+
+// APIKeysDataSource defines the data source implementation.
+type APIKeysDataSource struct {
+	BaseDataSource
+}
+
+// APIKeysDataSourceModel describes the data source data model.
+type APIKeysDataSourceModel struct {
+	Tenant      tenantNameValue    `tfsdk:"tenant"`
+	ExpiredOnly types.Bool         `tfsdk:"expired_only"`
+	PageSize    types.Int64        `tfsdk:"page_size"`
+	MaxItems    types.Int64        `tfsdk:"max_items"`
+	APIKeys     []APIKeyModel      `tfsdk:"api_keys"`
+	Timeouts    *ReadTimeoutsModel `tfsdk:"timeouts"`
+}
+
+// APIKeyModel describes the metadata of a single API key.
+type APIKeyModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	CreatedAt  types.String `tfsdk:"created_at"`
+	LastUsedAt types.String `tfsdk:"last_used_at"`
+	ExpiresAt  types.String `tfsdk:"expires_at"`
+	Expired    types.Bool   `tfsdk:"expired"`
+}
+
+// apiKeyMetadata only holds the metadata of an API key. The key material the
+// API may include as secret or token is never decoded.
+type apiKeyMetadata struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	CreatedAt  string  `json:"created_at"`
+	LastUsedAt *string `json:"last_used_at"`
+	ExpiresAt  *string `json:"expires_at"`
+}
+
+// expired reports whether the key expired before now. Keys without or with an
+// unparsable expiry are considered valid.
+func (k apiKeyMetadata) expired(now time.Time) bool {
+	if k.ExpiresAt == nil {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, *k.ExpiresAt)
+	return err == nil && !expiresAt.After(now)
+}
+
+func (d *APIKeysDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_keys"
+}
+
+func (d *APIKeysDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Metadata of the API keys of a tenant. The key material itself is never read",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant to list the API keys of",
+				Required:            true,
+			},
+			"expired_only": schema.BoolAttribute{
+				MarkdownDescription: "Only return keys whose expiry has passed",
+				Optional:            true,
+			},
+			"page_size": pageSizeAttribute(),
+			"max_items": maxItemsAttribute(),
+			"api_keys": schema.ListNestedAttribute{
+				MarkdownDescription: "API keys in the order returned by authproxy",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The database uuid",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the key",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "RFC 3339 timestamp of the creation of the key",
+							Computed:            true,
+						},
+						"last_used_at": schema.StringAttribute{
+							MarkdownDescription: "RFC 3339 timestamp of the last use of the key, null if it was never used",
+							Computed:            true,
+						},
+						"expires_at": schema.StringAttribute{
+							MarkdownDescription: "RFC 3339 timestamp of the expiry of the key, null if it does not expire",
+							Computed:            true,
+						},
+						"expired": schema.BoolAttribute{
+							MarkdownDescription: "Whether the key has expired at the time of the read",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *APIKeysDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data APIKeysDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_api_keys", data.Timeouts)
+	defer cancel()
+
+	keys, truncated, err := listPages[apiKeyMetadata](ctx, d.providerData, fmt.Sprintf("/tenants/%s/keys", url.PathEscape(data.Tenant.ValueString())), nil, listOptionsFrom(data.PageSize, data.MaxItems))
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "list API keys", err)
+		return
+	}
+	if truncated {
+		addTruncationWarning(&resp.Diagnostics, "API keys", data.MaxItems.ValueInt64())
+	}
+
+	now := time.Now()
+	data.APIKeys = make([]APIKeyModel, 0, len(keys))
+	for _, key := range keys {
+		expired := key.expired(now)
+		if data.ExpiredOnly.ValueBool() && !expired {
+			continue
+		}
+		data.APIKeys = append(data.APIKeys, APIKeyModel{
+			ID:         types.StringValue(key.ID),
+			Name:       types.StringValue(key.Name),
+			CreatedAt:  types.StringValue(key.CreatedAt),
+			LastUsedAt: types.StringPointerValue(key.LastUsedAt),
+			ExpiresAt:  types.StringPointerValue(key.ExpiresAt),
+			Expired:    types.BoolValue(expired),
+		})
+	}
+
+	tflog.Trace(ctx, "read API keys data source", map[string]interface{}{
+		"count": len(data.APIKeys),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccAPIKeysServer serves the keys of the "acme" tenant over two pages.
+// The fixture includes key material to make sure it never ends up in state.
+func testAccAPIKeysServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants/acme/keys", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"items":[
+				{"id":"k1","name":"ci","created_at":"2020-01-01T00:00:00Z","last_used_at":"2020-06-01T00:00:00Z","expires_at":"2021-01-01T00:00:00Z","secret":"ap_live_secret1"},
+				{"id":"k2","name":"deploy","created_at":"2022-01-01T00:00:00Z","last_used_at":null,"expires_at":"2999-01-01T00:00:00Z","token":"ap_live_token2"}
+			],"next_cursor":"2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[
+			{"id":"k3","name":"backup","created_at":"2023-01-01T00:00:00Z","secret":"ap_live_secret3"}
+		]}`)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestAccAPIKeysDataSource(t *testing.T) {
+	server := testAccAPIKeysServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_api_keys" "all" {
+  tenant = "acme"
+}
+
+data "authproxy_api_keys" "expired" {
+  tenant       = "acme"
+  expired_only = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_api_keys.all", "api_keys.#", "3"),
+					resource.TestCheckResourceAttr("data.authproxy_api_keys.all", "api_keys.1.name", "deploy"),
+					resource.TestCheckNoResourceAttr("data.authproxy_api_keys.all", "api_keys.1.last_used_at"),
+					resource.TestCheckResourceAttr("data.authproxy_api_keys.all", "api_keys.1.expired", "false"),
+					resource.TestCheckNoResourceAttr("data.authproxy_api_keys.all", "api_keys.2.expires_at"),
+					resource.TestCheckResourceAttr("data.authproxy_api_keys.expired", "api_keys.#", "1"),
+					resource.TestCheckResourceAttr("data.authproxy_api_keys.expired", "api_keys.0.name", "ci"),
+				),
+			},
+		},
+	})
+}
+
+func TestAPIKeysDataSource_stripsSecrets(t *testing.T) {
+	server := testAccAPIKeysServer(t)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewAPIKeysDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"tenant": tftypes.NewValue(tftypes.String, "acme"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+
+	state := resp.State.Raw.String()
+	if !strings.Contains(state, "backup") {
+		t.Fatalf("expected the API keys in state, got: %s", state)
+	}
+	for _, secret := range []string{"ap_live_secret1", "ap_live_token2", "ap_live_secret3"} {
+		if strings.Contains(state, secret) {
+			t.Errorf("state contains secret %q: %s", secret, state)
+		}
+	}
+}
+
+func TestAPIKeysDataSource_expiredOnly(t *testing.T) {
+	server := testAccAPIKeysServer(t)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewAPIKeysDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"tenant":       tftypes.NewValue(tftypes.String, "acme"),
+		"expired_only": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data APIKeysDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	if len(data.APIKeys) != 1 || data.APIKeys[0].ID.ValueString() != "k1" {
+		t.Fatalf("expected only the expired key k1, got %v", data.APIKeys)
+	}
+	if !data.APIKeys[0].Expired.ValueBool() {
+		t.Errorf("expected k1 to be marked expired")
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/planmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &APITokenResource{}
+var _ resource.ResourceWithImportState = &APITokenResource{}
+var _ resource.ResourceWithModifyPlan = &APITokenResource{}
+
+func NewAPITokenResource() resource.Resource {
+	return &APITokenResource{}
+}
+
+// APITokenResource defines the resource implementation.
+type APITokenResource struct {
+	BaseResource
+}
+
+// APITokenResourceModel describes the resource data model.
+type APITokenResourceModel struct {
+	ID        types.String    `tfsdk:"id"`
+	Tenant    tenantNameValue `tfsdk:"tenant"`
+	Name      types.String    `tfsdk:"name"`
+	Scopes    types.Set       `tfsdk:"scopes"`
+	ExpiresAt types.String    `tfsdk:"expires_at"`
+	CreatedAt types.String    `tfsdk:"created_at"`
+	Token     types.String    `tfsdk:"token"`
+	Rotation  types.Map       `tfsdk:"rotation"`
+}
+
+type apiTokenCreateRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt *string  `json:"expires_at,omitempty"`
+}
+
+// apiTokenResponse is a key as returned by the keys endpoints. Token is only
+// ever set in the response to its creation.
+type apiTokenResponse struct {
+	apiKeyMetadata
+	Scopes []string `json:"scopes"`
+	Token  string   `json:"token"`
+}
+
+// apiTokenPath returns the path of the key of tenant with the given id.
+func apiTokenPath(tenant string, id string) string {
+	return fmt.Sprintf("/tenants/%s/keys/%s", url.PathEscape(tenant), url.PathEscape(id))
+}
+
+// sameInstant reports whether the RFC 3339 timestamps a and b denote the same
+// instant, so a differently formatted answer does not replace the token.
+func sameInstant(a string, b string) bool {
+	first, err := time.Parse(time.RFC3339, a)
+	if err != nil {
+		return false
+	}
+	second, err := time.Parse(time.RFC3339, b)
+	return err == nil && first.Equal(second)
+}
+
+func (r *APITokenResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_token"
+}
+
+func (r *APITokenResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "API token of a tenant. Tokens cannot be changed, changing any argument other than `rotation` revokes the token and mints a new one",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the token belongs to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot move tokens between tenants"),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the token",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("the name of a token is fixed when it is issued"),
+				},
+			},
+			"scopes": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scopes granted to the token",
+				Required:            true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "RFC 3339 timestamp after which the token stops working. Tokens without it do not expire",
+				Optional:            true,
+				Validators: []validator.String{
+					rfc3339Timestamp(),
+				},
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("the expiry of a token is fixed when it is issued"),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "RFC 3339 timestamp of the creation of the token",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "The token itself. authproxy only reveals it when the token is created or rotated, it is null for imported tokens until they are rotated. A token rotated outside of Terraform cannot be detected",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"rotation": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Arbitrary values that rotate the token whenever any of them changes, such as a date set by a `time_rotating` resource. Rotating keeps the ID of the token, unlike replacing it",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// ModifyPlan plans a new token whenever rotation changes. Every other
+// argument requires replacement, so rotation is the only change updating
+// the token in place.
+func (r *APITokenResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Creation plans an unknown token already, destruction nothing at all.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state *APITokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.Rotation.Equal(state.Rotation) {
+		return
+	}
+
+	plan.Token = types.StringUnknown()
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *APITokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *APITokenResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var scopes []string
+	resp.Diagnostics.Append(data.Scopes.ElementsAs(ctx, &scopes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var token apiTokenResponse
+	err := r.doJSON(ctx, "POST", fmt.Sprintf("/tenants/%s/keys", url.PathEscape(data.Tenant.ValueString())), apiTokenCreateRequest{
+		Name:      data.Name.ValueString(),
+		Scopes:    scopes,
+		ExpiresAt: data.ExpiresAt.ValueStringPointer(),
+	}, &token)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create API token", err)
+		return
+	}
+	if token.Token == "" {
+		resp.Diagnostics.AddWarning(
+			"Missing Token",
+			"authproxy created the API token without revealing it, token is left empty. Replace the resource to mint a new token.",
+		)
+	}
+
+	data.ID = types.StringValue(token.ID)
+	data.CreatedAt = types.StringValue(token.CreatedAt)
+	data.Token = optionalString(token.Token)
+
+	tflog.Trace(ctx, "created an API token resource", map[string]interface{}{
+		"id": token.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *APITokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *APITokenResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var token apiTokenResponse
+	err := r.doJSON(ctx, "GET", apiTokenPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, &token)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "API token no longer exists, removing it from state", map[string]interface{}{
+			"id": data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read API token", err)
+		return
+	}
+
+	// The token is only revealed on creation, whatever was stored then is
+	// kept as is.
+	data.Name = types.StringValue(token.Name)
+	data.CreatedAt = types.StringValue(token.CreatedAt)
+	if token.ExpiresAt == nil || !sameInstant(data.ExpiresAt.ValueString(), *token.ExpiresAt) {
+		data.ExpiresAt = types.StringPointerValue(token.ExpiresAt)
+	}
+	if token.Scopes != nil {
+		scopes, diags := types.SetValueFrom(ctx, types.StringType, token.Scopes)
+		resp.Diagnostics.Append(diags...)
+		data.Scopes = scopes
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *APITokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *APITokenResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every argument but rotation requires replacement, so an update always
+	// rotates the token.
+	var token apiTokenResponse
+	err := r.doJSON(ctx, "POST", apiTokenPath(data.Tenant.ValueString(), data.ID.ValueString())+"/rotate", nil, &token)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "rotate API token", err)
+		return
+	}
+	if token.Token == "" {
+		resp.Diagnostics.AddWarning(
+			"Missing Token",
+			"authproxy rotated the API token without revealing it, token is left empty. Change rotation again to rotate it once more.",
+		)
+	}
+
+	data.Token = optionalString(token.Token)
+
+	tflog.Trace(ctx, "rotated an API token resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *APITokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *APITokenResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", apiTokenPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "revoke API token", err)
+		return
+	}
+
+	tflog.Trace(ctx, "revoked an API token resource")
+}
+
+func (r *APITokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, id, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
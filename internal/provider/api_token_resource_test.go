@@ -0,0 +1,241 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccAPITokenBackend is an in-memory stand-in for the keys endpoints of
+// the "acme" tenant. Like authproxy it reveals a token only once.
+type testAccAPITokenBackend struct {
+	mu       sync.Mutex
+	tokens   map[string]apiTokenResponse
+	revealed int
+	rotated  int
+	nextID   int
+}
+
+func (b *testAccAPITokenBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	const prefix = "/tenants/acme/keys"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	if id, found := strings.CutSuffix(id, "/rotate"); found && r.Method == http.MethodPost {
+		token, ok := b.tokens[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.revealed++
+		b.rotated++
+		token.Token = fmt.Sprintf("ap_live_%s_rotated_%d", id, b.rotated)
+		_ = json.NewEncoder(w).Encode(token)
+		return
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		var create apiTokenCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.nextID++
+		token := apiTokenResponse{
+			apiKeyMetadata: apiKeyMetadata{
+				ID:        fmt.Sprintf("k%d", b.nextID),
+				Name:      create.Name,
+				CreatedAt: "2023-01-01T00:00:00Z",
+				ExpiresAt: create.ExpiresAt,
+			},
+			Scopes: create.Scopes,
+		}
+		b.tokens[token.ID] = token
+		b.revealed++
+		token.Token = fmt.Sprintf("ap_live_%d", b.nextID)
+		_ = json.NewEncoder(w).Encode(token)
+	case id != "" && r.Method == http.MethodGet:
+		token, ok := b.tokens[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(token)
+	case id != "" && r.Method == http.MethodDelete:
+		if _, ok := b.tokens[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.tokens, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAccAPITokenResource(t *testing.T) {
+	backend := &testAccAPITokenBackend{tokens: map[string]apiTokenResponse{}}
+	server := testBackendServer(t, backend, "/tenants/acme/keys")
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if len(backend.tokens) != 0 {
+				return fmt.Errorf("expected every token to be revoked, %d left", len(backend.tokens))
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccAPITokenResourceConfig("users:read"),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "id", "k1"),
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "token", "ap_live_1"),
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "scopes.#", "1"),
+				),
+			},
+			// The token survives a refresh although authproxy does not
+			// reveal it again
+			{
+				RefreshState: true,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "token", "ap_live_1"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if backend.revealed != 1 {
+							return fmt.Errorf("expected the token to be revealed once, got %d", backend.revealed)
+						}
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "authproxy_api_token.test",
+				ImportState:             true,
+				ImportStateId:           "acme/k1",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"token"},
+			},
+			// Changing the scopes mints a new token
+			{
+				Config: testAccProviderConfig(server.URL) + testAccAPITokenResourceConfig("users:read", "users:write"),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "id", "k2"),
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "token", "ap_live_2"),
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "scopes.#", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAPITokenResource_rotation(t *testing.T) {
+	backend := &testAccAPITokenBackend{tokens: map[string]apiTokenResponse{}}
+	server := testBackendServer(t, backend, "/tenants/acme/keys")
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccAPITokenResourceRotationConfig("2024-01"),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "id", "k1"),
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "token", "ap_live_1"),
+				),
+			},
+			// Changing a rotation value rotates the token in place
+			{
+				Config: testAccProviderConfig(server.URL) + testAccAPITokenResourceRotationConfig("2024-02"),
+				ConfigPlanChecks: resourcetest.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("authproxy_api_token.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "id", "k1"),
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "token", "ap_live_k1_rotated_1"),
+					resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "rotation.month", "2024-02"),
+				),
+			},
+			// The rotated token survives a refresh as well
+			{
+				RefreshState: true,
+				Check:        resourcetest.TestCheckResourceAttr("authproxy_api_token.test", "token", "ap_live_k1_rotated_1"),
+			},
+			// Unchanged rotation values do not rotate the token
+			{
+				Config: testAccProviderConfig(server.URL) + testAccAPITokenResourceRotationConfig("2024-02"),
+				Check: func(s *terraform.State) error {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					if backend.rotated != 1 {
+						return fmt.Errorf("expected the token to be rotated once, got %d", backend.rotated)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccAPITokenResourceRotationConfig(month string) string {
+	return fmt.Sprintf(`
+resource "authproxy_api_token" "test" {
+  tenant = "acme"
+  name   = "ci"
+  scopes = ["users:read"]
+
+  rotation = {
+    month = %q
+  }
+}
+`, month)
+}
+
+func TestAPITokenResource_tokenSensitive(t *testing.T) {
+	resp := &resource.SchemaResponse{}
+	NewAPITokenResource().Schema(context.Background(), resource.SchemaRequest{}, resp)
+
+	attribute, ok := resp.Schema.Attributes["token"]
+	if !ok || !attribute.IsSensitive() {
+		t.Fatal("expected token to be a sensitive attribute")
+	}
+}
+
+func testAccAPITokenResourceConfig(scopes ...string) string {
+	return fmt.Sprintf(`
+resource "authproxy_api_token" "test" {
+  tenant     = "acme"
+  name       = "ci"
+  scopes     = %[1]s
+  expires_at = "2030-01-01T00:00:00Z"
+}
+`, testAccStringList(scopes...))
+}
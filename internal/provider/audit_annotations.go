@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// auditHeaderPrefix prefixes the keys of audit_annotations to the headers
+// writes carry them in, so the annotation ticket is sent as X-Audit-Ticket.
+const auditHeaderPrefix = "X-Audit-"
+
+// auditAnnotationsValue returns the configured audit_annotations. Unknown
+// values, which only occur while planning, when no writes are made, and
+// unknown elements are left out.
+func auditAnnotationsValue(annotations types.Map) map[string]string {
+	if annotations.IsNull() || annotations.IsUnknown() {
+		return nil
+	}
+
+	values := map[string]string{}
+	for key, element := range annotations.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		values[key] = value.ValueString()
+	}
+	return values
+}
+
+// setAuditHeaders sets the header of every annotation on header. The
+// annotations are not secrets, they are meant to show up in the audit log
+// of authproxy.
+func setAuditHeaders(header http.Header, annotations map[string]string) {
+	for key, value := range annotations {
+		header.Set(auditHeaderPrefix+key, value)
+	}
+}
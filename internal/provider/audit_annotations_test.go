@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestAuditAnnotations(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	backend := testserver.New(t)
+	backend.CreateTenant("acme")
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(backend.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+		"audit_annotations": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"ticket":       str("CHG-1234"),
+			"requested_by": str("alice"),
+		}),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	config := map[string]tftypes.Value{"tenant": str("acme"), "name": str("viewer"), "permissions": stringList("billing:read")}
+	state := testProviderApply(t, server, "authproxy_role", nil, config)
+	state = testProviderRefresh(t, server, "authproxy_role", state)
+	config["permissions"] = stringList("billing:read", "billing:write")
+	state = testProviderApply(t, server, "authproxy_role", state, config)
+	testProviderApply(t, server, "authproxy_role", state, nil)
+
+	writes := map[string]bool{}
+	for _, request := range backend.Requests() {
+		ticket, requestedBy := request.Header.Get("X-Audit-Ticket"), request.Header.Get("X-Audit-Requested_by")
+		if request.Method == http.MethodGet {
+			if ticket != "" || requestedBy != "" {
+				t.Errorf("expected %s %s to carry no audit annotations, got %v", request.Method, request.Path, request.Header)
+			}
+			continue
+		}
+		writes[request.Method] = true
+		if ticket != "CHG-1234" || requestedBy != "alice" {
+			t.Errorf("expected %s %s to carry the audit annotations, got %v", request.Method, request.Path, request.Header)
+		}
+	}
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodDelete} {
+		if !writes[method] {
+			t.Errorf("expected a %s request, got %v", method, backend.Requests())
+		}
+	}
+}
+
+func TestAuditAnnotations_logged(t *testing.T) {
+	backend := testserver.New(t)
+	providerData := testProviderData(backend.URL)
+	providerData.auditAnnotations = map[string]string{"ticket": "CHG-1234"}
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	if err := providerData.doJSON(ctx, "POST", "/tenants", map[string]string{"tenant": "acme"}, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := providerData.doJSON(ctx, "GET", "/tenants/acme", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(strings.NewReader(output.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a single entry per request, got %v", entries)
+	}
+	if annotations, ok := entries[0]["audit_annotations"].(map[string]interface{}); !ok || annotations["ticket"] != "CHG-1234" {
+		t.Errorf("expected the write to log its audit annotations, got %v", entries[0])
+	}
+	if _, ok := entries[1]["audit_annotations"]; ok {
+		t.Errorf("expected the read to log no audit annotations, got %v", entries[1])
+	}
+}
+
+func TestAuditAnnotations_validation(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		annotations map[string]tftypes.Value
+		errors      int
+	}{
+		"valid":               {annotations: map[string]tftypes.Value{"ticket": str("CHG-1234"), "requested_by": str("alice")}},
+		"space in key":        {annotations: map[string]tftypes.Value{"requested by": str("alice")}, errors: 1},
+		"colon in key":        {annotations: map[string]tftypes.Value{"ticket:": str("CHG-1234")}, errors: 1},
+		"line break in value": {annotations: map[string]tftypes.Value{"ticket": str("CHG-1234\r\nX-Admin: true")}, errors: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := NewProtocol6("test")()
+			schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			configType := schemaResp.Provider.ValueType().(tftypes.Object)
+			values := make(map[string]tftypes.Value, len(configType.AttributeTypes))
+			for name, attributeType := range configType.AttributeTypes {
+				values[name] = tftypes.NewValue(attributeType, nil)
+			}
+			values["audit_annotations"] = tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tc.annotations)
+			config, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, values))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := server.ValidateProviderConfig(ctx, &tfprotov6.ValidateProviderConfigRequest{Config: &config})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Diagnostics) != tc.errors {
+				t.Errorf("expected %d errors, got %v", tc.errors, resp.Diagnostics)
+			}
+			for _, diagnostic := range resp.Diagnostics {
+				if diagnostic.Severity != tfprotov6.DiagnosticSeverityError || diagnostic.Attribute == nil {
+					t.Errorf("expected an error at the annotation, got %v", diagnostic)
+				}
+			}
+		})
+	}
+}
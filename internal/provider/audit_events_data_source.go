@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// auditEventsDefaultLimit is the number of pages read when limit is unset.
+	auditEventsDefaultLimit = 10
+	// auditEventsMaxLimit keeps a single read from walking the whole audit log.
+	auditEventsMaxLimit = 100
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AuditEventsDataSource{}
+
+func NewAuditEventsDataSource() datasource.DataSource {
+	return &AuditEventsDataSource{}
+}
+
+// AuditEventsDataSource defines the data source implementation.
+type AuditEventsDataSource struct {
+	BaseDataSource
+}
+
+// AuditEventsDataSourceModel describes the data source data model.
+type AuditEventsDataSourceModel struct {
+	Tenant    tenantNameValue    `tfsdk:"tenant"`
+	Since     types.String       `tfsdk:"since"`
+	Until     types.String       `tfsdk:"until"`
+	Actions   types.List         `tfsdk:"actions"`
+	Limit     types.Int64        `tfsdk:"limit"`
+	Truncated types.Bool         `tfsdk:"truncated"`
+	Events    []AuditEventModel  `tfsdk:"events"`
+	Timeouts  *ReadTimeoutsModel `tfsdk:"timeouts"`
+}
+
+// AuditEventModel describes a single audit event.
+type AuditEventModel struct {
+	Actor     types.String `tfsdk:"actor"`
+	Action    types.String `tfsdk:"action"`
+	Target    types.String `tfsdk:"target"`
+	Timestamp types.String `tfsdk:"timestamp"`
+}
+
+type auditEventResponse struct {
+	Actor     string `json:"actor"`
+	Action    string `json:"action"`
+	Target    string `json:"target"`
+	Timestamp string `json:"timestamp"`
+}
+
+func (d *AuditEventsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_audit_events"
+}
+
+func (d *AuditEventsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Admin API audit events",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Only return events of this tenant",
+				Optional:            true,
+			},
+			"since": schema.StringAttribute{
+				MarkdownDescription: "Only return events at or after this RFC 3339 timestamp",
+				Optional:            true,
+				Validators: []validator.String{
+					rfc3339Timestamp(),
+				},
+			},
+			"until": schema.StringAttribute{
+				MarkdownDescription: "Only return events before this RFC 3339 timestamp",
+				Optional:            true,
+				Validators: []validator.String{
+					rfc3339Timestamp(),
+				},
+			},
+			"actions": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Only return events with one of these actions",
+				Optional:            true,
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of pages to read from the audit log, defaults to %d and may not exceed %d", auditEventsDefaultLimit, auditEventsMaxLimit),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64Between(1, auditEventsMaxLimit),
+				},
+			},
+			"truncated": schema.BoolAttribute{
+				MarkdownDescription: "Whether `limit` was reached before all matching events were read",
+				Computed:            true,
+			},
+			"events": schema.ListNestedAttribute{
+				MarkdownDescription: "Audit events in the order returned by the server",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"actor": schema.StringAttribute{
+							MarkdownDescription: "Principal that performed the action",
+							Computed:            true,
+						},
+						"action": schema.StringAttribute{
+							MarkdownDescription: "Action that was performed",
+							Computed:            true,
+						},
+						"target": schema.StringAttribute{
+							MarkdownDescription: "Object the action was performed on",
+							Computed:            true,
+						},
+						"timestamp": schema.StringAttribute{
+							MarkdownDescription: "When the action was performed",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *AuditEventsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AuditEventsDataSourceModel
+	var actions []string
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if !data.Actions.IsNull() {
+		resp.Diagnostics.Append(data.Actions.ElementsAs(ctx, &actions, false)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_audit_events", data.Timeouts)
+	defer cancel()
+
+	query := url.Values{}
+	if !data.Tenant.IsNull() {
+		query.Set("tenant", data.Tenant.ValueString())
+	}
+	if !data.Since.IsNull() {
+		query.Set("since", data.Since.ValueString())
+	}
+	if !data.Until.IsNull() {
+		query.Set("until", data.Until.ValueString())
+	}
+	for _, action := range actions {
+		query.Add("action", action)
+	}
+
+	limit := int64(auditEventsDefaultLimit)
+	if !data.Limit.IsNull() {
+		limit = data.Limit.ValueInt64()
+	}
+
+	events, truncated, err := listPages[auditEventResponse](ctx, d.providerData, "/audit", query, listOptions{MaxPages: int(limit)})
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "list audit events", err)
+		return
+	}
+
+	wanted := make(map[string]bool, len(actions))
+	for _, action := range actions {
+		wanted[action] = true
+	}
+
+	data.Truncated = types.BoolValue(truncated)
+	data.Events = make([]AuditEventModel, 0, len(events))
+	for _, event := range events {
+		if len(wanted) > 0 && !wanted[event.Action] {
+			continue
+		}
+		data.Events = append(data.Events, AuditEventModel{
+			Actor:     types.StringValue(event.Actor),
+			Action:    types.StringValue(event.Action),
+			Target:    types.StringValue(event.Target),
+			Timestamp: types.StringValue(event.Timestamp),
+		})
+	}
+
+	tflog.Trace(ctx, "read audit events data source", map[string]interface{}{
+		"count":     len(data.Events),
+		"truncated": truncated,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
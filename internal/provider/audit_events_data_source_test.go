@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccAuditServer serves an endless audit log with one event per hour and
+// one event per page, honoring the since/until window.
+func testAccAuditServer(t *testing.T) *httptest.Server {
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	return httptest.NewServer(testserver.ValidateRequests(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		cursor := 0
+		if since, err := time.Parse(time.RFC3339, query.Get("since")); err == nil {
+			cursor = int(since.Sub(start).Hours())
+		}
+		if query.Has("cursor") {
+			cursor, _ = strconv.Atoi(query.Get("cursor"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		timestamp := start.Add(time.Duration(cursor) * time.Hour)
+		if until, err := time.Parse(time.RFC3339, query.Get("until")); err == nil && !timestamp.Before(until) {
+			fmt.Fprint(w, `{"items":[]}`)
+			return
+		}
+		action := "role.update"
+		if cursor%2 == 0 {
+			action = "tenant.create"
+		}
+		fmt.Fprintf(w, `{"items":[{"actor":"admin","action":%q,"target":"acme","timestamp":%q}],"next_cursor":"%d"}`, action, timestamp.Format(time.RFC3339), cursor+1)
+	})))
+}
+
+func TestAccAuditEventsDataSource_window(t *testing.T) {
+	server := testAccAuditServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_audit_events" "test" {
+  since   = "2023-05-01T00:00:00Z"
+  until   = "2023-05-01T04:00:00Z"
+  actions = ["tenant.create"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_audit_events.test", "truncated", "false"),
+					resource.TestCheckResourceAttr("data.authproxy_audit_events.test", "events.#", "2"),
+					resource.TestCheckResourceAttr("data.authproxy_audit_events.test", "events.0.timestamp", "2023-05-01T00:00:00Z"),
+					resource.TestCheckResourceAttr("data.authproxy_audit_events.test", "events.1.timestamp", "2023-05-01T02:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAuditEventsDataSource_limit(t *testing.T) {
+	server := testAccAuditServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_audit_events" "test" {
+  limit = 3
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_audit_events.test", "truncated", "true"),
+					resource.TestCheckResourceAttr("data.authproxy_audit_events.test", "events.#", "3"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_audit_events" "test" {
+  limit = 1000
+}
+`,
+				ExpectError: regexp.MustCompile(`value must be between 1 and 100`),
+			},
+		},
+	})
+}
+
+func TestAccAuditEventsDataSource_invalidTimestamp(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig("http://localhost") + `
+data "authproxy_audit_events" "test" {
+  since = "yesterday"
+}
+`,
+				ExpectError: regexp.MustCompile(`RFC 3339 timestamp`),
+			},
+		},
+	})
+}
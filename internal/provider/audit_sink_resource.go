@@ -0,0 +1,374 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AuditSinkResource{}
+var _ resource.ResourceWithImportState = &AuditSinkResource{}
+var _ resource.ResourceWithValidateConfig = &AuditSinkResource{}
+
+func NewAuditSinkResource() resource.Resource {
+	return &AuditSinkResource{}
+}
+
+// AuditSinkResource defines the resource implementation.
+type AuditSinkResource struct {
+	BaseResource
+}
+
+// AuditSinkResourceModel describes the resource data model.
+type AuditSinkResourceModel struct {
+	ID                  types.String    `tfsdk:"id"`
+	Tenant              tenantNameValue `tfsdk:"tenant"`
+	Type                types.String    `tfsdk:"type"`
+	Endpoint            types.String    `tfsdk:"endpoint"`
+	AuthorizationHeader types.String    `tfsdk:"authorization_header"`
+	EventTypes          types.Set       `tfsdk:"event_types"`
+	Enabled             types.Bool      `tfsdk:"enabled"`
+	VerifyOnCreate      types.Bool      `tfsdk:"verify_on_create"`
+}
+
+type auditSinkRequest struct {
+	Type                string   `json:"type,omitempty"`
+	Endpoint            string   `json:"endpoint"`
+	AuthorizationHeader string   `json:"authorization_header"`
+	EventTypes          []string `json:"event_types"`
+	Enabled             bool     `json:"enabled"`
+}
+
+// auditSinkResponse leaves out the authorization header, authproxy only ever
+// returns it redacted.
+type auditSinkResponse struct {
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	Endpoint   string   `json:"endpoint"`
+	EventTypes []string `json:"event_types"`
+	Enabled    bool     `json:"enabled"`
+}
+
+func (r *AuditSinkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_audit_sink"
+}
+
+func (r *AuditSinkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Destination the audit log of a tenant is shipped to",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant whose audit log is shipped. Changing it recreates the sink",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Kind of sink, one of `http` and `syslog`. Changing it recreates the sink",
+				Required:            true,
+				Validators: []validator.String{
+					stringOneOf("http", "syslog"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "Where events are delivered, an `http` or `https` URL for `http` sinks and a `tcp`, `udp` or `tls` URL such as `tcp://logs.example.com:514` for `syslog` sinks",
+				Required:            true,
+			},
+			"authorization_header": schema.StringAttribute{
+				MarkdownDescription: "Value of the `Authorization` header sent with every delivery, such as a Splunk HEC token. Authproxy never returns it, so changes made outside of Terraform are not detected",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"event_types": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Only ship events of these types, all events when unset",
+				Optional:            true,
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether events are shipped. Defaults to `true`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"verify_on_create": schema.BoolAttribute{
+				MarkdownDescription: "Whether to send a test event when creating the sink, failing the creation when it cannot be delivered. Defaults to `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+func (r *AuditSinkResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data AuditSinkResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values that are not known yet are validated once they are.
+	if data.Type.IsUnknown() || data.Endpoint.IsNull() || data.Endpoint.IsUnknown() {
+		return
+	}
+
+	schemes := map[string][]string{
+		"http":   {"http", "https"},
+		"syslog": {"tcp", "udp", "tls"},
+	}[data.Type.ValueString()]
+	if schemes == nil {
+		return
+	}
+
+	endpoint, err := url.Parse(data.Endpoint.ValueString())
+	valid := err == nil && endpoint.Host != ""
+	if valid {
+		valid = false
+		for _, scheme := range schemes {
+			valid = valid || endpoint.Scheme == scheme
+		}
+	}
+	if !valid {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Invalid Attribute Value",
+			fmt.Sprintf("The endpoint of a %s sink must be a URL with one of the schemes %s, got: %q",
+				data.Type.ValueString(), strings.Join(schemes, ", "), data.Endpoint.ValueString()),
+		)
+	}
+}
+
+// auditSinksPath returns the path of the audit sinks collection of tenant.
+func auditSinksPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/audit-sinks", url.PathEscape(tenant))
+}
+
+// auditSinkPath returns the path of the audit sink of tenant with the given
+// id.
+func auditSinkPath(tenant string, id string) string {
+	return auditSinksPath(tenant) + "/" + url.PathEscape(id)
+}
+
+// request builds the create and update request body from data.
+func (data *AuditSinkResourceModel) request(ctx context.Context) (auditSinkRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	sink := auditSinkRequest{
+		Endpoint:            data.Endpoint.ValueString(),
+		AuthorizationHeader: data.AuthorizationHeader.ValueString(),
+		EventTypes:          []string{},
+		Enabled:             data.Enabled.ValueBool(),
+	}
+	if !data.EventTypes.IsNull() {
+		diags.Append(data.EventTypes.ElementsAs(ctx, &sink.EventTypes, false)...)
+		sort.Strings(sink.EventTypes)
+	}
+
+	return sink, diags
+}
+
+// setAuditSink copies the attributes authproxy returned for an audit sink
+// into data. The authorization header keeps its prior value.
+func (data *AuditSinkResourceModel) setAuditSink(ctx context.Context, sink auditSinkResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(sink.ID)
+	data.Type = types.StringValue(sink.Type)
+	data.Endpoint = types.StringValue(sink.Endpoint)
+	data.Enabled = types.BoolValue(sink.Enabled)
+
+	// No event types stay null unless they were configured as an empty set,
+	// so leaving event_types unset does not produce a diff.
+	if len(sink.EventTypes) > 0 || !data.EventTypes.IsNull() {
+		if sink.EventTypes == nil {
+			sink.EventTypes = []string{}
+		}
+		eventTypes, d := types.SetValueFrom(ctx, types.StringType, sink.EventTypes)
+		diags.Append(d...)
+		data.EventTypes = eventTypes
+	}
+
+	// Imported sinks have not been verified by Terraform.
+	if data.VerifyOnCreate.IsNull() {
+		data.VerifyOnCreate = types.BoolValue(false)
+	}
+
+	return diags
+}
+
+func (r *AuditSinkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *AuditSinkResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	body.Type = data.Type.ValueString()
+
+	var sink auditSinkResponse
+	err := r.doJSON(ctx, "POST", auditSinksPath(data.Tenant.ValueString()), body, &sink)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create audit sink", err)
+		return
+	}
+
+	if data.VerifyOnCreate.ValueBool() {
+		err := r.doJSON(ctx, "POST", auditSinkPath(data.Tenant.ValueString(), sink.ID)+"/test", nil, nil)
+		if err != nil {
+			// Remove the sink again, it would be left behind without
+			// being tracked in state otherwise.
+			detail := fmt.Sprintf("Authproxy could not deliver a test event to %s, the audit sink was not created: %s", data.Endpoint.ValueString(), err)
+			if deleteErr := r.doJSON(ctx, "DELETE", auditSinkPath(data.Tenant.ValueString(), sink.ID), nil, nil); deleteErr != nil {
+				detail = fmt.Sprintf("Authproxy could not deliver a test event to %s: %s\n\nRemoving the audit sink %q again failed as well, delete it manually: %s",
+					data.Endpoint.ValueString(), err, sink.ID, deleteErr)
+			}
+			resp.Diagnostics.AddAttributeError(path.Root("endpoint"), "Audit Sink Verification Failed", detail)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(data.setAuditSink(ctx, sink)...)
+
+	tflog.Trace(ctx, "created an audit sink resource", map[string]interface{}{
+		"id": sink.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AuditSinkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *AuditSinkResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var sink auditSinkResponse
+	err := r.doJSON(ctx, "GET", auditSinkPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, &sink)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "audit sink no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"id":     data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read audit sink", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setAuditSink(ctx, sink)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AuditSinkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *AuditSinkResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var sink auditSinkResponse
+	err := r.doJSON(ctx, "PATCH", auditSinkPath(data.Tenant.ValueString(), data.ID.ValueString()), body, &sink)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update audit sink", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setAuditSink(ctx, sink)...)
+
+	tflog.Trace(ctx, "updated an audit sink resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AuditSinkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *AuditSinkResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", auditSinkPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete audit sink", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted an audit sink resource")
+}
+
+func (r *AuditSinkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, id, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
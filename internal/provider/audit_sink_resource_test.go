@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccAuditSinkBackend is an in-memory stand-in for the audit sink
+// endpoints of the "acme" tenant. Like authproxy it only returns
+// authorization headers redacted, and test deliveries to endpoints on
+// unreachable.example.com fail.
+type testAccAuditSinkBackend struct {
+	mu     sync.Mutex
+	sinks  map[string]auditSinkRequest
+	tested []string
+	nextID int
+}
+
+func (b *testAccAuditSinkBackend) respond(w http.ResponseWriter, id string) {
+	sink := b.sinks[id]
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":                   id,
+		"type":                 sink.Type,
+		"endpoint":             sink.Endpoint,
+		"authorization_header": "**REDACTED**",
+		"event_types":          sink.EventTypes,
+		"enabled":              sink.Enabled,
+	})
+}
+
+func (b *testAccAuditSinkBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prefix := auditSinksPath("acme")
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	if id, found := strings.CutSuffix(id, "/test"); found && r.Method == http.MethodPost {
+		sink, ok := b.sinks[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.tested = append(b.tested, id)
+		if strings.Contains(sink.Endpoint, "unreachable.example.com") {
+			http.Error(w, "delivery failed: connection refused", http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		var create auditSinkRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.nextID++
+		id = fmt.Sprintf("s%d", b.nextID)
+		b.sinks[id] = create
+		b.respond(w, id)
+	case id != "" && r.Method == http.MethodGet:
+		if _, ok := b.sinks[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.respond(w, id)
+	case id != "" && r.Method == http.MethodPatch:
+		existing, ok := b.sinks[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var update auditSinkRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		update.Type = existing.Type
+		b.sinks[id] = update
+		b.respond(w, id)
+	case id != "" && r.Method == http.MethodDelete:
+		if _, ok := b.sinks[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.sinks, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (b *testAccAuditSinkBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.sinks) != 0 {
+		return fmt.Errorf("expected every audit sink to be deleted, %d left", len(b.sinks))
+	}
+	return nil
+}
+
+func TestAccAuditSinkResource(t *testing.T) {
+	backend := &testAccAuditSinkBackend{sinks: map[string]auditSinkRequest{}}
+	server := testBackendServer(t, backend, auditSinksPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, the sink is verified
+			{
+				Config: testAccProviderConfig(server.URL) + testAccAuditSinkResourceConfig("https://splunk.acme.io:8088/services/collector", "Splunk hec-1", `["user.login"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_audit_sink.test", "id", "s1"),
+					resourcetest.TestCheckResourceAttr("authproxy_audit_sink.test", "authorization_header", "Splunk hec-1"),
+					resourcetest.TestCheckResourceAttr("authproxy_audit_sink.test", "event_types.#", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_audit_sink.test", "enabled", "true"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if len(backend.tested) != 1 || backend.tested[0] != "s1" {
+							return fmt.Errorf("expected the sink to be tested once, got %v", backend.tested)
+						}
+						return nil
+					},
+				),
+			},
+			// The header survives a refresh although authproxy redacts it
+			{
+				RefreshState: true,
+				Check:        resourcetest.TestCheckResourceAttr("authproxy_audit_sink.test", "authorization_header", "Splunk hec-1"),
+			},
+			// ImportState testing, the header cannot be imported
+			{
+				ResourceName:            "authproxy_audit_sink.test",
+				ImportState:             true,
+				ImportStateId:           "acme/s1",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"authorization_header", "verify_on_create"},
+			},
+			// Update and Read testing, the sink is not tested again
+			{
+				Config: testAccProviderConfig(server.URL) + testAccAuditSinkResourceConfig("https://splunk.acme.io:8088/services/collector", "Splunk hec-2", `["user.login", "user.logout"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_audit_sink.test", "id", "s1"),
+					resourcetest.TestCheckResourceAttr("authproxy_audit_sink.test", "authorization_header", "Splunk hec-2"),
+					resourcetest.TestCheckResourceAttr("authproxy_audit_sink.test", "event_types.#", "2"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if header := backend.sinks["s1"].AuthorizationHeader; header != "Splunk hec-2" {
+							return fmt.Errorf("expected the header to be updated in authproxy, got %q", header)
+						}
+						if len(backend.tested) != 1 {
+							return fmt.Errorf("expected updates not to test the sink, got %v", backend.tested)
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAuditSinkResource_verificationFails(t *testing.T) {
+	backend := &testAccAuditSinkBackend{sinks: map[string]auditSinkRequest{}}
+	server := testBackendServer(t, backend, auditSinksPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// The sink that failed verification is removed again
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccAuditSinkResourceConfig("https://unreachable.example.com/collector", "Splunk hec-1", `["user.login"]`),
+				ExpectError: regexp.MustCompile(`Audit Sink Verification Failed`),
+			},
+		},
+	})
+}
+
+func testAccAuditSinkResourceConfig(endpoint string, header string, eventTypes string) string {
+	return fmt.Sprintf(`
+resource "authproxy_audit_sink" "test" {
+  tenant               = "acme"
+  type                 = "http"
+  endpoint             = %q
+  authorization_header = %q
+  event_types          = %s
+  verify_on_create     = true
+}
+`, endpoint, header, eventTypes)
+}
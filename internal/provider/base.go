@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// BaseResource holds what every resource of the provider needs to talk to
+// authproxy. Resources embed it to inherit Configure and the request helpers
+// and only implement their schema and CRUD operations:
+//
+//	type ExampleResource struct {
+//		BaseResource
+//	}
+type BaseResource struct {
+	providerData *ProviderData
+}
+
+// Configure stores the provider data handed to the resource.
+func (r *BaseResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.providerData = providerDataOf(req.ProviderData, "Resource", &resp.Diagnostics)
+}
+
+// doJSON sends an authenticated request to authproxy and decodes the JSON
+// response into out, see ProviderData.doJSON. Non 2xx responses are returned
+// as *apiError, to be checked with isStatus.
+func (r *BaseResource) doJSON(ctx context.Context, method string, path string, in interface{}, out interface{}) error {
+	return r.providerData.doJSON(ctx, method, path, in, out)
+}
+
+// doObjectJSON works like doJSON but fails unless the response describes an
+// object with the attributes named required, see ProviderData.doObjectJSON.
+func (r *BaseResource) doObjectJSON(ctx context.Context, method string, path string, in interface{}, out interface{}, required ...string) error {
+	return r.providerData.doObjectJSON(ctx, method, path, in, out, required...)
+}
+
+// checkUpdateApplied compares the attributes the update in req changes with
+// read, the model of the resource as read back from authproxy after the
+// update. Older authproxy builds accept some changes only to silently drop
+// them, which would make Terraform plan them again and again. When that
+// happened the update fails naming the ignored attributes, and read becomes
+// the state so it does not claim changes authproxy never made. It reports
+// whether every change was applied.
+func checkUpdateApplied(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, kind string, read interface{}) bool {
+	readState := tfsdk.State{Schema: req.Plan.Schema, Raw: req.Plan.Raw.Copy()}
+	resp.Diagnostics.Append(readState.Set(ctx, read)...)
+	if resp.Diagnostics.HasError() {
+		return false
+	}
+
+	names := make([]string, 0, len(req.Plan.Schema.GetAttributes()))
+	for name := range req.Plan.Schema.GetAttributes() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ignored []string
+	for _, name := range names {
+		var configured, prior, planned, actual attr.Value
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &configured)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(name), &prior)...)
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(name), &planned)...)
+		resp.Diagnostics.Append(readState.GetAttribute(ctx, path.Root(name), &actual)...)
+		if resp.Diagnostics.HasError() {
+			return false
+		}
+
+		// Attributes computed from others, such as the deprecated twin of a
+		// renamed attribute, are not changed by the update itself.
+		computed := configured.IsNull() && !planned.IsNull()
+		if computed || planned.IsUnknown() || planned.Equal(prior) || planned.Equal(actual) {
+			continue
+		}
+		if equal, diags := semanticallyEqual(ctx, planned, actual); diags.HasError() || equal {
+			resp.Diagnostics.Append(diags...)
+			continue
+		}
+		ignored = append(ignored, name)
+	}
+	if len(ignored) == 0 {
+		return true
+	}
+
+	resp.Diagnostics.AddError(
+		"Update Ignored by Authproxy",
+		fmt.Sprintf("Authproxy accepted the update of the %s but ignored the changes to %s, which it still reports unchanged. "+
+			"Older authproxy builds silently drop updates of some attributes, which Terraform would otherwise plan again on every run. "+
+			"Upgrade authproxy, or add the attributes to ignore_changes in the lifecycle block of the resource to stop managing them.",
+			kind, strings.Join(ignored, ", ")),
+	)
+	resp.State.Raw = readState.Raw
+	return false
+}
+
+// semanticallyEqual reports whether the string values planned and actual
+// are equal according to the semantic equality of their custom type, such as
+// tenant names differing in case.
+func semanticallyEqual(ctx context.Context, planned attr.Value, actual attr.Value) (bool, diag.Diagnostics) {
+	plannedValuable, ok := planned.(basetypes.StringValuableWithSemanticEquals)
+	if !ok {
+		return false, nil
+	}
+	actualValuable, ok := actual.(basetypes.StringValuable)
+	if !ok {
+		return false, nil
+	}
+	return plannedValuable.StringSemanticEquals(ctx, actualValuable)
+}
+
+// BaseDataSource is the BaseResource of data sources.
+type BaseDataSource struct {
+	providerData *ProviderData
+}
+
+// Configure stores the provider data handed to the data source.
+func (d *BaseDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.providerData = providerDataOf(req.ProviderData, "Data Source", &resp.Diagnostics)
+}
+
+// doJSON works like BaseResource.doJSON.
+func (d *BaseDataSource) doJSON(ctx context.Context, method string, path string, in interface{}, out interface{}) error {
+	return d.providerData.doJSON(ctx, method, path, in, out)
+}
+
+// providerDataOf returns the provider data passed to the Configure method of
+// a resource or data source, kind naming which in errors.
+func providerDataOf(providerData interface{}, kind string, diags *diag.Diagnostics) *ProviderData {
+	data, ok := providerData.(*ProviderData)
+	if !ok {
+		diags.AddError(
+			fmt.Sprintf("Unexpected %s Configure Type", kind),
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", providerData),
+		)
+		return nil
+	}
+
+	return data
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func TestBaseResource_configure(t *testing.T) {
+	ctx := context.Background()
+	providerData := testProviderData("http://authproxy.invalid")
+
+	r := &TenantResource{}
+	resp := &resource.ConfigureResponse{}
+	r.Configure(ctx, resource.ConfigureRequest{}, resp)
+	if resp.Diagnostics.HasError() || r.providerData != nil {
+		t.Fatalf("expected an unconfigured provider to be skipped, got %v", resp.Diagnostics)
+	}
+
+	r.Configure(ctx, resource.ConfigureRequest{ProviderData: providerData}, resp)
+	if resp.Diagnostics.HasError() || r.providerData != providerData {
+		t.Fatalf("expected the provider data to be stored, got %v", resp.Diagnostics)
+	}
+
+	r = &TenantResource{}
+	r.Configure(ctx, resource.ConfigureRequest{ProviderData: "authproxy"}, resp)
+	if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics[0].Summary() != "Unexpected Resource Configure Type" || r.providerData != nil {
+		t.Errorf("expected a configure type error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestBaseDataSource_configure(t *testing.T) {
+	ctx := context.Background()
+	providerData := testProviderData("http://authproxy.invalid")
+
+	d := &TenantDataSource{}
+	resp := &datasource.ConfigureResponse{}
+	d.Configure(ctx, datasource.ConfigureRequest{ProviderData: providerData}, resp)
+	if resp.Diagnostics.HasError() || d.providerData != providerData {
+		t.Fatalf("expected the provider data to be stored, got %v", resp.Diagnostics)
+	}
+
+	d = &TenantDataSource{}
+	d.Configure(ctx, datasource.ConfigureRequest{ProviderData: "authproxy"}, resp)
+	if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics[0].Summary() != "Unexpected Data Source Configure Type" || d.providerData != nil {
+		t.Errorf("expected a configure type error, got %v", resp.Diagnostics)
+	}
+}
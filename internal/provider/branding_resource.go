@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// brandingCustomCSSMaxBytes is the largest stylesheet authproxy accepts for
+// the login page.
+const brandingCustomCSSMaxBytes = 32 * 1024
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &BrandingResource{}
+var _ resource.ResourceWithImportState = &BrandingResource{}
+
+func NewBrandingResource() resource.Resource {
+	return &BrandingResource{}
+}
+
+// BrandingResource defines the resource implementation. Every tenant has
+// exactly one branding of its hosted login page, the resource manages it
+// rather than creating one.
+type BrandingResource struct {
+	BaseResource
+}
+
+// BrandingResourceModel describes the resource data model.
+type BrandingResourceModel struct {
+	ID           types.String    `tfsdk:"id"`
+	Tenant       tenantNameValue `tfsdk:"tenant"`
+	LogoURL      types.String    `tfsdk:"logo_url"`
+	PrimaryColor hexColorValue   `tfsdk:"primary_color"`
+	SupportURL   types.String    `tfsdk:"support_url"`
+	CustomCSS    types.String    `tfsdk:"custom_css"`
+}
+
+// brandingRequest is sent in full, empty settings fall back to the default
+// authproxy login page.
+type brandingRequest struct {
+	LogoURL      string `json:"logo_url"`
+	PrimaryColor string `json:"primary_color"`
+	SupportURL   string `json:"support_url"`
+	CustomCSS    string `json:"custom_css"`
+}
+
+type brandingResponse struct {
+	LogoURL      string `json:"logo_url"`
+	PrimaryColor string `json:"primary_color"`
+	SupportURL   string `json:"support_url"`
+	CustomCSS    string `json:"custom_css"`
+}
+
+func (r *BrandingResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_branding"
+}
+
+func (r *BrandingResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Branding of the hosted login page of a tenant. Every tenant has exactly one, destroying the resource resets it to the authproxy look",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the tenant",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant whose login page is branded. Changing it resets the branding of the previous tenant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"logo_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the logo shown above the login form, the authproxy logo when unset",
+				Optional:            true,
+			},
+			"primary_color": schema.StringAttribute{
+				CustomType:          hexColorType{},
+				MarkdownDescription: "Hex color of buttons and links such as `#1a73e8`. Authproxy stores colors in lowercase, which does not cause a diff",
+				Optional:            true,
+			},
+			"support_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the support link shown below the login form, no link when unset",
+				Optional:            true,
+			},
+			"custom_css": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Stylesheet added to the login page, at most %d bytes", brandingCustomCSSMaxBytes),
+				Optional:            true,
+				Validators: []validator.String{
+					stringMaxBytes(brandingCustomCSSMaxBytes),
+				},
+			},
+		},
+	}
+}
+
+// brandingPath returns the path of the login page branding of tenant.
+func brandingPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/branding", url.PathEscape(tenant))
+}
+
+// request builds the request body from data.
+func (data *BrandingResourceModel) request() brandingRequest {
+	return brandingRequest{
+		LogoURL:      data.LogoURL.ValueString(),
+		PrimaryColor: data.PrimaryColor.ValueString(),
+		SupportURL:   data.SupportURL.ValueString(),
+		CustomCSS:    data.CustomCSS.ValueString(),
+	}
+}
+
+// setBranding copies the settings authproxy returned into data. Settings
+// authproxy returns empty are null, they are not configured.
+func (data *BrandingResourceModel) setBranding(branding brandingResponse) {
+	optional := func(value string) types.String {
+		if value == "" {
+			return types.StringNull()
+		}
+		return types.StringValue(value)
+	}
+
+	data.ID = types.StringValue(data.Tenant.ValueString())
+	data.LogoURL = optional(branding.LogoURL)
+	data.SupportURL = optional(branding.SupportURL)
+	data.CustomCSS = optional(branding.CustomCSS)
+	data.PrimaryColor = hexColorNull()
+	if branding.PrimaryColor != "" {
+		data.PrimaryColor = hexColorStringValue(branding.PrimaryColor)
+	}
+}
+
+func (r *BrandingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *BrandingResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var branding brandingResponse
+	err := r.doJSON(ctx, "PUT", brandingPath(data.Tenant.ValueString()), data.request(), &branding)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "set branding", err)
+		return
+	}
+
+	data.setBranding(branding)
+
+	tflog.Trace(ctx, "created a branding resource", map[string]interface{}{
+		"tenant": data.Tenant.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BrandingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *BrandingResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var branding brandingResponse
+	err := r.doJSON(ctx, "GET", brandingPath(data.Tenant.ValueString()), nil, &branding)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant no longer exists, removing its branding from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read branding", err)
+		return
+	}
+
+	data.setBranding(branding)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BrandingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *BrandingResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var branding brandingResponse
+	err := r.doJSON(ctx, "PUT", brandingPath(data.Tenant.ValueString()), data.request(), &branding)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update branding", err)
+		return
+	}
+
+	data.setBranding(branding)
+
+	tflog.Trace(ctx, "updated a branding resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BrandingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *BrandingResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting the branding resets the login page to the authproxy look.
+	err := r.doJSON(ctx, "DELETE", brandingPath(data.Tenant.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "reset branding", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a branding resource")
+}
+
+func (r *BrandingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("tenant"), req, resp)
+}
@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccBrandingBackend is an in-memory stand-in for the branding endpoint
+// of the "acme" tenant. Like authproxy it stores colors in long lowercase
+// form.
+type testAccBrandingBackend struct {
+	mu       sync.Mutex
+	branding brandingResponse
+}
+
+func (b *testAccBrandingBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.URL.Path != brandingPath("acme") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var update brandingRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.branding = brandingResponse(update)
+		if update.PrimaryColor != "" {
+			b.branding.PrimaryColor = normalizeHexColor(update.PrimaryColor)
+		}
+	case http.MethodDelete:
+		b.branding = brandingResponse{}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(b.branding)
+}
+
+func TestAccBrandingResource(t *testing.T) {
+	backend := &testAccBrandingBackend{}
+	server := testBackendServer(t, backend, brandingPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if backend.branding != (brandingResponse{}) {
+				return fmt.Errorf("expected the branding to be reset, got %+v", backend.branding)
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, the normalized color is kept as
+			// configured
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_branding" "test" {
+  tenant        = "acme"
+  logo_url      = "https://cdn.acme.io/logo.svg"
+  primary_color = "#1A73E8"
+  support_url   = "https://acme.io/support"
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_branding.test", "id", "acme"),
+					resourcetest.TestCheckResourceAttr("authproxy_branding.test", "primary_color", "#1A73E8"),
+					resourcetest.TestCheckNoResourceAttr("authproxy_branding.test", "custom_css"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if backend.branding.PrimaryColor != "#1a73e8" {
+							return fmt.Errorf("expected authproxy to store the normalized color, got %q", backend.branding.PrimaryColor)
+						}
+						return nil
+					},
+				),
+			},
+			// Refreshing keeps the configured color as authproxy's
+			// normalization of it is semantically equal
+			{
+				RefreshState: true,
+				Check:        resourcetest.TestCheckResourceAttr("authproxy_branding.test", "primary_color", "#1A73E8"),
+			},
+			// ImportState testing, the imported color is authproxy's
+			// normalization
+			{
+				ResourceName:            "authproxy_branding.test",
+				ImportState:             true,
+				ImportStateId:           "acme",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"primary_color"},
+			},
+			// Update and Read testing, the short form of the color is
+			// semantically equal as well
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_branding" "test" {
+  tenant        = "acme"
+  primary_color = "#F0A"
+  custom_css    = ".login-form { border-radius: 8px; }"
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_branding.test", "primary_color", "#F0A"),
+					resourcetest.TestCheckResourceAttr("authproxy_branding.test", "custom_css", ".login-form { border-radius: 8px; }"),
+					resourcetest.TestCheckNoResourceAttr("authproxy_branding.test", "logo_url"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if backend.branding.PrimaryColor != "#ff00aa" || backend.branding.LogoURL != "" {
+							return fmt.Errorf("expected the branding to be updated, got %+v", backend.branding)
+						}
+						return nil
+					},
+				),
+			},
+			// A color changed outside of Terraform is detected
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					backend.branding.PrimaryColor = "#000000"
+				},
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_branding" "test" {
+  tenant        = "acme"
+  primary_color = "#F0A"
+  custom_css    = ".login-form { border-radius: 8px; }"
+}
+`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccBrandingResource_invalid(t *testing.T) {
+	for name, c := range map[string]struct {
+		attributes string
+		err        string
+	}{
+		"color": {
+			attributes: `primary_color = "blue"`,
+			err:        `must be a hex color`,
+		},
+		"custom_css": {
+			attributes: fmt.Sprintf(`custom_css = %q`, strings.Repeat("a", brandingCustomCSSMaxBytes+1)),
+			err:        `must be at most 32768 bytes long, got: 32769 bytes`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resourcetest.Test(t, resourcetest.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resourcetest.TestStep{
+					{
+						Config: testAccProviderConfig("http://127.0.0.1:1") + fmt.Sprintf(`
+resource "authproxy_branding" "test" {
+  tenant = "acme"
+  %s
+}
+`, c.attributes),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(c.err),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestHexColorValue_semanticEquals(t *testing.T) {
+	cases := map[string]struct {
+		prior    string
+		proposed string
+		equal    bool
+	}{
+		"identical":  {"#1a73e8", "#1a73e8", true},
+		"case":       {"#1A73E8", "#1a73e8", true},
+		"short form": {"#F0A", "#ff00aa", true},
+		"color":      {"#1a73e8", "#1a73e9", false},
+		"invalid":    {"blue", "blue", false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			equal, diags := hexColorStringValue(c.prior).StringSemanticEquals(context.Background(), hexColorStringValue(c.proposed))
+			if diags.HasError() {
+				t.Fatalf("unexpected error diagnostics: %v", diags)
+			}
+			if equal != c.equal {
+				t.Errorf("expected semantic equality %t, got %t", c.equal, equal)
+			}
+		})
+	}
+}
+
+func TestHexColorType_validate(t *testing.T) {
+	for color, valid := range map[string]bool{
+		"#1a73e8":  true,
+		"#1A73E8":  true,
+		"#fff":     true,
+		"1a73e8":   false,
+		"#1a73e":   false,
+		"#1a73e8f": false,
+		"#ggg":     false,
+	} {
+		diags := hexColorType{}.Validate(context.Background(), tftypes.NewValue(tftypes.String, color), path.Root("primary_color"))
+		if diags.HasError() == valid {
+			t.Errorf("expected %q to be valid=%t, got diagnostics: %v", color, valid, diags)
+		}
+	}
+}
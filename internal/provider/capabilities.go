@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// capabilities caches the feature flags advertised by the authproxy health
+// endpoint so optional endpoints are probed at most once per provider
+// instance.
+type capabilities struct {
+	mu       sync.Mutex
+	probed   bool
+	features map[string]bool
+}
+
+// supports reports whether the server advertises feature. Servers that do not
+// advertise features at all, such as legacy releases answering the health
+// endpoint in plain text, support no optional features.
+func (p *ProviderData) supports(ctx context.Context, feature string) (bool, error) {
+	p.capabilities.mu.Lock()
+	defer p.capabilities.mu.Unlock()
+
+	if p.capabilities.probed {
+		p.stats.addCacheHit()
+	} else {
+		resBody, err := p.do(ctx, "GET", "/health", nil)
+		if err != nil {
+			return false, err
+		}
+
+		var info serverInfoResponse
+		if err := json.Unmarshal(resBody, &info); err != nil {
+			tflog.Debug(ctx, "health endpoint did not return JSON, assuming no optional features")
+		}
+		if info.Version != nil {
+			// Servers too old to report their version in a header
+			// still report it here.
+			p.checkServerVersion(ctx, *info.Version)
+		}
+		p.capabilities.features = info.Features
+		p.capabilities.probed = true
+	}
+
+	return p.capabilities.features[feature], nil
+}
@@ -0,0 +1,325 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CertificateResource{}
+var _ resource.ResourceWithImportState = &CertificateResource{}
+var _ resource.ResourceWithModifyPlan = &CertificateResource{}
+
+// certificateExpiryWarning is how long before its expiry a configured
+// certificate is warned about.
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+func NewCertificateResource() resource.Resource {
+	return &CertificateResource{}
+}
+
+// CertificateResource defines the resource implementation.
+type CertificateResource struct {
+	BaseResource
+}
+
+// CertificateResourceModel describes the resource data model.
+type CertificateResourceModel struct {
+	ID             types.String    `tfsdk:"id"`
+	Tenant         tenantNameValue `tfsdk:"tenant"`
+	Usage          types.String    `tfsdk:"usage"`
+	CertificatePEM types.String    `tfsdk:"certificate_pem"`
+	Fingerprint    types.String    `tfsdk:"fingerprint"`
+	NotAfter       types.String    `tfsdk:"not_after"`
+}
+
+type certificateCreateRequest struct {
+	Usage          string `json:"usage"`
+	CertificatePEM string `json:"certificate_pem"`
+}
+
+type certificateResponse struct {
+	ID             string `json:"id"`
+	Usage          string `json:"usage"`
+	CertificatePEM string `json:"certificate_pem"`
+	Fingerprint    string `json:"fingerprint"`
+	NotAfter       string `json:"not_after"`
+}
+
+// parseCertificatePEM parses the first PEM block of data as an X.509
+// certificate.
+func parseCertificatePEM(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("expected a CERTIFICATE PEM block, got %s", block.Type)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// certificateFingerprint returns the SHA-256 fingerprint of certificate in
+// the lowercase hex form authproxy uses.
+func certificateFingerprint(certificate *x509.Certificate) string {
+	sum := sha256.Sum256(certificate.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeFingerprint brings fingerprints written with colons or in
+// uppercase into the form returned by certificateFingerprint.
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+}
+
+func (r *CertificateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate"
+}
+
+func (r *CertificateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Certificate uploaded to a tenant for SAML signing or mTLS trust. Certificates are never updated in place, " +
+			"use `create_before_destroy` to upload the new certificate before the old one is deleted",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the certificate is uploaded to. Changing it replaces the certificate",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"usage": schema.StringAttribute{
+				MarkdownDescription: "What the certificate is used for, one of `saml_signing` and `mtls_trust`. Changing it replaces the certificate",
+				Required:            true,
+				Validators: []validator.String{
+					stringOneOf("saml_signing", "mtls_trust"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"certificate_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded X.509 certificate. Changing it replaces the certificate",
+				Required:            true,
+				Validators: []validator.String{
+					certificatePEM(certificateExpiryWarning),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"fingerprint": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 fingerprint of the certificate as lowercase hex. A certificate re-uploaded outside of Terraform is detected by it",
+				Computed:            true,
+			},
+			"not_after": schema.StringAttribute{
+				MarkdownDescription: "RFC 3339 timestamp the certificate expires at",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// ModifyPlan derives fingerprint and not_after from the configured
+// certificate. When the fingerprint in state differs, the certificate was
+// replaced outside of Terraform and is uploaded again.
+func (r *CertificateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan *CertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.CertificatePEM.IsUnknown() {
+		return
+	}
+
+	certificate, err := parseCertificatePEM(plan.CertificatePEM.ValueString())
+	if err != nil {
+		// Reported by the certificate_pem validator.
+		return
+	}
+	plan.Fingerprint = types.StringValue(certificateFingerprint(certificate))
+	plan.NotAfter = types.StringValue(certificate.NotAfter.UTC().Format(time.RFC3339))
+
+	if !req.State.Raw.IsNull() {
+		var state *CertificateResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if sameInstant(state.NotAfter.ValueString(), plan.NotAfter.ValueString()) {
+			plan.NotAfter = state.NotAfter
+		}
+		if state.Fingerprint.ValueString() != plan.Fingerprint.ValueString() {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("fingerprint"))
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// certificatesPath returns the path of the certificates collection of tenant.
+func certificatesPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/certificates", url.PathEscape(tenant))
+}
+
+// certificatePath returns the path of the certificate of tenant with the
+// given id.
+func certificatePath(tenant string, id string) string {
+	return certificatesPath(tenant) + "/" + url.PathEscape(id)
+}
+
+// setCertificate copies the attributes authproxy returned for a certificate
+// into data. The configured PEM is kept, authproxy may format it differently.
+func (data *CertificateResourceModel) setCertificate(certificate certificateResponse) {
+	data.ID = types.StringValue(certificate.ID)
+	data.Usage = types.StringValue(certificate.Usage)
+	if data.CertificatePEM.IsNull() {
+		data.CertificatePEM = types.StringValue(certificate.CertificatePEM)
+	}
+	data.Fingerprint = types.StringValue(normalizeFingerprint(certificate.Fingerprint))
+	if !sameInstant(data.NotAfter.ValueString(), certificate.NotAfter) {
+		data.NotAfter = types.StringValue(certificate.NotAfter)
+	}
+}
+
+func (r *CertificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *CertificateResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var certificate certificateResponse
+	err := r.doJSON(ctx, "POST", certificatesPath(data.Tenant.ValueString()), certificateCreateRequest{
+		Usage:          data.Usage.ValueString(),
+		CertificatePEM: data.CertificatePEM.ValueString(),
+	}, &certificate)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "upload certificate", err)
+		return
+	}
+
+	data.setCertificate(certificate)
+
+	tflog.Trace(ctx, "created a certificate resource", map[string]interface{}{
+		"id":          certificate.ID,
+		"fingerprint": data.Fingerprint.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CertificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CertificateResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var certificate certificateResponse
+	err := r.doJSON(ctx, "GET", certificatePath(data.Tenant.ValueString(), data.ID.ValueString()), nil, &certificate)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "certificate no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"id":     data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read certificate", err)
+		return
+	}
+
+	if fingerprint := normalizeFingerprint(certificate.Fingerprint); !data.Fingerprint.IsNull() && fingerprint != data.Fingerprint.ValueString() {
+		tflog.Warn(ctx, "certificate was replaced outside of Terraform", map[string]interface{}{
+			"id":          data.ID.ValueString(),
+			"fingerprint": fingerprint,
+		})
+	}
+
+	data.setCertificate(certificate)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CertificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.AddError(
+		"Unexpected Update",
+		"Certificates cannot be updated in place. Please report this issue to the provider developers.",
+	)
+}
+
+func (r *CertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *CertificateResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", certificatePath(data.Tenant.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete certificate", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a certificate resource")
+}
+
+func (r *CertificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, id, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// chaosSeed replays the faults of a failed chaos test, pass the seed it
+// logged.
+var chaosSeed = flag.Int64("chaos-seed", 0, "seed of the testserver chaos mode, random when 0")
+
+// testChaosAttempts is how often the provider tries a request in chaos tests,
+// enough for requests failing at random to eventually succeed.
+const testChaosAttempts = 10
+
+// testChaosProviderData returns provider data pointing at endpoint that
+// retries like the configured provider, only without waiting long.
+func testChaosProviderData(endpoint string) *ProviderData {
+	providerData := testProviderData(endpoint)
+	providerData.client = &http.Client{
+		Timeout: time.Minute,
+		Transport: &retryTransport{
+			next:     http.DefaultTransport,
+			attempts: testChaosAttempts,
+			backoff:  time.Millisecond,
+			maxWait:  10 * time.Millisecond,
+		},
+	}
+	return providerData
+}
+
+func TestChaos_converges(t *testing.T) {
+	for name, chaos := range map[string]testserver.Chaos{
+		"throttled": {
+			Rate:   0.3,
+			Faults: []testserver.Fault{testserver.FaultTooManyRequests, testserver.FaultSlow},
+		},
+		"flaky reads and deletes": {
+			Rate:    0.3,
+			Faults:  []testserver.Fault{testserver.FaultInternalError, testserver.FaultConnectionReset},
+			Methods: []string{http.MethodGet, http.MethodDelete},
+		},
+	} {
+		chaos := chaos
+		t.Run(name, func(t *testing.T) {
+			server := testserver.New(t)
+			server.CreateTenant("acme")
+			chaos.Seed = *chaosSeed
+			server.SetChaos(chaos)
+			providerData := testChaosProviderData(server.URL)
+
+			// Resources are applied one after another, so the faults only
+			// depend on the seed.
+			for i := 0; i < 4; i++ {
+				if err := testLifecycle(t, NewTenantResource, providerData, map[string]tftypes.Value{
+					"id":   tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+					"name": tftypes.NewValue(tftypes.String, fmt.Sprintf("tenant-%d", i)),
+				}, "name", fmt.Sprintf("tenant-%d-renamed", i)); err != nil {
+					t.Errorf("tenant %d: %s", i, err)
+				}
+				if err := testLifecycle(t, NewRoleResource, providerData, map[string]tftypes.Value{
+					"id":     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+					"tenant": tftypes.NewValue(tftypes.String, "acme"),
+					"name":   tftypes.NewValue(tftypes.String, fmt.Sprintf("role-%d", i)),
+					"scopes": stringList("billing:read"),
+				}, "name", fmt.Sprintf("role-%d-renamed", i)); err != nil {
+					t.Errorf("role %d: %s", i, err)
+				}
+			}
+
+			if server.InjectedFaults() == 0 {
+				t.Error("expected chaos mode to inject faults")
+			}
+			if tenants := server.Tenants(); len(tenants) != 1 || tenants[0].Name != "acme" {
+				t.Errorf("expected only acme to be left, got %v", tenants)
+			}
+			if roles := server.Roles("acme"); len(roles) != 0 {
+				t.Errorf("expected every role to be deleted, got %v", roles)
+			}
+		})
+	}
+}
+
+func TestChaos_failsCleanly(t *testing.T) {
+	for name, tc := range map[string]struct {
+		chaos    testserver.Chaos
+		expected string
+		faults   int
+	}{
+		"failing writes": {
+			chaos:    testserver.Chaos{Rate: 1, Faults: []testserver.Fault{testserver.FaultInternalError}, Methods: []string{http.MethodPost, http.MethodPatch}},
+			expected: "status 500",
+			faults:   4,
+		},
+		"reset writes": {
+			chaos:    testserver.Chaos{Rate: 1, Faults: []testserver.Fault{testserver.FaultConnectionReset}, Methods: []string{http.MethodPost, http.MethodPatch}},
+			expected: "Connection Error",
+			faults:   4,
+		},
+		"persistent throttling": {
+			chaos:    testserver.Chaos{Rate: 1, Faults: []testserver.Fault{testserver.FaultTooManyRequests}, Methods: []string{http.MethodPost, http.MethodPatch}},
+			expected: "status 429",
+			faults:   4 * testChaosAttempts,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			server := testserver.New(t)
+			tenant := server.CreateTenant("acme")
+			role := server.PutRole("acme", testserver.Role{Name: "viewer", Scopes: []string{"billing:read"}})
+			tc.chaos.Seed = *chaosSeed
+			server.SetChaos(tc.chaos)
+			providerData := testChaosProviderData(server.URL)
+
+			str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+			unknown := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+			tenantState := map[string]tftypes.Value{"id": str(tenant.ID), "name": str("acme"), "on_destroy": str("delete"), "wait_for_ready": tftypes.NewValue(tftypes.Bool, true), "status": str("active")}
+			roleState := map[string]tftypes.Value{"id": str(role.ID), "tenant": str("acme"), "name": str("viewer"), "scopes": stringList("billing:read"), "permissions": stringList("billing:read"), "on_destroy": str("delete")}
+
+			for _, op := range []struct {
+				name      string
+				resource  func() resource.Resource
+				operation string
+				prior     map[string]tftypes.Value
+				planned   map[string]tftypes.Value
+			}{
+				{name: "tenant create", resource: NewTenantResource, operation: "create", planned: map[string]tftypes.Value{"id": unknown, "name": str("lidl")}},
+				{name: "tenant update", resource: NewTenantResource, operation: "update", prior: tenantState, planned: map[string]tftypes.Value{"id": str(tenant.ID), "name": str("acme-eu")}},
+				{name: "role create", resource: NewRoleResource, operation: "create", planned: map[string]tftypes.Value{"id": unknown, "tenant": str("acme"), "name": str("editor"), "scopes": stringList("billing:write"), "permissions": stringList("billing:write")}},
+				{name: "role update", resource: NewRoleResource, operation: "update", prior: roleState, planned: map[string]tftypes.Value{"id": str(role.ID), "tenant": str("acme"), "name": str("editor"), "scopes": stringList("billing:read"), "permissions": stringList("billing:read")}},
+			} {
+				state, diags := contractOperation(t, op.resource(), providerData, op.operation, op.prior, op.planned)
+				if !diags.HasError() {
+					t.Errorf("%s: expected to fail", op.name)
+					continue
+				}
+				if !strings.Contains(fmt.Sprint(diags), tc.expected) {
+					t.Errorf("%s: expected the error to mention %q, got %v", op.name, tc.expected, diags)
+				}
+
+				// Failed operations must leave the state as it was: nothing
+				// is tracked for failed creates and updates keep the prior
+				// state.
+				expected := tftypes.NewValue(state.Type(), nil)
+				if op.prior != nil {
+					expected, _ = contractOperation(t, op.resource(), testProviderData(server.URL), "read", op.prior, nil)
+				}
+				if !state.Equal(expected) {
+					t.Errorf("%s: expected the state %s, got %s", op.name, expected, state)
+				}
+			}
+
+			if faults := server.InjectedFaults(); faults != tc.faults {
+				t.Errorf("expected %d faults, got %d", tc.faults, faults)
+			}
+			if tenants := server.Tenants(); len(tenants) != 1 || tenants[0] != tenant {
+				t.Errorf("expected only acme to be left unchanged, got %v", tenants)
+			}
+			if roles := server.Roles("acme"); len(roles) != 1 || roles[0].Name != "viewer" || roles[0].ID != role.ID {
+				t.Errorf("expected only viewer to be left unchanged, got %v", roles)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ClaimMappingResource{}
+var _ resource.ResourceWithImportState = &ClaimMappingResource{}
+
+func NewClaimMappingResource() resource.Resource {
+	return &ClaimMappingResource{}
+}
+
+// ClaimMappingResource defines the resource implementation.
+type ClaimMappingResource struct {
+	BaseResource
+}
+
+// ClaimMappingResourceModel describes the resource data model.
+type ClaimMappingResourceModel struct {
+	ID          types.String    `tfsdk:"id"`
+	Tenant      tenantNameValue `tfsdk:"tenant"`
+	IdPID       types.String    `tfsdk:"idp_id"`
+	Claim       types.String    `tfsdk:"claim"`
+	MatchValue  types.String    `tfsdk:"match_value"`
+	MappedRoles types.Set       `tfsdk:"mapped_roles"`
+}
+
+type claimMappingRequest struct {
+	Claim       string   `json:"claim"`
+	MatchValue  string   `json:"match_value"`
+	MappedRoles []string `json:"mapped_roles"`
+}
+
+type claimMappingResponse struct {
+	ID          string   `json:"id"`
+	Claim       string   `json:"claim"`
+	MatchValue  string   `json:"match_value"`
+	MappedRoles []string `json:"mapped_roles"`
+}
+
+// claimMappingUnprocessableResponse is the body authproxy answers with when a
+// mapping refers to roles that do not exist.
+type claimMappingUnprocessableResponse struct {
+	MissingRoles []string `json:"missing_roles"`
+}
+
+func (r *ClaimMappingResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claim_mapping"
+}
+
+func (r *ClaimMappingResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Grants roles to users logging in through an identity provider whose claim carries a given value",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the identity provider belongs to. Changing it recreates the mapping",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"idp_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the identity provider whose claims are mapped. Changing it recreates the mapping",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"claim": schema.StringAttribute{
+				MarkdownDescription: "Name of the claim to match, such as `groups`. Claims holding a list match when any of their values does",
+				Required:            true,
+			},
+			"match_value": schema.StringAttribute{
+				MarkdownDescription: "Value the claim has to carry for the roles to be granted",
+				Required:            true,
+			},
+			"mapped_roles": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names of the roles of the tenant granted to matching users",
+				Required:            true,
+			},
+		},
+	}
+}
+
+// claimMappingsPath returns the path of the claim mappings collection of the
+// identity provider of tenant with the given id.
+func claimMappingsPath(tenant string, idpID string) string {
+	return identityProviderPath(tenant, idpID) + "/mappings"
+}
+
+// claimMappingPath returns the path of the claim mapping with the given id.
+func claimMappingPath(tenant string, idpID string, id string) string {
+	return claimMappingsPath(tenant, idpID) + "/" + url.PathEscape(id)
+}
+
+// request builds the create and update request body from data.
+func (data *ClaimMappingResourceModel) request(ctx context.Context) (claimMappingRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	mapping := claimMappingRequest{
+		Claim:       data.Claim.ValueString(),
+		MatchValue:  data.MatchValue.ValueString(),
+		MappedRoles: []string{},
+	}
+	diags.Append(data.MappedRoles.ElementsAs(ctx, &mapping.MappedRoles, false)...)
+	sort.Strings(mapping.MappedRoles)
+
+	return mapping, diags
+}
+
+// setClaimMapping copies the attributes authproxy returned for a claim
+// mapping into data.
+func (data *ClaimMappingResourceModel) setClaimMapping(ctx context.Context, mapping claimMappingResponse) diag.Diagnostics {
+	data.ID = types.StringValue(mapping.ID)
+	data.Claim = types.StringValue(mapping.Claim)
+	data.MatchValue = types.StringValue(mapping.MatchValue)
+
+	if mapping.MappedRoles == nil {
+		mapping.MappedRoles = []string{}
+	}
+	mappedRoles, diags := types.SetValueFrom(ctx, types.StringType, mapping.MappedRoles)
+	data.MappedRoles = mappedRoles
+
+	return diags
+}
+
+// addMissingRolesError reports a request authproxy rejected because mapped
+// roles do not exist as an error on mapped_roles. It returns false for any
+// other error.
+func (data *ClaimMappingResourceModel) addMissingRolesError(diags *diag.Diagnostics, err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+
+	var unprocessable claimMappingUnprocessableResponse
+	if json.Unmarshal([]byte(apiErr.Body), &unprocessable) != nil || len(unprocessable.MissingRoles) == 0 {
+		return false
+	}
+
+	sort.Strings(unprocessable.MissingRoles)
+	detail := fmt.Sprintf("No role named %q exists in tenant %q.", unprocessable.MissingRoles[0], data.Tenant.ValueString())
+	if len(unprocessable.MissingRoles) > 1 {
+		detail = fmt.Sprintf("The roles %s do not exist in tenant %q.", strings.Join(unprocessable.MissingRoles, ", "), data.Tenant.ValueString())
+	}
+	diags.AddAttributeError(path.Root("mapped_roles"), "Role Not Found", detail)
+	return true
+}
+
+func (r *ClaimMappingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *ClaimMappingResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mapping claimMappingResponse
+	err := r.doJSON(ctx, "POST", claimMappingsPath(data.Tenant.ValueString(), data.IdPID.ValueString()), body, &mapping)
+	if data.addMissingRolesError(&resp.Diagnostics, err) {
+		return
+	}
+	if isStatus(err, http.StatusNotFound) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("idp_id"),
+			"Identity Provider Not Found",
+			fmt.Sprintf("No identity provider with the id %q exists in tenant %q.", data.IdPID.ValueString(), data.Tenant.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create claim mapping", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setClaimMapping(ctx, mapping)...)
+
+	tflog.Trace(ctx, "created a claim mapping resource", map[string]interface{}{
+		"id": mapping.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ClaimMappingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ClaimMappingResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mapping claimMappingResponse
+	err := r.doJSON(ctx, "GET", claimMappingPath(data.Tenant.ValueString(), data.IdPID.ValueString(), data.ID.ValueString()), nil, &mapping)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "claim mapping no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"idp_id": data.IdPID.ValueString(),
+			"id":     data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read claim mapping", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setClaimMapping(ctx, mapping)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ClaimMappingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *ClaimMappingResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mapping claimMappingResponse
+	err := r.doJSON(ctx, "PATCH", claimMappingPath(data.Tenant.ValueString(), data.IdPID.ValueString(), data.ID.ValueString()), body, &mapping)
+	if data.addMissingRolesError(&resp.Diagnostics, err) {
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update claim mapping", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setClaimMapping(ctx, mapping)...)
+
+	tflog.Trace(ctx, "updated a claim mapping resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ClaimMappingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *ClaimMappingResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", claimMappingPath(data.Tenant.ValueString(), data.IdPID.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete claim mapping", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a claim mapping resource")
+}
+
+func (r *ClaimMappingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/idp_id/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("idp_id"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[2])...)
+}
@@ -0,0 +1,197 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccClaimMappingBackend is an in-memory stand-in for the claim mapping
+// endpoints of the identity provider "idp1" of the "acme" tenant. Mappings
+// may only refer to the roles in roles.
+type testAccClaimMappingBackend struct {
+	mu       sync.Mutex
+	roles    map[string]bool
+	mappings map[string]claimMappingResponse
+	nextID   int
+}
+
+// missingRoles returns the roles of mapping that do not exist.
+func (b *testAccClaimMappingBackend) missingRoles(mapping claimMappingRequest) []string {
+	var missing []string
+	for _, role := range mapping.MappedRoles {
+		if !b.roles[role] {
+			missing = append(missing, role)
+		}
+	}
+	return missing
+}
+
+func (b *testAccClaimMappingBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prefix := claimMappingsPath("acme", "idp1")
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	var body claimMappingRequest
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if missing := b.missingRoles(body); len(missing) > 0 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_ = json.NewEncoder(w).Encode(claimMappingUnprocessableResponse{MissingRoles: missing})
+			return
+		}
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		b.nextID++
+		id = fmt.Sprintf("m%d", b.nextID)
+		b.mappings[id] = claimMappingResponse{ID: id, Claim: body.Claim, MatchValue: body.MatchValue, MappedRoles: body.MappedRoles}
+		_ = json.NewEncoder(w).Encode(b.mappings[id])
+	case id != "" && r.Method == http.MethodGet:
+		mapping, ok := b.mappings[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(mapping)
+	case id != "" && r.Method == http.MethodPatch:
+		if _, ok := b.mappings[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.mappings[id] = claimMappingResponse{ID: id, Claim: body.Claim, MatchValue: body.MatchValue, MappedRoles: body.MappedRoles}
+		_ = json.NewEncoder(w).Encode(b.mappings[id])
+	case id != "" && r.Method == http.MethodDelete:
+		if _, ok := b.mappings[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.mappings, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (b *testAccClaimMappingBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.mappings) != 0 {
+		return fmt.Errorf("expected every claim mapping to be deleted, %d left", len(b.mappings))
+	}
+	return nil
+}
+
+func newTestAccClaimMappingBackend() *testAccClaimMappingBackend {
+	return &testAccClaimMappingBackend{
+		roles:    map[string]bool{"admin": true, "auditor": true, "developer": true},
+		mappings: map[string]claimMappingResponse{},
+	}
+}
+
+func TestAccClaimMappingResource(t *testing.T) {
+	backend := newTestAccClaimMappingBackend()
+	server := testBackendServer(t, backend, claimMappingsPath("acme", "idp1"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccClaimMappingResourceConfig("platform-admins", `["admin", "auditor"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_claim_mapping.test", "id", "m1"),
+					resourcetest.TestCheckResourceAttr("authproxy_claim_mapping.test", "claim", "groups"),
+					resourcetest.TestCheckResourceAttr("authproxy_claim_mapping.test", "match_value", "platform-admins"),
+					resourcetest.TestCheckResourceAttr("authproxy_claim_mapping.test", "mapped_roles.#", "2"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_claim_mapping.test", "mapped_roles.*", "admin"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_claim_mapping.test", "mapped_roles.*", "auditor"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_claim_mapping.test",
+				ImportState:       true,
+				ImportStateId:     "acme/idp1/m1",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccClaimMappingResourceConfig("engineering", `["auditor", "developer", "admin"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_claim_mapping.test", "id", "m1"),
+					resourcetest.TestCheckResourceAttr("authproxy_claim_mapping.test", "match_value", "engineering"),
+					resourcetest.TestCheckResourceAttr("authproxy_claim_mapping.test", "mapped_roles.#", "3"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						roles := append([]string(nil), backend.mappings["m1"].MappedRoles...)
+						sort.Strings(roles)
+						if strings.Join(roles, ",") != "admin,auditor,developer" {
+							return fmt.Errorf("expected all three roles to be mapped in authproxy, got %v", roles)
+						}
+						return nil
+					},
+				),
+			},
+			// Roles that do not exist are reported on mapped_roles
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccClaimMappingResourceConfig("engineering", `["developer", "release-manager"]`),
+				ExpectError: regexp.MustCompile(`No role named "release-manager" exists in tenant "acme"`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccClaimMappingResource_missingRoles(t *testing.T) {
+	backend := newTestAccClaimMappingBackend()
+	server := testBackendServer(t, backend, claimMappingsPath("acme", "idp1"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccClaimMappingResourceConfig("platform-admins", `["admin", "owner", "billing"]`),
+				ExpectError: regexp.MustCompile(`The roles billing, owner do not exist in tenant "acme"`),
+			},
+		},
+	})
+}
+
+func testAccClaimMappingResourceConfig(matchValue string, mappedRoles string) string {
+	return fmt.Sprintf(`
+resource "authproxy_claim_mapping" "test" {
+  tenant       = "acme"
+  idp_id       = "idp1"
+  claim        = "groups"
+  match_value  = %q
+  mapped_roles = %s
+}
+`, matchValue, mappedRoles)
+}
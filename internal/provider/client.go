@@ -0,0 +1,664 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+)
+
+// maxResponseSize bounds the response bodies read from authproxy so a
+// misbehaving server cannot make the provider allocate without limit.
+const maxResponseSize = 16 << 20
+
+// readDeduplicationKey marks contexts whose GET requests may be shared with
+// identical concurrent requests.
+type readDeduplicationKey struct{}
+
+// withReadDeduplication returns a context under which identical concurrent
+// GET requests share a single round trip. Data sources use it for their reads
+// so that many modules reading the same object only hit authproxy once.
+func withReadDeduplication(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readDeduplicationKey{}, true)
+}
+
+// requestHeaderKey carries additional headers for the requests made under a
+// context.
+type requestHeaderKey struct{}
+
+// withRequestHeader returns a context under which requests carry the given
+// header in addition to the ones set by the request helpers.
+func withRequestHeader(ctx context.Context, name string, value string) context.Context {
+	header := http.Header{}
+	if existing, ok := ctx.Value(requestHeaderKey{}).(http.Header); ok {
+		header = existing.Clone()
+	}
+	header.Set(name, value)
+	return context.WithValue(ctx, requestHeaderKey{}, header)
+}
+
+// operationHeader and providerVersionHeader tell authproxy which Terraform
+// operation and provider version made a request, see withOperation.
+const (
+	operationHeader       = "X-Terraform-Operation"
+	providerVersionHeader = "X-Terraform-Provider-Version"
+)
+
+// requestIDHeader is the response header authproxy identifies requests with
+// in its own logs.
+const requestIDHeader = "X-Request-Id"
+
+// attemptsKey carries the *requestAttempts of the request made under a
+// context, which retryTransport updates.
+type attemptsKey struct{}
+
+// requestAttempts counts the attempts of a single request.
+type requestAttempts struct {
+	count int
+	// rateLimited counts the attempts answered with 429 that were retried.
+	rateLimited int
+}
+
+// detachedContext carries the values of a context, such as its logger, but
+// neither its deadline nor its cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// detached returns a context with the values of ctx that is bounded by the
+// request timeout instead of the deadline and cancellation of ctx, for
+// requests that must not be cut short by the operation that started them.
+func (p *ProviderData) detached(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.requestTimeout <= 0 {
+		return context.WithCancel(detachedContext{ctx})
+	}
+	return context.WithTimeout(detachedContext{ctx}, p.requestTimeout)
+}
+
+// readGroup deduplicates identical concurrent GET requests of a provider
+// instance, keyed by method, path, operation label and additional headers.
+type readGroup struct {
+	group singleflight.Group
+}
+
+// apiError is returned by the request helpers whenever authproxy answers
+// with a non 2xx status code.
+type apiError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// invalidObjectError is returned by doObjectJSON for successful responses
+// that do not describe an object, which happens when the endpoint points at
+// something else than the authproxy API, such as the login page of a proxy.
+type invalidObjectError struct {
+	Method  string
+	Path    string
+	Problem string
+	Body    string
+}
+
+func (e *invalidObjectError) Error() string {
+	return fmt.Sprintf("%s %s returned a response that %s: %s", e.Method, e.Path, e.Problem, e.Body)
+}
+
+// maxBodyExcerpt bounds the part of a response body quoted in errors.
+const maxBodyExcerpt = 200
+
+// bodyExcerpt quotes the start of body for errors.
+func bodyExcerpt(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) > maxBodyExcerpt {
+		return fmt.Sprintf("%q...", body[:maxBodyExcerpt])
+	}
+	return fmt.Sprintf("%q", body)
+}
+
+// isStatus reports whether err is an *apiError carrying the given status code.
+func isStatus(err error, code int) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
+
+// response is the part of an authproxy response the request helpers hand
+// back to callers.
+type response struct {
+	Header http.Header
+	Body   []byte
+}
+
+// do sends an authenticated request to the authproxy API and returns the raw
+// response body. in is marshalled as the JSON request body unless it is nil.
+func (p *ProviderData) do(ctx context.Context, method string, path string, in interface{}) ([]byte, error) {
+	res, err := p.roundTrip(ctx, method, path, in)
+	if res == nil {
+		return nil, err
+	}
+	return res.Body, err
+}
+
+// roundTrip works like do but also returns the response headers.
+func (p *ProviderData) roundTrip(ctx context.Context, method string, path string, in interface{}) (*response, error) {
+	if method != "GET" || ctx.Value(readDeduplicationKey{}) == nil {
+		return p.send(ctx, method, path, in)
+	}
+
+	// The request carries the operation label and the additional headers of
+	// the caller that starts it, so only callers agreeing on them share it.
+	key := method + " " + path
+	if operation, ok := ctx.Value(operationKey{}).(string); ok {
+		key += " " + operation
+	}
+	if header, ok := ctx.Value(requestHeaderKey{}).(http.Header); ok {
+		key += " " + fmt.Sprint(header)
+	}
+	sent := false
+	result := p.reads.group.DoChan(key, func() (interface{}, error) {
+		sent = true
+		// Callers joining the request may wait longer than the one that
+		// started it, so the request is not bound to its deadline.
+		shared, cancel := p.detached(ctx)
+		defer cancel()
+		// Warnings about the request concern the operation of every
+		// caller, so they are collected and handed to each of them.
+		var warnings *operationWarnings
+		if _, ok := ctx.Value(warningsKey{}).(*operationWarnings); ok {
+			warnings = &operationWarnings{}
+			shared = context.WithValue(shared, warningsKey{}, warnings)
+		}
+		res, err := p.send(shared, method, path, nil)
+		return sharedRead{response: res, warnings: warnings}, err
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if !sent {
+			p.stats.addCacheHit()
+		}
+		if res.Shared {
+			tflog.Debug(ctx, "shared response of an identical concurrent request", map[string]interface{}{
+				"path": path,
+			})
+		}
+		read, _ := res.Val.(sharedRead)
+		if read.warnings != nil {
+			for _, warning := range read.warnings.warnings {
+				addWarning(ctx, warning.Summary, warning.Detail)
+			}
+		}
+		// The response is shared between callers and must only be read.
+		return read.response, res.Err
+	}
+}
+
+// sharedRead is the result of a GET request shared by roundTrip, along with
+// the warnings about it collected for the operations of its callers.
+type sharedRead struct {
+	response *response
+	warnings *operationWarnings
+}
+
+// send performs a single request, see do.
+func (p *ProviderData) send(ctx context.Context, method string, path string, in interface{}) (*response, error) {
+	return p.exchange(ctx, method, path, in, readResponseBody)
+}
+
+// exchange performs a single request like send, handing the body of a
+// successful response to read. The body read returns ends up in the
+// response, read may also consume the body itself and return nil. The body
+// of an error status is always read with readResponseBody.
+func (p *ProviderData) exchange(ctx context.Context, method string, path string, in interface{}, read func(*http.Response) ([]byte, error)) (*response, error) {
+	// Dry runs change nothing.
+	if method != "GET" && path != validatePath {
+		p.roleBatch.forget()
+	}
+
+	var body *requestBody
+	if in != nil {
+		var err error
+		body, err = newRequestBody(in)
+		if err != nil {
+			return nil, err
+		}
+		defer body.release()
+	}
+
+	attempts := &requestAttempts{count: 1}
+	request, err := http.NewRequestWithContext(context.WithValue(ctx, attemptsKey{}, attempts), method, p.endpoint+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Body = body.open()
+		request.GetBody = func() (io.ReadCloser, error) { return body.open(), nil }
+		request.ContentLength = int64(len(body.data))
+	}
+	if header, ok := ctx.Value(requestHeaderKey{}).(http.Header); ok {
+		for name, values := range header {
+			request.Header[name] = values
+		}
+	}
+	if p.operationHeaders {
+		if operation, ok := ctx.Value(operationKey{}).(string); ok {
+			request.Header.Set(operationHeader, operation)
+		}
+		request.Header.Set(providerVersionHeader, p.version)
+	}
+	var annotations map[string]string
+	if method != "GET" {
+		annotations = p.auditAnnotations
+		setAuditHeaders(request.Header, annotations)
+	}
+	if in != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	request.SetBasicAuth(p.username, p.password)
+
+	start := time.Now()
+	done := func(res *http.Response, err error) {
+		elapsed := time.Since(start)
+		logRequest(ctx, method, path, annotations, elapsed, attempts.count, res, err)
+		p.checkSlowRequest(ctx, method, path, elapsed)
+	}
+	res, err := p.client.Do(request)
+	p.stats.addCall(method, attempts)
+	if err != nil {
+		done(nil, err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		read = readResponseBody
+	}
+	resBody, err := read(res)
+	if err != nil {
+		done(res, err)
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		err = &apiError{
+			Method:     method,
+			Path:       path,
+			StatusCode: res.StatusCode,
+			Body:       string(resBody),
+		}
+	}
+	done(res, err)
+	if err == nil {
+		p.checkServerVersion(ctx, res.Header.Get(serverVersionHeader))
+	}
+
+	return &response{Header: res.Header, Body: resBody}, err
+}
+
+// logRequest emits the single log entry of a call to the authproxy API: at
+// DEBUG level when it succeeded and at ERROR level when it failed, be it
+// with err or an error status. Only the path is logged, never the endpoint,
+// which may carry credentials. The audit annotations a write carried are
+// logged as they are, they are not secrets.
+func logRequest(ctx context.Context, method string, path string, annotations map[string]string, elapsed time.Duration, attempts int, res *http.Response, err error) {
+	fields := map[string]interface{}{
+		"method":      method,
+		"path":        path,
+		"duration_ms": elapsed.Milliseconds(),
+		"attempt":     attempts,
+	}
+	if len(annotations) > 0 {
+		fields["audit_annotations"] = annotations
+	}
+	if res != nil {
+		fields["status"] = res.StatusCode
+		fields["request_id"] = res.Header.Get(requestIDHeader)
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Error(ctx, "authproxy request failed", fields)
+		return
+	}
+	tflog.Debug(ctx, "authproxy request", fields)
+}
+
+// checkSlowRequest warns about the request taking elapsed in the operation
+// ctx belongs to, if it took longer than the slow request threshold.
+func (p *ProviderData) checkSlowRequest(ctx context.Context, method string, path string, elapsed time.Duration) {
+	if p.slowRequestThreshold <= 0 || elapsed <= p.slowRequestThreshold {
+		return
+	}
+
+	addWarning(ctx, "Slow Authproxy Request", fmt.Sprintf("%s %s took %s, longer than the slow_request_threshold of %s. "+
+		"Authproxy or the network to it may be degraded.", method, path, elapsed.Round(time.Millisecond), p.slowRequestThreshold))
+}
+
+// readResponseBody reads the body of res, failing when it exceeds
+// maxResponseSize.
+func readResponseBody(res *http.Response) ([]byte, error) {
+	if res.ContentLength > 0 && res.ContentLength <= maxResponseSize {
+		// The transport stops reading at the announced length, so the
+		// body is read into a single allocation of that size.
+		body := make([]byte, res.ContentLength)
+		if _, err := io.ReadFull(res.Body, body); err != nil {
+			return nil, err
+		}
+		return body, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxResponseSize {
+		return nil, responseTooLarge(res)
+	}
+	return body, nil
+}
+
+// responseTooLarge is the error for a response exceeding maxResponseSize.
+func responseTooLarge(res *http.Response) error {
+	return fmt.Errorf("the response to %s %s exceeds %d bytes", res.Request.Method, res.Request.URL.Path, maxResponseSize)
+}
+
+// limitedBody reads the body of a response like readResponseBody, failing
+// once more than maxResponseSize bytes were read. It keeps the error reading
+// failed with, to tell it apart from decoding errors.
+type limitedBody struct {
+	res  *http.Response
+	read int64
+	err  error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.res.Body.Read(p)
+	b.read += int64(n)
+	if b.read > maxResponseSize {
+		err = responseTooLarge(b.res)
+	}
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
+}
+
+// excerptWriter keeps the start of what is written to it, as much as
+// bodyExcerpt quotes.
+type excerptWriter struct {
+	buf [maxBodyExcerpt + 1]byte
+	n   int
+}
+
+func (w *excerptWriter) Write(p []byte) (int, error) {
+	w.n += copy(w.buf[w.n:], p)
+	return len(p), nil
+}
+
+func (w *excerptWriter) Bytes() []byte {
+	return w.buf[:w.n]
+}
+
+// decodeError is returned by the request helpers for successful responses
+// that are not the JSON the caller expected.
+type decodeError struct {
+	Method string
+	Path   string
+	Body   string
+	Err    error
+}
+
+func (e *decodeError) Error() string {
+	return fmt.Sprintf("decode the response to %s %s: %s: %s", e.Method, e.Path, e.Err, e.Body)
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.Err
+}
+
+// maxBufferedDecodeSize is the largest response body of known length that
+// decodeJSON reads in full before decoding it. Smaller bodies are cheaper to
+// decode once read, larger ones and bodies of unknown length, such as list
+// pages, are decoded while they are read.
+const maxBufferedDecodeSize = 64 << 10
+
+// decodeJSON performs a request like do and decodes the JSON response into
+// out, leaving out unchanged when the response body is empty. It returns the
+// start of the body, see bodyExcerpt.
+func (p *ProviderData) decodeJSON(ctx context.Context, method string, path string, in interface{}, out interface{}) ([]byte, error) {
+	if method == "GET" && ctx.Value(readDeduplicationKey{}) != nil {
+		// The response is shared with other callers and read in full.
+		resBody, err := p.do(ctx, method, path, in)
+		if err != nil {
+			return resBody, err
+		}
+		return resBody, unmarshalResponse(method, path, resBody, out)
+	}
+
+	var captured *excerptWriter
+	res, err := p.exchange(ctx, method, path, in, func(res *http.Response) ([]byte, error) {
+		if res.ContentLength >= 0 && res.ContentLength <= maxBufferedDecodeSize {
+			return readResponseBody(res)
+		}
+
+		// A single capture of the start of the body serves the errors of
+		// both the decoding here and the callers.
+		captured = &excerptWriter{}
+		body := &limitedBody{res: res}
+		tee := io.TeeReader(body, captured)
+		decoder := json.NewDecoder(tee)
+		err := decoder.Decode(out)
+		if err == nil {
+			// Like json.Unmarshal, refuse anything after the value.
+			if _, err = decoder.Token(); err == nil {
+				err = errors.New("invalid character after top-level value")
+			}
+		}
+		switch {
+		case err == io.EOF:
+			return nil, nil
+		case body.err != nil:
+			return nil, body.err
+		}
+		// Decoding stops at the first invalid byte, the capture is
+		// completed so that the error quotes as much as it would have
+		// from a body read in full.
+		_, _ = io.CopyN(io.Discard, tee, int64(len(captured.buf)-captured.n))
+		return nil, &decodeError{Method: method, Path: path, Body: bodyExcerpt(captured.Bytes()), Err: err}
+	})
+	switch {
+	case captured != nil:
+		return captured.Bytes(), err
+	case err != nil:
+		return nil, err
+	}
+	return res.Body, unmarshalResponse(method, path, res.Body, out)
+}
+
+// unmarshalResponse decodes the JSON response body read for decodeJSON into
+// out, leaving out unchanged when the body is empty.
+func unmarshalResponse(method string, path string, body []byte, out interface{}) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return &decodeError{Method: method, Path: path, Body: bodyExcerpt(body), Err: err}
+	}
+	return nil
+}
+
+// doJSON performs a request like do and decodes the JSON response into out,
+// which may be nil when the response body is of no interest.
+func (p *ProviderData) doJSON(ctx context.Context, method string, path string, in interface{}, out interface{}) error {
+	if out == nil {
+		_, err := p.do(ctx, method, path, in)
+		return err
+	}
+	_, err := p.decodeJSON(ctx, method, path, in, out)
+	return err
+}
+
+// objectResponse decodes a response describing a single object into out,
+// recording the problem when it is not an object whose attributes named
+// required are non-empty strings, see doObjectJSON.
+type objectResponse struct {
+	out      interface{}
+	required []string
+	problem  string
+}
+
+func (o *objectResponse) UnmarshalJSON(data []byte) error {
+	// The attributes are left undecoded, only the required ones are looked
+	// at before decoding the response into out.
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil || object == nil {
+		return nil
+	}
+	for _, attribute := range o.required {
+		var value string
+		if json.Unmarshal(object[attribute], &value) != nil || value == "" {
+			o.problem = fmt.Sprintf("lacks the %q attribute", attribute)
+			return nil
+		}
+	}
+	o.problem = ""
+	return json.Unmarshal(data, o.out)
+}
+
+// doObjectJSON works like doJSON for responses describing a single object,
+// failing with an *invalidObjectError unless the response is a JSON object
+// whose attributes named required are non-empty strings, such as "id".
+func (p *ProviderData) doObjectJSON(ctx context.Context, method string, path string, in interface{}, out interface{}, required ...string) error {
+	// Empty bodies and bodies that are not JSON leave the problem as is.
+	object := &objectResponse{out: out, required: required, problem: "is not a JSON object"}
+	resBody, err := p.decodeJSON(ctx, method, path, in, object)
+	if err != nil {
+		var decodeErr *decodeError
+		if !errors.As(err, &decodeErr) {
+			return err
+		}
+	}
+	if object.problem != "" {
+		return &invalidObjectError{Method: method, Path: path, Problem: object.problem, Body: bodyExcerpt(resBody)}
+	}
+	return err
+}
+
+// forEachConcurrently calls fn for every index below n, running at most
+// maxConcurrentRequests calls at once. A failing call does not stop the
+// others, the errors of all of them are joined in the order of their
+// indexes, so callers should name the item in the errors fn returns. Calls
+// not started before ctx is done are skipped and ctx's error is returned.
+func (p *ProviderData) forEachConcurrently(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	limit := p.maxConcurrentRequests
+	if limit <= 0 {
+		limit = defaultMaxConcurrentRequests
+	}
+
+	errs := make([]error, n)
+	var group errgroup.Group
+	group.SetLimit(limit)
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		i := i
+		group.Go(func() error {
+			if ctx.Err() == nil {
+				errs[i] = fn(ctx, i)
+			}
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// addClientError appends err to diags using the "Unable to <action>" wording
+// used throughout the provider, where action names the operation and the
+// object type, such as "create tenant". The summary names the category of the
+// failure, see clientErrorSummary, and the detail adds guidance for some of
+// them.
+func addClientError(diags *diag.Diagnostics, action string, err error) {
+	summary := clientErrorSummary(err)
+	detail := fmt.Sprintf("Unable to %s, got error: %s", action, err)
+	switch summary {
+	case "Authentication Failed":
+		detail += "\n\nAuthproxy rejected the configured credentials. Check the provider's username and password."
+	case "Permission Denied":
+		detail += "\n\nThe configured user lacks the permissions this operation requires."
+	case "Connection Error":
+		detail += "\n\nCheck the provider's endpoint and that authproxy is reachable from where Terraform runs."
+	case "Unexpected Authproxy Response":
+		detail += "\n\nThe provider's endpoint may be misconfigured. Check that it points at the authproxy API rather than, for example, a proxy in front of it."
+	case "TLS Error":
+		guidance, _ := tlsGuidance(err)
+		detail += "\n\n" + guidance
+	}
+	diags.AddError(summary, detail)
+}
+
+// clientErrorSummary returns the diagnostic summary for err: the category of
+// the status code of an *apiError, "TLS Error" for failed TLS handshakes, see
+// tlsGuidance, "Connection Error" for other requests that got no response,
+// "Unexpected Authproxy Response" for an *invalidObjectError and "Client
+// Error" for failures of the provider itself, such as responses it cannot
+// decode.
+func clientErrorSummary(err error) string {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		switch code := apiErr.StatusCode; {
+		case code == http.StatusUnauthorized:
+			return "Authentication Failed"
+		case code == http.StatusForbidden:
+			return "Permission Denied"
+		case code == http.StatusNotFound:
+			return "Not Found"
+		case code == http.StatusConflict || code == http.StatusPreconditionFailed:
+			return "Conflict"
+		case code == http.StatusTooManyRequests || code >= 500:
+			// Throttling left after the retries is on authproxy's side,
+			// nothing about the request is wrong.
+			return "Authproxy Server Error"
+		default:
+			return "Invalid Request"
+		}
+	}
+
+	var objectErr *invalidObjectError
+	if errors.As(err, &objectErr) {
+		return "Unexpected Authproxy Response"
+	}
+
+	if _, ok := tlsGuidance(err); ok {
+		return "TLS Error"
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return "Connection Error"
+	}
+	return "Client Error"
+}
@@ -0,0 +1,521 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestProviderData_deduplicatesConcurrentReads(t *testing.T) {
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		// Keep the request in flight long enough for every reader to join.
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"t1","name":"acme"}`)
+	}))
+	defer server.Close()
+
+	providerData := testProviderData(server.URL)
+	ctx := withReadDeduplication(context.Background())
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var tenant tenantDataReadResponse
+			if err := providerData.doJSON(ctx, "GET", "/tenants/acme", nil, &tenant); err != nil {
+				errs <- err
+				return
+			}
+			if tenant.ID != "t1" {
+				errs <- fmt.Errorf("unexpected tenant id %q", tenant.ID)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if requests != 1 {
+		t.Errorf("expected a single request to reach the server, got %d", requests)
+	}
+	if hits := providerData.stats.fields()["cache_hits"]; hits != int64(19) {
+		t.Errorf("expected 19 cache hits, got %v", hits)
+	}
+}
+
+func TestProviderData_deduplicatedReadOutlivesFirstCaller(t *testing.T) {
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"t1","name":"acme"}`)
+	}))
+	defer server.Close()
+
+	providerData := testProviderData(server.URL)
+	ctx := withReadDeduplication(context.Background())
+
+	// The first caller gives up before the response arrives, the second one
+	// joins its request and waits for it.
+	short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	first := make(chan error, 1)
+	go func() {
+		first <- providerData.doJSON(short, "GET", "/tenants/acme", nil, nil)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	var tenant tenantDataReadResponse
+	if err := providerData.doJSON(ctx, "GET", "/tenants/acme", nil, &tenant); err != nil {
+		t.Fatalf("expected the joined read to outlive the first caller, got: %s", err)
+	}
+	if tenant.ID != "t1" {
+		t.Errorf("unexpected tenant id %q", tenant.ID)
+	}
+	if err := <-first; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the first caller to time out, got: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected a single request to reach the server, got %d", requests)
+	}
+}
+
+func TestProviderData_deduplicatesReadsPerOperation(t *testing.T) {
+	var mu sync.Mutex
+	operations := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		operations[r.Header.Get(operationHeader)]++
+		mu.Unlock()
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	providerData := testProviderData(server.URL)
+	providerData.operationHeaders = true
+	providerData.slowRequestThreshold = 50 * time.Millisecond
+
+	labels := []string{"data.authproxy_tenant.read", "data.authproxy_tenant.read", "authproxy_tenant.read"}
+	warnings := make([]*operationWarnings, len(labels))
+	var wg sync.WaitGroup
+	for i, label := range labels {
+		warnings[i] = &operationWarnings{}
+		ctx := context.WithValue(withOperation(withReadDeduplication(context.Background()), label), warningsKey{}, warnings[i])
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = providerData.doJSON(ctx, "GET", "/tenants/acme", nil, nil)
+		}()
+	}
+	wg.Wait()
+
+	if len(operations) != 2 || operations["data.authproxy_tenant.read"] != 1 || operations["authproxy_tenant.read"] != 1 {
+		t.Errorf("expected a request per operation label, got %v", operations)
+	}
+	for i, w := range warnings {
+		if len(w.warnings) != 1 || w.warnings[0].Summary != "Slow Authproxy Request" {
+			t.Errorf("expected caller %d to get the slow request warning, got %v", i, w.warnings)
+		}
+	}
+}
+
+func TestProviderData_doesNotDeduplicateWithoutOptIn(t *testing.T) {
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	providerData := testProviderData(server.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = providerData.doJSON(context.Background(), "GET", "/tenants/acme", nil, nil)
+		}()
+	}
+	wg.Wait()
+
+	if requests != 5 {
+		t.Errorf("expected every request to reach the server, got %d", requests)
+	}
+}
+
+func TestProviderData_sendsContextHeaders(t *testing.T) {
+	headers := make(chan http.Header, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	providerData := testProviderData(server.URL)
+	ctx := withRequestHeader(context.Background(), "If-None-Match", "*")
+
+	if err := providerData.doJSON(ctx, "PUT", "/tenants/acme/password-policy", map[string]int{"min_length": 12}, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := providerData.doJSON(context.Background(), "PUT", "/tenants/acme/password-policy", map[string]int{"min_length": 12}, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := (<-headers).Get("If-None-Match"); got != "*" {
+		t.Errorf("expected the context header to be sent, got %q", got)
+	}
+	if got := (<-headers).Get("If-None-Match"); got != "" {
+		t.Errorf("expected no header without the context, got %q", got)
+	}
+}
+
+func TestProviderData_limitsResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte(" "), maxResponseSize+1))
+	}))
+	defer server.Close()
+
+	_, err := testProviderData(server.URL).do(context.Background(), "GET", "/tenants", nil)
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected the oversized response to be refused, got: %v", err)
+	}
+
+	// Bodies of unknown length are decoded while read and refused alike.
+	var out interface{}
+	err = testProviderData(server.URL).doJSON(context.Background(), "GET", "/tenants", nil, &out)
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected the oversized response to be refused while decoding, got: %v", err)
+	}
+}
+
+func TestProviderData_doJSON(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body  string
+		names []string
+		err   string
+	}{
+		"list":           {body: `[{"name": "acme"}, {"name": "globex"}]`, names: []string{"acme", "globex"}},
+		"empty body":     {body: ``},
+		"whitespace":     {body: " \n"},
+		"trailing space": {body: `[{"name": "acme"}]` + "\n", names: []string{"acme"}},
+		"trailing data":  {body: `[{"name": "acme"}] []`, err: "after top-level value"},
+		"truncated":      {body: `[{"name": "acme"}`, err: "decode the response to GET /tenants"},
+		"html":           {body: `<html>` + strings.Repeat("x", 1000) + `</html>`, err: `"<html>` + strings.Repeat("x", maxBodyExcerpt-6) + `"...`},
+	} {
+		for _, streamed := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s streamed=%t", name, streamed), func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if streamed {
+						// Flushing first sends the body chunked, of
+						// unknown length.
+						w.(http.Flusher).Flush()
+					}
+					_, _ = w.Write([]byte(tc.body))
+				}))
+				defer server.Close()
+
+				var tenants []struct {
+					Name string `json:"name"`
+				}
+				err := testProviderData(server.URL).doJSON(context.Background(), "GET", "/tenants", nil, &tenants)
+				if tc.err != "" {
+					var decodeErr *decodeError
+					if !errors.As(err, &decodeErr) || !strings.Contains(err.Error(), tc.err) {
+						t.Fatalf("expected a decode error containing %s, got %v", tc.err, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				var names []string
+				for _, tenant := range tenants {
+					names = append(names, tenant.Name)
+				}
+				if fmt.Sprint(names) != fmt.Sprint(tc.names) {
+					t.Errorf("expected %v, got %v", tc.names, names)
+				}
+			})
+		}
+	}
+}
+
+func TestProviderData_logsRequests(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", fmt.Sprintf("req-%d", atomic.AddInt32(&requests, 1)))
+		if r.URL.Path == "/tenants/unavailable" {
+			http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	providerData := testProviderData(server.URL)
+	providerData.client = &http.Client{Transport: &retryTransport{next: http.DefaultTransport, attempts: 2}}
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	if err := providerData.doJSON(ctx, "GET", "/tenants/acme", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := providerData.do(ctx, "GET", "/tenants/unavailable", nil); !isStatus(err, http.StatusServiceUnavailable) {
+		t.Fatalf("expected the unavailable response to fail, got: %v", err)
+	}
+
+	logged := output.String()
+	if strings.Contains(logged, server.URL) || strings.Contains(logged, testserver.Password) {
+		t.Errorf("expected neither the endpoint nor the password to be logged, got %s", logged)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(strings.NewReader(logged))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a single entry per request, got %v", entries)
+	}
+
+	for i, expected := range []map[string]interface{}{
+		{"@level": "debug", "method": "GET", "path": "/tenants/acme", "status": float64(200), "attempt": float64(1), "request_id": "req-1"},
+		{"@level": "error", "method": "GET", "path": "/tenants/unavailable", "status": float64(503), "attempt": float64(2), "request_id": "req-3"},
+	} {
+		for key, value := range expected {
+			if entries[i][key] != value {
+				t.Errorf("expected %s to be %v in entry %d, got %v", key, value, i, entries[i][key])
+			}
+		}
+		if _, ok := entries[i]["duration_ms"].(float64); !ok {
+			t.Errorf("expected a duration in entry %d, got %v", i, entries[i])
+		}
+	}
+}
+
+func TestProviderData_forEachConcurrently(t *testing.T) {
+	providerData := testProviderData("http://authproxy.invalid")
+	providerData.maxConcurrentRequests = 4
+
+	var running, peak, calls int64
+	err := providerData.forEachConcurrently(context.Background(), 50, func(ctx context.Context, i int) error {
+		atomic.AddInt64(&calls, 1)
+		current := atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+		for {
+			seen := atomic.LoadInt64(&peak)
+			if current <= seen || atomic.CompareAndSwapInt64(&peak, seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		if i == 7 || i == 3 {
+			return fmt.Errorf("item %d failed", i)
+		}
+		return nil
+	})
+
+	if calls != 50 {
+		t.Errorf("expected failures not to stop the other calls, got %d calls", calls)
+	}
+	if peak < 2 || peak > 4 {
+		t.Errorf("expected up to 4 concurrent calls, got %d", peak)
+	}
+	if err == nil || err.Error() != "item 3 failed\nitem 7 failed" {
+		t.Errorf("expected the errors joined in order, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	err = providerData.forEachConcurrently(ctx, 50, func(ctx context.Context, i int) error {
+		atomic.AddInt64(&calls, 1)
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || calls != 0 {
+		t.Errorf("expected no calls once the context is done, got %d calls and %v", calls, err)
+	}
+}
+
+func TestAddClientError(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err     error
+		summary string
+		detail  string
+	}{
+		"unauthorized":    {err: &apiError{StatusCode: http.StatusUnauthorized}, summary: "Authentication Failed", detail: "Check the provider's username and password"},
+		"forbidden":       {err: &apiError{StatusCode: http.StatusForbidden}, summary: "Permission Denied", detail: "lacks the permissions"},
+		"not found":       {err: &apiError{StatusCode: http.StatusNotFound}, summary: "Not Found"},
+		"conflict":        {err: &apiError{StatusCode: http.StatusConflict}, summary: "Conflict"},
+		"precondition":    {err: &apiError{StatusCode: http.StatusPreconditionFailed}, summary: "Conflict"},
+		"bad request":     {err: &apiError{StatusCode: http.StatusBadRequest}, summary: "Invalid Request"},
+		"unprocessable":   {err: &apiError{StatusCode: http.StatusUnprocessableEntity}, summary: "Invalid Request"},
+		"throttled":       {err: &apiError{StatusCode: http.StatusTooManyRequests}, summary: "Authproxy Server Error"},
+		"internal error":  {err: &apiError{StatusCode: http.StatusInternalServerError}, summary: "Authproxy Server Error"},
+		"unavailable":     {err: fmt.Errorf("reading page 2: %w", &apiError{StatusCode: http.StatusServiceUnavailable}), summary: "Authproxy Server Error"},
+		"connection":      {err: &url.Error{Op: "Post", URL: "https://authproxy.invalid/tenants", Err: errors.New("connection refused")}, summary: "Connection Error", detail: "reachable"},
+		"invalid payload": {err: errors.New("unexpected end of JSON input"), summary: "Client Error"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addClientError(&diags, "create tenant", tc.err)
+
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("expected a single error, got %v", diags)
+			}
+			if summary := diags[0].Summary(); summary != tc.summary {
+				t.Errorf("expected the summary %q, got %q", tc.summary, summary)
+			}
+			detail := diags[0].Detail()
+			if !strings.HasPrefix(detail, "Unable to create tenant, got error: "+tc.err.Error()) {
+				t.Errorf("expected the detail to name the operation and the error, got %q", detail)
+			}
+			if !strings.Contains(detail, tc.detail) {
+				t.Errorf("expected the detail to contain %q, got %q", tc.detail, detail)
+			}
+		})
+	}
+}
+
+func TestAddClientError_resources(t *testing.T) {
+	server := testserver.New(t)
+	server.CreateTenant("acme")
+	providerData := testProviderData(server.URL)
+
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	unknown := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	server.PutRole("acme", testserver.Role{Name: "viewer", Scopes: []string{"billing:read"}})
+	_, diags := contractOperation(t, NewRoleResource(), providerData, "create", nil, map[string]tftypes.Value{"id": unknown, "tenant": str("acme"), "name": str("viewer"), "scopes": stringList("billing:read"), "permissions": stringList("billing:read")})
+	if diags.ErrorsCount() != 1 || diags[0].Summary() != "Conflict" || !strings.HasPrefix(diags[0].Detail(), "Unable to create role") {
+		t.Errorf("expected a conflict creating the role, got %v", diags)
+	}
+
+	_, diags = contractOperation(t, NewRoleResource(), providerData, "create", nil, map[string]tftypes.Value{"id": unknown, "tenant": str("lidl"), "name": str("viewer"), "scopes": stringList("billing:read"), "permissions": stringList("billing:read")})
+	if diags.ErrorsCount() != 1 || diags[0].Summary() != "Not Found" || !strings.HasPrefix(diags[0].Detail(), "Unable to create role") {
+		t.Errorf("expected the role of the missing tenant not to be found, got %v", diags)
+	}
+
+	providerData.password = "wrong"
+	resp := testDataSourceRead(t, NewTenantDataSource(), providerData, map[string]tftypes.Value{"name": str("acme")})
+	if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics[0].Summary() != "Authentication Failed" || !strings.HasPrefix(resp.Diagnostics[0].Detail(), "Unable to read tenant") {
+		t.Errorf("expected the wrong password to be rejected, got %v", resp.Diagnostics)
+	}
+}
+
+func TestProviderData_doObjectJSON(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body    string
+		problem string
+	}{
+		"object":         {body: `{"id": "t1", "name": "acme"}`},
+		"empty body":     {body: ``, problem: "is not a JSON object"},
+		"empty object":   {body: `{}`, problem: `lacks the "id" attribute`},
+		"null":           {body: `null`, problem: "is not a JSON object"},
+		"array":          {body: `[{"id": "t1", "name": "acme"}]`, problem: "is not a JSON object"},
+		"html":           {body: `<html><body>Sign in</body></html>`, problem: "is not a JSON object"},
+		"envelope":       {body: `{"data": {"id": "t1", "name": "acme"}}`, problem: `lacks the "id" attribute`},
+		"empty name":     {body: `{"id": "t1", "name": ""}`, problem: `lacks the "name" attribute`},
+		"numeric id":     {body: `{"id": 1, "name": "acme"}`, problem: `lacks the "id" attribute`},
+		"long html page": {body: "<html>" + strings.Repeat("x", 1000) + "</html>", problem: "is not a JSON object"},
+	} {
+		for _, streamed := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s streamed=%t", name, streamed), func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if streamed {
+						w.(http.Flusher).Flush()
+					}
+					_, _ = w.Write([]byte(tc.body))
+				}))
+				defer server.Close()
+
+				var tenant readResponse
+				err := testProviderData(server.URL).doObjectJSON(context.Background(), "GET", "/tenants/acme", nil, &tenant, "id", "name")
+				if tc.problem == "" {
+					if err != nil || tenant.ID != "t1" {
+						t.Fatalf("expected the tenant to be decoded, got %v and error %v", tenant, err)
+					}
+					return
+				}
+
+				var objectErr *invalidObjectError
+				if !errors.As(err, &objectErr) {
+					t.Fatalf("expected an invalid object error, got %v", err)
+				}
+				if objectErr.Problem != tc.problem {
+					t.Errorf("expected the problem %q, got %q", tc.problem, objectErr.Problem)
+				}
+				switch {
+				case len(tc.body) <= maxBodyExcerpt && objectErr.Body != strconv.Quote(tc.body):
+					t.Errorf("expected the body to be quoted, got %s", objectErr.Body)
+				case len(tc.body) > maxBodyExcerpt && (!strings.HasSuffix(objectErr.Body, `"...`) || len(objectErr.Body) != maxBodyExcerpt+5):
+					t.Errorf("expected an excerpt of the body, got %s", objectErr.Body)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCreateRole(b *testing.B) {
+	server := testserver.New(b)
+	server.CreateTenant("acme")
+	providerData := testProviderData(server.URL)
+	scopes := []string{"billing:read", "billing:write", "users:read"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var created createRoleResponse
+		err := providerData.doObjectJSON(context.Background(), "POST", tenantRolesPath("acme"), createRoleRequest{
+			Name:       fmt.Sprintf("role-%d", i),
+			roleScopes: roleScopes{Scopes: &scopes},
+		}, &created, "id")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadTenant(b *testing.B) {
+	server := testserver.New(b)
+	server.CreateTenant("acme")
+	providerData := testProviderData(server.URL)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var tenant tenantDataReadResponse
+		if err := providerData.doObjectJSON(context.Background(), "GET", "/tenants/acme", nil, &tenant, "id", "name"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
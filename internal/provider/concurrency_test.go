@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// The tests below share a single ProviderData between many goroutines, the
+// way Terraform runs resources of one provider instance in parallel. Run them
+// with -race, as CI does, for data races to fail them.
+
+// testConcurrency is the number of resources handled at once.
+const testConcurrency = 50
+
+// testLifecycle runs create, read, update and delete for a resource through
+// providerData, changing the attribute rename to renamed on update. It
+// returns the error of the first operation that failed.
+func testLifecycle(t *testing.T, newResource func() resource.Resource, providerData *ProviderData, planned map[string]tftypes.Value, rename string, renamed string) error {
+	t.Helper()
+
+	state, diags := contractOperation(t, newResource(), providerData, "create", nil, planned)
+	if diags.HasError() {
+		return fmt.Errorf("create: %v", diags)
+	}
+	prior := testStateValues(t, state)
+
+	state, diags = contractOperation(t, newResource(), providerData, "read", prior, nil)
+	if diags.HasError() {
+		return fmt.Errorf("read: %v", diags)
+	}
+	prior = testStateValues(t, state)
+
+	planned = testStateValues(t, state)
+	planned[rename] = tftypes.NewValue(tftypes.String, renamed)
+	state, diags = contractOperation(t, newResource(), providerData, "update", prior, planned)
+	if diags.HasError() {
+		return fmt.Errorf("update: %v", diags)
+	}
+
+	_, diags = contractOperation(t, newResource(), providerData, "delete", testStateValues(t, state), nil)
+	if diags.HasError() {
+		return fmt.Errorf("delete: %v", diags)
+	}
+	return nil
+}
+
+// testStateValues returns a copy of the attributes of a state returned by
+// contractOperation, which may be changed without affecting state.
+func testStateValues(t *testing.T, state tftypes.Value) map[string]tftypes.Value {
+	t.Helper()
+	var attributes map[string]tftypes.Value
+	if err := state.As(&attributes); err != nil {
+		t.Errorf("unexpected state %s: %s", state, err)
+	}
+	values := make(map[string]tftypes.Value, len(attributes))
+	for name, value := range attributes {
+		values[name] = value
+	}
+	return values
+}
+
+func TestProviderData_concurrentResources(t *testing.T) {
+	server := testserver.New(t)
+	server.CreateTenant("acme")
+	providerData := testProviderData(server.URL)
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < testConcurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			ctx := context.Background()
+			if _, err := providerData.supports(ctx, tenantUsageFeature); err != nil {
+				t.Errorf("probing capabilities: %s", err)
+			}
+
+			var err error
+			if i%2 == 0 {
+				err = testLifecycle(t, NewTenantResource, providerData, map[string]tftypes.Value{
+					"id":   tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+					"name": tftypes.NewValue(tftypes.String, fmt.Sprintf("tenant-%d", i)),
+				}, "name", fmt.Sprintf("tenant-%d-renamed", i))
+			} else {
+				err = testLifecycle(t, NewRoleResource, providerData, map[string]tftypes.Value{
+					"id":     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+					"tenant": tftypes.NewValue(tftypes.String, "acme"),
+					"name":   tftypes.NewValue(tftypes.String, fmt.Sprintf("role-%d", i)),
+					"scopes": stringList("billing:read"),
+				}, "name", fmt.Sprintf("role-%d-renamed", i))
+			}
+			if err != nil {
+				t.Errorf("resource %d: %s", i, err)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	if checks := server.HealthChecks(); checks != 1 {
+		t.Errorf("expected capabilities to be probed once, got %d health checks", checks)
+	}
+	if tenants := server.Tenants(); len(tenants) != 1 || tenants[0].Name != "acme" {
+		t.Errorf("expected only acme to be left, got %v", tenants)
+	}
+	if roles := server.Roles("acme"); len(roles) != 0 {
+		t.Errorf("expected every role to be deleted, got %v", roles)
+	}
+
+	writes := map[string]int{}
+	for _, request := range server.Requests() {
+		if request.Method != http.MethodGet {
+			writes[request.Method]++
+		}
+	}
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodDelete} {
+		if writes[method] != testConcurrency {
+			t.Errorf("expected %d %s requests, one per resource, got %d", testConcurrency, method, writes[method])
+		}
+	}
+}
+
+func TestProviderData_concurrentCapabilityProbe(t *testing.T) {
+	server := testserver.New(t)
+	server.SetFeatures(map[string]bool{rolesBatchFeature: true})
+	providerData := testProviderData(server.URL)
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < testConcurrency; i++ {
+		wg.Add(1)
+		go func(feature string) {
+			defer wg.Done()
+			<-start
+
+			supported, err := providerData.supports(context.Background(), feature)
+			if err != nil {
+				t.Errorf("probing %s: %s", feature, err)
+				return
+			}
+			if supported != (feature == rolesBatchFeature) {
+				t.Errorf("expected %s to be supported only if advertised, got %t", feature, supported)
+			}
+		}([]string{rolesBatchFeature, tenantUsageFeature}[i%2])
+	}
+	close(start)
+	wg.Wait()
+
+	if checks := server.HealthChecks(); checks != 1 {
+		t.Errorf("expected capabilities to be probed once, got %d health checks", checks)
+	}
+}
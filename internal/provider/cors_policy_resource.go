@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CORSPolicyResource{}
+var _ resource.ResourceWithImportState = &CORSPolicyResource{}
+var _ resource.ResourceWithValidateConfig = &CORSPolicyResource{}
+
+func NewCORSPolicyResource() resource.Resource {
+	return &CORSPolicyResource{}
+}
+
+// CORSPolicyResource defines the resource implementation. Every tenant has
+// exactly one CORS policy, the resource manages it rather than creating one.
+type CORSPolicyResource struct {
+	BaseResource
+}
+
+// CORSPolicyResourceModel describes the resource data model.
+type CORSPolicyResourceModel struct {
+	ID               types.String    `tfsdk:"id"`
+	Tenant           tenantNameValue `tfsdk:"tenant"`
+	AllowedOrigins   types.Set       `tfsdk:"allowed_origins"`
+	AllowedHeaders   types.Set       `tfsdk:"allowed_headers"`
+	AllowCredentials types.Bool      `tfsdk:"allow_credentials"`
+	MaxAgeSeconds    types.Int64     `tfsdk:"max_age_seconds"`
+}
+
+type corsPolicy struct {
+	AllowedOrigins   []string `json:"allowed_origins"`
+	AllowedHeaders   []string `json:"allowed_headers"`
+	AllowCredentials bool     `json:"allow_credentials"`
+	MaxAgeSeconds    int64    `json:"max_age_seconds"`
+}
+
+func (r *CORSPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cors_policy"
+}
+
+func (r *CORSPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "CORS policy of a tenant. Every tenant has exactly one, destroying the resource resets it to the authproxy defaults",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the tenant",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the CORS policy applies to. Changing it resets the policy of the previous tenant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"allowed_origins": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Origins allowed to make cross-origin requests such as `https://app.example.com`, or `*` for any origin",
+				Required:            true,
+				Validators: []validator.Set{
+					corsOrigins(),
+				},
+			},
+			"allowed_headers": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Request headers allowed in addition to the CORS-safelisted ones. Defaults to none",
+				Optional:            true,
+				Computed:            true,
+				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+			},
+			"allow_credentials": schema.BoolAttribute{
+				MarkdownDescription: "Whether cross-origin requests may include credentials such as cookies. Cannot be combined with the `*` origin. Defaults to `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"max_age_seconds": schema.Int64Attribute{
+				MarkdownDescription: "How long browsers may cache preflight responses, `0` to leave it to the browser. Defaults to `0`",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64Between(0, 86400),
+				},
+			},
+		},
+	}
+}
+
+func (r *CORSPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data CORSPolicyResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values that are not known yet are validated once they are.
+	if !data.AllowCredentials.ValueBool() || data.AllowedOrigins.IsUnknown() {
+		return
+	}
+
+	for _, element := range data.AllowedOrigins.Elements() {
+		if origin, ok := element.(types.String); ok && origin.ValueString() == "*" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("allow_credentials"),
+				"Invalid Attribute Combination",
+				"The `*` origin cannot be combined with `allow_credentials = true`, browsers refuse credentialed requests to wildcard origins. List the allowed origins instead.",
+			)
+			return
+		}
+	}
+}
+
+// corsPolicyPath returns the path of the CORS policy of tenant.
+func corsPolicyPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/cors", url.PathEscape(tenant))
+}
+
+// request builds the request body from data.
+func (data *CORSPolicyResourceModel) request(ctx context.Context) (corsPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	policy := corsPolicy{
+		AllowedOrigins:   []string{},
+		AllowedHeaders:   []string{},
+		AllowCredentials: data.AllowCredentials.ValueBool(),
+		MaxAgeSeconds:    data.MaxAgeSeconds.ValueInt64(),
+	}
+	diags.Append(data.AllowedOrigins.ElementsAs(ctx, &policy.AllowedOrigins, false)...)
+	diags.Append(data.AllowedHeaders.ElementsAs(ctx, &policy.AllowedHeaders, false)...)
+	sort.Strings(policy.AllowedOrigins)
+	sort.Strings(policy.AllowedHeaders)
+
+	return policy, diags
+}
+
+// setCORSPolicy copies the policy authproxy returned into data.
+func (data *CORSPolicyResourceModel) setCORSPolicy(ctx context.Context, policy corsPolicy) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if policy.AllowedOrigins == nil {
+		policy.AllowedOrigins = []string{}
+	}
+	if policy.AllowedHeaders == nil {
+		policy.AllowedHeaders = []string{}
+	}
+
+	data.ID = types.StringValue(data.Tenant.ValueString())
+
+	allowedOrigins, d := types.SetValueFrom(ctx, types.StringType, policy.AllowedOrigins)
+	diags.Append(d...)
+	data.AllowedOrigins = allowedOrigins
+
+	allowedHeaders, d := types.SetValueFrom(ctx, types.StringType, policy.AllowedHeaders)
+	diags.Append(d...)
+	data.AllowedHeaders = allowedHeaders
+
+	data.AllowCredentials = types.BoolValue(policy.AllowCredentials)
+	data.MaxAgeSeconds = types.Int64Value(policy.MaxAgeSeconds)
+
+	return diags
+}
+
+// put replaces the CORS policy of the tenant of data.
+func (r *CORSPolicyResource) put(ctx context.Context, data *CORSPolicyResourceModel, diags *diag.Diagnostics) {
+	body, d := data.request(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+
+	var policy corsPolicy
+	err := r.doJSON(ctx, "PUT", corsPolicyPath(data.Tenant.ValueString()), body, &policy)
+	if err != nil {
+		addClientError(diags, "update CORS policy", err)
+		return
+	}
+
+	diags.Append(data.setCORSPolicy(ctx, policy)...)
+}
+
+func (r *CORSPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *CORSPolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.put(ctx, data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a CORS policy resource", map[string]interface{}{
+		"tenant": data.Tenant.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CORSPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CORSPolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy corsPolicy
+	err := r.doJSON(ctx, "GET", corsPolicyPath(data.Tenant.ValueString()), nil, &policy)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant no longer exists, removing its CORS policy from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read CORS policy", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setCORSPolicy(ctx, policy)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CORSPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *CORSPolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.put(ctx, data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "updated a CORS policy resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CORSPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *CORSPolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting the policy resets it to the authproxy defaults.
+	err := r.doJSON(ctx, "DELETE", corsPolicyPath(data.Tenant.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "reset CORS policy", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a CORS policy resource")
+}
+
+func (r *CORSPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("tenant"), req, resp)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccCORSPolicyBackend is an in-memory stand-in for the CORS policy of
+// the "acme" tenant.
+type testAccCORSPolicyBackend struct {
+	mu     sync.Mutex
+	policy corsPolicy
+}
+
+func (b *testAccCORSPolicyBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.URL.Path != corsPolicyPath("acme") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var policy corsPolicy
+		if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.policy = policy
+	case http.MethodDelete:
+		b.policy = corsPolicy{}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(b.policy)
+}
+
+func TestAccCORSPolicyResource(t *testing.T) {
+	backend := &testAccCORSPolicyBackend{}
+	server := testBackendServer(t, backend, corsPolicyPath("acme"))
+
+	config := testAccProviderConfig(server.URL) + `
+resource "authproxy_cors_policy" "test" {
+  tenant            = "acme"
+  allowed_origins   = ["https://app.acme.io", "http://localhost:3000"]
+  allowed_headers   = ["Authorization"]
+  allow_credentials = true
+  max_age_seconds   = 600
+}
+`
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if !reflect.DeepEqual(backend.policy, corsPolicy{}) {
+				return fmt.Errorf("expected the CORS policy to be reset, got %+v", backend.policy)
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_cors_policy" "test" {
+  tenant          = "acme"
+  allowed_origins = ["*"]
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_cors_policy.test", "id", "acme"),
+					resourcetest.TestCheckResourceAttr("authproxy_cors_policy.test", "allowed_origins.#", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_cors_policy.test", "allowed_headers.#", "0"),
+					resourcetest.TestCheckResourceAttr("authproxy_cors_policy.test", "allow_credentials", "false"),
+					resourcetest.TestCheckResourceAttr("authproxy_cors_policy.test", "max_age_seconds", "0"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: config,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_cors_policy.test", "allowed_origins.#", "2"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_cors_policy.test", "allowed_origins.*", "http://localhost:3000"),
+					resourcetest.TestCheckResourceAttr("authproxy_cors_policy.test", "allow_credentials", "true"),
+					resourcetest.TestCheckResourceAttr("authproxy_cors_policy.test", "max_age_seconds", "600"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_cors_policy.test",
+				ImportState:       true,
+				ImportStateId:     "acme",
+				ImportStateVerify: true,
+			},
+			// An origin added in the UI is detected as drift
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					backend.policy.AllowedOrigins = append(backend.policy.AllowedOrigins, "https://evil.example.com")
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and removed again by the next apply
+			{
+				Config: config,
+				Check: func(s *terraform.State) error {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					if len(backend.policy.AllowedOrigins) != 2 {
+						return fmt.Errorf("expected the drift to be corrected, got origins %v", backend.policy.AllowedOrigins)
+					}
+					return nil
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccCORSPolicyResource_validation(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config: testAccProviderConfig("http://127.0.0.1:1") + `
+resource "authproxy_cors_policy" "test" {
+  tenant            = "acme"
+  allowed_origins   = ["*"]
+  allow_credentials = true
+}
+`,
+				ExpectError: regexp.MustCompile(`cannot be combined with`),
+			},
+			{
+				Config: testAccProviderConfig("http://127.0.0.1:1") + `
+resource "authproxy_cors_policy" "test" {
+  tenant          = "acme"
+  allowed_origins = ["https://app.acme.io/login"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+		},
+	})
+}
@@ -0,0 +1,255 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The sources of an effective role: bound to the user directly, or to a group
+// of the user, as "group:<name>".
+const (
+	effectiveRoleSourceDirect      = "direct"
+	effectiveRoleSourceGroupPrefix = "group:"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &EffectiveScopesDataSource{}
+
+func NewEffectiveScopesDataSource() datasource.DataSource {
+	return &EffectiveScopesDataSource{}
+}
+
+// EffectiveScopesDataSource defines the data source implementation.
+type EffectiveScopesDataSource struct {
+	BaseDataSource
+}
+
+// EffectiveScopesDataSourceModel describes the data source data model.
+type EffectiveScopesDataSourceModel struct {
+	Tenant   tenantNameValue      `tfsdk:"tenant"`
+	UserID   types.String         `tfsdk:"user_id"`
+	Scopes   types.List           `tfsdk:"scopes"`
+	Roles    []EffectiveRoleModel `tfsdk:"roles"`
+	Timeouts *ReadTimeoutsModel   `tfsdk:"timeouts"`
+}
+
+// EffectiveRoleModel describes a role granted to the user and where the grant
+// comes from.
+type EffectiveRoleModel struct {
+	Name   types.String `tfsdk:"name"`
+	Source types.String `tfsdk:"source"`
+	Scopes types.List   `tfsdk:"scopes"`
+}
+
+// roleGrants are the principals the user is granted a role through.
+type roleGrants struct {
+	direct bool
+	groups []string
+}
+
+func (d *EffectiveScopesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_effective_scopes"
+}
+
+func (d *EffectiveScopesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Scopes a user of a tenant is granted, through roles bound to the user directly and roles bound to the groups of the user. Meant for access reviews. " +
+			"Authproxy lists bindings per role and has no endpoint listing the roles or groups of a user, so reading the data source lists the bindings of every role of the tenant, one request per role, and checks the membership of the user in every group bound to a role, one request per group. Expect reads to take long in tenants with many roles",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the user belongs to",
+				Required:            true,
+			},
+			"user_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the user",
+				Required:            true,
+			},
+			"scopes": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Sorted scopes of all roles granted to the user, each listed once",
+				Computed:            true,
+			},
+			"roles": schema.ListNestedAttribute{
+				MarkdownDescription: "Roles granted to the user, once per source, sorted by name and source",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the role",
+							Computed:            true,
+						},
+						"source": schema.StringAttribute{
+							MarkdownDescription: "How the role is granted: `direct` when it is bound to the user, `group:<name>` when it is bound to a group of the user",
+							Computed:            true,
+						},
+						"scopes": schema.ListAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "Sorted scopes of the role",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *EffectiveScopesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data EffectiveScopesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_effective_scopes", data.Timeouts)
+	defer cancel()
+
+	tenant := data.Tenant.ValueString()
+	userID := data.UserID.ValueString()
+	roles, err := listAll[readRoleResponse](ctx, d.providerData, tenantRolesPath(tenant), nil)
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "list roles", err)
+		return
+	}
+
+	grants, err := d.readGrants(ctx, tenant, userID, roles)
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "read role bindings", err)
+		return
+	}
+
+	data.Roles = []EffectiveRoleModel{}
+	effective := map[string]bool{}
+	for i, role := range roles {
+		scopes := role.Permissions
+		if scopes == nil {
+			scopes = role.Scopes
+		}
+		scopes = append([]string{}, scopes...)
+		sort.Strings(scopes)
+		scopesValue, diags := types.ListValueFrom(ctx, types.StringType, scopes)
+		resp.Diagnostics.Append(diags...)
+
+		sources := grants[i].groups
+		if grants[i].direct {
+			sources = append([]string{effectiveRoleSourceDirect}, sources...)
+		}
+		for _, source := range sources {
+			data.Roles = append(data.Roles, EffectiveRoleModel{
+				Name:   types.StringValue(role.Name),
+				Source: types.StringValue(source),
+				Scopes: scopesValue,
+			})
+		}
+		if len(sources) > 0 {
+			for _, scope := range scopes {
+				effective[scope] = true
+			}
+		}
+	}
+	sort.SliceStable(data.Roles, func(i, j int) bool {
+		return data.Roles[i].Name.ValueString() < data.Roles[j].Name.ValueString()
+	})
+
+	scopes := make([]string, 0, len(effective))
+	for scope := range effective {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	scopesValue, diags := types.ListValueFrom(ctx, types.StringType, scopes)
+	resp.Diagnostics.Append(diags...)
+	data.Scopes = scopesValue
+
+	tflog.Trace(ctx, "read effective scopes data source", map[string]interface{}{
+		"roles":  len(data.Roles),
+		"scopes": len(scopes),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// readGrants returns the grants of every role of roles to the user userID of
+// tenant. authproxy only lists bindings per role, so it lists the bindings of
+// every role, then checks the membership of the user in every group bound to
+// any of them. The sources of a grant are sorted, groups by name.
+func (d *EffectiveScopesDataSource) readGrants(ctx context.Context, tenant string, userID string, roles []readRoleResponse) ([]roleGrants, error) {
+	bindings := make([][]roleBindingResponse, len(roles))
+	err := d.providerData.forEachConcurrently(ctx, len(roles), func(ctx context.Context, i int) error {
+		var err error
+		bindings[i], err = listAll[roleBindingResponse](ctx, d.providerData, roleBindingsPath(tenant, roles[i].Name), nil)
+		if err != nil {
+			return fmt.Errorf("role %q: %w", roles[i].Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []string
+	bound := map[string]bool{}
+	for _, roleBindings := range bindings {
+		for _, binding := range roleBindings {
+			if binding.PrincipalType == "group" && !bound[binding.PrincipalName] {
+				bound[binding.PrincipalName] = true
+				groups = append(groups, binding.PrincipalName)
+			}
+		}
+	}
+
+	member := make([]bool, len(groups))
+	err = d.providerData.forEachConcurrently(ctx, len(groups), func(ctx context.Context, i int) error {
+		err := d.doJSON(ctx, "GET", groupMemberPath(tenant, groups[i], userID), nil, nil)
+		if isStatus(err, http.StatusNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("group %q: %w", groups[i], err)
+		}
+		member[i] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	memberOf := map[string]bool{}
+	for i, group := range groups {
+		memberOf[group] = member[i]
+	}
+
+	grants := make([]roleGrants, len(roles))
+	for i, roleBindings := range bindings {
+		for _, binding := range roleBindings {
+			switch {
+			case binding.PrincipalType == "user" && binding.PrincipalID == userID:
+				grants[i].direct = true
+			case binding.PrincipalType == "group" && memberOf[binding.PrincipalName]:
+				grants[i].groups = append(grants[i].groups, effectiveRoleSourceGroupPrefix+binding.PrincipalName)
+			}
+		}
+		sort.Strings(grants[i].groups)
+	}
+	return grants, nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccEffectiveScopesServer serves a tenant in which alice (u1) is granted
+// viewer directly and through the ops group, admin through the ops group and
+// billing only through the finance group she is not a member of.
+func testAccEffectiveScopesServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants/acme/roles", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[
+			{"id":"00000000-0000-4000-8000-000000000001","name":"viewer","tenant":"acme","scopes":["deployments:read","billing:read"]},
+			{"id":"00000000-0000-4000-8000-000000000002","name":"admin","tenant":"acme","scopes":["deployments:write","deployments:read"]},
+			{"id":"00000000-0000-4000-8000-000000000003","name":"billing","tenant":"acme","scopes":["billing:write"]}
+		]}`)
+	})
+	mux.HandleFunc("/tenants/acme/roles/viewer/bindings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"items":[
+				{"principal_type":"user","principal_id":"u2","principal_name":"bob"},
+				{"principal_type":"group","principal_id":"g1","principal_name":"ops"}
+			],"next_cursor":"2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"principal_type":"user","principal_id":"u1","principal_name":"alice"}]}`)
+	})
+	mux.HandleFunc("/tenants/acme/roles/admin/bindings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[{"principal_type":"group","principal_id":"g1","principal_name":"ops"}]}`)
+	})
+	mux.HandleFunc("/tenants/acme/roles/billing/bindings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[
+			{"principal_type":"group","principal_id":"g2","principal_name":"finance"},
+			{"principal_type":"service_account","principal_id":"u1","principal_name":"ci"}
+		]}`)
+	})
+	mux.HandleFunc("/tenants/acme/groups/ops/members/u1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/tenants/acme/groups/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"not a member"}`, http.StatusNotFound)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestAccEffectiveScopesDataSource(t *testing.T) {
+	server := testAccEffectiveScopesServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_effective_scopes" "alice" {
+  tenant  = "acme"
+  user_id = "u1"
+}
+
+data "authproxy_effective_scopes" "nobody" {
+  tenant  = "acme"
+  user_id = "u9"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "scopes.#", "3"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "scopes.0", "billing:read"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "scopes.1", "deployments:read"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "scopes.2", "deployments:write"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "roles.#", "3"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "roles.0.name", "admin"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "roles.0.source", "group:ops"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "roles.0.scopes.0", "deployments:read"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "roles.1.name", "viewer"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "roles.1.source", "direct"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "roles.2.name", "viewer"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.alice", "roles.2.source", "group:ops"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.nobody", "scopes.#", "0"),
+					resource.TestCheckResourceAttr("data.authproxy_effective_scopes.nobody", "roles.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestEffectiveScopesDataSource_failedRoles(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants/acme/roles", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[
+			{"id":"00000000-0000-4000-8000-000000000001","name":"viewer","tenant":"acme","scopes":["deployments:read"]},
+			{"id":"00000000-0000-4000-8000-000000000002","name":"admin","tenant":"acme","scopes":["deployments:write"]},
+			{"id":"00000000-0000-4000-8000-000000000003","name":"billing","tenant":"acme","scopes":["billing:write"]}
+		]}`)
+	})
+	mux.HandleFunc("/tenants/acme/roles/viewer/bindings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[]}`)
+	})
+	mux.HandleFunc("/tenants/acme/roles/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
+	})
+	server := httptest.NewServer(testserver.ValidateRequests(t, mux))
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewEffectiveScopesDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"tenant":  tftypes.NewValue(tftypes.String, "acme"),
+		"user_id": tftypes.NewValue(tftypes.String, "u1"),
+	})
+
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary() != "Permission Denied" {
+		t.Fatalf("expected a single Permission Denied error, got %v", resp.Diagnostics)
+	}
+	detail := resp.Diagnostics[0].Detail()
+	if !strings.Contains(detail, `role "admin"`) || !strings.Contains(detail, `role "billing"`) || strings.Contains(detail, `role "viewer"`) {
+		t.Errorf("expected the error to name the roles whose bindings failed, got %q", detail)
+	}
+}
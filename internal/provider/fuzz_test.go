@@ -0,0 +1,259 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// The fuzz tests below feed authproxy responses the provider does not expect,
+// such as truncated, deeply nested or type-confused JSON, into the code
+// decoding them. A panic there takes down the whole plugin, so anything the
+// server answers must end up in diagnostics instead. Inputs that once failed
+// are kept in testdata/fuzz and replayed by go test. Run a fuzz test with:
+//
+//	go test ./internal/provider -run '^$' -fuzz FuzzResourceResponses
+
+// testFuzzBodies are malformed bodies seeding every fuzz test next to the
+// contract fixtures.
+var testFuzzBodies = []string{
+	``,
+	`null`,
+	`{`,
+	`{"id":`,
+	`[]`,
+	`[{"id":"a"},`,
+	`{"items":null,"next_cursor":""}`,
+	`{"items":[{"id":"a"}],"next_cursor":"a"}`,
+	`{"id":1,"name":true,"scopes":"billing:read"}`,
+	`{"id":"a","name":"b","scopes":[null,1,{}]}`,
+	`{"features":[],"status":{}}`,
+	`[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]`,
+	`"\ud800"`,
+}
+
+// testFuzzServer answers every request with the status and body of the
+// current fuzz input.
+type testFuzzServer struct {
+	mu     sync.Mutex
+	status int
+	body   []byte
+}
+
+func (s *testFuzzServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(s.status)
+	_, _ = w.Write(s.body)
+}
+
+// answer makes the server answer with status and body from now on.
+func (s *testFuzzServer) answer(status int, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+	s.body = body
+}
+
+// newTestFuzzServer starts a testFuzzServer for f, seeding f with the
+// contract fixtures and testFuzzBodies as successful and failed responses.
+func newTestFuzzServer(f *testing.F) (*testFuzzServer, *httptest.Server) {
+	for _, fixture := range contractFixtures(f) {
+		f.Add(fixture.Status, []byte(fixture.Body))
+	}
+	for _, body := range testFuzzBodies {
+		f.Add(http.StatusOK, []byte(body))
+		f.Add(http.StatusConflict, []byte(body))
+	}
+
+	backend := &testFuzzServer{status: http.StatusOK}
+	server := httptest.NewServer(backend)
+	f.Cleanup(server.Close)
+	return backend, server
+}
+
+// testDecodeResponse returns a decoder unmarshalling a response body into T
+// and handing it to set, the way resources copy responses into their models.
+func testDecodeResponse[T any](set func(ctx context.Context, response T)) func(ctx context.Context, body []byte) error {
+	return func(ctx context.Context, body []byte) error {
+		var response T
+		if err := json.Unmarshal(body, &response); err != nil {
+			return err
+		}
+		set(ctx, response)
+		return nil
+	}
+}
+
+// testResponseDecoders decode the responses of the resources into empty
+// models, keyed by resource.
+var testResponseDecoders = map[string]func(ctx context.Context, body []byte) error{
+	"access_rule": testDecodeResponse(func(ctx context.Context, rule accessRuleResponse) {
+		(&AccessRuleResourceModel{}).setAccessRule(ctx, rule)
+	}),
+	"audit_sink": testDecodeResponse(func(ctx context.Context, sink auditSinkResponse) {
+		(&AuditSinkResourceModel{}).setAuditSink(ctx, sink)
+	}),
+	"branding": testDecodeResponse(func(ctx context.Context, branding brandingResponse) {
+		(&BrandingResourceModel{}).setBranding(branding)
+	}),
+	"certificate": testDecodeResponse(func(ctx context.Context, certificate certificateResponse) {
+		(&CertificateResourceModel{}).setCertificate(certificate)
+	}),
+	"claim_mapping": testDecodeResponse(func(ctx context.Context, mapping claimMappingResponse) {
+		(&ClaimMappingResourceModel{}).setClaimMapping(ctx, mapping)
+	}),
+	"cors_policy": testDecodeResponse(func(ctx context.Context, policy corsPolicy) {
+		(&CORSPolicyResourceModel{}).setCORSPolicy(ctx, policy)
+	}),
+	"global_role": testDecodeResponse(func(ctx context.Context, role globalRoleResponse) {
+		(&GlobalRoleResourceModel{}).setRole(ctx, role)
+	}),
+	"header_policy": testDecodeResponse(func(ctx context.Context, policy headerPolicy) {
+		(&HeaderPolicyResourceModel{}).setHeaderPolicy(ctx, policy)
+	}),
+	"m2m_grant": testDecodeResponse(func(ctx context.Context, grant m2mGrantResponse) {
+		(&M2MGrantResourceModel{}).setGrant(ctx, grant)
+	}),
+	"oidc_identity_provider": testDecodeResponse(func(ctx context.Context, idp oidcIdentityProviderResponse) {
+		(&OIDCIdentityProviderResourceModel{}).setIdentityProvider(ctx, idp)
+	}),
+	"password_policy": testDecodeResponse(func(ctx context.Context, policy passwordPolicyResponse) {
+		(&PasswordPolicyResourceModel{}).setPasswordPolicy(policy)
+	}),
+	"policy": testDecodeResponse(func(ctx context.Context, policy policyResponse) {
+		(&PolicyResourceModel{}).setPolicy(policy)
+	}),
+	"rate_limit": testDecodeResponse(func(ctx context.Context, rateLimit rateLimitResponse) {
+		(&RateLimitResourceModel{}).setRateLimit(ctx, rateLimit)
+	}),
+	"role_binding": testDecodeResponse(func(ctx context.Context, binding roleBindingResponse) {
+		(&RoleBindingResourceModel{}).setBinding(binding)
+	}),
+	"route": testDecodeResponse(func(ctx context.Context, route routeResponse) {
+		(&RouteResourceModel{}).setRoute(ctx, route)
+	}),
+	"saml_identity_provider": testDecodeResponse(func(ctx context.Context, idp samlIdentityProviderResponse) {
+		(&SAMLIdentityProviderResourceModel{}).setIdentityProvider(ctx, idp)
+	}),
+	"scim_config": testDecodeResponse(func(ctx context.Context, config scimConfigResponse) {
+		(&SCIMConfigResourceModel{}).setSCIMConfig(ctx, config)
+	}),
+	"scope": testDecodeResponse(func(ctx context.Context, scope scopeResponse) {
+		(&ScopeResourceModel{}).setScope(scope)
+	}),
+	"service": testDecodeResponse(func(ctx context.Context, service serviceResponse) {
+		(&ServiceResourceModel{}).setService(service)
+	}),
+	"session_policy": testDecodeResponse(func(ctx context.Context, policy sessionPolicyResponse) {
+		(&SessionPolicyResourceModel{}).setSessionPolicy(policy)
+	}),
+	"smtp_settings": testDecodeResponse(func(ctx context.Context, settings smtpSettingsResponse) {
+		(&SMTPSettingsResourceModel{}).setSMTPSettings(settings)
+	}),
+	"tenant_membership": testDecodeResponse(func(ctx context.Context, member tenantMemberResponse) {
+		(&TenantMembershipResourceModel{}).setMember(member)
+	}),
+	"tenant_settings": testDecodeResponse(func(ctx context.Context, settings tenantSettingsResponse) {
+		(&TenantSettingsResourceModel{}).setTenantSettings(ctx, settings)
+	}),
+	"token_exchange_policy": testDecodeResponse(func(ctx context.Context, policy tokenExchangePolicyResponse) {
+		(&TokenExchangePolicyResourceModel{}).setPolicy(ctx, policy)
+	}),
+	"user": testDecodeResponse(func(ctx context.Context, user userResponse) {
+		(&UserResourceModel{}).setUser(user)
+	}),
+}
+
+// FuzzResourceResponses decodes arbitrary responses through doJSON into the
+// response structs of the resources and copies them into their models.
+func FuzzResourceResponses(f *testing.F) {
+	backend, server := newTestFuzzServer(f)
+
+	f.Fuzz(func(t *testing.T, status int, body []byte) {
+		if status < 200 || status > 599 {
+			t.Skip("not a final HTTP status code")
+		}
+		backend.answer(status, body)
+		ctx := context.Background()
+
+		var raw json.RawMessage
+		if err := testProviderData(server.URL).doJSON(ctx, "GET", "/", nil, &raw); err != nil {
+			var diags diag.Diagnostics
+			addClientError(&diags, "fuzz", err)
+			return
+		}
+		for _, decode := range testResponseDecoders {
+			_ = decode(ctx, raw)
+		}
+	})
+}
+
+// FuzzErrorResponses hands arbitrary error responses to the helpers turning
+// them into diagnostics.
+func FuzzErrorResponses(f *testing.F) {
+	_, _ = newTestFuzzServer(f)
+
+	f.Fuzz(func(t *testing.T, status int, body []byte) {
+		err := &apiError{Method: "POST", Path: "/tenants/acme", StatusCode: status, Body: string(body)}
+
+		var diags diag.Diagnostics
+		addClientError(&diags, "fuzz", err)
+		(&RouteResourceModel{}).addConflictError(&diags, err)
+		(&TokenExchangePolicyResourceModel{}).addConflictError(&diags, err)
+		(&TenantAliasResourceModel{}).addConflictError(&diags, err)
+		(&ClaimMappingResourceModel{}).addMissingRolesError(&diags, err)
+		(&M2MGrantResourceModel{}).addUnknownScopesError(&diags, err)
+	})
+}
+
+// testFuzzDataSources are the data sources FuzzDataSourceReads reads, along
+// with the configuration values they need.
+var testFuzzDataSources = map[string]struct {
+	dataSource func() datasource.DataSource
+	config     map[string]tftypes.Value
+}{
+	"api_keys":           {dataSource: NewAPIKeysDataSource, config: map[string]tftypes.Value{"tenant": tftypes.NewValue(tftypes.String, "acme")}},
+	"audit_events":       {dataSource: NewAuditEventsDataSource, config: map[string]tftypes.Value{"limit": tftypes.NewValue(tftypes.Number, 3)}},
+	"identity_providers": {dataSource: NewIdentityProvidersDataSource, config: map[string]tftypes.Value{"tenant": tftypes.NewValue(tftypes.String, "acme")}},
+	"role_bindings":      {dataSource: NewRoleBindingsDataSource, config: map[string]tftypes.Value{"tenant": tftypes.NewValue(tftypes.String, "acme"), "role": tftypes.NewValue(tftypes.String, "admin")}},
+	"scopes":             {dataSource: NewScopesDataSource},
+	"server_info":        {dataSource: NewServerInfoDataSource},
+	"tenant":             {dataSource: NewTenantDataSource, config: map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "acme"), "include_roles": tftypes.NewValue(tftypes.Bool, true)}},
+	"tenant_search":      {dataSource: NewTenantSearchDataSource},
+	"tenant_usage":       {dataSource: NewTenantUsageDataSource, config: map[string]tftypes.Value{"tenant": tftypes.NewValue(tftypes.String, "acme")}},
+	"token_info":         {dataSource: NewTokenInfoDataSource, config: map[string]tftypes.Value{"token": tftypes.NewValue(tftypes.String, "secret")}},
+	"user":               {dataSource: NewUserDataSource, config: map[string]tftypes.Value{"tenant": tftypes.NewValue(tftypes.String, "acme"), "username": tftypes.NewValue(tftypes.String, "alice")}},
+	"users":              {dataSource: NewUsersDataSource, config: map[string]tftypes.Value{"tenant": tftypes.NewValue(tftypes.String, "acme")}},
+	"webhooks":           {dataSource: NewWebhooksDataSource, config: map[string]tftypes.Value{"tenant": tftypes.NewValue(tftypes.String, "acme")}},
+	"whoami":             {dataSource: NewWhoamiDataSource},
+}
+
+// FuzzDataSourceReads reads every data source from a server answering each
+// request with the same arbitrary response.
+func FuzzDataSourceReads(f *testing.F) {
+	backend, server := newTestFuzzServer(f)
+
+	f.Fuzz(func(t *testing.T, status int, body []byte) {
+		if status < 200 || status > 599 {
+			t.Skip("not a final HTTP status code")
+		}
+		backend.answer(status, body)
+
+		for _, testCase := range testFuzzDataSources {
+			testDataSourceRead(t, testCase.dataSource(), testProviderData(server.URL), testCase.config)
+		}
+	})
+}
@@ -0,0 +1,399 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &GenericResource{}
+var _ resource.ResourceWithImportState = &GenericResource{}
+
+// genericPathPattern is the format of the paths of generic objects, absolute
+// paths of the admin API without query or fragment.
+var genericPathPattern = regexp.MustCompile(`^/[^?#]*$`)
+
+func NewGenericResource() resource.Resource {
+	return &GenericResource{}
+}
+
+// GenericResource manages an arbitrary JSON object of the admin API, for
+// endpoints the provider does not model yet.
+type GenericResource struct {
+	BaseResource
+}
+
+// GenericResourceModel describes the resource data model.
+type GenericResourceModel struct {
+	ID           types.String      `tfsdk:"id"`
+	Path         types.String      `tfsdk:"path"`
+	CreateMethod types.String      `tfsdk:"create_method"`
+	UpdateMethod types.String      `tfsdk:"update_method"`
+	Body         jsonDocumentValue `tfsdk:"body"`
+	IDAttribute  types.String      `tfsdk:"id_attribute"`
+	ReadPath     types.String      `tfsdk:"read_path"`
+	DeletePath   types.String      `tfsdk:"delete_path"`
+}
+
+func (r *GenericResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_generic"
+}
+
+func (r *GenericResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	pathValidators := []validator.String{
+		stringMatchesValidator{
+			pattern:     genericPathPattern,
+			description: `value must be an absolute path without query or fragment such as "/tenants/acme/feature-flags/{id}"`,
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Arbitrary JSON object of the admin API, for endpoints the provider does not model yet. " +
+			"Prefer the dedicated resources where they exist, they validate their attributes and know how authproxy stores them",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the object, taken from the create response with `id_attribute`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path of the collection the object is created in, such as `/tenants/acme/feature-flags`. Changing it recreates the object",
+				Required:            true,
+				Validators:          pathValidators,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"create_method": schema.StringAttribute{
+				MarkdownDescription: "Request method creating the object at `path`, `POST` or `PUT`. Defaults to `POST`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(http.MethodPost),
+				Validators: []validator.String{
+					stringOneOf(http.MethodPost, http.MethodPut),
+				},
+			},
+			"update_method": schema.StringAttribute{
+				MarkdownDescription: "Request method updating the object at `read_path`, `PUT`, `PATCH` or `POST`. Defaults to `PUT`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(http.MethodPut),
+				Validators: []validator.String{
+					stringOneOf(http.MethodPut, http.MethodPatch, http.MethodPost),
+				},
+			},
+			"body": schema.StringAttribute{
+				CustomType: jsonDocumentType{},
+				MarkdownDescription: "JSON body of the create and update requests, usually built with `jsonencode`. " +
+					"Differences in key order and whitespace are ignored. When the object is read, only the top-level keys of the body are compared, " +
+					"so attributes authproxy adds to the object do not cause drift",
+				Required: true,
+			},
+			"id_attribute": schema.StringAttribute{
+				MarkdownDescription: "Path of the identifier in the create response, keys separated by dots and list elements selected by their index such as `data.id` or `items.0.id`. " +
+					"Dots in keys are escaped with a backslash. Defaults to `id`",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("id"),
+			},
+			"read_path": schema.StringAttribute{
+				MarkdownDescription: "Path the object is read and updated at, `{id}` being replaced with its escaped identifier. Defaults to `path` followed by `/{id}`",
+				Optional:            true,
+				Validators:          pathValidators,
+			},
+			"delete_path": schema.StringAttribute{
+				MarkdownDescription: "Path the object is deleted at, `{id}` being replaced with its escaped identifier. Defaults to `read_path`",
+				Optional:            true,
+				Validators:          pathValidators,
+			},
+		},
+	}
+}
+
+// readPath returns the path the object is read and updated at.
+func (data *GenericResourceModel) readPath() string {
+	template := data.Path.ValueString() + "/{id}"
+	if !data.ReadPath.IsNull() {
+		template = data.ReadPath.ValueString()
+	}
+	return expandGenericPath(template, data.ID.ValueString())
+}
+
+// deletePath returns the path the object is deleted at.
+func (data *GenericResourceModel) deletePath() string {
+	if data.DeletePath.IsNull() {
+		return data.readPath()
+	}
+	return expandGenericPath(data.DeletePath.ValueString(), data.ID.ValueString())
+}
+
+// expandGenericPath replaces every {id} in template with id.
+func expandGenericPath(template string, id string) string {
+	return strings.ReplaceAll(template, "{id}", url.PathEscape(id))
+}
+
+// decodeGenericObject decodes a response of authproxy, keeping numbers as
+// they were sent so identifiers are not rounded.
+func decodeGenericObject(body []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var object interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// selectGenericID returns the identifier at selector in object, see the
+// id_attribute attribute for the syntax. Identifiers are strings or numbers.
+func selectGenericID(object interface{}, selector string) (string, error) {
+	value := object
+	for _, key := range splitGenericSelector(selector) {
+		switch container := value.(type) {
+		case map[string]interface{}:
+			element, ok := container[key]
+			if !ok {
+				return "", fmt.Errorf("the response has no %q", selector)
+			}
+			value = element
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(container) {
+				return "", fmt.Errorf("the response has no %q", selector)
+			}
+			value = container[index]
+		default:
+			return "", fmt.Errorf("the response has no %q", selector)
+		}
+	}
+
+	switch id := value.(type) {
+	case string:
+		if id == "" {
+			return "", fmt.Errorf("%q of the response is empty", selector)
+		}
+		return id, nil
+	case json.Number:
+		return id.String(), nil
+	default:
+		return "", fmt.Errorf("%q of the response is neither a string nor a number", selector)
+	}
+}
+
+// splitGenericSelector splits selector at the dots not escaped with a
+// backslash.
+func splitGenericSelector(selector string) []string {
+	var keys []string
+	var key strings.Builder
+	for i := 0; i < len(selector); i++ {
+		switch {
+		case selector[i] == '\\' && i+1 < len(selector):
+			i++
+			key.WriteByte(selector[i])
+		case selector[i] == '.':
+			keys = append(keys, key.String())
+			key.Reset()
+		default:
+			key.WriteByte(selector[i])
+		}
+	}
+	return append(keys, key.String())
+}
+
+// setGenericBody copies object, the object authproxy returned, into the body
+// of data. Only the top-level keys of a known body are kept, so attributes
+// authproxy adds such as timestamps do not show up as drift, while changes
+// to and removals of the managed ones do.
+func (data *GenericResourceModel) setGenericBody(object interface{}) error {
+	var body map[string]interface{}
+	fields, ok := object.(map[string]interface{})
+	if ok && !data.Body.IsNull() && json.Unmarshal([]byte(data.Body.ValueString()), &body) == nil && body != nil {
+		for key := range body {
+			if value, found := fields[key]; found {
+				body[key] = value
+			} else {
+				delete(body, key)
+			}
+		}
+		object = body
+	}
+
+	document, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	data.Body = jsonDocumentStringValue(string(document))
+	return nil
+}
+
+func (r *GenericResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *GenericResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var created json.RawMessage
+	err := r.doJSON(ctx, data.CreateMethod.ValueString(), data.Path.ValueString(), json.RawMessage(data.Body.ValueString()), &created)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create object", err)
+		return
+	}
+
+	object, err := decodeGenericObject(created)
+	if err == nil {
+		var id string
+		id, err = selectGenericID(object, data.IDAttribute.ValueString())
+		data.ID = types.StringValue(id)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Missing Object Identifier",
+			fmt.Sprintf("Authproxy created the object at %s, but its identifier could not be read from the response: %s. "+
+				"Set id_attribute to the path of the identifier and remove the object manually, Terraform does not track it.", data.Path.ValueString(), err),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "created a generic resource", map[string]interface{}{
+		"path": data.Path.ValueString(),
+		"id":   data.ID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GenericResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *GenericResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var read json.RawMessage
+	err := r.doJSON(ctx, "GET", data.readPath(), nil, &read)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "object no longer exists, removing it from state", map[string]interface{}{
+			"path": data.readPath(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read object", err)
+		return
+	}
+
+	object, err := decodeGenericObject(read)
+	if err == nil {
+		err = data.setGenericBody(object)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unexpected Object Response",
+			fmt.Sprintf("Authproxy answered the read of %s with an invalid JSON document: %s", data.readPath(), err),
+		)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GenericResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *GenericResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The body is kept as planned, values authproxy normalizes show up when
+	// the object is read next.
+	err := r.doJSON(ctx, data.UpdateMethod.ValueString(), data.readPath(), json.RawMessage(data.Body.ValueString()), nil)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update object", err)
+		return
+	}
+
+	tflog.Trace(ctx, "updated a generic resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GenericResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *GenericResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", data.deletePath(), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete object", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a generic resource")
+}
+
+// ImportState imports objects by their path followed by their identifier,
+// such as "/tenants/acme/feature-flags/dark-mode". The whole object read from
+// authproxy becomes the body, and read_path and delete_path take their
+// defaults until they are configured.
+func (r *GenericResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	index := strings.LastIndex(req.ID, "/")
+	if index <= 0 || index == len(req.ID)-1 || !genericPathPattern.MatchString(req.ID) {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form /path/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	id, err := url.PathUnescape(req.ID[index+1:])
+	if err != nil {
+		id = req.ID[index+1:]
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("path"), req.ID[:index])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("create_method"), http.MethodPost)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("update_method"), http.MethodPut)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id_attribute"), "id")...)
+}
@@ -0,0 +1,242 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testGenericBackend is an in-memory stand-in for an endpoint the provider
+// does not model, the feature flags of the "acme" tenant. It wraps created
+// flags in a "data" envelope and adds attributes of its own to them, like
+// authproxy adds timestamps to the objects it stores.
+type testGenericBackend struct {
+	mu     sync.Mutex
+	flags  map[string]map[string]interface{}
+	nextID int
+}
+
+func newTestGenericBackend() *testGenericBackend {
+	return &testGenericBackend{flags: map[string]map[string]interface{}{}}
+}
+
+func (b *testGenericBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	const prefix = "/tenants/acme/feature-flags"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		b.nextID++
+		id = fmt.Sprint(b.nextID)
+		body["id"] = b.nextID
+		body["created_at"] = "2023-07-01T12:00:00Z"
+		b.flags[id] = body
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": body})
+	case id != "" && b.flags[id] == nil:
+		http.NotFound(w, r)
+	case r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(b.flags[id])
+	case r.Method == http.MethodPatch:
+		for key, value := range body {
+			b.flags[id][key] = value
+		}
+		_ = json.NewEncoder(w).Encode(b.flags[id])
+	case r.Method == http.MethodDelete:
+		delete(b.flags, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// flag returns the stored flag with the given id, or nil.
+func (b *testGenericBackend) flag(id string) map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flags[id]
+}
+
+func TestAccGenericResource(t *testing.T) {
+	backend := newTestGenericBackend()
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if len(backend.flags) != 0 {
+				return fmt.Errorf("expected every feature flag to be deleted, %d left", len(backend.flags))
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, the attributes authproxy adds must
+			// not produce a diff
+			{
+				Config: testAccProviderConfig(server.URL) + testAccGenericResourceConfig(true),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_generic.test", "id", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_generic.test", "create_method", "POST"),
+					resourcetest.TestCheckResourceAttr("authproxy_generic.test", "update_method", "PATCH"),
+				),
+			},
+			// ImportState testing, the imported body is the whole object
+			{
+				ResourceName:            "authproxy_generic.test",
+				ImportState:             true,
+				ImportStateId:           "/tenants/acme/feature-flags/1",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"body", "id_attribute", "update_method"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccGenericResourceConfig(false),
+				Check: func(s *terraform.State) error {
+					if flag := backend.flag("1"); flag["enabled"] != false {
+						return fmt.Errorf("expected the feature flag to be disabled, got: %v", flag)
+					}
+					return nil
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccGenericResourceConfig(enabled bool) string {
+	return fmt.Sprintf(`
+resource "authproxy_generic" "test" {
+  path          = "/tenants/acme/feature-flags"
+  update_method = "PATCH"
+  id_attribute  = "data.id"
+  body = jsonencode({
+    name    = "dark-mode"
+    enabled = %t
+  })
+}
+`, enabled)
+}
+
+func TestGenericResource(t *testing.T) {
+	backend := newTestGenericBackend()
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	provider, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(server.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	config := map[string]tftypes.Value{
+		"path":          str("/tenants/acme/feature-flags"),
+		"update_method": str("PATCH"),
+		"id_attribute":  str("data.id"),
+		"read_path":     str("/tenants/acme/feature-flags/{id}"),
+		"body":          str(`{"name": "dark-mode", "enabled": true}`),
+	}
+	state := testProviderApply(t, provider, "authproxy_generic", nil, config)
+	if !state["id"].Equal(str("1")) || backend.flag("1") == nil {
+		t.Fatalf("expected the feature flag 1 to be created, got %v", state)
+	}
+
+	// The created_at authproxy adds is ignored, the configured body is kept.
+	state = testProviderRefresh(t, provider, "authproxy_generic", state)
+	if !state["body"].Equal(config["body"]) {
+		t.Errorf("expected the configured body to be kept, got %s", state["body"])
+	}
+
+	// Changes to the managed attributes outside of Terraform are drift.
+	backend.mu.Lock()
+	backend.flags["1"]["enabled"] = false
+	backend.mu.Unlock()
+	state = testProviderRefresh(t, provider, "authproxy_generic", state)
+	if !state["body"].Equal(str(`{"enabled":false,"name":"dark-mode"}`)) {
+		t.Errorf("expected the drift to show up in the body, got %s", state["body"])
+	}
+
+	state = testProviderApply(t, provider, "authproxy_generic", state, config)
+	if flag := backend.flag("1"); flag["enabled"] != true || flag["created_at"] == nil {
+		t.Errorf("expected the feature flag to be patched, got %v", flag)
+	}
+
+	// Objects deleted outside of Terraform are removed from the state.
+	backend.mu.Lock()
+	delete(backend.flags, "1")
+	backend.mu.Unlock()
+	if refreshed := testProviderRefresh(t, provider, "authproxy_generic", state); len(refreshed) != 0 {
+		t.Errorf("expected the deleted feature flag to be removed from the state, got %v", refreshed)
+	}
+
+	// Responses without an identifier fail the apply.
+	config["id_attribute"] = str("data.uuid")
+	applyResp := testProviderApplyResponse(t, provider, "authproxy_generic", nil, config)
+	if len(applyResp.Diagnostics) != 1 || applyResp.Diagnostics[0].Summary != "Missing Object Identifier" {
+		t.Errorf("expected a missing identifier error, got %v", applyResp.Diagnostics)
+	}
+}
+
+func TestSelectGenericID(t *testing.T) {
+	object, err := decodeGenericObject([]byte(`{
+  "id": 12345678901234567890,
+  "name": "dark-mode",
+  "data": {"id": "abc", "empty": "", "enabled": true},
+  "items": [{"id": "first"}],
+  "a.b": "dotted"
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for selector, expected := range map[string]string{
+		"id":         "12345678901234567890",
+		"name":       "dark-mode",
+		"data.id":    "abc",
+		"items.0.id": "first",
+		`a\.b`:       "dotted",
+	} {
+		if id, err := selectGenericID(object, selector); err != nil || id != expected {
+			t.Errorf("expected %q to select %q, got %q and error %v", selector, expected, id, err)
+		}
+	}
+
+	for _, selector := range []string{"", "uuid", "data", "data.empty", "data.enabled", "items.1.id", "items.x", "name.x"} {
+		if id, err := selectGenericID(object, selector); err == nil {
+			t.Errorf("expected %q to fail, got %q", selector, id)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &GlobalRoleResource{}
+var _ resource.ResourceWithImportState = &GlobalRoleResource{}
+
+func NewGlobalRoleResource() resource.Resource {
+	return &GlobalRoleResource{}
+}
+
+// GlobalRoleResource defines the resource implementation. Global roles are
+// not scoped to a tenant and grant their scopes proxy-wide.
+type GlobalRoleResource struct {
+	BaseResource
+}
+
+// GlobalRoleResourceModel describes the resource data model.
+type GlobalRoleResourceModel struct {
+	ID     types.String `tfsdk:"id"`
+	Name   types.String `tfsdk:"name"`
+	Scopes types.Set    `tfsdk:"scopes"`
+}
+
+type globalRoleCreateRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type globalRoleUpdateRequest struct {
+	Scopes []string `json:"scopes"`
+}
+
+type globalRoleResponse struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// globalRolesPath is the path of the collection of global roles.
+const globalRolesPath = "/roles"
+
+// globalRolePath returns the path of the global role named name.
+func globalRolePath(name string) string {
+	return globalRolesPath + "/" + url.PathEscape(name)
+}
+
+func (r *GlobalRoleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_global_role"
+}
+
+func (r *GlobalRoleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Proxy-wide role for platform operators, granting its scopes regardless of tenant",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the role. Changing it recreates the role",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"scopes": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "The scopes of the role",
+				Required:            true,
+				Validators: []validator.Set{
+					scopeNames(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// scopes returns the configured scopes of data, sorted.
+func (data *GlobalRoleResourceModel) scopes(ctx context.Context) ([]string, diag.Diagnostics) {
+	scopes := []string{}
+	diags := data.Scopes.ElementsAs(ctx, &scopes, false)
+	sort.Strings(scopes)
+
+	return scopes, diags
+}
+
+// setRole copies the attributes authproxy returned for a global role into
+// data.
+func (data *GlobalRoleResourceModel) setRole(ctx context.Context, role globalRoleResponse) diag.Diagnostics {
+	data.ID = types.StringValue(role.ID)
+	data.Name = types.StringValue(role.Name)
+
+	if role.Scopes == nil {
+		role.Scopes = []string{}
+	}
+	scopes, diags := types.SetValueFrom(ctx, types.StringType, role.Scopes)
+	data.Scopes = scopes
+
+	return diags
+}
+
+func (r *GlobalRoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *GlobalRoleResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scopes, diags := data.scopes(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var role globalRoleResponse
+	err := r.doJSON(ctx, "POST", globalRolesPath, globalRoleCreateRequest{
+		Name:   data.Name.ValueString(),
+		Scopes: scopes,
+	}, &role)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create global role", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRole(ctx, role)...)
+
+	tflog.Trace(ctx, "created a global role resource", map[string]interface{}{
+		"id": role.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GlobalRoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *GlobalRoleResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var role globalRoleResponse
+	err := r.doJSON(ctx, "GET", globalRolePath(data.Name.ValueString()), nil, &role)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "global role no longer exists, removing it from state", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read global role", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRole(ctx, role)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GlobalRoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *GlobalRoleResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scopes, diags := data.scopes(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var role globalRoleResponse
+	err := r.doJSON(ctx, "PATCH", globalRolePath(data.Name.ValueString()), globalRoleUpdateRequest{
+		Scopes: scopes,
+	}, &role)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update global role", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRole(ctx, role)...)
+
+	tflog.Trace(ctx, "updated a global role resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GlobalRoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *GlobalRoleResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", globalRolePath(data.Name.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete global role", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a global role resource")
+}
+
+func (r *GlobalRoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
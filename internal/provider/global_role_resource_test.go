@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccGlobalRoleBackend is an in-memory stand-in for the global role
+// endpoints, keyed by role name.
+type testAccGlobalRoleBackend struct {
+	mu     sync.Mutex
+	roles  map[string]globalRoleResponse
+	nextID int
+}
+
+func (b *testAccGlobalRoleBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, globalRolesPath) {
+		http.NotFound(w, r)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, globalRolesPath), "/")
+
+	switch {
+	case name == "" && r.Method == http.MethodPost:
+		var body globalRoleCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := b.roles[body.Name]; ok {
+			http.Error(w, "role already exists", http.StatusConflict)
+			return
+		}
+		b.nextID++
+		b.roles[body.Name] = globalRoleResponse{ID: fmt.Sprintf("r%d", b.nextID), Name: body.Name, Scopes: body.Scopes}
+		_ = json.NewEncoder(w).Encode(b.roles[body.Name])
+	case name != "" && r.Method == http.MethodGet:
+		role, ok := b.roles[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(role)
+	case name != "" && r.Method == http.MethodPatch:
+		role, ok := b.roles[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var body globalRoleUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		role.Scopes = body.Scopes
+		b.roles[name] = role
+		_ = json.NewEncoder(w).Encode(role)
+	case name != "" && r.Method == http.MethodDelete:
+		if _, ok := b.roles[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.roles, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (b *testAccGlobalRoleBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.roles) != 0 {
+		return fmt.Errorf("expected every global role to be deleted, %d left", len(b.roles))
+	}
+	return nil
+}
+
+func TestAccGlobalRoleResource(t *testing.T) {
+	backend := &testAccGlobalRoleBackend{roles: map[string]globalRoleResponse{}}
+	server := testBackendServer(t, backend, globalRolesPath)
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccGlobalRoleResourceConfig(`["tenants:read", "tenants:write"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_global_role.test", "id", "r1"),
+					resourcetest.TestCheckResourceAttr("authproxy_global_role.test", "name", "platform-operator"),
+					resourcetest.TestCheckResourceAttr("authproxy_global_role.test", "scopes.#", "2"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_global_role.test", "scopes.*", "tenants:read"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_global_role.test", "scopes.*", "tenants:write"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_global_role.test",
+				ImportState:       true,
+				ImportStateId:     "platform-operator",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccGlobalRoleResourceConfig(`["tenants:read", "audit:read"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_global_role.test", "id", "r1"),
+					resourcetest.TestCheckResourceAttr("authproxy_global_role.test", "scopes.#", "2"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						scopes := append([]string(nil), backend.roles["platform-operator"].Scopes...)
+						sort.Strings(scopes)
+						if strings.Join(scopes, ",") != "audit:read,tenants:read" {
+							return fmt.Errorf("expected the scopes to be replaced in authproxy, got %v", scopes)
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccGlobalRoleResource_validation(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			// Global roles are not scoped to a tenant
+			{
+				Config: testAccProviderConfig("http://127.0.0.1:1") + `
+resource "authproxy_global_role" "test" {
+  name   = "platform-operator"
+  tenant = "acme"
+  scopes = ["tenants:read"]
+}
+`,
+				ExpectError: regexp.MustCompile(`An argument named "tenant" is not expected here`),
+			},
+			{
+				Config:      testAccProviderConfig("http://127.0.0.1:1") + testAccGlobalRoleResourceConfig(`["Tenants Read"]`),
+				ExpectError: regexp.MustCompile(`values must be scope names`),
+			},
+		},
+	})
+}
+
+func testAccGlobalRoleResourceConfig(scopes string) string {
+	return fmt.Sprintf(`
+resource "authproxy_global_role" "test" {
+  name   = "platform-operator"
+  scopes = %s
+}
+`, scopes)
+}
@@ -0,0 +1,319 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &GroupMembersResource{}
+var _ resource.ResourceWithImportState = &GroupMembersResource{}
+
+func NewGroupMembersResource() resource.Resource {
+	return &GroupMembersResource{}
+}
+
+// GroupMembersResource defines the resource implementation.
+type GroupMembersResource struct {
+	BaseResource
+}
+
+// GroupMembersResourceModel describes the resource data model.
+type GroupMembersResourceModel struct {
+	ID        types.String    `tfsdk:"id"`
+	Tenant    tenantNameValue `tfsdk:"tenant"`
+	Group     types.String    `tfsdk:"group"`
+	UserIDs   types.Set       `tfsdk:"user_ids"`
+	Exclusive types.Bool      `tfsdk:"exclusive"`
+}
+
+func (r *GroupMembersResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_members"
+}
+
+func (r *GroupMembersResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Manages a set of members of a group. By default members added outside of Terraform are left alone, with `exclusive` set they are removed",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the group of the form `tenant/group`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the group belongs to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"group": schema.StringAttribute{
+				MarkdownDescription: "Name of the group",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user_ids": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "IDs of the users that are members of the group",
+				Required:            true,
+			},
+			"exclusive": schema.BoolAttribute{
+				MarkdownDescription: "Whether `user_ids` is the complete member list, removing every other member of the group. Defaults to `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+// userIDs returns the user_ids of data, nil when they are null.
+func (data *GroupMembersResourceModel) userIDs(ctx context.Context) ([]string, diag.Diagnostics) {
+	var userIDs []string
+	if data.UserIDs.IsNull() {
+		return nil, nil
+	}
+	diags := data.UserIDs.ElementsAs(ctx, &userIDs, false)
+	return userIDs, diags
+}
+
+// members returns the IDs of the current members of the group.
+func (r *GroupMembersResource) members(ctx context.Context, data *GroupMembersResourceModel) (map[string]bool, error) {
+	members, err := listAll[groupMemberResponse](ctx, r.providerData, groupMembersPath(data.Tenant.ValueString(), data.Group.ValueString()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]bool, len(members))
+	for _, member := range members {
+		current[member.UserID] = true
+	}
+	return current, nil
+}
+
+// reconcile adds the users of desired missing from the group and removes the
+// users of managed that are no longer desired. In exclusive mode every member
+// not in desired is removed.
+func (r *GroupMembersResource) reconcile(ctx context.Context, data *GroupMembersResourceModel, desired []string, managed []string, diags *diag.Diagnostics) {
+	tenant, group := data.Tenant.ValueString(), data.Group.ValueString()
+
+	current, err := r.members(ctx, data)
+	if err != nil {
+		addClientError(diags, "list group members", err)
+		return
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, userID := range desired {
+		wanted[userID] = true
+		if current[userID] {
+			continue
+		}
+		if err := r.doJSON(ctx, "PUT", groupMemberPath(tenant, group, userID), nil, nil); err != nil {
+			addClientError(diags, "add group member", err)
+			return
+		}
+	}
+
+	remove := managed
+	if data.Exclusive.ValueBool() {
+		remove = make([]string, 0, len(current))
+		for userID := range current {
+			remove = append(remove, userID)
+		}
+	}
+	for _, userID := range remove {
+		if wanted[userID] || !current[userID] {
+			continue
+		}
+		tflog.Debug(ctx, "removing group member", map[string]interface{}{
+			"group":   group,
+			"user_id": userID,
+		})
+		err := r.doJSON(ctx, "DELETE", groupMemberPath(tenant, group, userID), nil, nil)
+		if err != nil && !isStatus(err, http.StatusNotFound) {
+			addClientError(diags, "remove group member", err)
+			return
+		}
+	}
+}
+
+func (r *GroupMembersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *GroupMembersResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired, diags := data.userIDs(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.reconcile(ctx, data, desired, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(data.Tenant.ValueString() + "/" + data.Group.ValueString())
+
+	tflog.Trace(ctx, "created a group members resource", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GroupMembersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *GroupMembersResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, err := r.members(ctx, data)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "group no longer exists, removing its members from state", map[string]interface{}{
+			"id": data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list group members", err)
+		return
+	}
+
+	managed, diags := data.userIDs(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Exclusive and freshly imported resources own every member, otherwise
+	// only the managed members that are still in the group are reported.
+	userIDs := make([]string, 0, len(current))
+	if data.Exclusive.ValueBool() || managed == nil {
+		for userID := range current {
+			userIDs = append(userIDs, userID)
+		}
+	} else {
+		for _, userID := range managed {
+			if current[userID] {
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+
+	data.UserIDs, diags = types.SetValueFrom(ctx, types.StringType, userIDs)
+	resp.Diagnostics.Append(diags...)
+	if data.Exclusive.IsNull() {
+		data.Exclusive = types.BoolValue(false)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GroupMembersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *GroupMembersResourceModel
+	var old *GroupMembersResourceModel
+
+	// Read Terraform old data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &old)...)
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired, diags := data.userIDs(ctx)
+	resp.Diagnostics.Append(diags...)
+	managed, diags := old.userIDs(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.reconcile(ctx, data, desired, managed, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "updated a group members resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GroupMembersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *GroupMembersResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed, diags := data.userIDs(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, userID := range managed {
+		err := r.doJSON(ctx, "DELETE", groupMemberPath(data.Tenant.ValueString(), data.Group.ValueString(), userID), nil, nil)
+		if err != nil && !isStatus(err, http.StatusNotFound) {
+			addClientError(&resp.Diagnostics, "remove group member", err)
+			return
+		}
+	}
+
+	tflog.Trace(ctx, "deleted a group members resource")
+}
+
+func (r *GroupMembersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, group, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || group == "" || strings.Contains(group, "/") {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/group, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group"), group)...)
+}
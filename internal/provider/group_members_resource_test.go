@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGroupMembersResource_additive(t *testing.T) {
+	backend := newTestAccGroupBackend("ops")
+	backend.set("ops", "external", true)
+	server := testBackendServer(t, backend, groupMembersPath("acme", "ops"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGroupMembers(backend, "ops", "external"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccGroupMembersResourceConfig(false, "u1", "u2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_group_members.test", "user_ids.#", "2"),
+					testAccCheckGroupMembers(backend, "ops", "external", "u1", "u2"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server.URL) + testAccGroupMembersResourceConfig(false, "u2", "u3"),
+				Check:  testAccCheckGroupMembers(backend, "ops", "external", "u2", "u3"),
+			},
+			// Members added outside of Terraform are ignored
+			{
+				PreConfig: func() { backend.set("ops", "late", true) },
+				Config:    testAccProviderConfig(server.URL) + testAccGroupMembersResourceConfig(false, "u2", "u3"),
+				PlanOnly:  true,
+			},
+		},
+	})
+}
+
+func TestAccGroupMembersResource_exclusive(t *testing.T) {
+	backend := newTestAccGroupBackend("ops")
+	backend.set("ops", "external", true)
+	server := testBackendServer(t, backend, groupMembersPath("acme", "ops"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGroupMembers(backend, "ops"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccGroupMembersResourceConfig(true, "u1", "u2"),
+				Check:  testAccCheckGroupMembers(backend, "ops", "u1", "u2"),
+			},
+			// Members added outside of Terraform are removed again
+			{
+				PreConfig: func() { backend.set("ops", "late", true) },
+				Config:    testAccProviderConfig(server.URL) + testAccGroupMembersResourceConfig(true, "u1", "u2"),
+				Check:     testAccCheckGroupMembers(backend, "ops", "u1", "u2"),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "authproxy_group_members.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"exclusive"},
+			},
+		},
+	})
+}
+
+func testAccGroupMembersResourceConfig(exclusive bool, userIDs ...string) string {
+	return fmt.Sprintf(`
+resource "authproxy_group_members" "test" {
+  tenant    = "acme"
+  group     = "ops"
+  user_ids  = %[1]s
+  exclusive = %[2]t
+}
+`, testAccStringList(userIDs...), exclusive)
+}
@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/planmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &GroupMembershipResource{}
+var _ resource.ResourceWithImportState = &GroupMembershipResource{}
+
+func NewGroupMembershipResource() resource.Resource {
+	return &GroupMembershipResource{}
+}
+
+// GroupMembershipResource defines the resource implementation.
+type GroupMembershipResource struct {
+	BaseResource
+}
+
+// GroupMembershipResourceModel describes the resource data model.
+type GroupMembershipResourceModel struct {
+	ID     types.String    `tfsdk:"id"`
+	Tenant tenantNameValue `tfsdk:"tenant"`
+	Group  types.String    `tfsdk:"group"`
+	UserID types.String    `tfsdk:"user_id"`
+}
+
+// groupMemberResponse is a member as returned by the group members endpoints.
+type groupMemberResponse struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// groupMembersPath returns the path of the members collection of group.
+func groupMembersPath(tenant string, group string) string {
+	return fmt.Sprintf("/tenants/%s/groups/%s/members", url.PathEscape(tenant), url.PathEscape(group))
+}
+
+// groupMemberPath returns the path of the membership of userID in group.
+func groupMemberPath(tenant string, group string, userID string) string {
+	return groupMembersPath(tenant, group) + "/" + url.PathEscape(userID)
+}
+
+func (r *GroupMembershipResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_membership"
+}
+
+func (r *GroupMembershipResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Adds a single user to a group, leaving other members of the group untouched",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the membership of the form `tenant/group/user_id`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the group belongs to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("a membership cannot move to another tenant"),
+				},
+			},
+			"group": schema.StringAttribute{
+				MarkdownDescription: "Name of the group",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot update memberships, adding the user to another group creates a new membership"),
+				},
+			},
+			"user_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the user to add to the group",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot update memberships, adding another user to the group creates a new membership"),
+				},
+			},
+		},
+	}
+}
+
+func (r *GroupMembershipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *GroupMembershipResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "PUT", groupMemberPath(data.Tenant.ValueString(), data.Group.ValueString(), data.UserID.ValueString()), nil, nil)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "add group member", err)
+		return
+	}
+
+	data.ID = types.StringValue(strings.Join([]string{data.Tenant.ValueString(), data.Group.ValueString(), data.UserID.ValueString()}, "/"))
+
+	tflog.Trace(ctx, "created a group membership resource", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GroupMembershipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *GroupMembershipResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "GET", groupMemberPath(data.Tenant.ValueString(), data.Group.ValueString(), data.UserID.ValueString()), nil, nil)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "user is no longer a member of the group, removing the membership from state", map[string]interface{}{
+			"id": data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read group member", err)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GroupMembershipResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every argument requires replacement, there is nothing to update.
+	resp.Diagnostics.AddError(
+		"Unexpected Update",
+		"Group memberships cannot be updated in place. Please report this issue to the provider developers.",
+	)
+}
+
+func (r *GroupMembershipResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *GroupMembershipResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", groupMemberPath(data.Tenant.ValueString(), data.Group.ValueString(), data.UserID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "remove group member", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a group membership resource")
+}
+
+func (r *GroupMembershipResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/group/user_id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_id"), parts[2])...)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccGroupBackend is an in-memory stand-in for the group members
+// endpoints of the "acme" tenant.
+type testAccGroupBackend struct {
+	mu     sync.Mutex
+	groups map[string]map[string]bool
+}
+
+func newTestAccGroupBackend(groups ...string) *testAccGroupBackend {
+	backend := &testAccGroupBackend{groups: map[string]map[string]bool{}}
+	for _, group := range groups {
+		backend.groups[group] = map[string]bool{}
+	}
+	return backend
+}
+
+// members returns the sorted members of group.
+func (b *testAccGroupBackend) members(group string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	members := make([]string, 0, len(b.groups[group]))
+	for userID := range b.groups[group] {
+		members = append(members, userID)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// set adds or removes userID to or from group behind Terraform's back.
+func (b *testAccGroupBackend) set(group string, userID string, member bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if member {
+		b.groups[group][userID] = true
+	} else {
+		delete(b.groups[group], userID)
+	}
+}
+
+func (b *testAccGroupBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// /tenants/acme/groups/{group}/members[/{user}]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/tenants/acme/groups/"), "/")
+	if len(parts) < 2 || parts[1] != "members" {
+		http.NotFound(w, r)
+		return
+	}
+	members, ok := b.groups[parts[0]]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if len(parts) == 2 {
+		items := make([]groupMemberResponse, 0, len(members))
+		for userID := range members {
+			items = append(items, groupMemberResponse{UserID: userID})
+		}
+		_ = json.NewEncoder(w).Encode(page[groupMemberResponse]{Items: items})
+		return
+	}
+
+	userID := parts[2]
+	switch r.Method {
+	case http.MethodGet:
+		if !members[userID] {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(groupMemberResponse{UserID: userID})
+	case http.MethodPut:
+		members[userID] = true
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if !members[userID] {
+			http.NotFound(w, r)
+			return
+		}
+		delete(members, userID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// testAccCheckGroupMembers checks the members of group in backend.
+func testAccCheckGroupMembers(backend *testAccGroupBackend, group string, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if members := backend.members(group); strings.Join(members, ",") != strings.Join(expected, ",") {
+			return fmt.Errorf("expected members %v of group %s, got %v", expected, group, members)
+		}
+		return nil
+	}
+}
+
+func TestAccGroupMembershipResource(t *testing.T) {
+	backend := newTestAccGroupBackend("ops")
+	backend.set("ops", "u0", true)
+	server := testBackendServer(t, backend, groupMembersPath("acme", "ops"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGroupMembers(backend, "ops", "u0"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccGroupMembershipResourceConfig("u1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_group_membership.test", "id", "acme/ops/u1"),
+					testAccCheckGroupMembers(backend, "ops", "u0", "u1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_group_membership.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Replace testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccGroupMembershipResourceConfig("u2"),
+				Check:  testAccCheckGroupMembers(backend, "ops", "u0", "u2"),
+			},
+			// Removal outside of Terraform is detected and repaired
+			{
+				PreConfig: func() { backend.set("ops", "u2", false) },
+				Config:    testAccProviderConfig(server.URL) + testAccGroupMembershipResourceConfig("u2"),
+				Check:     testAccCheckGroupMembers(backend, "ops", "u0", "u2"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccGroupMembershipResourceConfig(userID string) string {
+	return fmt.Sprintf(`
+resource "authproxy_group_membership" "test" {
+  tenant  = "acme"
+  group   = "ops"
+  user_id = %[1]q
+}
+`, userID)
+}
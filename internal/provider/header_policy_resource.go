@@ -0,0 +1,282 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &HeaderPolicyResource{}
+var _ resource.ResourceWithImportState = &HeaderPolicyResource{}
+
+func NewHeaderPolicyResource() resource.Resource {
+	return &HeaderPolicyResource{}
+}
+
+// HeaderPolicyResource defines the resource implementation. Every service has
+// exactly one header policy, the resource manages it rather than creating
+// one.
+type HeaderPolicyResource struct {
+	BaseResource
+}
+
+// HeaderPolicyResourceModel describes the resource data model.
+type HeaderPolicyResourceModel struct {
+	ID            types.String    `tfsdk:"id"`
+	Tenant        tenantNameValue `tfsdk:"tenant"`
+	Service       types.String    `tfsdk:"service"`
+	SetHeaders    types.Map       `tfsdk:"set_headers"`
+	RemoveHeaders types.Set       `tfsdk:"remove_headers"`
+}
+
+type headerPolicy struct {
+	SetHeaders    map[string]string `json:"set_headers"`
+	RemoveHeaders []string          `json:"remove_headers"`
+}
+
+func (r *HeaderPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_header_policy"
+}
+
+func (r *HeaderPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Headers authproxy sets on or removes from the requests it proxies to a service, such as the identity of the caller. Every service has exactly one header policy, destroying the resource resets it to the authproxy defaults",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the header policy of the form `tenant/service`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant of the service. Changing it resets the policy of the previous service",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service": schema.StringAttribute{
+				MarkdownDescription: "Name of the service the header policy applies to. Changing it resets the policy of the previous service",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"set_headers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Maps header names to the values authproxy sets them to, templates evaluated per request such as `{{ .user.id }}`. The `Authorization` header cannot be set",
+				Optional:            true,
+				Validators: []validator.Map{
+					headerNames("Authorization"),
+				},
+			},
+			"remove_headers": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names of headers authproxy removes from requests before proxying them",
+				Optional:            true,
+				Validators: []validator.Set{
+					headerNames(),
+				},
+			},
+		},
+	}
+}
+
+// headerPolicyPath returns the path of the header policy of the service of
+// tenant named service.
+func headerPolicyPath(tenant string, service string) string {
+	return servicePath(tenant, service) + "/header-policy"
+}
+
+// request builds the request body from data.
+func (data *HeaderPolicyResourceModel) request(ctx context.Context) (headerPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	policy := headerPolicy{
+		SetHeaders:    map[string]string{},
+		RemoveHeaders: []string{},
+	}
+	if !data.SetHeaders.IsNull() {
+		diags.Append(data.SetHeaders.ElementsAs(ctx, &policy.SetHeaders, false)...)
+	}
+	if !data.RemoveHeaders.IsNull() {
+		diags.Append(data.RemoveHeaders.ElementsAs(ctx, &policy.RemoveHeaders, false)...)
+		sort.Strings(policy.RemoveHeaders)
+	}
+
+	return policy, diags
+}
+
+// setHeaderPolicy copies the policy authproxy returned into data.
+func (data *HeaderPolicyResourceModel) setHeaderPolicy(ctx context.Context, policy headerPolicy) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(data.Tenant.ValueString() + "/" + data.Service.ValueString())
+
+	// Empty headers stay null unless they were configured, so leaving
+	// set_headers or remove_headers unset does not produce a diff.
+	if len(policy.SetHeaders) > 0 || !data.SetHeaders.IsNull() {
+		if policy.SetHeaders == nil {
+			policy.SetHeaders = map[string]string{}
+		}
+		setHeaders, d := types.MapValueFrom(ctx, types.StringType, policy.SetHeaders)
+		diags.Append(d...)
+		data.SetHeaders = setHeaders
+	}
+	if len(policy.RemoveHeaders) > 0 || !data.RemoveHeaders.IsNull() {
+		if policy.RemoveHeaders == nil {
+			policy.RemoveHeaders = []string{}
+		}
+		removeHeaders, d := types.SetValueFrom(ctx, types.StringType, policy.RemoveHeaders)
+		diags.Append(d...)
+		data.RemoveHeaders = removeHeaders
+	}
+
+	return diags
+}
+
+// put replaces the header policy of the service of data.
+func (r *HeaderPolicyResource) put(ctx context.Context, data *HeaderPolicyResourceModel, diags *diag.Diagnostics) {
+	body, d := data.request(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+
+	var policy headerPolicy
+	err := r.doJSON(ctx, "PUT", headerPolicyPath(data.Tenant.ValueString(), data.Service.ValueString()), body, &policy)
+	if err != nil {
+		addClientError(diags, "update header policy", err)
+		return
+	}
+
+	diags.Append(data.setHeaderPolicy(ctx, policy)...)
+}
+
+func (r *HeaderPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *HeaderPolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.put(ctx, data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a header policy resource", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HeaderPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *HeaderPolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy headerPolicy
+	err := r.doJSON(ctx, "GET", headerPolicyPath(data.Tenant.ValueString(), data.Service.ValueString()), nil, &policy)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "service no longer exists, removing its header policy from state", map[string]interface{}{
+			"tenant":  data.Tenant.ValueString(),
+			"service": data.Service.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read header policy", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setHeaderPolicy(ctx, policy)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HeaderPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *HeaderPolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.put(ctx, data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "updated a header policy resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HeaderPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *HeaderPolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting the policy resets it to the authproxy defaults.
+	err := r.doJSON(ctx, "DELETE", headerPolicyPath(data.Tenant.ValueString(), data.Service.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "reset header policy", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a header policy resource")
+}
+
+func (r *HeaderPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, service, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || service == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/service, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service"), service)...)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccHeaderPolicyBackend is an in-memory stand-in for the header policy
+// of the "billing" service of the "acme" tenant.
+type testAccHeaderPolicyBackend struct {
+	mu     sync.Mutex
+	policy headerPolicy
+}
+
+func (b *testAccHeaderPolicyBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.URL.Path != headerPolicyPath("acme", "billing") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var policy headerPolicy
+		if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.policy = policy
+	case http.MethodDelete:
+		b.policy = headerPolicy{}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(b.policy)
+}
+
+func TestAccHeaderPolicyResource(t *testing.T) {
+	backend := &testAccHeaderPolicyBackend{}
+	server := testBackendServer(t, backend, headerPolicyPath("acme", "billing"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if !reflect.DeepEqual(backend.policy, headerPolicy{}) {
+				return fmt.Errorf("expected the header policy to be reset, got %+v", backend.policy)
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, templates are passed through verbatim
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_header_policy" "test" {
+  tenant  = "acme"
+  service = "billing"
+  set_headers = {
+    "X-User-Id"     = "{{ .user.id }}"
+    "X-User-Scopes" = "{{ join .token.scopes \" \" }}"
+  }
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_header_policy.test", "id", "acme/billing"),
+					resourcetest.TestCheckResourceAttr("authproxy_header_policy.test", "set_headers.%", "2"),
+					resourcetest.TestCheckResourceAttr("authproxy_header_policy.test", "set_headers.X-User-Id", "{{ .user.id }}"),
+					resourcetest.TestCheckNoResourceAttr("authproxy_header_policy.test", "remove_headers"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if got := backend.policy.SetHeaders["X-User-Scopes"]; got != `{{ join .token.scopes " " }}` {
+							return fmt.Errorf("expected the template to reach authproxy unchanged, got %q", got)
+						}
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_header_policy.test",
+				ImportState:       true,
+				ImportStateId:     "acme/billing",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_header_policy" "test" {
+  tenant  = "acme"
+  service = "billing"
+  set_headers = {
+    "X-User-Id" = "{{ .user.id }}"
+  }
+  remove_headers = ["Cookie", "X-Forwarded-User"]
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_header_policy.test", "set_headers.%", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_header_policy.test", "remove_headers.#", "2"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_header_policy.test", "remove_headers.*", "Cookie"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccHeaderPolicyResource_validation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		attributes string
+		err        string
+	}{
+		"authorization": {
+			attributes: `set_headers = { authorization = "Bearer {{ .token.raw }}" }`,
+			err:        `must not configure the Authorization header`,
+		},
+		"set header name": {
+			attributes: `set_headers = { "X User" = "{{ .user.id }}" }`,
+			err:        `must be an HTTP header name`,
+		},
+		"removed header name": {
+			attributes: `remove_headers = ["X-Forwarded-User:"]`,
+			err:        `must be an HTTP header name`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resourcetest.Test(t, resourcetest.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resourcetest.TestStep{
+					{
+						Config: testAccProviderConfig("http://127.0.0.1:1") + fmt.Sprintf(`
+resource "authproxy_header_policy" "test" {
+  tenant  = "acme"
+  service = "billing"
+  %s
+}
+`, tc.attributes),
+						ExpectError: regexp.MustCompile(tc.err),
+					},
+				},
+			})
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// hexColorPattern matches CSS hex colors in their short "#f0a" and long
+// "#ff00aa" forms.
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// normalizeHexColor returns color in the long lowercase form authproxy
+// stores colors in, "#F0A" becoming "#ff00aa".
+func normalizeHexColor(color string) string {
+	color = strings.ToLower(color)
+	if len(color) == 4 {
+		color = string([]byte{'#', color[1], color[1], color[2], color[2], color[3], color[3]})
+	}
+	return color
+}
+
+var _ basetypes.StringTypable = hexColorType{}
+var _ xattr.TypeWithValidate = hexColorType{}
+
+// hexColorType is a string type holding a CSS hex color. Colors that only
+// differ in case or in using the short form are semantically equal, so
+// authproxy normalizing a color does not cause drift.
+type hexColorType struct {
+	basetypes.StringType
+}
+
+func (t hexColorType) Equal(o attr.Type) bool {
+	other, ok := o.(hexColorType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t hexColorType) String() string {
+	return "hexColorType"
+}
+
+func (t hexColorType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return hexColorValue{StringValue: in}, nil
+}
+
+func (t hexColorType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return hexColorValue{StringValue: stringValue}, nil
+}
+
+func (t hexColorType) ValueType(ctx context.Context) attr.Value {
+	return hexColorValue{}
+}
+
+// Validate rejects values that are not hex colors, failing the plan rather
+// than the apply.
+func (t hexColorType) Validate(ctx context.Context, in tftypes.Value, path path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !in.IsKnown() || in.IsNull() {
+		return diags
+	}
+
+	var color string
+	if err := in.As(&color); err != nil {
+		diags.AddAttributeError(
+			path,
+			"Invalid Hex Color",
+			fmt.Sprintf("Unable to convert the value to a string: %s", err),
+		)
+		return diags
+	}
+
+	if !hexColorPattern.MatchString(color) {
+		diags.AddAttributeError(
+			path,
+			"Invalid Hex Color",
+			fmt.Sprintf("Attribute %s must be a hex color such as \"#1a73e8\" or \"#fff\", got: %q", path, color),
+		)
+	}
+
+	return diags
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = hexColorValue{}
+
+// hexColorValue is the value of a hexColorType attribute.
+type hexColorValue struct {
+	basetypes.StringValue
+}
+
+// hexColorStringValue returns a known hexColorValue holding color.
+func hexColorStringValue(color string) hexColorValue {
+	return hexColorValue{StringValue: basetypes.NewStringValue(color)}
+}
+
+// hexColorNull returns a null hexColorValue.
+func hexColorNull() hexColorValue {
+	return hexColorValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v hexColorValue) Equal(o attr.Value) bool {
+	other, ok := o.(hexColorValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v hexColorValue) Type(ctx context.Context) attr.Type {
+	return hexColorType{}
+}
+
+// StringSemanticEquals reports whether both values are the same color,
+// ignoring case and the short form.
+func (v hexColorValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(hexColorValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	if !hexColorPattern.MatchString(v.ValueString()) || !hexColorPattern.MatchString(newValue.ValueString()) {
+		return false, diags
+	}
+
+	return normalizeHexColor(v.ValueString()) == normalizeHexColor(newValue.ValueString()), diags
+}
@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &IdentityProvidersDataSource{}
+
+func NewIdentityProvidersDataSource() datasource.DataSource {
+	return &IdentityProvidersDataSource{}
+}
+
+// IdentityProvidersDataSource defines the data source implementation.
+type IdentityProvidersDataSource struct {
+	BaseDataSource
+}
+
+// IdentityProvidersDataSourceModel describes the data source data model.
+type IdentityProvidersDataSourceModel struct {
+	Tenant            tenantNameValue         `tfsdk:"tenant"`
+	PageSize          types.Int64             `tfsdk:"page_size"`
+	MaxItems          types.Int64             `tfsdk:"max_items"`
+	IdentityProviders []IdentityProviderModel `tfsdk:"identity_providers"`
+	Timeouts          *ReadTimeoutsModel      `tfsdk:"timeouts"`
+}
+
+// IdentityProviderModel describes a single identity provider.
+type IdentityProviderModel struct {
+	ID      types.String `tfsdk:"id"`
+	Type    types.String `tfsdk:"type"`
+	Name    types.String `tfsdk:"name"`
+	Issuer  types.String `tfsdk:"issuer"`
+	Enabled types.Bool   `tfsdk:"enabled"`
+}
+
+// identityProviderSummary only holds the non-secret identity provider fields,
+// client secrets and private keys are dropped while decoding.
+type identityProviderSummary struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Issuer   string `json:"issuer"`
+	EntityID string `json:"entity_id"`
+	Enabled  bool   `json:"enabled"`
+}
+
+func (d *IdentityProvidersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identity_providers"
+}
+
+func (d *IdentityProvidersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Identity providers configured on a tenant. Secrets are never included",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant to list the identity providers of",
+				Required:            true,
+			},
+			"page_size": pageSizeAttribute(),
+			"max_items": maxItemsAttribute(),
+			"identity_providers": schema.ListNestedAttribute{
+				MarkdownDescription: "Identity providers of the tenant",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The database uuid",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Protocol of the identity provider, `oidc` or `saml`",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the identity provider",
+							Computed:            true,
+						},
+						"issuer": schema.StringAttribute{
+							MarkdownDescription: "OIDC issuer URL or SAML entity ID",
+							Computed:            true,
+						},
+						"enabled": schema.BoolAttribute{
+							MarkdownDescription: "Whether logins through the identity provider are enabled",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *IdentityProvidersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data IdentityProvidersDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_identity_providers", data.Timeouts)
+	defer cancel()
+
+	idps, truncated, err := listPages[identityProviderSummary](ctx, d.providerData, fmt.Sprintf("/tenants/%s/idps", url.PathEscape(data.Tenant.ValueString())), nil, listOptionsFrom(data.PageSize, data.MaxItems))
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "list identity providers", err)
+		return
+	}
+	if truncated {
+		addTruncationWarning(&resp.Diagnostics, "identity providers", data.MaxItems.ValueInt64())
+	}
+
+	data.IdentityProviders = make([]IdentityProviderModel, 0, len(idps))
+	for _, idp := range idps {
+		issuer := idp.Issuer
+		if issuer == "" {
+			issuer = idp.EntityID
+		}
+		data.IdentityProviders = append(data.IdentityProviders, IdentityProviderModel{
+			ID:      types.StringValue(idp.ID),
+			Type:    types.StringValue(idp.Type),
+			Name:    types.StringValue(idp.Name),
+			Issuer:  types.StringValue(issuer),
+			Enabled: types.BoolValue(idp.Enabled),
+		})
+	}
+
+	tflog.Trace(ctx, "read identity providers data source", map[string]interface{}{
+		"count": len(data.IdentityProviders),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
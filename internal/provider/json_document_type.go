@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ basetypes.StringTypable = jsonDocumentType{}
+var _ xattr.TypeWithValidate = jsonDocumentType{}
+
+// jsonDocumentType is a string type holding a JSON document. Documents that
+// only differ in key order or insignificant whitespace are semantically
+// equal, so authproxy re-serializing a document does not cause drift.
+type jsonDocumentType struct {
+	basetypes.StringType
+}
+
+func (t jsonDocumentType) Equal(o attr.Type) bool {
+	other, ok := o.(jsonDocumentType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t jsonDocumentType) String() string {
+	return "jsonDocumentType"
+}
+
+func (t jsonDocumentType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return jsonDocumentValue{StringValue: in}, nil
+}
+
+func (t jsonDocumentType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return jsonDocumentValue{StringValue: stringValue}, nil
+}
+
+func (t jsonDocumentType) ValueType(ctx context.Context) attr.Value {
+	return jsonDocumentValue{}
+}
+
+// Validate rejects documents that are not valid JSON, failing the plan
+// rather than the apply.
+func (t jsonDocumentType) Validate(ctx context.Context, in tftypes.Value, path path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !in.IsKnown() || in.IsNull() {
+		return diags
+	}
+
+	var document string
+	if err := in.As(&document); err != nil {
+		diags.AddAttributeError(
+			path,
+			"Invalid JSON Document",
+			fmt.Sprintf("Unable to convert the value to a string: %s", err),
+		)
+		return diags
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(document), &parsed); err != nil {
+		diags.AddAttributeError(
+			path,
+			"Invalid JSON Document",
+			fmt.Sprintf("Attribute %s must be a valid JSON document, got error: %s", path, err),
+		)
+	}
+
+	return diags
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = jsonDocumentValue{}
+
+// jsonDocumentValue is the value of a jsonDocumentType attribute.
+type jsonDocumentValue struct {
+	basetypes.StringValue
+}
+
+// jsonDocumentStringValue returns a known jsonDocumentValue holding document.
+func jsonDocumentStringValue(document string) jsonDocumentValue {
+	return jsonDocumentValue{StringValue: basetypes.NewStringValue(document)}
+}
+
+func (v jsonDocumentValue) Equal(o attr.Value) bool {
+	other, ok := o.(jsonDocumentValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v jsonDocumentValue) Type(ctx context.Context) attr.Type {
+	return jsonDocumentType{}
+}
+
+// StringSemanticEquals reports whether both documents parse to the same JSON
+// value, ignoring key order and insignificant whitespace.
+func (v jsonDocumentValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(jsonDocumentValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	var prior, proposed interface{}
+	if err := json.Unmarshal([]byte(v.ValueString()), &prior); err != nil {
+		return false, diags
+	}
+	if err := json.Unmarshal([]byte(newValue.ValueString()), &proposed); err != nil {
+		return false, diags
+	}
+
+	return reflect.DeepEqual(prior, proposed), diags
+}
@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// jwksPath is where authproxy serves the keys it signs tokens with, below
+// the root for global keys and below a tenant for the keys of the tenant.
+const jwksPath = "/.well-known/jwks.json"
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &JWKSDataSource{}
+
+func NewJWKSDataSource() datasource.DataSource {
+	return &JWKSDataSource{}
+}
+
+// JWKSDataSource defines the data source implementation.
+type JWKSDataSource struct {
+	BaseDataSource
+}
+
+// JWKSDataSourceModel describes the data source data model.
+type JWKSDataSourceModel struct {
+	Tenant   tenantNameValue    `tfsdk:"tenant"`
+	Keys     []JWKModel         `tfsdk:"keys"`
+	JWKSJSON types.String       `tfsdk:"jwks_json"`
+	Timeouts *ReadTimeoutsModel `tfsdk:"timeouts"`
+}
+
+// JWKModel describes a single public key. Members the key type does not use
+// are null.
+type JWKModel struct {
+	KID types.String `tfsdk:"kid"`
+	Alg types.String `tfsdk:"alg"`
+	Use types.String `tfsdk:"use"`
+	N   types.String `tfsdk:"n"`
+	E   types.String `tfsdk:"e"`
+	X   types.String `tfsdk:"x"`
+	Y   types.String `tfsdk:"y"`
+	Crv types.String `tfsdk:"crv"`
+}
+
+// jwksResponse keeps the keys undecoded, so that jwks_json holds them with
+// every member the server sent.
+type jwksResponse struct {
+	Keys []json.RawMessage `json:"keys"`
+}
+
+// jwkResponse holds the members of a key the data source exposes, the
+// others are dropped while decoding.
+type jwkResponse struct {
+	KID *string `json:"kid"`
+	Alg *string `json:"alg"`
+	Use *string `json:"use"`
+	N   *string `json:"n"`
+	E   *string `json:"e"`
+	X   *string `json:"x"`
+	Y   *string `json:"y"`
+	Crv *string `json:"crv"`
+}
+
+func (d *JWKSDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_jwks"
+}
+
+func (d *JWKSDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	keyMember := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{
+			MarkdownDescription: description,
+			Computed:            true,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Public keys authproxy signs tokens with, for services validating them",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant to read the signing keys of. The global keys are read when not set",
+				Optional:            true,
+			},
+			"keys": schema.ListNestedAttribute{
+				MarkdownDescription: "Signing keys in the order the server lists them. During a rotation both the old and the new key are listed",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"kid": keyMember("Key ID tokens signed with the key name in their header"),
+						"alg": keyMember("Algorithm the key is used with, such as `RS256`"),
+						"use": keyMember("Intended use of the key, `sig` for signing keys"),
+						"n":   keyMember("Modulus of an RSA key, base64url encoded"),
+						"e":   keyMember("Exponent of an RSA key, base64url encoded"),
+						"x":   keyMember("X coordinate of an elliptic curve key, base64url encoded"),
+						"y":   keyMember("Y coordinate of an elliptic curve key, base64url encoded"),
+						"crv": keyMember("Curve of an elliptic curve key, such as `P-256`"),
+					},
+				},
+			},
+			"jwks_json": schema.StringAttribute{
+				MarkdownDescription: "The key set as JSON, including the key members not exposed as attributes, for passing to other providers",
+				Computed:            true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *JWKSDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data JWKSDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_jwks", data.Timeouts)
+	defer cancel()
+
+	keysPath := jwksPath
+	if !data.Tenant.IsNull() {
+		keysPath = fmt.Sprintf("/tenants/%s%s", url.PathEscape(data.Tenant.ValueString()), jwksPath)
+	}
+
+	var jwks jwksResponse
+	if err := d.doJSON(ctx, "GET", keysPath, nil, &jwks); err != nil {
+		addReadError(ctx, &resp.Diagnostics, "read signing keys", err)
+		return
+	}
+	if jwks.Keys == nil {
+		jwks.Keys = []json.RawMessage{}
+	}
+
+	data.Keys = make([]JWKModel, 0, len(jwks.Keys))
+	for i, raw := range jwks.Keys {
+		var key jwkResponse
+		if err := json.Unmarshal(raw, &key); err != nil {
+			addClientError(&resp.Diagnostics, fmt.Sprintf("decode signing key %d", i), err)
+			return
+		}
+		data.Keys = append(data.Keys, JWKModel{
+			KID: types.StringPointerValue(key.KID),
+			Alg: types.StringPointerValue(key.Alg),
+			Use: types.StringPointerValue(key.Use),
+			N:   types.StringPointerValue(key.N),
+			E:   types.StringPointerValue(key.E),
+			X:   types.StringPointerValue(key.X),
+			Y:   types.StringPointerValue(key.Y),
+			Crv: types.StringPointerValue(key.Crv),
+		})
+	}
+
+	// Marshaling the raw keys again compacts them, so that formatting
+	// changes on the server side do not show up as a difference.
+	jwksJSON, err := json.Marshal(jwks)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "encode signing keys", err)
+		return
+	}
+	data.JWKSJSON = types.StringValue(string(jwksJSON))
+
+	tflog.Trace(ctx, "read jwks data source", map[string]interface{}{
+		"count": len(data.Keys),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
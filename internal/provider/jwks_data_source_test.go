@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccJWKSServer serves global keys in the middle of a rotation, an RSA
+// key being replaced by an elliptic curve one, and a single key for the acme
+// tenant. Keys have members the data source does not expose.
+func testAccJWKSServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"keys": [
+			{"kty": "RSA", "kid": "2023", "alg": "RS256", "use": "sig", "n": "0vx7agoebGcQSuu", "e": "AQAB", "x5t": "dGhpcyBpcyBh"},
+			{"kty": "EC", "kid": "2024", "alg": "ES256", "use": "sig", "crv": "P-256", "x": "MKBCTNIcKUSDii11", "y": "4Etl6SRW2YiLUrN5"}
+		]}`)
+	})
+	mux.HandleFunc("/tenants/acme/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"keys":[{"kty":"OKP","kid":"acme-1","crv":"Ed25519","x":"11qYAYKxCrfVS_7T"}]}`)
+	})
+	mux.HandleFunc("/tenants/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"tenant not found"}`, http.StatusNotFound)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestAccJWKSDataSource(t *testing.T) {
+	server := testAccJWKSServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_jwks" "global" {}
+
+data "authproxy_jwks" "acme" {
+  tenant = "acme"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_jwks.global", "keys.#", "2"),
+					resource.TestCheckResourceAttr("data.authproxy_jwks.global", "keys.0.kid", "2023"),
+					resource.TestCheckResourceAttr("data.authproxy_jwks.global", "keys.0.n", "0vx7agoebGcQSuu"),
+					resource.TestCheckNoResourceAttr("data.authproxy_jwks.global", "keys.0.crv"),
+					resource.TestCheckResourceAttr("data.authproxy_jwks.global", "keys.1.kid", "2024"),
+					resource.TestCheckResourceAttr("data.authproxy_jwks.global", "keys.1.crv", "P-256"),
+					resource.TestCheckNoResourceAttr("data.authproxy_jwks.global", "keys.1.n"),
+					resource.TestCheckResourceAttr("data.authproxy_jwks.acme", "keys.#", "1"),
+					resource.TestCheckResourceAttr("data.authproxy_jwks.acme", "keys.0.kid", "acme-1"),
+				),
+			},
+		},
+	})
+}
+
+func TestJWKSDataSource(t *testing.T) {
+	server := testAccJWKSServer(t)
+	defer server.Close()
+
+	str := func(s string) types.String { return types.StringValue(s) }
+	for name, tc := range map[string]struct {
+		tenant   tftypes.Value
+		keys     []JWKModel
+		jwksJSON string
+	}{
+		"rotation": {
+			tenant: tftypes.NewValue(tftypes.String, nil),
+			keys: []JWKModel{
+				{KID: str("2023"), Alg: str("RS256"), Use: str("sig"), N: str("0vx7agoebGcQSuu"), E: str("AQAB"), X: types.StringNull(), Y: types.StringNull(), Crv: types.StringNull()},
+				{KID: str("2024"), Alg: str("ES256"), Use: str("sig"), N: types.StringNull(), E: types.StringNull(), X: str("MKBCTNIcKUSDii11"), Y: str("4Etl6SRW2YiLUrN5"), Crv: str("P-256")},
+			},
+			jwksJSON: `{"keys":[{"kty":"RSA","kid":"2023","alg":"RS256","use":"sig","n":"0vx7agoebGcQSuu","e":"AQAB","x5t":"dGhpcyBpcyBh"},{"kty":"EC","kid":"2024","alg":"ES256","use":"sig","crv":"P-256","x":"MKBCTNIcKUSDii11","y":"4Etl6SRW2YiLUrN5"}]}`,
+		},
+		"tenant": {
+			tenant: tftypes.NewValue(tftypes.String, "acme"),
+			keys: []JWKModel{
+				{KID: str("acme-1"), Alg: types.StringNull(), Use: types.StringNull(), N: types.StringNull(), E: types.StringNull(), X: str("11qYAYKxCrfVS_7T"), Y: types.StringNull(), Crv: str("Ed25519")},
+			},
+			jwksJSON: `{"keys":[{"kty":"OKP","kid":"acme-1","crv":"Ed25519","x":"11qYAYKxCrfVS_7T"}]}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := testDataSourceRead(t, NewJWKSDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+				"tenant": tc.tenant,
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data JWKSDataSourceModel
+			if diags := resp.State.Get(context.Background(), &data); diags.HasError() {
+				t.Fatalf("unexpected state diagnostics: %v", diags)
+			}
+			if len(data.Keys) != len(tc.keys) {
+				t.Fatalf("expected %d keys, got %v", len(tc.keys), data.Keys)
+			}
+			for i, key := range data.Keys {
+				if key != tc.keys[i] {
+					t.Errorf("expected key %d to be %v, got %v", i, tc.keys[i], key)
+				}
+			}
+			if data.JWKSJSON.ValueString() != tc.jwksJSON {
+				t.Errorf("expected jwks_json to keep every member, got %s", data.JWKSJSON)
+			}
+		})
+	}
+}
+
+func TestJWKSDataSource_unknownTenant(t *testing.T) {
+	server := testAccJWKSServer(t)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewJWKSDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"tenant": tftypes.NewValue(tftypes.String, "globex"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a tenant that does not exist")
+	}
+}
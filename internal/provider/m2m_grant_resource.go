@@ -0,0 +1,303 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/planmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &M2MGrantResource{}
+var _ resource.ResourceWithImportState = &M2MGrantResource{}
+
+func NewM2MGrantResource() resource.Resource {
+	return &M2MGrantResource{}
+}
+
+// M2MGrantResource defines the resource implementation.
+type M2MGrantResource struct {
+	BaseResource
+}
+
+// M2MGrantResourceModel describes the resource data model.
+type M2MGrantResourceModel struct {
+	ID       types.String    `tfsdk:"id"`
+	Tenant   tenantNameValue `tfsdk:"tenant"`
+	ClientID types.String    `tfsdk:"client_id"`
+	Scopes   types.Set       `tfsdk:"scopes"`
+}
+
+type m2mGrantRequest struct {
+	Scopes []string `json:"scopes"`
+}
+
+// m2mGrantResponse is the effective grant of a client, which also reflects
+// changes made in the authproxy UI.
+type m2mGrantResponse struct {
+	ClientID string   `json:"client_id"`
+	Scopes   []string `json:"scopes"`
+}
+
+// m2mGrantUnprocessableResponse is the body authproxy answers with when a
+// grant refers to scopes that do not exist.
+type m2mGrantUnprocessableResponse struct {
+	UnknownScopes []string `json:"unknown_scopes"`
+}
+
+func (r *M2MGrantResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_m2m_grant"
+}
+
+func (r *M2MGrantResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Grants scopes to a machine-to-machine client. Tokens issued through the client credentials flow only carry granted scopes",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the grant of the form `tenant/client_id`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the client belongs to. Changing it recreates the grant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("a grant cannot move to another tenant"),
+				},
+			},
+			"client_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the client the scopes are granted to. Changing it recreates the grant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot update grants, granting another client creates a new grant"),
+				},
+			},
+			"scopes": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names of the scopes granted to the client",
+				Required:            true,
+			},
+		},
+	}
+}
+
+// m2mGrantPath returns the path of the grant of the client of tenant with
+// the given id.
+func m2mGrantPath(tenant string, clientID string) string {
+	return fmt.Sprintf("/tenants/%s/clients/%s/grants", url.PathEscape(tenant), url.PathEscape(clientID))
+}
+
+// request builds the create and update request body from data.
+func (data *M2MGrantResourceModel) request(ctx context.Context) (m2mGrantRequest, diag.Diagnostics) {
+	grant := m2mGrantRequest{
+		Scopes: []string{},
+	}
+	diags := data.Scopes.ElementsAs(ctx, &grant.Scopes, false)
+	sort.Strings(grant.Scopes)
+
+	return grant, diags
+}
+
+// setGrant copies the effective grant authproxy returned into data.
+func (data *M2MGrantResourceModel) setGrant(ctx context.Context, grant m2mGrantResponse) diag.Diagnostics {
+	data.ID = types.StringValue(data.Tenant.ValueString() + "/" + grant.ClientID)
+	data.ClientID = types.StringValue(grant.ClientID)
+
+	if grant.Scopes == nil {
+		grant.Scopes = []string{}
+	}
+	scopes, diags := types.SetValueFrom(ctx, types.StringType, grant.Scopes)
+	data.Scopes = scopes
+
+	return diags
+}
+
+// addUnknownScopesError reports a request authproxy rejected because granted
+// scopes do not exist as an error on each of them. It returns false for any
+// other error.
+func (data *M2MGrantResourceModel) addUnknownScopesError(diags *diag.Diagnostics, err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+
+	var unprocessable m2mGrantUnprocessableResponse
+	if json.Unmarshal([]byte(apiErr.Body), &unprocessable) != nil || len(unprocessable.UnknownScopes) == 0 {
+		return false
+	}
+
+	sort.Strings(unprocessable.UnknownScopes)
+	for _, scope := range unprocessable.UnknownScopes {
+		diags.AddAttributeError(
+			path.Root("scopes").AtSetValue(types.StringValue(scope)),
+			"Scope Not Found",
+			fmt.Sprintf("No scope named %q exists, create it with an authproxy_scope resource first.", scope),
+		)
+	}
+	return true
+}
+
+func (r *M2MGrantResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *M2MGrantResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var grant m2mGrantResponse
+	err := r.doJSON(ctx, "POST", m2mGrantPath(data.Tenant.ValueString(), data.ClientID.ValueString()), body, &grant)
+	if data.addUnknownScopesError(&resp.Diagnostics, err) {
+		return
+	}
+	if isStatus(err, http.StatusNotFound) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_id"),
+			"Client Not Found",
+			fmt.Sprintf("No client with the id %q exists in tenant %q.", data.ClientID.ValueString(), data.Tenant.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create m2m grant", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setGrant(ctx, grant)...)
+
+	tflog.Trace(ctx, "created an m2m grant resource", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *M2MGrantResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *M2MGrantResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var grant m2mGrantResponse
+	err := r.doJSON(ctx, "GET", m2mGrantPath(data.Tenant.ValueString(), data.ClientID.ValueString()), nil, &grant)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "m2m grant no longer exists, removing it from state", map[string]interface{}{
+			"tenant":    data.Tenant.ValueString(),
+			"client_id": data.ClientID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read m2m grant", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setGrant(ctx, grant)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *M2MGrantResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *M2MGrantResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The scope set is replaced as a whole, scopes granted in the UI in the
+	// meantime are revoked.
+	var grant m2mGrantResponse
+	err := r.doJSON(ctx, "PUT", m2mGrantPath(data.Tenant.ValueString(), data.ClientID.ValueString()), body, &grant)
+	if data.addUnknownScopesError(&resp.Diagnostics, err) {
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update m2m grant", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setGrant(ctx, grant)...)
+
+	tflog.Trace(ctx, "updated an m2m grant resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *M2MGrantResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *M2MGrantResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", m2mGrantPath(data.Tenant.ValueString(), data.ClientID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete m2m grant", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted an m2m grant resource")
+}
+
+func (r *M2MGrantResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, clientID, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || clientID == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/client_id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("client_id"), clientID)...)
+}
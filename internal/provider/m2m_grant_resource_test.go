@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccM2MGrantBackend is an in-memory stand-in for the grant endpoint of
+// the client "ci-runner" of the "acme" tenant. Grants may only refer to the
+// scopes in scopes.
+type testAccM2MGrantBackend struct {
+	mu     sync.Mutex
+	scopes map[string]bool
+	grant  []string
+}
+
+// unknownScopes returns the scopes of grant that do not exist.
+func (b *testAccM2MGrantBackend) unknownScopes(grant m2mGrantRequest) []string {
+	var unknown []string
+	for _, scope := range grant.Scopes {
+		if !b.scopes[scope] {
+			unknown = append(unknown, scope)
+		}
+	}
+	return unknown
+}
+
+func (b *testAccM2MGrantBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.URL.Path != m2mGrantPath("acme", "ci-runner") {
+		http.NotFound(w, r)
+		return
+	}
+
+	var body m2mGrantRequest
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if unknown := b.unknownScopes(body); len(unknown) > 0 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_ = json.NewEncoder(w).Encode(m2mGrantUnprocessableResponse{UnknownScopes: unknown})
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		if b.grant != nil {
+			http.Error(w, "client already has a grant", http.StatusConflict)
+			return
+		}
+		b.grant = body.Scopes
+	case http.MethodPut:
+		if b.grant == nil {
+			http.NotFound(w, r)
+			return
+		}
+		b.grant = body.Scopes
+	case http.MethodGet:
+		if b.grant == nil {
+			http.NotFound(w, r)
+			return
+		}
+	case http.MethodDelete:
+		if b.grant == nil {
+			http.NotFound(w, r)
+			return
+		}
+		b.grant = nil
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(m2mGrantResponse{ClientID: "ci-runner", Scopes: b.grant})
+}
+
+// granted returns the scopes granted in authproxy, sorted and comma
+// separated.
+func (b *testAccM2MGrantBackend) granted() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	scopes := append([]string(nil), b.grant...)
+	sort.Strings(scopes)
+	return strings.Join(scopes, ",")
+}
+
+func (b *testAccM2MGrantBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.grant != nil {
+		return fmt.Errorf("expected the grant to be deleted, got %v", b.grant)
+	}
+	return nil
+}
+
+func newTestAccM2MGrantBackend() *testAccM2MGrantBackend {
+	return &testAccM2MGrantBackend{
+		scopes: map[string]bool{"deployments:read": true, "deployments:write": true, "artifacts:read": true},
+	}
+}
+
+func TestAccM2MGrantResource(t *testing.T) {
+	backend := newTestAccM2MGrantBackend()
+	server := testBackendServer(t, backend, m2mGrantPath("acme", "ci-runner"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccM2MGrantResourceConfig(`["deployments:read", "artifacts:read"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_m2m_grant.test", "id", "acme/ci-runner"),
+					resourcetest.TestCheckResourceAttr("authproxy_m2m_grant.test", "scopes.#", "2"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_m2m_grant.test", "scopes.*", "deployments:read"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_m2m_grant.test", "scopes.*", "artifacts:read"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_m2m_grant.test",
+				ImportState:       true,
+				ImportStateId:     "acme/ci-runner",
+				ImportStateVerify: true,
+			},
+			// Adding a scope
+			{
+				Config: testAccProviderConfig(server.URL) + testAccM2MGrantResourceConfig(`["deployments:read", "deployments:write", "artifacts:read"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_m2m_grant.test", "scopes.#", "3"),
+					func(s *terraform.State) error {
+						if granted := backend.granted(); granted != "artifacts:read,deployments:read,deployments:write" {
+							return fmt.Errorf("expected all three scopes to be granted in authproxy, got %s", granted)
+						}
+						return nil
+					},
+				),
+			},
+			// Removing a scope
+			{
+				Config: testAccProviderConfig(server.URL) + testAccM2MGrantResourceConfig(`["deployments:write"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_m2m_grant.test", "scopes.#", "1"),
+					func(s *terraform.State) error {
+						if granted := backend.granted(); granted != "deployments:write" {
+							return fmt.Errorf("expected only deployments:write to be granted in authproxy, got %s", granted)
+						}
+						return nil
+					},
+				),
+			},
+			// Scopes granted outside of Terraform show up as drift
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					backend.grant = append(backend.grant, "artifacts:read")
+				},
+				Config:             testAccProviderConfig(server.URL) + testAccM2MGrantResourceConfig(`["deployments:write"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccProviderConfig(server.URL) + testAccM2MGrantResourceConfig(`["deployments:write"]`),
+				Check: func(s *terraform.State) error {
+					if granted := backend.granted(); granted != "deployments:write" {
+						return fmt.Errorf("expected the drift to be reverted in authproxy, got %s", granted)
+					}
+					return nil
+				},
+			},
+			// Scopes that do not exist are reported on scopes
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccM2MGrantResourceConfig(`["deployments:write", "secrets:read"]`),
+				ExpectError: regexp.MustCompile(`No scope named "secrets:read" exists`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccM2MGrantResource_unknownScopes(t *testing.T) {
+	backend := newTestAccM2MGrantBackend()
+	server := testBackendServer(t, backend, m2mGrantPath("acme", "ci-runner"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccM2MGrantResourceConfig(`["deployments:read", "secrets:read", "billing:write"]`),
+				ExpectError: regexp.MustCompile(`No scope named "billing:write" exists(.|\n)*No scope named "secrets:read" exists`),
+			},
+		},
+	})
+}
+
+func testAccM2MGrantResourceConfig(scopes string) string {
+	return fmt.Sprintf(`
+resource "authproxy_m2m_grant" "test" {
+  tenant    = "acme"
+  client_id = "ci-runner"
+  scopes    = %s
+}
+`, scopes)
+}
+
+func TestM2MGrantResourceModel_addUnknownScopesError(t *testing.T) {
+	body, _ := json.Marshal(m2mGrantUnprocessableResponse{UnknownScopes: []string{"secrets:read", "billing:write", "audit:read"}})
+	err := &apiError{Method: "POST", Path: m2mGrantPath("acme", "ci-runner"), StatusCode: http.StatusUnprocessableEntity, Body: string(body)}
+
+	var diags diag.Diagnostics
+	if !(&M2MGrantResourceModel{}).addUnknownScopesError(&diags, err) {
+		t.Fatal("expected the unknown scopes to be reported")
+	}
+	if diags.ErrorsCount() != 3 {
+		t.Fatalf("expected an error for every unknown scope, got %v", diags)
+	}
+	for i, scope := range []string{"audit:read", "billing:write", "secrets:read"} {
+		expected := path.Root("scopes").AtSetValue(types.StringValue(scope))
+		if diagnostic, ok := diags[i].(interface{ Path() path.Path }); !ok || !diagnostic.Path().Equal(expected) ||
+			!strings.Contains(diags[i].Detail(), fmt.Sprintf("No scope named %q exists", scope)) {
+			t.Errorf("expected an error at %s, got %v", expected, diags[i])
+		}
+	}
+
+	if (&M2MGrantResourceModel{}).addUnknownScopesError(&diags, &apiError{StatusCode: http.StatusUnprocessableEntity, Body: "invalid grant"}) {
+		t.Error("expected other unprocessable requests not to be reported")
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OIDCIdentityProviderResource{}
+var _ resource.ResourceWithImportState = &OIDCIdentityProviderResource{}
+
+func NewOIDCIdentityProviderResource() resource.Resource {
+	return &OIDCIdentityProviderResource{}
+}
+
+// OIDCIdentityProviderResource defines the resource implementation.
+type OIDCIdentityProviderResource struct {
+	BaseResource
+}
+
+// OIDCIdentityProviderResourceModel describes the resource data model.
+type OIDCIdentityProviderResourceModel struct {
+	ID            types.String    `tfsdk:"id"`
+	Tenant        tenantNameValue `tfsdk:"tenant"`
+	Name          types.String    `tfsdk:"name"`
+	IssuerURL     types.String    `tfsdk:"issuer_url"`
+	ClientID      types.String    `tfsdk:"client_id"`
+	ClientSecret  types.String    `tfsdk:"client_secret"`
+	Scopes        types.Set       `tfsdk:"scopes"`
+	ClaimMappings types.Map       `tfsdk:"claim_mappings"`
+}
+
+type oidcIdentityProviderRequest struct {
+	Type          string            `json:"type,omitempty"`
+	Name          string            `json:"name"`
+	Issuer        string            `json:"issuer"`
+	ClientID      string            `json:"client_id"`
+	ClientSecret  string            `json:"client_secret"`
+	Scopes        []string          `json:"scopes"`
+	ClaimMappings map[string]string `json:"claim_mappings"`
+}
+
+// oidcIdentityProviderResponse leaves out the client secret, authproxy only
+// ever returns it redacted.
+type oidcIdentityProviderResponse struct {
+	ID            string            `json:"id"`
+	Type          string            `json:"type"`
+	Name          string            `json:"name"`
+	Issuer        string            `json:"issuer"`
+	ClientID      string            `json:"client_id"`
+	Scopes        []string          `json:"scopes"`
+	ClaimMappings map[string]string `json:"claim_mappings"`
+}
+
+func (r *OIDCIdentityProviderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_oidc_identity_provider"
+}
+
+func (r *OIDCIdentityProviderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Upstream OpenID Connect identity provider of a tenant",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the identity provider belongs to. Changing it recreates the identity provider",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the identity provider shown on the login page",
+				Required:            true,
+			},
+			"issuer_url": schema.StringAttribute{
+				MarkdownDescription: "Issuer URL of the identity provider, its discovery document must be served below it",
+				Required:            true,
+				Validators: []validator.String{
+					issuerURL(),
+				},
+			},
+			"client_id": schema.StringAttribute{
+				MarkdownDescription: "Client ID authproxy is registered with at the identity provider",
+				Required:            true,
+			},
+			"client_secret": schema.StringAttribute{
+				MarkdownDescription: "Client secret authproxy is registered with at the identity provider. Authproxy never returns it, so changes made outside of Terraform are not detected",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"scopes": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scopes requested from the identity provider. Defaults to `[\"openid\"]`",
+				Optional:            true,
+				Computed:            true,
+				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{types.StringValue("openid")})),
+			},
+			"claim_mappings": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Maps claims of the identity provider's ID token, the keys, to authproxy user attributes such as `email`",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// identityProviderPath returns the path of the identity provider of tenant
+// with the given id.
+func identityProviderPath(tenant string, id string) string {
+	return fmt.Sprintf("/tenants/%s/idps/%s", url.PathEscape(tenant), url.PathEscape(id))
+}
+
+// request builds the create and update request body from data.
+func (data *OIDCIdentityProviderResourceModel) request(ctx context.Context) (oidcIdentityProviderRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	idp := oidcIdentityProviderRequest{
+		Name:          data.Name.ValueString(),
+		Issuer:        data.IssuerURL.ValueString(),
+		ClientID:      data.ClientID.ValueString(),
+		ClientSecret:  data.ClientSecret.ValueString(),
+		Scopes:        []string{},
+		ClaimMappings: map[string]string{},
+	}
+	diags.Append(data.Scopes.ElementsAs(ctx, &idp.Scopes, false)...)
+	if !data.ClaimMappings.IsNull() {
+		diags.Append(data.ClaimMappings.ElementsAs(ctx, &idp.ClaimMappings, false)...)
+	}
+	sort.Strings(idp.Scopes)
+
+	return idp, diags
+}
+
+// setIdentityProvider copies the attributes authproxy returned for an
+// identity provider into data. The client secret keeps its prior value.
+func (data *OIDCIdentityProviderResourceModel) setIdentityProvider(ctx context.Context, idp oidcIdentityProviderResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(idp.ID)
+	data.Name = types.StringValue(idp.Name)
+	data.IssuerURL = types.StringValue(idp.Issuer)
+	data.ClientID = types.StringValue(idp.ClientID)
+
+	scopes, d := types.SetValueFrom(ctx, types.StringType, idp.Scopes)
+	diags.Append(d...)
+	data.Scopes = scopes
+
+	// No claim mappings stay null unless they were configured as an empty
+	// map, so leaving claim_mappings unset does not produce a diff.
+	if len(idp.ClaimMappings) > 0 || !data.ClaimMappings.IsNull() {
+		if idp.ClaimMappings == nil {
+			idp.ClaimMappings = map[string]string{}
+		}
+		claimMappings, d := types.MapValueFrom(ctx, types.StringType, idp.ClaimMappings)
+		diags.Append(d...)
+		data.ClaimMappings = claimMappings
+	}
+
+	return diags
+}
+
+func (r *OIDCIdentityProviderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *OIDCIdentityProviderResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	body.Type = "oidc"
+
+	var idp oidcIdentityProviderResponse
+	err := r.doJSON(ctx, "POST", fmt.Sprintf("/tenants/%s/idps", url.PathEscape(data.Tenant.ValueString())), body, &idp)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create identity provider", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setIdentityProvider(ctx, idp)...)
+
+	tflog.Trace(ctx, "created an oidc identity provider resource", map[string]interface{}{
+		"id": idp.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OIDCIdentityProviderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *OIDCIdentityProviderResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var idp oidcIdentityProviderResponse
+	err := r.doJSON(ctx, "GET", identityProviderPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, &idp)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "identity provider no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"id":     data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read identity provider", err)
+		return
+	}
+	if idp.Type != "" && idp.Type != "oidc" {
+		resp.Diagnostics.AddError(
+			"Unexpected Identity Provider Type",
+			fmt.Sprintf("The identity provider %q of tenant %q is of type %q, not an OIDC identity provider.", data.ID.ValueString(), data.Tenant.ValueString(), idp.Type),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setIdentityProvider(ctx, idp)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OIDCIdentityProviderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *OIDCIdentityProviderResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var idp oidcIdentityProviderResponse
+	err := r.doJSON(ctx, "PATCH", identityProviderPath(data.Tenant.ValueString(), data.ID.ValueString()), body, &idp)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update identity provider", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setIdentityProvider(ctx, idp)...)
+
+	tflog.Trace(ctx, "updated an oidc identity provider resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OIDCIdentityProviderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *OIDCIdentityProviderResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", identityProviderPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete identity provider", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted an oidc identity provider resource")
+}
+
+func (r *OIDCIdentityProviderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, id, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccOIDCIdentityProviderBackend is an in-memory stand-in for the
+// identity provider endpoints of the "acme" tenant. Like authproxy it only
+// ever returns client secrets redacted.
+type testAccOIDCIdentityProviderBackend struct {
+	mu     sync.Mutex
+	idps   map[string]oidcIdentityProviderRequest
+	nextID int
+}
+
+func (b *testAccOIDCIdentityProviderBackend) respond(w http.ResponseWriter, id string) {
+	idp := b.idps[id]
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":             id,
+		"type":           idp.Type,
+		"name":           idp.Name,
+		"issuer":         idp.Issuer,
+		"client_id":      idp.ClientID,
+		"client_secret":  "**REDACTED**",
+		"scopes":         idp.Scopes,
+		"claim_mappings": idp.ClaimMappings,
+	})
+}
+
+func (b *testAccOIDCIdentityProviderBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	const prefix = "/tenants/acme/idps"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		var create oidcIdentityProviderRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.nextID++
+		id = fmt.Sprintf("i%d", b.nextID)
+		b.idps[id] = create
+		b.respond(w, id)
+	case id != "" && r.Method == http.MethodGet:
+		if _, ok := b.idps[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.respond(w, id)
+	case id != "" && r.Method == http.MethodPatch:
+		existing, ok := b.idps[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var update oidcIdentityProviderRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		update.Type = existing.Type
+		b.idps[id] = update
+		b.respond(w, id)
+	case id != "" && r.Method == http.MethodDelete:
+		if _, ok := b.idps[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.idps, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// secret returns the client secret authproxy stores for id.
+func (b *testAccOIDCIdentityProviderBackend) secret(id string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.idps[id].ClientSecret
+}
+
+func TestAccOIDCIdentityProviderResource(t *testing.T) {
+	backend := &testAccOIDCIdentityProviderBackend{idps: map[string]oidcIdentityProviderRequest{}}
+	server := testBackendServer(t, backend, "/tenants/acme/idps")
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if len(backend.idps) != 0 {
+				return fmt.Errorf("expected every identity provider to be deleted, %d left", len(backend.idps))
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccOIDCIdentityProviderResourceConfig("s3cr3t", `email = "email"`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "id", "i1"),
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "client_secret", "s3cr3t"),
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "scopes.#", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "claim_mappings.%", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "claim_mappings.email", "email"),
+				),
+			},
+			// The secret survives a refresh although authproxy redacts it
+			{
+				RefreshState: true,
+				Check:        resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "client_secret", "s3cr3t"),
+			},
+			// ImportState testing, the secret cannot be imported
+			{
+				ResourceName:            "authproxy_oidc_identity_provider.test",
+				ImportState:             true,
+				ImportStateId:           "acme/i1",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"client_secret"},
+			},
+			// Updating the claim mappings
+			{
+				Config: testAccProviderConfig(server.URL) + testAccOIDCIdentityProviderResourceConfig("s3cr3t", `
+    email = "email"
+    groups = "roles"
+`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "id", "i1"),
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "claim_mappings.%", "2"),
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "claim_mappings.groups", "roles"),
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "client_secret", "s3cr3t"),
+				),
+			},
+			// Rotating the secret
+			{
+				Config: testAccProviderConfig(server.URL) + testAccOIDCIdentityProviderResourceConfig("n3w-s3cr3t", `groups = "roles"`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "claim_mappings.%", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_oidc_identity_provider.test", "client_secret", "n3w-s3cr3t"),
+					func(s *terraform.State) error {
+						if secret := backend.secret("i1"); secret != "n3w-s3cr3t" {
+							return fmt.Errorf("expected the secret to be rotated in authproxy, got %q", secret)
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccOIDCIdentityProviderResource_invalidIssuer(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config: testAccProviderConfig("http://127.0.0.1:1") + `
+resource "authproxy_oidc_identity_provider" "test" {
+  tenant        = "acme"
+  name          = "google"
+  issuer_url    = "http://accounts.google.com?tenant=acme"
+  client_id     = "authproxy"
+  client_secret = "s3cr3t"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+		},
+	})
+}
+
+func testAccOIDCIdentityProviderResourceConfig(secret string, claimMappings string) string {
+	return fmt.Sprintf(`
+resource "authproxy_oidc_identity_provider" "test" {
+  tenant        = "acme"
+  name          = "google"
+  issuer_url    = "https://accounts.google.com"
+  client_id     = "authproxy"
+  client_secret = %q
+
+  claim_mappings = {
+    %s
+  }
+}
+`, secret, claimMappings)
+}
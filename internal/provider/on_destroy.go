@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The values of the on_destroy attribute: destroying the resource either
+// deletes the object in authproxy or only removes it from the state.
+const (
+	onDestroyDelete  = "delete"
+	onDestroyAbandon = "abandon"
+)
+
+// onDestroyAttribute is the on_destroy attribute of the resources managing
+// objects of the given kind, such as "tenant". Being computed, changing it
+// updates the resource in place rather than replacing it.
+func onDestroyAttribute(kind string) schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: fmt.Sprintf("What destroying the resource does to the %[1]s: `\"delete\"` (default) deletes it, `\"abandon\"` only removes it from the Terraform state and leaves the %[1]s in authproxy, such as when another system takes it over", kind),
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString(onDestroyDelete),
+		Validators: []validator.String{
+			stringOneOf(onDestroyDelete, onDestroyAbandon),
+		},
+	}
+}
+
+// onDestroyValue returns onDestroy, or its default for states lacking it,
+// such as the ones of imported resources.
+func onDestroyValue(onDestroy types.String) types.String {
+	if onDestroy.IsNull() || onDestroy.IsUnknown() {
+		return types.StringValue(onDestroyDelete)
+	}
+	return onDestroy
+}
+
+// abandon reports whether destroying a resource with the given on_destroy
+// value must leave the object in authproxy, logging that it does. fields
+// identify the object in the log.
+func abandon(ctx context.Context, onDestroy types.String, kind string, fields map[string]interface{}) bool {
+	if onDestroy.ValueString() != onDestroyAbandon {
+		return false
+	}
+	tflog.Info(ctx, fmt.Sprintf("abandoning the %s, it is removed from the state but left in authproxy", kind), fields)
+	return true
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// maxPageSize is the largest page size authproxy accepts.
+	maxPageSize = 500
+	// maxListPages bounds the pages read from a single list endpoint, so a
+	// server handing out a new cursor on every page cannot keep a read going
+	// until it times out.
+	maxListPages = 1000
+)
+
+// page is the envelope paginated authproxy list endpoints answer with.
+type page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// listOptions controls how many items the pagination helpers request and
+// read. Zero values leave the respective limit unset.
+type listOptions struct {
+	// PageSize is sent as the page_size query parameter.
+	PageSize int64
+	// MaxItems stops reading once this many items were collected.
+	MaxItems int64
+	// MaxPages stops reading after this many pages.
+	MaxPages int
+}
+
+// listOptionsFrom builds listOptions from the page_size and max_items
+// arguments of a list data source.
+func listOptionsFrom(pageSize types.Int64, maxItems types.Int64) listOptions {
+	return listOptions{
+		PageSize: pageSize.ValueInt64(),
+		MaxItems: maxItems.ValueInt64(),
+	}
+}
+
+// pageSizeAttribute is the page_size argument shared by list data sources.
+func pageSizeAttribute() schema.Int64Attribute {
+	return schema.Int64Attribute{
+		MarkdownDescription: fmt.Sprintf("Number of items to request per page, between 1 and %d. Defaults to the server's page size", maxPageSize),
+		Optional:            true,
+		Validators: []validator.Int64{
+			int64Between(1, maxPageSize),
+		},
+	}
+}
+
+// maxItemsAttribute is the max_items argument shared by list data sources.
+func maxItemsAttribute() schema.Int64Attribute {
+	return schema.Int64Attribute{
+		MarkdownDescription: "Maximum number of items to read from authproxy before any filtering. A warning is emitted when results are cut off. Defaults to reading every page",
+		Optional:            true,
+		Validators: []validator.Int64{
+			int64Between(1, 1<<31-1),
+		},
+	}
+}
+
+// addTruncationWarning tells the practitioner that max_items cut off the
+// results of a list data source.
+func addTruncationWarning(diags *diag.Diagnostics, what string, maxItems int64) {
+	diags.AddWarning(
+		"Results Truncated",
+		fmt.Sprintf("Only the first %d %s were read because max_items was reached. "+
+			"Raise or remove max_items to read all of them.", maxItems, what),
+	)
+}
+
+// listAll fetches every page of the list endpoint at path and returns the
+// concatenated items. Endpoints that answer with a bare JSON array are treated
+// as a single page. Lists of more than maxListPages pages are an error.
+func listAll[T any](ctx context.Context, p *ProviderData, path string, query url.Values) ([]T, error) {
+	items, _, err := listPages[T](ctx, p, path, query, listOptions{})
+	return items, err
+}
+
+// listPages works like listAll but honours the limits of opts, reporting
+// whether items were left unread because of them.
+//
+// The next page is taken from the next_cursor of the response envelope or,
+// for endpoints paginating through headers, from the rel="next" Link header.
+// Pointing at a page that was read already is an error, as following it would
+// never end, and so is reading more than maxListPages pages without reaching
+// a limit of opts.
+func listPages[T any](ctx context.Context, p *ProviderData, path string, query url.Values, opts listOptions) ([]T, bool, error) {
+	params := url.Values{}
+	for key, values := range query {
+		params[key] = values
+	}
+	if opts.PageSize > 0 {
+		params.Set("page_size", strconv.FormatInt(opts.PageSize, 10))
+	}
+
+	target := path
+	if encoded := params.Encode(); encoded != "" {
+		target += "?" + encoded
+	}
+
+	var items []T
+	read := map[string]bool{}
+	for pages := 1; ; pages++ {
+		read[target] = true
+
+		res, err := p.roundTrip(ctx, "GET", target, nil)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var current page[T]
+		if trimmed := bytes.TrimSpace(res.Body); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(trimmed, &current.Items)
+		} else {
+			err = json.Unmarshal(res.Body, &current)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, current.Items...)
+
+		next := ""
+		if current.NextCursor != "" {
+			params.Set("cursor", current.NextCursor)
+			next = path + "?" + params.Encode()
+		} else if link := nextLink(res.Header.Values("Link")); link != "" {
+			next, err = p.relativePath(link)
+			if err != nil {
+				return nil, false, err
+			}
+		}
+
+		if opts.MaxItems > 0 && int64(len(items)) >= opts.MaxItems {
+			truncated := int64(len(items)) > opts.MaxItems || next != ""
+			return items[:opts.MaxItems], truncated, nil
+		}
+		if next == "" {
+			return items, false, nil
+		}
+		if opts.MaxPages > 0 && pages >= opts.MaxPages {
+			return items, true, nil
+		}
+		if pages >= maxListPages {
+			return nil, false, fmt.Errorf("%s has more than %d pages, stopped reading in case the server never stops paginating", path, maxListPages)
+		}
+		if read[next] {
+			return nil, false, fmt.Errorf("the next page of %s links back to the already read page %s", path, next)
+		}
+		target = next
+	}
+}
+
+// nextLink returns the target of the rel="next" entry of RFC 8288 Link
+// header values, or "" if there is none.
+func nextLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			segments := strings.Split(link, ";")
+			target := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range segments[1:] {
+				name, rel, found := strings.Cut(strings.TrimSpace(param), "=")
+				if !found || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+				for _, r := range strings.Fields(strings.Trim(strings.TrimSpace(rel), `"`)) {
+					if strings.EqualFold(r, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// relativePath resolves link against the configured endpoint and returns it
+// as a path suitable for do. Links leading away from the endpoint are refused
+// so credentials are never sent to another host.
+func (p *ProviderData) relativePath(link string) (string, error) {
+	base, err := url.Parse(strings.TrimSuffix(p.endpoint, "/") + "/")
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid next page link %q: %w", link, err)
+	}
+
+	resolved := base.ResolveReference(ref)
+	basePath := strings.TrimSuffix(base.Path, "/")
+	if resolved.Scheme != base.Scheme || resolved.Host != base.Host || !strings.HasPrefix(resolved.Path, basePath+"/") {
+		return "", fmt.Errorf("next page link %q points outside of the configured endpoint %s", link, p.endpoint)
+	}
+
+	return strings.TrimPrefix(resolved.RequestURI(), basePath), nil
+}
@@ -0,0 +1,322 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testPaginatedUsers returns count users named user000, user001 and so on.
+func testPaginatedUsers(count int) []userResponse {
+	users := make([]userResponse, 0, count)
+	for i := 0; i < count; i++ {
+		users = append(users, userResponse{
+			ID:       fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%03d", i),
+			Email:    fmt.Sprintf("user%03d@example.com", i),
+			Enabled:  true,
+		})
+	}
+	return users
+}
+
+// testPageBounds returns the slice bounds of the page starting at offset.
+func testPageBounds(r *http.Request, total int, offset int) (int, int) {
+	size := 2
+	if value := r.URL.Query().Get("page_size"); value != "" {
+		size, _ = strconv.Atoi(value)
+	}
+	end := offset + size
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
+
+// testCursorServer serves users through next_cursor envelopes.
+func testCursorServer(t *testing.T, users []userResponse, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		start, end := testPageBounds(r, len(users), offset)
+
+		current := page[userResponse]{Items: users[start:end]}
+		if end < len(users) {
+			current.NextCursor = strconv.Itoa(end)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(current); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+// testLinkServer serves users as bare arrays below /api, announcing further
+// pages through absolute Link headers.
+func testLinkServer(t *testing.T, users []userResponse, requests *int) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			t.Errorf("request outside of the endpoint: %s", r.URL)
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		start, end := testPageBounds(r, len(users), offset)
+
+		if end < len(users) {
+			next := r.URL.Query()
+			next.Set("offset", strconv.Itoa(end))
+			w.Header().Add("Link", fmt.Sprintf(`<%s%s?%s>; rel="next", <%s%s>; rel="first"`, server.URL, r.URL.Path, next.Encode(), server.URL, r.URL.Path))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(users[start:end]); err != nil {
+			t.Error(err)
+		}
+	}))
+	return server
+}
+
+func TestListPages(t *testing.T) {
+	servers := map[string]func(*testing.T, []userResponse, *int) *httptest.Server{
+		"cursor": testCursorServer,
+		"link":   testLinkServer,
+	}
+
+	for name, newServer := range servers {
+		t.Run(name, func(t *testing.T) {
+			var requests int
+			server := newServer(t, testPaginatedUsers(6), &requests)
+			defer server.Close()
+
+			endpoint := server.URL
+			if name == "link" {
+				endpoint += "/api"
+			}
+
+			users, truncated, err := listPages[userResponse](context.Background(), testProviderData(endpoint), "/tenants/acme/users", nil, listOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(users) != 6 || truncated {
+				t.Errorf("expected 6 users without truncation, got %d (truncated: %t)", len(users), truncated)
+			}
+			if requests != 3 {
+				t.Errorf("expected 3 page requests, got %d", requests)
+			}
+			if users[5].Username != "user005" {
+				t.Errorf("expected pages in order, got last user %q", users[5].Username)
+			}
+		})
+	}
+}
+
+func TestListPages_pageSize(t *testing.T) {
+	var requests int
+	server := testCursorServer(t, testPaginatedUsers(6), &requests)
+	defer server.Close()
+
+	users, _, err := listPages[userResponse](context.Background(), testProviderData(server.URL), "/tenants/acme/users", nil, listOptions{PageSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 6 || requests != 2 {
+		t.Errorf("expected 6 users over 2 requests, got %d over %d", len(users), requests)
+	}
+}
+
+func TestListPages_maxItems(t *testing.T) {
+	testCases := map[string]struct {
+		maxItems  int64
+		expected  int
+		truncated bool
+	}{
+		"within page": {maxItems: 3, expected: 3, truncated: true},
+		"page border": {maxItems: 4, expected: 4, truncated: true},
+		"all items":   {maxItems: 6, expected: 6, truncated: false},
+		"above total": {maxItems: 10, expected: 6, truncated: false},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var requests int
+			server := testLinkServer(t, testPaginatedUsers(6), &requests)
+			defer server.Close()
+
+			users, truncated, err := listPages[userResponse](context.Background(), testProviderData(server.URL+"/api"), "/users", nil, listOptions{MaxItems: testCase.maxItems})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(users) != testCase.expected || truncated != testCase.truncated {
+				t.Errorf("expected %d users (truncated: %t), got %d (truncated: %t)", testCase.expected, testCase.truncated, len(users), truncated)
+			}
+		})
+	}
+}
+
+func TestListPages_foreignLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<https://attacker.example/users?offset=2>; rel="next"`)
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	_, _, err := listPages[userResponse](context.Background(), testProviderData(server.URL), "/users", nil, listOptions{})
+	if err == nil || !strings.Contains(err.Error(), "outside of the configured endpoint") {
+		t.Fatalf("expected the foreign link to be refused, got: %v", err)
+	}
+}
+
+func TestListPages_repeatedPage(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"cursor": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"items":[{"id":"u1"}],"next_cursor":"again"}`)
+		},
+		"link": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Link", `</users?offset=2>; rel="next"`)
+			fmt.Fprint(w, `[{"id":"u1"}]`)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			_, _, err := listPages[userResponse](context.Background(), testProviderData(server.URL), "/users", nil, listOptions{})
+			if err == nil || !strings.Contains(err.Error(), "links back to the already read page") {
+				t.Fatalf("expected paging in circles to be refused, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestListPages_endlessPages(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"items":[{"id":"u%d"}],"next_cursor":"%d"}`, requests, requests)
+	}))
+	defer server.Close()
+
+	_, err := listAll[userResponse](context.Background(), testProviderData(server.URL), "/users", nil)
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("more than %d pages", maxListPages)) {
+		t.Fatalf("expected an endless list to be refused, got: %v", err)
+	}
+	if requests != maxListPages {
+		t.Errorf("expected %d requests, got %d", maxListPages, requests)
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	testCases := map[string]struct {
+		values   []string
+		expected string
+	}{
+		"none":     {values: nil, expected: ""},
+		"next":     {values: []string{`</users?offset=2>; rel="next"`}, expected: "/users?offset=2"},
+		"unquoted": {values: []string{`</users?offset=2>; rel=next`}, expected: "/users?offset=2"},
+		"multiple": {values: []string{`</users>; rel="first", </users?offset=4>; rel="next"`}, expected: "/users?offset=4"},
+		"split":    {values: []string{`</users>; rel="prev"`, `</users?offset=6>; rel="next last"`}, expected: "/users?offset=6"},
+		"last":     {values: []string{`</users?offset=8>; rel="last"`}, expected: ""},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := nextLink(testCase.values); got != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestUsersDataSource_maxItems(t *testing.T) {
+	var requests int
+	server := testCursorServer(t, testPaginatedUsers(6), &requests)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewUsersDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"tenant":    tftypes.NewValue(tftypes.String, "acme"),
+		"max_items": tftypes.NewValue(tftypes.Number, big.NewFloat(5)),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 || resp.Diagnostics.Warnings()[0].Summary() != "Results Truncated" {
+		t.Fatalf("expected a truncation warning, got: %v", resp.Diagnostics)
+	}
+
+	var data UsersDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	if len(data.Users) != 5 {
+		t.Errorf("expected 5 users, got %d", len(data.Users))
+	}
+}
+
+// FuzzListPages pages through a server answering every page with the same
+// arbitrary body and Link header, which must neither crash nor page forever.
+func FuzzListPages(f *testing.F) {
+	for _, fixture := range contractFixtures(f) {
+		f.Add([]byte(fixture.Body), "")
+	}
+	for _, body := range testFuzzBodies {
+		f.Add([]byte(body), "")
+	}
+	f.Add([]byte(`{"items":[{"id":"a"}],"next_cursor":"2"}`), "")
+	f.Add([]byte(`[{"id":"a"}]`), `</api/users?offset=2>; rel="next"`)
+
+	var mu sync.Mutex
+	var body []byte
+	var link string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if link != "" {
+			w.Header().Set("Link", link)
+		}
+		_, _ = w.Write(body)
+	}))
+	f.Cleanup(server.Close)
+
+	f.Fuzz(func(t *testing.T, fuzzedBody []byte, fuzzedLink string) {
+		mu.Lock()
+		body, link = fuzzedBody, fuzzedLink
+		mu.Unlock()
+
+		ctx := context.Background()
+		_, _, _ = listPages[userResponse](ctx, testProviderData(server.URL+"/api"), "/users", nil, listOptions{})
+		_, _, _ = listPages[json.RawMessage](ctx, testProviderData(server.URL+"/api"), "/users", url.Values{"email": []string{"a@example.com"}}, listOptions{PageSize: 2, MaxItems: 3})
+	})
+}
+
+// FuzzNextLink parses arbitrary Link headers and resolves the next page they
+// announce.
+func FuzzNextLink(f *testing.F) {
+	f.Add(`</users?offset=2>; rel="next"`)
+	f.Add(`</users>; rel="first", </users?offset=4>; rel="next"`)
+	f.Add(`<https://attacker.example/users>; rel=next`)
+	f.Add(`<>; rel="next"`)
+	f.Add(`<; rel="next"`)
+	f.Add(`<%zz>; rel="next"`)
+
+	providerData := testProviderData("https://authproxy.example.com/api")
+	f.Fuzz(func(t *testing.T, value string) {
+		link := nextLink([]string{value})
+		if link == "" {
+			return
+		}
+		if next, err := providerData.relativePath(link); err == nil && !strings.HasPrefix(next, "/") {
+			t.Errorf("expected %q to resolve to a path below the endpoint, got %q", link, next)
+		}
+	})
+}
@@ -0,0 +1,279 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PasswordPolicyResource{}
+var _ resource.ResourceWithImportState = &PasswordPolicyResource{}
+
+func NewPasswordPolicyResource() resource.Resource {
+	return &PasswordPolicyResource{}
+}
+
+// PasswordPolicyResource defines the resource implementation. Every tenant
+// has exactly one password policy, the resource manages it rather than
+// creating one.
+type PasswordPolicyResource struct {
+	BaseResource
+}
+
+// PasswordPolicyResourceModel describes the resource data model.
+type PasswordPolicyResourceModel struct {
+	ID             types.String    `tfsdk:"id"`
+	Tenant         tenantNameValue `tfsdk:"tenant"`
+	MinLength      types.Int64     `tfsdk:"min_length"`
+	RequireSymbols types.Bool      `tfsdk:"require_symbols"`
+	RequireNumbers types.Bool      `tfsdk:"require_numbers"`
+	MaxAgeDays     types.Int64     `tfsdk:"max_age_days"`
+	HistoryCount   types.Int64     `tfsdk:"history_count"`
+}
+
+// passwordPolicyRequest leaves out unconfigured settings, authproxy applies
+// its defaults for them.
+type passwordPolicyRequest struct {
+	MinLength      *int64 `json:"min_length,omitempty"`
+	RequireSymbols *bool  `json:"require_symbols,omitempty"`
+	RequireNumbers *bool  `json:"require_numbers,omitempty"`
+	MaxAgeDays     *int64 `json:"max_age_days,omitempty"`
+	HistoryCount   *int64 `json:"history_count,omitempty"`
+}
+
+type passwordPolicyResponse struct {
+	MinLength      int64 `json:"min_length"`
+	RequireSymbols bool  `json:"require_symbols"`
+	RequireNumbers bool  `json:"require_numbers"`
+	MaxAgeDays     int64 `json:"max_age_days"`
+	HistoryCount   int64 `json:"history_count"`
+}
+
+func (r *PasswordPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_password_policy"
+}
+
+func (r *PasswordPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Password policy of a tenant. Every tenant has exactly one, destroying the resource resets it to the authproxy defaults",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the tenant",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the password policy applies to. Changing it resets the policy of the previous tenant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"min_length": schema.Int64Attribute{
+				MarkdownDescription: "Minimum number of characters of a password. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64Between(1, 128),
+				},
+			},
+			"require_symbols": schema.BoolAttribute{
+				MarkdownDescription: "Whether passwords must contain a symbol. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+			},
+			"require_numbers": schema.BoolAttribute{
+				MarkdownDescription: "Whether passwords must contain a number. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+			},
+			"max_age_days": schema.Int64Attribute{
+				MarkdownDescription: "Days after which a password must be changed, `0` for never. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64Between(0, 3650),
+				},
+			},
+			"history_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of previous passwords that may not be reused, `0` to allow any. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64Between(0, 24),
+				},
+			},
+		},
+	}
+}
+
+// passwordPolicyPath returns the path of the password policy of tenant.
+func passwordPolicyPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/password-policy", url.PathEscape(tenant))
+}
+
+// request builds the request body from the known settings of data.
+func (data *PasswordPolicyResourceModel) request() passwordPolicyRequest {
+	var policy passwordPolicyRequest
+	if !data.MinLength.IsUnknown() {
+		policy.MinLength = data.MinLength.ValueInt64Pointer()
+	}
+	if !data.RequireSymbols.IsUnknown() {
+		policy.RequireSymbols = data.RequireSymbols.ValueBoolPointer()
+	}
+	if !data.RequireNumbers.IsUnknown() {
+		policy.RequireNumbers = data.RequireNumbers.ValueBoolPointer()
+	}
+	if !data.MaxAgeDays.IsUnknown() {
+		policy.MaxAgeDays = data.MaxAgeDays.ValueInt64Pointer()
+	}
+	if !data.HistoryCount.IsUnknown() {
+		policy.HistoryCount = data.HistoryCount.ValueInt64Pointer()
+	}
+	return policy
+}
+
+// setPasswordPolicy copies the settings authproxy returned into data.
+func (data *PasswordPolicyResourceModel) setPasswordPolicy(policy passwordPolicyResponse) {
+	data.ID = types.StringValue(data.Tenant.ValueString())
+	data.MinLength = types.Int64Value(policy.MinLength)
+	data.RequireSymbols = types.BoolValue(policy.RequireSymbols)
+	data.RequireNumbers = types.BoolValue(policy.RequireNumbers)
+	data.MaxAgeDays = types.Int64Value(policy.MaxAgeDays)
+	data.HistoryCount = types.Int64Value(policy.HistoryCount)
+}
+
+func (r *PasswordPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *PasswordPolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Authproxy refuses to overwrite a policy that was already customized,
+	// which keeps two resources from fighting over the same tenant.
+	var policy passwordPolicyResponse
+	err := r.doJSON(withRequestHeader(ctx, "If-None-Match", "*"), "PUT", passwordPolicyPath(data.Tenant.ValueString()), data.request(), &policy)
+	if isStatus(err, http.StatusConflict) {
+		resp.Diagnostics.AddError(
+			"Password Policy Already Managed",
+			fmt.Sprintf("The password policy of tenant %q has already been customized, possibly by another authproxy_password_policy resource. "+
+				"Every tenant has a single password policy, import it instead of creating it:\n\n"+
+				"terraform import authproxy_password_policy.<name> %s", data.Tenant.ValueString(), data.Tenant.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "set password policy", err)
+		return
+	}
+
+	data.setPasswordPolicy(policy)
+
+	tflog.Trace(ctx, "created a password policy resource", map[string]interface{}{
+		"tenant": data.Tenant.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PasswordPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *PasswordPolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy passwordPolicyResponse
+	err := r.doJSON(ctx, "GET", passwordPolicyPath(data.Tenant.ValueString()), nil, &policy)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant no longer exists, removing its password policy from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read password policy", err)
+		return
+	}
+
+	data.setPasswordPolicy(policy)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PasswordPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *PasswordPolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy passwordPolicyResponse
+	err := r.doJSON(ctx, "PUT", passwordPolicyPath(data.Tenant.ValueString()), data.request(), &policy)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update password policy", err)
+		return
+	}
+
+	data.setPasswordPolicy(policy)
+
+	tflog.Trace(ctx, "updated a password policy resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PasswordPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *PasswordPolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting the policy resets it to the authproxy defaults.
+	err := r.doJSON(ctx, "DELETE", passwordPolicyPath(data.Tenant.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "reset password policy", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a password policy resource")
+}
+
+func (r *PasswordPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("tenant"), req, resp)
+}
@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+var testAccDefaultPasswordPolicy = passwordPolicyResponse{MinLength: 8, MaxAgeDays: 0, HistoryCount: 0}
+
+// testAccPasswordPolicyBackend is an in-memory stand-in for the password
+// policy endpoints. Like authproxy it refuses conditional writes to policies
+// that were already customized.
+type testAccPasswordPolicyBackend struct {
+	mu         sync.Mutex
+	policies   map[string]passwordPolicyResponse
+	customized map[string]bool
+}
+
+func (b *testAccPasswordPolicyBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tenant, found := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/tenants/"), "/password-policy")
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	policy, ok := b.policies[tenant]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if r.Header.Get("If-None-Match") == "*" && b.customized[tenant] {
+			http.Error(w, "password policy already customized", http.StatusConflict)
+			return
+		}
+		var update passwordPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		policy = testAccDefaultPasswordPolicy
+		if update.MinLength != nil {
+			policy.MinLength = *update.MinLength
+		}
+		if update.RequireSymbols != nil {
+			policy.RequireSymbols = *update.RequireSymbols
+		}
+		if update.RequireNumbers != nil {
+			policy.RequireNumbers = *update.RequireNumbers
+		}
+		if update.MaxAgeDays != nil {
+			policy.MaxAgeDays = *update.MaxAgeDays
+		}
+		if update.HistoryCount != nil {
+			policy.HistoryCount = *update.HistoryCount
+		}
+		b.policies[tenant] = policy
+		b.customized[tenant] = true
+	case http.MethodDelete:
+		b.policies[tenant] = testAccDefaultPasswordPolicy
+		b.customized[tenant] = false
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(policy)
+}
+
+func TestAccPasswordPolicyResource(t *testing.T) {
+	backend := &testAccPasswordPolicyBackend{
+		policies:   map[string]passwordPolicyResponse{"acme": testAccDefaultPasswordPolicy},
+		customized: map[string]bool{},
+	}
+	server := testBackendServer(t, backend, passwordPolicyPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if backend.customized["acme"] || backend.policies["acme"] != testAccDefaultPasswordPolicy {
+				return fmt.Errorf("expected the password policy to be reset, got %+v", backend.policies["acme"])
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_password_policy" "test" {
+  tenant          = "acme"
+  min_length      = 12
+  require_symbols = true
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_password_policy.test", "id", "acme"),
+					resourcetest.TestCheckResourceAttr("authproxy_password_policy.test", "min_length", "12"),
+					resourcetest.TestCheckResourceAttr("authproxy_password_policy.test", "require_symbols", "true"),
+					resourcetest.TestCheckResourceAttr("authproxy_password_policy.test", "require_numbers", "false"),
+					resourcetest.TestCheckResourceAttr("authproxy_password_policy.test", "history_count", "0"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_password_policy.test",
+				ImportState:       true,
+				ImportStateId:     "acme",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_password_policy" "test" {
+  tenant          = "acme"
+  min_length      = 16
+  require_symbols = true
+  require_numbers = true
+  max_age_days    = 90
+  history_count   = 5
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_password_policy.test", "min_length", "16"),
+					resourcetest.TestCheckResourceAttr("authproxy_password_policy.test", "require_numbers", "true"),
+					resourcetest.TestCheckResourceAttr("authproxy_password_policy.test", "max_age_days", "90"),
+					resourcetest.TestCheckResourceAttr("authproxy_password_policy.test", "history_count", "5"),
+				),
+			},
+			// A second resource for the same tenant is refused
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_password_policy" "test" {
+  tenant          = "acme"
+  min_length      = 16
+  require_symbols = true
+  require_numbers = true
+  max_age_days    = 90
+  history_count   = 5
+}
+
+resource "authproxy_password_policy" "duplicate" {
+  tenant = "acme"
+}
+`,
+				ExpectError: regexp.MustCompile(`Password Policy Already Managed`),
+			},
+			// Reset-on-destroy is verified by CheckDestroy
+		},
+	})
+}
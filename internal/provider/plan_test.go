@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testResourceValue encodes values as the state or configuration of a
+// resource of typeName, leaving missing attributes null. A nil map encodes
+// the null object of a resource that does not exist.
+func testResourceValue(t *testing.T, server tfprotov6.ProviderServer, typeName string, values map[string]tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objectType := schemaResp.ResourceSchemas[typeName].ValueType().(tftypes.Object)
+	object := tftypes.NewValue(objectType, nil)
+	if values != nil {
+		all := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+		for name, attributeType := range objectType.AttributeTypes {
+			if value, ok := values[name]; ok {
+				all[name] = value
+				continue
+			}
+			all[name] = tftypes.NewValue(attributeType, nil)
+		}
+		object = tftypes.NewValue(objectType, all)
+	}
+
+	value, err := tfprotov6.NewDynamicValue(objectType, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &value
+}
+
+// testResourceValues decodes the state or plan of a resource of typeName.
+func testResourceValues(t *testing.T, server tfprotov6.ProviderServer, typeName string, value *tfprotov6.DynamicValue) map[string]tftypes.Value {
+	t.Helper()
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	object, err := value.Unmarshal(schemaResp.ResourceSchemas[typeName].ValueType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testStateValues(t, object)
+}
+
+// testProviderPlan plans the change of a resource of typeName from prior to
+// config through server, the way Terraform does for terraform plan. A nil
+// prior plans the creation of the resource.
+func testProviderPlan(t *testing.T, server tfprotov6.ProviderServer, typeName string, prior map[string]tftypes.Value, config map[string]tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+	t.Helper()
+
+	resp, err := server.PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       testResourceValue(t, server, typeName, prior),
+		ProposedNewState: testResourceValue(t, server, typeName, config),
+		Config:           testResourceValue(t, server, typeName, config),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// testProviderApply plans and applies the change of a resource of typeName
+// from prior to config through server, returning the new state.
+func testProviderApply(t *testing.T, server tfprotov6.ProviderServer, typeName string, prior map[string]tftypes.Value, config map[string]tftypes.Value) map[string]tftypes.Value {
+	t.Helper()
+
+	resp := testProviderApplyResponse(t, server, typeName, prior, config)
+	if len(resp.Diagnostics) != 0 {
+		t.Fatalf("unexpected apply diagnostics: %v", resp.Diagnostics)
+	}
+	return testResourceValues(t, server, typeName, resp.NewState)
+}
+
+// testProviderApplyResponse works like testProviderApply but returns the
+// response of the apply, for applies expected to fail. The plan must
+// succeed.
+func testProviderApplyResponse(t *testing.T, server tfprotov6.ProviderServer, typeName string, prior map[string]tftypes.Value, config map[string]tftypes.Value) *tfprotov6.ApplyResourceChangeResponse {
+	t.Helper()
+
+	planResp := testProviderPlan(t, server, typeName, prior, config)
+	if len(planResp.Diagnostics) != 0 {
+		t.Fatalf("unexpected plan diagnostics: %v", planResp.Diagnostics)
+	}
+
+	resp, err := server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   testResourceValue(t, server, typeName, prior),
+		PlannedState: planResp.PlannedState,
+		Config:       testResourceValue(t, server, typeName, config),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// testProviderRefresh reads a resource of typeName through server the way
+// Terraform refreshes it before planning.
+func testProviderRefresh(t *testing.T, server tfprotov6.ProviderServer, typeName string, state map[string]tftypes.Value) map[string]tftypes.Value {
+	t.Helper()
+
+	resp, err := server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: testResourceValue(t, server, typeName, state),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 0 {
+		t.Fatalf("unexpected read diagnostics: %v", resp.Diagnostics)
+	}
+	return testResourceValues(t, server, typeName, resp.NewState)
+}
+
+func TestResourcePlan_immutableAttributes(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+
+	for name, tc := range map[string]struct {
+		typeName  string
+		prior     map[string]tftypes.Value
+		attribute string
+		value     tftypes.Value
+		expected  string
+	}{
+		"role tenant": {
+			typeName:  "authproxy_role",
+			prior:     map[string]tftypes.Value{"id": str("acme/admin"), "name": str("admin"), "tenant": str("acme")},
+			attribute: "tenant",
+			value:     str("lidl"),
+			expected:  `Changing tenant from "acme" to "lidl" destroys and recreates the resource, as authproxy cannot move roles between tenants.`,
+		},
+		"role binding principal": {
+			typeName: "authproxy_role_binding",
+			prior: map[string]tftypes.Value{
+				"id":             str("acme/admin/user/42"),
+				"tenant":         str("acme"),
+				"role":           str("admin"),
+				"principal_type": str("user"),
+				"principal_id":   str("42"),
+			},
+			attribute: "principal_id",
+			value:     str("43"),
+			expected:  "granting the role to another principal creates a new binding",
+		},
+		"user username": {
+			typeName:  "authproxy_user",
+			prior:     map[string]tftypes.Value{"id": str("42"), "tenant": str("acme"), "username": str("alice")},
+			attribute: "username",
+			value:     str("bob"),
+			expected:  "authproxy does not support renaming users",
+		},
+		"api token expiry": {
+			typeName:  "authproxy_api_token",
+			prior:     map[string]tftypes.Value{"id": str("tok-1"), "tenant": str("acme"), "name": str("ci"), "expires_at": str("2030-01-01T00:00:00Z")},
+			attribute: "expires_at",
+			value:     str("2031-01-01T00:00:00Z"),
+			expected:  "the expiry of a token is fixed when it is issued",
+		},
+		"group membership unknown group": {
+			typeName:  "authproxy_group_membership",
+			prior:     map[string]tftypes.Value{"id": str("acme/admins/42"), "tenant": str("acme"), "group": str("admins"), "user_id": str("42")},
+			attribute: "group",
+			value:     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			expected:  `Changing group from "admins" to a value known after apply`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := make(map[string]tftypes.Value, len(tc.prior))
+			for attribute, value := range tc.prior {
+				if attribute != "id" {
+					config[attribute] = value
+				}
+			}
+
+			// Unchanged attributes plan an update in place without warnings.
+			server := providerserver.NewProtocol6(New("test")())()
+			resp := testProviderPlan(t, server, tc.typeName, tc.prior, config)
+			if len(resp.RequiresReplace) != 0 || len(resp.Diagnostics) != 0 {
+				t.Fatalf("expected no replacement and no diagnostics, got %v and %v", resp.RequiresReplace, resp.Diagnostics)
+			}
+
+			config[tc.attribute] = tc.value
+			resp = testProviderPlan(t, server, tc.typeName, tc.prior, config)
+
+			attributePath := tftypes.NewAttributePath().WithAttributeName(tc.attribute)
+			if len(resp.RequiresReplace) != 1 || !resp.RequiresReplace[0].Equal(attributePath) {
+				t.Errorf("expected %s to require replacement, got %v", tc.attribute, resp.RequiresReplace)
+			}
+			if len(resp.Diagnostics) != 1 {
+				t.Fatalf("expected a single warning, got %v", resp.Diagnostics)
+			}
+			diagnostic := resp.Diagnostics[0]
+			if diagnostic.Severity != tfprotov6.DiagnosticSeverityWarning || !diagnostic.Attribute.Equal(attributePath) {
+				t.Errorf("expected a warning about %s, got %v", tc.attribute, diagnostic)
+			}
+			if !strings.Contains(diagnostic.Detail, tc.expected) {
+				t.Errorf("expected the warning to contain %q, got %q", tc.expected, diagnostic.Detail)
+			}
+		})
+	}
+}
+
+// testDerivedAttributes are computed attributes that are derived during the
+// plan rather than kept from the state.
+var testDerivedAttributes = map[string]bool{
+	// Derived from certificate_pem in ModifyPlan.
+	"authproxy_certificate.fingerprint": true,
+	"authproxy_certificate.not_after":   true,
+}
+
+// TestResourceSchemas_useStateForUnknown guards against spurious
+// "(known after apply)" values in plans: every attribute only authproxy sets
+// has to keep its value from the state.
+func TestResourceSchemas_useStateForUnknown(t *testing.T) {
+	ctx := context.Background()
+	useStateForUnknown := []interface{}{
+		stringplanmodifier.UseStateForUnknown(),
+		int64planmodifier.UseStateForUnknown(),
+		boolplanmodifier.UseStateForUnknown(),
+		listplanmodifier.UseStateForUnknown(),
+		setplanmodifier.UseStateForUnknown(),
+		mapplanmodifier.UseStateForUnknown(),
+	}
+
+	for _, newResource := range New("test")().Resources(ctx) {
+		r := newResource()
+		metadataResp := &resource.MetadataResponse{}
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "authproxy"}, metadataResp)
+		schemaResp := &resource.SchemaResponse{}
+		r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+		for name, attribute := range schemaResp.Schema.Attributes {
+			if !attribute.IsComputed() || attribute.IsOptional() || testDerivedAttributes[metadataResp.TypeName+"."+name] {
+				continue
+			}
+
+			var modifiers []interface{}
+			switch attribute := attribute.(type) {
+			case schema.StringAttribute:
+				for _, modifier := range attribute.PlanModifiers {
+					modifiers = append(modifiers, modifier)
+				}
+			case schema.Int64Attribute:
+				for _, modifier := range attribute.PlanModifiers {
+					modifiers = append(modifiers, modifier)
+				}
+			case schema.BoolAttribute:
+				for _, modifier := range attribute.PlanModifiers {
+					modifiers = append(modifiers, modifier)
+				}
+			case schema.ListAttribute:
+				for _, modifier := range attribute.PlanModifiers {
+					modifiers = append(modifiers, modifier)
+				}
+			case schema.SetAttribute:
+				for _, modifier := range attribute.PlanModifiers {
+					modifiers = append(modifiers, modifier)
+				}
+			case schema.MapAttribute:
+				for _, modifier := range attribute.PlanModifiers {
+					modifiers = append(modifiers, modifier)
+				}
+			default:
+				t.Errorf("%s.%s: unexpected attribute type %T", metadataResp.TypeName, name, attribute)
+				continue
+			}
+
+			found := false
+			for _, modifier := range modifiers {
+				for _, expected := range useStateForUnknown {
+					found = found || reflect.TypeOf(modifier) == reflect.TypeOf(expected)
+				}
+			}
+			if !found {
+				t.Errorf("%s.%s: expected the computed attribute to use its state for unknown values", metadataResp.TypeName, name)
+			}
+		}
+	}
+}
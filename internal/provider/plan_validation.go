@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// validatePath is the dry-run endpoint of authproxy, which checks a proposed
+// object against the server policy, such as name policies, scope existence
+// and quotas, without creating it.
+const validatePath = "/validate"
+
+// planValidation is the state of plan_validation.
+type planValidation struct {
+	enabled bool
+
+	mu sync.Mutex
+	// unavailable is set once authproxy answered that it has no dry-run
+	// endpoint, which is only warned about once.
+	unavailable bool
+}
+
+type validateRequest struct {
+	Operation string      `json:"operation"`
+	Kind      string      `json:"kind"`
+	Tenant    string      `json:"tenant,omitempty"`
+	Object    interface{} `json:"object"`
+}
+
+type validateResponse struct {
+	Errors []validationProblem `json:"errors"`
+}
+
+// validationProblem is a policy violation of a proposed object. Field names
+// the violating field of the object as sent, empty for violations of the
+// object as a whole such as exhausted quotas.
+type validationProblem struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// validatePlanned submits object, the planned kind of object of tenant as it
+// would be sent by the create or update operation, to the dry-run endpoint
+// when plan_validation is set. The violations authproxy reports are added to
+// diags as errors at the attribute fields maps their field to, or at none.
+// Failing to validate only warns, as applying reports the violations anyway.
+func (p *ProviderData) validatePlanned(ctx context.Context, diags *diag.Diagnostics, operation string, kind string, tenant string, object interface{}, fields map[string]path.Path) {
+	if p == nil || !p.planValidation.enabled {
+		return
+	}
+	p.planValidation.mu.Lock()
+	unavailable := p.planValidation.unavailable
+	p.planValidation.mu.Unlock()
+	if unavailable {
+		return
+	}
+
+	var validation validateResponse
+	err := p.doJSON(ctx, "POST", validatePath, validateRequest{Operation: operation, Kind: kind, Tenant: tenant, Object: object}, &validation)
+	if isStatus(err, http.StatusNotFound) {
+		p.planValidation.mu.Lock()
+		defer p.planValidation.mu.Unlock()
+		if !p.planValidation.unavailable {
+			p.planValidation.unavailable = true
+			diags.AddWarning(
+				"Plan Validation Unavailable",
+				"plan_validation is set but authproxy has no dry-run endpoint, so policy violations only surface during apply. "+
+					"Upgrade authproxy or unset plan_validation.",
+			)
+		}
+		return
+	}
+	if err != nil {
+		diags.AddWarning(
+			"Plan Validation Failed",
+			fmt.Sprintf("The planned %s could not be validated by authproxy, so policy violations only surface during apply: %s", kind, err),
+		)
+		return
+	}
+
+	for _, problem := range validation.Errors {
+		summary := "Rejected by Authproxy Policy"
+		detail := fmt.Sprintf("Authproxy would reject the planned %s: %s", kind, problem.Message)
+		if attribute, ok := fields[problem.Field]; ok {
+			diags.AddAttributeError(attribute, summary, detail)
+			continue
+		}
+		diags.AddError(summary, detail)
+	}
+}
+
+// plannedKnown reports whether values, the planned values submitted for
+// validation, are known down to the elements of lists. Plans depending on
+// values known only during apply are not validated.
+func plannedKnown(ctx context.Context, values ...attr.Value) bool {
+	for _, value := range values {
+		if value.IsUnknown() {
+			tflog.Debug(ctx, "skipping plan validation of a plan with unknown values")
+			return false
+		}
+		if list, ok := value.(types.List); ok && !plannedKnown(ctx, list.Elements()...) {
+			return false
+		}
+	}
+	return true
+}
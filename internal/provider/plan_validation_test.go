@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPlanValidation(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	unknown := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	role := func(tenant tftypes.Value, name string, attribute string, scopes ...string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{"tenant": tenant, "name": str(name), attribute: stringList(scopes...)}
+	}
+
+	for name, tc := range map[string]struct {
+		disabled  bool
+		typeName  string
+		prior     map[string]tftypes.Value
+		config    map[string]tftypes.Value
+		errors    map[string]string
+		validated bool
+	}{
+		"valid tenant": {
+			typeName:  "authproxy_tenant",
+			config:    map[string]tftypes.Value{"name": str("lidl")},
+			validated: true,
+		},
+		"reserved tenant name": {
+			typeName:  "authproxy_tenant",
+			config:    map[string]tftypes.Value{"name": str("system")},
+			errors:    map[string]string{"name": "the name system is reserved"},
+			validated: true,
+		},
+		"renamed to a reserved tenant name": {
+			typeName:  "authproxy_tenant",
+			prior:     map[string]tftypes.Value{"id": str("00000000-0000-4000-8000-000000000001"), "name": str("acme"), "on_destroy": str("delete"), "wait_for_ready": tftypes.NewValue(tftypes.Bool, true), "status": str("active")},
+			config:    map[string]tftypes.Value{"name": str("system")},
+			errors:    map[string]string{"name": "the name system is reserved"},
+			validated: true,
+		},
+		"valid role": {
+			typeName:  "authproxy_role",
+			config:    role(str("acme"), "viewer", "permissions", "billing:read"),
+			validated: true,
+		},
+		"unknown permission": {
+			typeName:  "authproxy_role",
+			config:    role(str("acme"), "viewer", "permissions", "billing:read", "billing:export"),
+			errors:    map[string]string{"permissions": "the scope billing:export does not exist"},
+			validated: true,
+		},
+		"unknown deprecated scope": {
+			typeName:  "authproxy_role",
+			config:    role(str("acme"), "system", "scopes", "billing:export"),
+			errors:    map[string]string{"name": "the name system is reserved", "scopes": "the scope billing:export does not exist"},
+			validated: true,
+		},
+		"unknown tenant": {
+			typeName: "authproxy_role",
+			config:   role(unknown, "system", "permissions", "billing:read"),
+		},
+		"unknown scope": {
+			typeName: "authproxy_role",
+			config:   map[string]tftypes.Value{"tenant": str("acme"), "name": str("system"), "permissions": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{unknown})},
+		},
+		"unchanged": {
+			typeName: "authproxy_tenant",
+			prior:    map[string]tftypes.Value{"id": str("00000000-0000-4000-8000-000000000001"), "name": str("system"), "on_destroy": str("delete"), "wait_for_ready": tftypes.NewValue(tftypes.Bool, true), "status": str("active")},
+			config:   map[string]tftypes.Value{"name": str("system")},
+		},
+		"disabled": {
+			disabled: true,
+			typeName: "authproxy_tenant",
+			config:   map[string]tftypes.Value{"name": str("system")},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := testserver.New(t)
+			backend.SetPolicy(testserver.Policy{ReservedNames: []string{"system"}, Scopes: []string{"billing:read", "billing:write"}})
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint":        str(backend.URL),
+				"username":        str(testserver.Username),
+				"password":        str(testserver.Password),
+				"plan_validation": tftypes.NewValue(tftypes.Bool, !tc.disabled),
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			resp := testProviderPlan(t, server, tc.typeName, tc.prior, tc.config)
+			if len(resp.Diagnostics) != len(tc.errors) {
+				t.Errorf("expected %d errors, got %v", len(tc.errors), resp.Diagnostics)
+			}
+			for attribute, message := range tc.errors {
+				found := false
+				for _, diagnostic := range resp.Diagnostics {
+					found = found || diagnostic.Severity == tfprotov6.DiagnosticSeverityError &&
+						diagnostic.Summary == "Rejected by Authproxy Policy" &&
+						diagnostic.Attribute.Equal(tftypes.NewAttributePath().WithAttributeName(attribute)) &&
+						strings.Contains(diagnostic.Detail, message)
+				}
+				if !found {
+					t.Errorf("expected an error at %s mentioning %q, got %v", attribute, message, resp.Diagnostics)
+				}
+			}
+
+			validations := 0
+			for _, request := range backend.Requests() {
+				if request.Method == http.MethodPost && request.Path == "/validate" {
+					validations++
+				}
+			}
+			if validated := validations == 1; validated != tc.validated || validations > 1 {
+				t.Errorf("expected validating the plan to be %t, got %d requests", tc.validated, validations)
+			}
+		})
+	}
+}
+
+func TestPlanValidation_throttled(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	backend := testserver.New(t)
+	backend.SetPolicy(testserver.Policy{ReservedNames: []string{"system"}})
+	backend.SetChaos(testserver.Chaos{Rate: 1, Faults: []testserver.Fault{testserver.FaultTooManyRequests}, Methods: []string{http.MethodPost}})
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint":        str(backend.URL),
+		"username":        str(testserver.Username),
+		"password":        str(testserver.Password),
+		"plan_validation": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	// Throttled validations are retried like every request and only warn
+	// once authproxy keeps throttling them.
+	resp := testProviderPlan(t, server, "authproxy_tenant", nil, map[string]tftypes.Value{"name": str("system")})
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Severity != tfprotov6.DiagnosticSeverityWarning || resp.Diagnostics[0].Summary != "Plan Validation Failed" {
+		t.Errorf("expected a Plan Validation Failed warning, got %v", resp.Diagnostics)
+	}
+	if faults := backend.InjectedFaults(); faults != defaultRetryAttempts {
+		t.Errorf("expected the validation to be attempted %d times, got %d", defaultRetryAttempts, faults)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PolicyResource{}
+var _ resource.ResourceWithImportState = &PolicyResource{}
+
+func NewPolicyResource() resource.Resource {
+	return &PolicyResource{}
+}
+
+// PolicyResource defines the resource implementation.
+type PolicyResource struct {
+	BaseResource
+}
+
+// PolicyResourceModel describes the resource data model.
+type PolicyResourceModel struct {
+	ID       types.String      `tfsdk:"id"`
+	Tenant   tenantNameValue   `tfsdk:"tenant"`
+	Name     types.String      `tfsdk:"name"`
+	Document jsonDocumentValue `tfsdk:"document"`
+}
+
+type policyCreateRequest struct {
+	Name     string          `json:"name"`
+	Document json.RawMessage `json:"document"`
+}
+
+type policyUpdateRequest struct {
+	Document json.RawMessage `json:"document"`
+}
+
+type policyResponse struct {
+	Name     string          `json:"name"`
+	Document json.RawMessage `json:"document"`
+}
+
+func (r *PolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policy"
+}
+
+func (r *PolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Fine-grained access policy of a tenant",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the form `tenant/name`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the policy is attached to. Changing it recreates the policy",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the policy. Changing it recreates the policy",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"document": schema.StringAttribute{
+				CustomType:          jsonDocumentType{},
+				MarkdownDescription: "JSON policy document, usually built with `jsonencode`. Differences in key order and whitespace are ignored",
+				Required:            true,
+			},
+		},
+	}
+}
+
+// policiesPath returns the path of the policies collection of tenant.
+func policiesPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/policies", url.PathEscape(tenant))
+}
+
+// policyPath returns the path of the policy of tenant named name.
+func policyPath(tenant string, name string) string {
+	return policiesPath(tenant) + "/" + url.PathEscape(name)
+}
+
+// setPolicy copies the attributes authproxy returned for a policy into data.
+func (data *PolicyResourceModel) setPolicy(policy policyResponse) {
+	data.ID = types.StringValue(data.Tenant.ValueString() + "/" + policy.Name)
+	data.Name = types.StringValue(policy.Name)
+	data.Document = jsonDocumentStringValue(string(policy.Document))
+}
+
+func (r *PolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *PolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy policyResponse
+	err := r.doJSON(ctx, "POST", policiesPath(data.Tenant.ValueString()), policyCreateRequest{
+		Name:     data.Name.ValueString(),
+		Document: json.RawMessage(data.Document.ValueString()),
+	}, &policy)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create policy", err)
+		return
+	}
+
+	data.setPolicy(policy)
+
+	tflog.Trace(ctx, "created a policy resource", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *PolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy policyResponse
+	err := r.doJSON(ctx, "GET", policyPath(data.Tenant.ValueString(), data.Name.ValueString()), nil, &policy)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "policy no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"name":   data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read policy", err)
+		return
+	}
+
+	// The document is kept as configured as long as authproxy's serialization
+	// of it is semantically equal.
+	data.setPolicy(policy)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *PolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy policyResponse
+	err := r.doJSON(ctx, "PATCH", policyPath(data.Tenant.ValueString(), data.Name.ValueString()), policyUpdateRequest{
+		Document: json.RawMessage(data.Document.ValueString()),
+	}, &policy)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update policy", err)
+		return
+	}
+
+	data.setPolicy(policy)
+
+	tflog.Trace(ctx, "updated a policy resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *PolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", policyPath(data.Tenant.ValueString(), data.Name.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete policy", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a policy resource")
+}
+
+func (r *PolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, name, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || name == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/name, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
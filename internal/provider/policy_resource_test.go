@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccPolicyBackend is an in-memory stand-in for the policies endpoints
+// of the "acme" tenant. Like authproxy it stores documents parsed and hands
+// them back re-serialized, with sorted keys and without whitespace.
+type testAccPolicyBackend struct {
+	mu       sync.Mutex
+	policies map[string]interface{}
+}
+
+func (b *testAccPolicyBackend) respond(w http.ResponseWriter, name string) {
+	document, _ := json.Marshal(b.policies[name])
+	_ = json.NewEncoder(w).Encode(policyResponse{Name: name, Document: document})
+}
+
+func (b *testAccPolicyBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	const prefix = "/tenants/acme/policies"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case name == "" && r.Method == http.MethodPost:
+		var create struct {
+			Name     string      `json:"name"`
+			Document interface{} `json:"document"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.policies[create.Name] = create.Document
+		b.respond(w, create.Name)
+	case name != "" && r.Method == http.MethodGet:
+		if _, ok := b.policies[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.respond(w, name)
+	case name != "" && r.Method == http.MethodPatch:
+		if _, ok := b.policies[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var update struct {
+			Document interface{} `json:"document"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.policies[name] = update.Document
+		b.respond(w, name)
+	case name != "" && r.Method == http.MethodDelete:
+		if _, ok := b.policies[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.policies, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAccPolicyResource(t *testing.T) {
+	backend := &testAccPolicyBackend{policies: map[string]interface{}{}}
+	server := testBackendServer(t, backend, policiesPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if len(backend.policies) != 0 {
+				return fmt.Errorf("expected every policy to be deleted, %d left", len(backend.policies))
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, the re-serialized document must not
+			// produce a diff
+			{
+				Config: testAccProviderConfig(server.URL) + testAccPolicyResourceConfig(`{
+      "statements": [ { "effect": "allow", "actions": ["billing:read"] } ],
+      "version": 1
+    }`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_policy.test", "id", "acme/billing"),
+					resourcetest.TestMatchResourceAttr("authproxy_policy.test", "document", regexp.MustCompile(`"statements": \[ \{`)),
+				),
+			},
+			// ImportState testing, the imported document is authproxy's
+			// serialization
+			{
+				ResourceName:            "authproxy_policy.test",
+				ImportState:             true,
+				ImportStateId:           "acme/billing",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"document"},
+			},
+			// Refreshing keeps the configured document as authproxy's
+			// serialization of it is semantically equal
+			{
+				RefreshState: true,
+				Check:        resourcetest.TestMatchResourceAttr("authproxy_policy.test", "document", regexp.MustCompile(`"statements": \[ \{`)),
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccPolicyResourceConfig(`{"version":1,"statements":[{"effect":"deny","actions":["billing:read"]}]}`),
+				Check: func(s *terraform.State) error {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					document, _ := json.Marshal(backend.policies["billing"])
+					if !strings.Contains(string(document), `"deny"`) {
+						return fmt.Errorf("expected the document to be updated, got: %s", document)
+					}
+					return nil
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccPolicyResource_invalidDocument(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config:      testAccProviderConfig("http://127.0.0.1:1") + testAccPolicyResourceConfig(`{"version": 1,`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid JSON Document`),
+			},
+		},
+	})
+}
+
+func testAccPolicyResourceConfig(document string) string {
+	return fmt.Sprintf(`
+resource "authproxy_policy" "test" {
+  tenant   = "acme"
+  name     = "billing"
+  document = <<-EOT
+    %s
+  EOT
+}
+`, document)
+}
+
+func TestJSONDocumentValue_semanticEquals(t *testing.T) {
+	cases := map[string]struct {
+		prior    string
+		proposed string
+		equal    bool
+	}{
+		"identical":  {`{"a":1}`, `{"a":1}`, true},
+		"whitespace": {`{"a":1,"b":[1,2]}`, "{\n  \"a\": 1,\n  \"b\": [ 1, 2 ]\n}\n", true},
+		"key order":  {`{"a":1,"b":{"c":true,"d":null}}`, `{"b":{"d":null,"c":true},"a":1}`, true},
+		"number":     {`{"a":1}`, `{"a":1.0}`, true},
+		"value":      {`{"a":1}`, `{"a":2}`, false},
+		"list order": {`[1,2]`, `[2,1]`, false},
+		"invalid":    {`{"a":1}`, `{"a":`, false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			equal, diags := jsonDocumentStringValue(c.prior).StringSemanticEquals(context.Background(), jsonDocumentStringValue(c.proposed))
+			if diags.HasError() {
+				t.Fatalf("unexpected error diagnostics: %v", diags)
+			}
+			if equal != c.equal {
+				t.Errorf("expected semantic equality %t, got %t", c.equal, equal)
+			}
+		})
+	}
+}
+
+func TestJSONDocumentType_validate(t *testing.T) {
+	for document, valid := range map[string]bool{
+		`{"version":1}`:   true,
+		`[]`:              true,
+		`{"version":1,`:   false,
+		`version: 1`:      false,
+		`{"a":1} {"b":2}`: false,
+	} {
+		diags := jsonDocumentType{}.Validate(context.Background(), tftypes.NewValue(tftypes.String, document), path.Root("document"))
+		if diags.HasError() == valid {
+			t.Errorf("expected %q to be valid=%t, got diagnostics: %v", document, valid, diags)
+		}
+	}
+}
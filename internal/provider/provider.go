@@ -5,12 +5,41 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"github.com/4thel00z/terraform-provider-authproxy/internal/validators"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultRequestTimeout is used when request_timeout is not configured.
+const defaultRequestTimeout = 30 * time.Second
+
+// defaultSlowRequestThreshold is used when slow_request_threshold is not
+// configured.
+const defaultSlowRequestThreshold = 10 * time.Second
+
+// defaultMaxConcurrentRequests is used when max_concurrent_requests is not
+// configured.
+const defaultMaxConcurrentRequests = 8
+
+// Environment variables the connection settings fall back to when they are
+// not configured.
+const (
+	endpointEnvVar = "AUTHPROXY_ENDPOINT"
+	usernameEnvVar = "AUTHPROXY_USERNAME"
+	passwordEnvVar = "AUTHPROXY_PASSWORD"
 )
 
 // Ensure AuthProxy satisfies various provider interfaces.
@@ -22,13 +51,27 @@ type AuthProxy struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// stats counts the API calls of the provider, see NewProtocol6.
+	stats *apiStats
 }
 
 // Model describes the provider data model.
 type Model struct {
-	Endpoint types.String `tfsdk:"endpoint"`
-	Password types.String `tfsdk:"password"`
-	Username types.String `tfsdk:"username"`
+	Endpoint              types.String `tfsdk:"endpoint"`
+	Password              types.String `tfsdk:"password"`
+	Username              types.String `tfsdk:"username"`
+	RequestTimeout        types.String `tfsdk:"request_timeout"`
+	CountRoleBindings     types.Bool   `tfsdk:"count_role_bindings"`
+	SendOperationHeaders  types.Bool   `tfsdk:"send_operation_headers"`
+	SlowRequestThreshold  types.String `tfsdk:"slow_request_threshold"`
+	MaxConcurrentRequests types.Int64  `tfsdk:"max_concurrent_requests"`
+	BatchReads            types.Bool   `tfsdk:"batch_reads"`
+	WaitForConsistency    types.Bool   `tfsdk:"wait_for_consistency"`
+	PlanValidation        types.Bool   `tfsdk:"plan_validation"`
+	AuditAnnotations      types.Map    `tfsdk:"audit_annotations"`
+	CACertPEM             types.String `tfsdk:"ca_cert_pem"`
+	InsecureSkipVerify    types.Bool   `tfsdk:"insecure_skip_verify"`
 }
 
 type ProviderData struct {
@@ -36,6 +79,61 @@ type ProviderData struct {
 	endpoint string
 	username string
 	password string
+
+	// requestTimeout bounds single requests and, unless configured
+	// otherwise, data source reads.
+	requestTimeout time.Duration
+
+	// slowRequestThreshold is how long requests may take before the
+	// operation making them warns about it, see checkSlowRequest.
+	slowRequestThreshold time.Duration
+
+	// maxConcurrentRequests bounds the requests an operation fanning out
+	// makes at once, see forEachConcurrently. Zero means
+	// defaultMaxConcurrentRequests.
+	maxConcurrentRequests int
+
+	// countRoleBindings makes plans revoking scopes from a role count the
+	// principals bound to it.
+	countRoleBindings bool
+
+	// version is the provider version, sent along with the label of the
+	// Terraform operation making a request unless operationHeaders is unset.
+	version          string
+	operationHeaders bool
+
+	// batchReads makes role reads use roleBatch.
+	batchReads bool
+
+	// waitForConsistency makes roles not configuring wait_for_consistency
+	// read their writes back, see RoleResource.awaitConsistency.
+	waitForConsistency bool
+
+	// auditAnnotations are sent along with every write, see
+	// setAuditHeaders.
+	auditAnnotations map[string]string
+
+	capabilities   capabilities
+	serverVersion  serverVersion
+	planValidation planValidation
+	roleBatch      roleBatch
+	reads          readGroup
+	stats          *apiStats
+}
+
+// String describes the provider data without the password, so formatting it
+// with %v never leaks the credentials into Terraform's output or logs.
+func (p *ProviderData) String() string {
+	password := ""
+	if p.password != "" {
+		password = "<redacted>"
+	}
+	return fmt.Sprintf("ProviderData{endpoint: %q, username: %q, password: %q}", p.endpoint, p.username, password)
+}
+
+// GoString redacts the password from %#v like String does.
+func (p *ProviderData) GoString() string {
+	return "&provider." + p.String()
 }
 
 func (p *AuthProxy) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -47,19 +145,74 @@ func (p *AuthProxy) Schema(ctx context.Context, req provider.SchemaRequest, resp
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "Points to the endpoint of the target authproxy instance",
-				Optional:            false,
-				Required:            true,
+				MarkdownDescription: "Points to the endpoint of the target authproxy instance, such as `\"https://authproxy.example.com\"`. May also be set with the `" + endpointEnvVar + "` environment variable",
+				Optional:            true,
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "Authproxy admin password",
-				Optional:            false,
+				MarkdownDescription: "Authproxy admin password. May also be set with the `" + passwordEnvVar + "` environment variable",
+				Optional:            true,
 				Sensitive:           true,
-				Required:            true,
 			}, "username": schema.StringAttribute{
-				MarkdownDescription: "Authproxy admin username",
-				Optional:            false,
-				Required:            true,
+				MarkdownDescription: "Authproxy admin username. May also be set with the `" + usernameEnvVar + "` environment variable",
+				Optional:            true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long a single request to authproxy may take, retries of throttled and failed requests included, such as `\"30s\"` or `\"2m\"`. Also the default read timeout of data sources. Defaults to `\"30s\"`",
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"count_role_bindings": schema.BoolAttribute{
+				MarkdownDescription: "Whether plans removing scopes from a role also tell how many users and groups are bound to it, costing a request per such role. Defaults to `false`",
+				Optional:            true,
+			},
+			"send_operation_headers": schema.BoolAttribute{
+				MarkdownDescription: "Whether requests tell authproxy which Terraform operation made them, such as `authproxy_role.update`, and the provider version, in the `X-Terraform-Operation` and `X-Terraform-Provider-Version` headers. Set to `false` to keep them out of the access logs of authproxy. Defaults to `true`",
+				Optional:            true,
+			},
+			"slow_request_threshold": schema.StringAttribute{
+				MarkdownDescription: "How long a single request to authproxy may take before the operation making it warns that authproxy is slow, such as `\"10s\"`. The operation still succeeds. Defaults to `\"10s\"`",
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "How many requests to authproxy a data source reading details of every item of a list, such as `authproxy_tenant_search` with `include_role_counts`, makes at once, between 1 and 64. Defaults to `8`",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64Between(1, 64),
+				},
+			},
+			"batch_reads": schema.BoolAttribute{
+				MarkdownDescription: "Whether refreshing `authproxy_role` resources lists the roles of their tenant once rather than reading every role on its own, which saves requests for tenants with many managed roles. Defaults to `false`",
+				Optional:            true,
+			},
+			"wait_for_consistency": schema.BoolAttribute{
+				MarkdownDescription: "Whether creating and updating `authproxy_role` resources reads the role back until authproxy answers with the write, for deployments replicating roles across regions. Roles may override it. Defaults to `false`",
+				Optional:            true,
+			},
+			"plan_validation": schema.BoolAttribute{
+				MarkdownDescription: "Whether planning `authproxy_tenant` and `authproxy_role` resources submits the planned tenants and roles to the dry-run endpoint of authproxy, so violations of the server policy such as reserved names, unknown scopes or exhausted quotas fail the plan rather than the apply. Costs a request per changed resource. Defaults to `false`",
+				Optional:            true,
+			},
+			"audit_annotations": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Annotations every request creating, updating or deleting objects carries for the audit log of authproxy, such as `{ ticket = \"CHG-1234\", requested_by = \"alice\" }`. Each is sent in an `X-Audit-<key>` header, so keys must be valid HTTP header names. Reads carry none. The values are not treated as secrets and show up in the provider logs",
+				Optional:            true,
+				Validators: []validator.Map{
+					headerNames(),
+					headerValues(),
+				},
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded certificates of the authorities trusted to sign the certificate of authproxy, in addition to the ones the system trusts, such as `file(\"ca.pem\")`. For authproxy instances serving a certificate of a private authority",
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Whether to skip verifying the certificate of authproxy. Only meant for testing, as anyone on the network path can then impersonate authproxy and read the admin credentials. Prefer `ca_cert_pem`. Defaults to `false`",
+				Optional:            true,
 			},
 		},
 	}
@@ -73,34 +226,194 @@ func (p *AuthProxy) Configure(ctx context.Context, req provider.ConfigureRequest
 		return
 	}
 
-	// Configuration values are now available.
-	// if data.Endpoint.IsNull() { /* ... */ }
+	endpoint := configOrEnv(&resp.Diagnostics, data.Endpoint, "endpoint", endpointEnvVar)
+	username := configOrEnv(&resp.Diagnostics, data.Username, "username", usernameEnvVar)
+	password := configOrEnv(&resp.Diagnostics, data.Password, "password", passwordEnvVar)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The endpoint is checked here rather than by a validator so values
+	// from the environment are covered too.
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || !validators.IsHTTPSURL(endpoint, true) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Invalid Authproxy Endpoint",
+			fmt.Sprintf("The endpoint must be an http or https URL such as \"https://authproxy.example.com\", got: %q", endpoint),
+		)
+		return
+	}
+	if endpointURL.Scheme == "http" && !isLoopback(endpointURL.Hostname()) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("endpoint"),
+			"Insecure Authproxy Endpoint",
+			fmt.Sprintf("The endpoint %s uses plain http, so the admin credentials are sent unencrypted. "+
+				"Use https unless authproxy is only reachable through a trusted network.", endpoint),
+		)
+	}
+
+	transport, err := tlsTransport(data.CACertPEM.ValueString(), data.InsecureSkipVerify.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_cert_pem"),
+			"Invalid CA Certificate",
+			fmt.Sprintf("The ca_cert_pem cannot be used: %s.", err),
+		)
+		return
+	}
+	if data.InsecureSkipVerify.ValueBool() {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"Insecure TLS Verification",
+			"The certificate of authproxy is not verified, so anyone on the network path can impersonate it and read the admin credentials. "+
+				"Set ca_cert_pem to the certificate of the authority signing it instead.",
+		)
+	}
+
+	requestTimeout := defaultRequestTimeout
+	if !data.RequestTimeout.IsNull() {
+		// The value was validated already, ignore the error.
+		if configured, err := time.ParseDuration(data.RequestTimeout.ValueString()); err == nil {
+			requestTimeout = configured
+		}
+	}
+	slowRequestThreshold := defaultSlowRequestThreshold
+	if !data.SlowRequestThreshold.IsNull() {
+		if configured, err := time.ParseDuration(data.SlowRequestThreshold.ValueString()); err == nil {
+			slowRequestThreshold = configured
+		}
+	}
+	maxConcurrentRequests := defaultMaxConcurrentRequests
+	if !data.MaxConcurrentRequests.IsNull() {
+		maxConcurrentRequests = int(data.MaxConcurrentRequests.ValueInt64())
+	}
+
+	// Data sources and resources share the provider data so state such as
+	// the probed server capabilities is only gathered once.
+	providerData := &ProviderData{
+		client:                &http.Client{Timeout: requestTimeout, Transport: newRetryTransport(transport)},
+		endpoint:              endpoint,
+		password:              password,
+		username:              username,
+		requestTimeout:        requestTimeout,
+		slowRequestThreshold:  slowRequestThreshold,
+		maxConcurrentRequests: maxConcurrentRequests,
+		countRoleBindings:     data.CountRoleBindings.ValueBool(),
+		version:               p.version,
+		operationHeaders:      data.SendOperationHeaders.IsNull() || data.SendOperationHeaders.ValueBool(),
+		batchReads:            data.BatchReads.ValueBool(),
+		waitForConsistency:    data.WaitForConsistency.ValueBool(),
+		planValidation:        planValidation{enabled: data.PlanValidation.ValueBool()},
+		auditAnnotations:      auditAnnotationsValue(data.AuditAnnotations),
+		stats:                 p.stats,
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+}
 
-	// Example providerData configuration for data sources and resources
-	resp.DataSourceData = &ProviderData{
-		client:   http.DefaultClient,
-		endpoint: data.Endpoint.ValueString(),
-		password: data.Password.ValueString(),
-		username: data.Username.ValueString(),
+// configOrEnv returns the configured value of the attribute name, or the
+// value of the environment variable envVar if it is not configured. An error
+// is added to diags when the value is unknown or missing from both.
+func configOrEnv(diags *diag.Diagnostics, value types.String, name string, envVar string) string {
+	title := strings.ToUpper(name[:1]) + name[1:]
+	if value.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root(name),
+			"Unknown Authproxy "+title,
+			fmt.Sprintf("The provider cannot connect to authproxy as the %s is not known until apply, "+
+				"such as when authproxy itself is created in the same configuration. "+
+				"Apply the resources the value comes from first with terraform apply -target=<address>, then apply the rest. "+
+				"Alternatively set the value statically in the configuration or use the %s environment variable.", name, envVar),
+		)
+		return ""
 	}
 
-	resp.ResourceData = &ProviderData{
-		client:   http.DefaultClient,
-		endpoint: data.Endpoint.ValueString(),
-		password: data.Password.ValueString(),
-		username: data.Username.ValueString(),
+	configured := value.ValueString()
+	if value.IsNull() {
+		configured = os.Getenv(envVar)
+	}
+	if configured == "" {
+		diags.AddAttributeError(
+			path.Root(name),
+			"Missing Authproxy "+title,
+			fmt.Sprintf("The provider cannot connect to authproxy without a %[1]s. "+
+				"Set the %[1]s in the configuration or use the %[2]s environment variable. "+
+				"If either is already set, ensure the value is not empty.", name, envVar),
+		)
+	}
+	return configured
+}
+
+// isLoopback reports whether host names the local machine, which plain http
+// endpoints do not need to be warned about.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
 	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
 
 func (p *AuthProxy) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewTenantResource,
+		NewRoleResource,
+		NewUserResource,
+		NewRoleBindingResource,
+		NewGroupMembershipResource,
+		NewGroupMembersResource,
+		NewAPITokenResource,
+		NewScopeResource,
+		NewPolicyResource,
+		NewOIDCIdentityProviderResource,
+		NewPasswordPolicyResource,
+		NewTenantSettingsResource,
+		NewCORSPolicyResource,
+		NewRateLimitResource,
+		NewCertificateResource,
+		NewSessionPolicyResource,
+		NewAuditSinkResource,
+		NewClaimMappingResource,
+		NewTenantMembershipResource,
+		NewBrandingResource,
+		NewSAMLIdentityProviderResource,
+		NewSCIMConfigResource,
+		NewSMTPSettingsResource,
+		NewM2MGrantResource,
+		NewGlobalRoleResource,
+		NewTokenExchangePolicyResource,
+		NewRolesResource,
+		NewTenantAliasResource,
+		NewServiceResource,
+		NewRouteResource,
+		NewHeaderPolicyResource,
+		NewAccessRuleResource,
+		NewGenericResource,
+		NewTenantBootstrapResource,
 	}
 }
 
 func (p *AuthProxy) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewTenantDataSource,
+		NewServerInfoDataSource,
+		NewWhoamiDataSource,
+		NewScopesDataSource,
+		NewUsersDataSource,
+		NewUserDataSource,
+		NewRoleBindingsDataSource,
+		NewEffectiveScopesDataSource,
+		NewJWKSDataSource,
+		NewAuditEventsDataSource,
+		NewTokenInfoDataSource,
+		NewIdentityProvidersDataSource,
+		NewTenantUsageDataSource,
+		NewAPIKeysDataSource,
+		NewWebhooksDataSource,
+		NewTenantSearchDataSource,
 	}
 }
 
@@ -108,6 +421,7 @@ func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &AuthProxy{
 			version: version,
+			stats:   &apiStats{},
 		}
 	}
 }
@@ -4,10 +4,20 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -15,7 +25,9 @@ import (
 // CLI command executed to create a provider server to which the CLI can
 // reattach.
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"scaffolding": providerserver.NewProtocol6WithError(New("test")()),
+	"authproxy": func() (tfprotov6.ProviderServer, error) {
+		return NewProtocol6("test")(), nil
+	},
 }
 
 func testAccPreCheck(t *testing.T) {
@@ -23,3 +35,349 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+// testAccProviderConfig returns a provider block pointing at endpoint, meant to
+// be prepended to the configuration of a test step. It uses the credentials
+// of testserver, pass the URL of a testserver.Server to run against the fake
+// authproxy.
+func testAccProviderConfig(endpoint string) string {
+	return fmt.Sprintf(`
+provider "authproxy" {
+  endpoint = %[1]q
+  username = %[2]q
+  password = %[3]q
+}
+`, endpoint, testserver.Username, testserver.Password)
+}
+
+// testProviderData returns provider data pointing at endpoint with the
+// credentials used by testAccProviderConfig.
+func testProviderData(endpoint string) *ProviderData {
+	return &ProviderData{
+		client:   http.DefaultClient,
+		endpoint: endpoint,
+		username: testserver.Username,
+		password: testserver.Password,
+		stats:    &apiStats{},
+	}
+}
+
+// testBackendServer starts a testserver.Server faking the endpoints at and
+// below each of paths with backend, for resources whose endpoints testserver
+// does not implement. Requests reach backend only when they are
+// authenticated and conform to the OpenAPI document.
+func testBackendServer(t *testing.T, backend http.Handler, paths ...string) *testserver.Server {
+	server := testserver.New(t)
+	for _, path := range paths {
+		server.Handle(path, backend)
+		server.Handle(path+"/", backend)
+	}
+	return server
+}
+
+// testAccCheckDestroyed returns a CheckDestroy function reading every
+// resource of resourceType left in the state from the authproxy at endpoint,
+// failing unless authproxy answers with 404. path returns the API path of a
+// resource from its state attributes. The check only talks to the API, so it
+// holds against the fake authproxy of testserver and a real one alike.
+func testAccCheckDestroyed(endpoint string, resourceType string, path func(attributes map[string]string) string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		providerData := testProviderData(endpoint)
+		for name, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			resourcePath := path(rs.Primary.Attributes)
+			_, err := providerData.do(context.Background(), http.MethodGet, resourcePath, nil)
+			if err == nil {
+				return fmt.Errorf("expected %s to be destroyed, authproxy still serves %s", name, resourcePath)
+			}
+			if !isStatus(err, http.StatusNotFound) {
+				return fmt.Errorf("unable to check that %s was destroyed: %w", name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// testDataSourceRead configures d with providerData and calls its Read method
+// directly with the given configuration values, leaving every attribute that
+// is not part of config null. It allows asserting diagnostics such as
+// warnings, which acceptance tests cannot observe.
+func testDataSourceRead(t *testing.T, d datasource.DataSource, providerData *ProviderData, config map[string]tftypes.Value) *datasource.ReadResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	configureResp := &datasource.ConfigureResponse{}
+	d.(datasource.DataSourceWithConfigure).Configure(ctx, datasource.ConfigureRequest{ProviderData: providerData}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected configure diagnostics: %v", configureResp.Diagnostics)
+	}
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := config[name]; ok {
+			values[name] = value
+			continue
+		}
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+	}
+	resp := &datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+	d.Read(ctx, req, resp)
+
+	return resp
+}
+
+// testAccStringList renders values as an HCL list of strings.
+func testAccStringList(values ...string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// testProviderConfigure configures a protocol 6 server of the provider with
+// the given provider block values, leaving the others null, the way Terraform
+// does before any data source or resource is used.
+func testProviderConfigure(t *testing.T, config map[string]tftypes.Value) (tfprotov6.ProviderServer, []*tfprotov6.Diagnostic) {
+	t.Helper()
+	ctx := context.Background()
+
+	server := NewProtocol6("test")()
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configType := schemaResp.Provider.ValueType().(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(configType.AttributeTypes))
+	for name, attributeType := range configType.AttributeTypes {
+		if value, ok := config[name]; ok {
+			values[name] = value
+			continue
+		}
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	configValue, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, values))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.5.0",
+		Config:           &configValue,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, resp.Diagnostics
+}
+
+// testProviderReadTenant reads the acme tenant through the authproxy_tenant
+// data source of a server configured by testProviderConfigure.
+func testProviderReadTenant(t *testing.T, server tfprotov6.ProviderServer) []*tfprotov6.Diagnostic {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configType := schemaResp.DataSourceSchemas["authproxy_tenant"].ValueType().(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(configType.AttributeTypes))
+	for name, attributeType := range configType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	values["name"] = tftypes.NewValue(tftypes.String, "acme")
+	configValue, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, values))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: "authproxy_tenant",
+		Config:   &configValue,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Diagnostics
+}
+
+func TestProviderConfigure(t *testing.T) {
+	backend := testserver.New(t)
+	backend.CreateTenant("acme")
+
+	str := func(value string) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, value)
+	}
+
+	for name, testCase := range map[string]struct {
+		config map[string]tftypes.Value
+		env    map[string]string
+		// configure are the expected summaries of the configure
+		// diagnostics, read is expected in the detail of the error reading
+		// a tenant afterwards.
+		configure []string
+		read      string
+	}{
+		"configuration": {
+			config: map[string]tftypes.Value{"endpoint": str(backend.URL), "username": str(testserver.Username), "password": str(testserver.Password)},
+		},
+		"environment": {
+			env: map[string]string{endpointEnvVar: backend.URL, usernameEnvVar: testserver.Username, passwordEnvVar: testserver.Password},
+		},
+		"configuration over environment": {
+			config: map[string]tftypes.Value{"password": str(testserver.Password)},
+			env:    map[string]string{endpointEnvVar: backend.URL, usernameEnvVar: testserver.Username, passwordEnvVar: "wrong"},
+		},
+		"missing": {
+			config:    map[string]tftypes.Value{"username": str(testserver.Username)},
+			configure: []string{"Missing Authproxy Endpoint", "Missing Authproxy Password"},
+		},
+		"unknown": {
+			config:    map[string]tftypes.Value{"endpoint": tftypes.NewValue(tftypes.String, tftypes.UnknownValue), "username": str(testserver.Username), "password": str(testserver.Password)},
+			configure: []string{"Unknown Authproxy Endpoint"},
+		},
+		"unknown credentials": {
+			config: map[string]tftypes.Value{
+				"endpoint": str(backend.URL),
+				"username": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"password": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			},
+			configure: []string{"Unknown Authproxy Username", "Unknown Authproxy Password"},
+		},
+		"malformed endpoint": {
+			config:    map[string]tftypes.Value{"endpoint": str("authproxy.example.com:8080"), "username": str(testserver.Username), "password": str(testserver.Password)},
+			configure: []string{"Invalid Authproxy Endpoint"},
+		},
+		"malformed endpoint from environment": {
+			env:       map[string]string{endpointEnvVar: "ftp://authproxy.example.com", usernameEnvVar: testserver.Username, passwordEnvVar: testserver.Password},
+			configure: []string{"Invalid Authproxy Endpoint"},
+		},
+		"plain http": {
+			config:    map[string]tftypes.Value{"endpoint": str("http://authproxy.example.com"), "username": str(testserver.Username), "password": str(testserver.Password)},
+			configure: []string{"Insecure Authproxy Endpoint"},
+		},
+		"wrong credentials": {
+			config: map[string]tftypes.Value{"endpoint": str(backend.URL), "username": str(testserver.Username), "password": str("wrong")},
+			read:   "Authproxy rejected the configured credentials",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, envVar := range []string{endpointEnvVar, usernameEnvVar, passwordEnvVar} {
+				t.Setenv(envVar, testCase.env[envVar])
+			}
+
+			server, diags := testProviderConfigure(t, testCase.config)
+			var summaries []string
+			for _, diagnostic := range diags {
+				summaries = append(summaries, diagnostic.Summary)
+				if diagnostic.Attribute == nil {
+					t.Errorf("expected %q to point at an attribute", diagnostic.Summary)
+				}
+			}
+			if strings.Join(summaries, "\n") != strings.Join(testCase.configure, "\n") {
+				t.Fatalf("expected the configure diagnostics %v, got %v", testCase.configure, summaries)
+			}
+			for _, diagnostic := range diags {
+				if strings.HasPrefix(diagnostic.Summary, "Unknown") && !strings.Contains(diagnostic.Detail, "-target") {
+					t.Errorf("expected %q to suggest -target, got %q", diagnostic.Summary, diagnostic.Detail)
+				}
+			}
+			if len(diags) != 0 {
+				// Only the cleanly configured providers point at the
+				// backend.
+				return
+			}
+
+			diags = testProviderReadTenant(t, server)
+			switch {
+			case testCase.read == "" && len(diags) != 0:
+				t.Errorf("expected the tenant to be read, got %v", diags)
+			case testCase.read != "" && (len(diags) != 1 || !strings.Contains(diags[0].Detail, testCase.read)):
+				t.Errorf("expected an error containing %q, got %v", testCase.read, diags)
+			}
+		})
+	}
+}
+
+func TestProviderData_redactsPassword(t *testing.T) {
+	const password = "s3cret-admin-password"
+	providerData := testProviderData("http://authproxy.invalid/\x7f")
+	providerData.password = password
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		if formatted := fmt.Sprintf(format, providerData); strings.Contains(formatted, password) || !strings.Contains(formatted, "<redacted>") {
+			t.Errorf("expected %s to redact the password, got %s", format, formatted)
+		}
+	}
+
+	// The endpoint is no valid URL, so building the request fails.
+	_, diags := contractOperation(t, NewTenantResource(), providerData, "create", nil, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "acme"),
+	})
+	if !diags.HasError() {
+		t.Fatal("expected the create to fail")
+	}
+	for _, diagnostic := range diags {
+		if strings.Contains(diagnostic.Summary()+diagnostic.Detail(), password) {
+			t.Errorf("expected the password to never appear in diagnostics, got %q: %q", diagnostic.Summary(), diagnostic.Detail())
+		}
+	}
+}
+
+func TestAccCheckDestroyed(t *testing.T) {
+	server := testserver.New(t)
+	server.CreateTenant("acme")
+	state := &terraform.State{
+		Modules: []*terraform.ModuleState{{
+			Path: []string{"root"},
+			Resources: map[string]*terraform.ResourceState{
+				"authproxy_tenant.test": {
+					Type:    "authproxy_tenant",
+					Primary: &terraform.InstanceState{ID: "1", Attributes: map[string]string{"name": "acme"}},
+				},
+			},
+		}},
+	}
+	check := testAccCheckDestroyed(server.URL, "authproxy_tenant", func(attributes map[string]string) string {
+		return "/tenants/" + attributes["name"]
+	})
+
+	if err := check(state); err == nil || !strings.Contains(err.Error(), "authproxy still serves /tenants/acme") {
+		t.Errorf("expected the remaining tenant to fail the check, got %v", err)
+	}
+	if err := testAccCheckDestroyed(server.URL, "authproxy_role", nil)(state); err != nil {
+		t.Errorf("expected resources of other types to be ignored, got %s", err)
+	}
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	if err := testAccCheckDestroyed(unavailable.URL, "authproxy_tenant", func(attributes map[string]string) string {
+		return "/tenants/" + attributes["name"]
+	})(state); err == nil || !strings.Contains(err.Error(), "unable to check") {
+		t.Errorf("expected failing reads to fail the check, got %v", err)
+	}
+
+	server.DeleteTenant("acme")
+	if err := check(state); err != nil {
+		t.Errorf("expected the deleted tenant to pass the check, got %s", err)
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RateLimitResource{}
+var _ resource.ResourceWithImportState = &RateLimitResource{}
+
+func NewRateLimitResource() resource.Resource {
+	return &RateLimitResource{}
+}
+
+// RateLimitResource defines the resource implementation.
+type RateLimitResource struct {
+	BaseResource
+}
+
+// RateLimitResourceModel describes the resource data model.
+type RateLimitResourceModel struct {
+	ID                types.String    `tfsdk:"id"`
+	Tenant            tenantNameValue `tfsdk:"tenant"`
+	RequestsPerMinute types.Int64     `tfsdk:"requests_per_minute"`
+	Burst             types.Int64     `tfsdk:"burst"`
+	Paths             types.Set       `tfsdk:"paths"`
+}
+
+type rateLimitRequest struct {
+	RequestsPerMinute int64    `json:"requests_per_minute"`
+	Burst             *int64   `json:"burst,omitempty"`
+	Paths             []string `json:"paths"`
+}
+
+type rateLimitResponse struct {
+	ID                string   `json:"id"`
+	RequestsPerMinute int64    `json:"requests_per_minute"`
+	Burst             int64    `json:"burst"`
+	Paths             []string `json:"paths"`
+}
+
+func (r *RateLimitResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rate_limit"
+}
+
+func (r *RateLimitResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Rate limit of a tenant. Tenants without rate limits are unlimited",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the rate limit applies to. Changing it recreates the rate limit",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"requests_per_minute": schema.Int64Attribute{
+				MarkdownDescription: "Sustained number of requests allowed per minute. Authproxy rounds it to the nearest multiple of 10",
+				Required:            true,
+				Validators: []validator.Int64{
+					int64Between(10, 1000000),
+				},
+			},
+			"burst": schema.Int64Attribute{
+				MarkdownDescription: "Number of requests allowed in a burst above the sustained rate. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64Between(0, 1000000),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"paths": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Path prefixes the rate limit is scoped to, all paths when unset",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// rateLimitsPath returns the path of the rate limits collection of tenant.
+func rateLimitsPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/rate-limits", url.PathEscape(tenant))
+}
+
+// rateLimitPath returns the path of the rate limit of tenant with the given id.
+func rateLimitPath(tenant string, id string) string {
+	return rateLimitsPath(tenant) + "/" + url.PathEscape(id)
+}
+
+// roundRequestsPerMinute rounds rpm to the nearest multiple of 10 like
+// authproxy does when storing a rate limit.
+func roundRequestsPerMinute(rpm int64) int64 {
+	return (rpm + 5) / 10 * 10
+}
+
+// request builds the create and update request body from data.
+func (data *RateLimitResourceModel) request(ctx context.Context) (rateLimitRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	rateLimit := rateLimitRequest{
+		RequestsPerMinute: data.RequestsPerMinute.ValueInt64(),
+		Paths:             []string{},
+	}
+	if !data.Burst.IsUnknown() {
+		rateLimit.Burst = data.Burst.ValueInt64Pointer()
+	}
+	if !data.Paths.IsNull() {
+		diags.Append(data.Paths.ElementsAs(ctx, &rateLimit.Paths, false)...)
+		sort.Strings(rateLimit.Paths)
+	}
+
+	return rateLimit, diags
+}
+
+// setRateLimit copies the attributes authproxy returned for a rate limit into
+// data. A configured requests_per_minute that authproxy rounded keeps its
+// prior value so that plans do not flap between the two.
+func (data *RateLimitResourceModel) setRateLimit(ctx context.Context, rateLimit rateLimitResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(rateLimit.ID)
+
+	if data.RequestsPerMinute.IsNull() || data.RequestsPerMinute.IsUnknown() ||
+		roundRequestsPerMinute(data.RequestsPerMinute.ValueInt64()) != rateLimit.RequestsPerMinute {
+		data.RequestsPerMinute = types.Int64Value(rateLimit.RequestsPerMinute)
+	}
+
+	data.Burst = types.Int64Value(rateLimit.Burst)
+
+	// No paths stay null unless they were configured as an empty set, so
+	// leaving paths unset does not produce a diff.
+	if len(rateLimit.Paths) > 0 || !data.Paths.IsNull() {
+		if rateLimit.Paths == nil {
+			rateLimit.Paths = []string{}
+		}
+		paths, d := types.SetValueFrom(ctx, types.StringType, rateLimit.Paths)
+		diags.Append(d...)
+		data.Paths = paths
+	}
+
+	return diags
+}
+
+func (r *RateLimitResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RateLimitResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var rateLimit rateLimitResponse
+	err := r.doJSON(ctx, "POST", rateLimitsPath(data.Tenant.ValueString()), body, &rateLimit)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create rate limit", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRateLimit(ctx, rateLimit)...)
+
+	tflog.Trace(ctx, "created a rate limit resource", map[string]interface{}{
+		"id": rateLimit.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RateLimitResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *RateLimitResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var rateLimit rateLimitResponse
+	err := r.doJSON(ctx, "GET", rateLimitPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, &rateLimit)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "rate limit no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"id":     data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read rate limit", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRateLimit(ctx, rateLimit)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RateLimitResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *RateLimitResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var rateLimit rateLimitResponse
+	err := r.doJSON(ctx, "PATCH", rateLimitPath(data.Tenant.ValueString(), data.ID.ValueString()), body, &rateLimit)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update rate limit", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRateLimit(ctx, rateLimit)...)
+
+	tflog.Trace(ctx, "updated a rate limit resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RateLimitResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *RateLimitResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", rateLimitPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete rate limit", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a rate limit resource")
+}
+
+func (r *RateLimitResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, id, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
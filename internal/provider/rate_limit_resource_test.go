@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccRateLimitBackend is an in-memory stand-in for the rate limit
+// endpoints of the "acme" tenant. Like authproxy it rounds requests per
+// minute to the nearest multiple of 10.
+type testAccRateLimitBackend struct {
+	mu         sync.Mutex
+	rateLimits map[string]rateLimitResponse
+	nextID     int
+}
+
+func (b *testAccRateLimitBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prefix := rateLimitsPath("acme")
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	decode := func(rateLimit *rateLimitResponse) bool {
+		var write rateLimitRequest
+		if err := json.NewDecoder(r.Body).Decode(&write); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return false
+		}
+		rateLimit.RequestsPerMinute = roundRequestsPerMinute(write.RequestsPerMinute)
+		rateLimit.Burst = rateLimit.RequestsPerMinute / 2
+		if write.Burst != nil {
+			rateLimit.Burst = *write.Burst
+		}
+		rateLimit.Paths = write.Paths
+		return true
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		b.nextID++
+		rateLimit := rateLimitResponse{ID: fmt.Sprintf("rl%d", b.nextID)}
+		if !decode(&rateLimit) {
+			return
+		}
+		b.rateLimits[rateLimit.ID] = rateLimit
+		_ = json.NewEncoder(w).Encode(rateLimit)
+	case id != "" && r.Method == http.MethodGet:
+		rateLimit, ok := b.rateLimits[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(rateLimit)
+	case id != "" && r.Method == http.MethodPatch:
+		rateLimit, ok := b.rateLimits[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if !decode(&rateLimit) {
+			return
+		}
+		b.rateLimits[id] = rateLimit
+		_ = json.NewEncoder(w).Encode(rateLimit)
+	case id != "" && r.Method == http.MethodDelete:
+		if _, ok := b.rateLimits[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.rateLimits, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAccRateLimitResource(t *testing.T) {
+	backend := &testAccRateLimitBackend{rateLimits: map[string]rateLimitResponse{}}
+	server := testBackendServer(t, backend, rateLimitsPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if len(backend.rateLimits) != 0 {
+				return fmt.Errorf("expected the tenant to be unlimited again, %d rate limits left", len(backend.rateLimits))
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, the rounded value does not cause a
+			// diff
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_rate_limit" "test" {
+  tenant              = "acme"
+  requests_per_minute = 1234
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_rate_limit.test", "id", "rl1"),
+					resourcetest.TestCheckResourceAttr("authproxy_rate_limit.test", "requests_per_minute", "1234"),
+					resourcetest.TestCheckResourceAttr("authproxy_rate_limit.test", "burst", "615"),
+					resourcetest.TestCheckNoResourceAttr("authproxy_rate_limit.test", "paths"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if rpm := backend.rateLimits["rl1"].RequestsPerMinute; rpm != 1230 {
+							return fmt.Errorf("expected authproxy to store 1230 requests per minute, got %d", rpm)
+						}
+						return nil
+					},
+				),
+			},
+			// ImportState testing, the imported value is the rounded one
+			{
+				ResourceName:            "authproxy_rate_limit.test",
+				ImportState:             true,
+				ImportStateId:           "acme/rl1",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"requests_per_minute"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_rate_limit" "test" {
+  tenant              = "acme"
+  requests_per_minute = 55
+  burst               = 10
+  paths               = ["/oauth/token", "/login"]
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_rate_limit.test", "id", "rl1"),
+					resourcetest.TestCheckResourceAttr("authproxy_rate_limit.test", "requests_per_minute", "55"),
+					resourcetest.TestCheckResourceAttr("authproxy_rate_limit.test", "burst", "10"),
+					resourcetest.TestCheckResourceAttr("authproxy_rate_limit.test", "paths.#", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestRoundRequestsPerMinute(t *testing.T) {
+	for rpm, expected := range map[int64]int64{
+		10:   10,
+		14:   10,
+		15:   20,
+		55:   60,
+		1234: 1230,
+		1000: 1000,
+	} {
+		if got := roundRequestsPerMinute(rpm); got != expected {
+			t.Errorf("expected %d to round to %d, got %d", rpm, expected, got)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// maxPooledBodySize bounds the buffers kept in bodyBuffers, so a single
+// large request does not pin its buffer for the rest of the run.
+const maxPooledBodySize = 64 << 10
+
+// bodyBuffers pools the buffers request bodies are encoded into, see
+// newRequestBody.
+var bodyBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// requestBody is the JSON encoded body of a request, held in a buffer of
+// bodyBuffers. The transport may read the body more than once, as retries
+// do, and may close its readers after the request returned, so the buffer
+// is only returned to the pool once the request released it and every
+// reader opened is closed.
+type requestBody struct {
+	mu   sync.Mutex
+	buf  *bytes.Buffer
+	data []byte
+	refs int
+}
+
+// newRequestBody encodes in as the body of a request. The caller must call
+// release once the request is done.
+func newRequestBody(in interface{}) (*requestBody, error) {
+	buf := bodyBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	if err := json.NewEncoder(buf).Encode(in); err != nil {
+		bodyBuffers.Put(buf)
+		return nil, err
+	}
+	// Unlike json.Marshal, Encode terminates the value with a newline.
+	return &requestBody{buf: buf, data: bytes.TrimSuffix(buf.Bytes(), []byte("\n")), refs: 1}, nil
+}
+
+// open returns a reader of the body for the transport.
+func (b *requestBody) open() io.ReadCloser {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refs++
+	return &requestBodyReader{Reader: bytes.NewReader(b.data), body: b}
+}
+
+// release drops a reference to the buffer, returning it to the pool once
+// the last one is dropped.
+func (b *requestBody) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refs--
+	if b.refs > 0 || b.buf == nil {
+		return
+	}
+	if b.buf.Cap() <= maxPooledBodySize {
+		bodyBuffers.Put(b.buf)
+	}
+	b.buf = nil
+	b.data = nil
+}
+
+// requestBodyReader reads a requestBody, releasing it when closed.
+type requestBodyReader struct {
+	*bytes.Reader
+	body *requestBody
+	once sync.Once
+}
+
+func (r *requestBodyReader) Close() error {
+	r.once.Do(r.body.release)
+	return nil
+}
@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"io"
+	"testing"
+)
+
+func TestRequestBody(t *testing.T) {
+	body, err := newRequestBody(map[string]string{"name": "<viewer>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every reader reads the whole body, encoded like json.Marshal does.
+	first, second := body.open(), body.open()
+	for _, reader := range []io.ReadCloser{first, second} {
+		if read, _ := io.ReadAll(reader); string(read) != `{"name":"\u003cviewer\u003e"}` {
+			t.Errorf("unexpected body %q", read)
+		}
+	}
+
+	// The buffer is kept until the request and every reader released it,
+	// closing a reader twice only counts once.
+	body.release()
+	first.Close()
+	first.Close()
+	if body.buf == nil {
+		t.Fatal("expected the buffer to be kept while a reader is open")
+	}
+	second.Close()
+	if body.buf != nil {
+		t.Error("expected the buffer to be returned to the pool")
+	}
+}
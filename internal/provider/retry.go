@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryAttempts = 5
+	defaultRetryBackoff  = 500 * time.Millisecond
+	defaultRetryMaxWait  = 30 * time.Second
+)
+
+// retryTransport retries requests failing in ways that are likely
+// transient. Throttled and unavailable responses, 429 and 503, are retried
+// for every method as authproxy did not process the request. Other server
+// errors and failed connections are only retried for idempotent methods, as
+// the request may have been applied before the failure.
+//
+// Waits double from backoff between attempts unless the response carries a
+// Retry-After header, and never exceed maxWait. The timeout of the client
+// using the transport bounds every attempt together. The number of the
+// current attempt is stored in the *requestAttempts of the request context
+// under attemptsKey, when there is one, for the log entry and the counters of
+// the request.
+type retryTransport struct {
+	next     http.RoundTripper
+	attempts int
+	backoff  time.Duration
+	maxWait  time.Duration
+}
+
+// newRetryTransport returns a retryTransport sending requests through next
+// with the default attempts and waits.
+func newRetryTransport(next http.RoundTripper) *retryTransport {
+	return &retryTransport{
+		next:     next,
+		attempts: defaultRetryAttempts,
+		backoff:  defaultRetryBackoff,
+		maxWait:  defaultRetryMaxWait,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	wait := t.backoff
+
+	for attempt := 1; ; attempt++ {
+		attempts, _ := ctx.Value(attemptsKey{}).(*requestAttempts)
+		if attempts != nil {
+			attempts.count = attempt
+		}
+		res, err := t.next.RoundTrip(req)
+		if attempt >= t.attempts || !retryable(req, res, err) || (req.Body != nil && req.GetBody == nil) {
+			return res, err
+		}
+		if attempts != nil && res != nil && res.StatusCode == http.StatusTooManyRequests {
+			attempts.rateLimited++
+		}
+
+		delay := wait
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxResponseSize))
+			res.Body.Close()
+		}
+		if delay > t.maxWait {
+			delay = t.maxWait
+		}
+		wait *= 2
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// retryable reports whether the outcome of req, either res or err, is worth
+// another attempt, see retryTransport.
+func retryable(req *http.Request, res *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		// Certificate problems do not go away by retrying.
+		if _, ok := tlsGuidance(err); ok {
+			return false
+		}
+		return idempotent(req.Method)
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(req.Method)
+	}
+	return false
+}
+
+// idempotent reports whether sending a request of method twice has the same
+// effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP
+// date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		method    string
+		status    int
+		err       error
+		retryable bool
+	}{
+		{method: http.MethodPost, status: http.StatusTooManyRequests, retryable: true},
+		{method: http.MethodPatch, status: http.StatusServiceUnavailable, retryable: true},
+		{method: http.MethodGet, status: http.StatusInternalServerError, retryable: true},
+		{method: http.MethodDelete, status: http.StatusBadGateway, retryable: true},
+		{method: http.MethodPost, status: http.StatusInternalServerError},
+		{method: http.MethodPatch, status: http.StatusGatewayTimeout},
+		{method: http.MethodGet, status: http.StatusNotFound},
+		{method: http.MethodGet, status: http.StatusOK},
+		{method: http.MethodGet, err: io.ErrUnexpectedEOF, retryable: true},
+		{method: http.MethodPost, err: io.ErrUnexpectedEOF},
+		{method: http.MethodGet, err: context.DeadlineExceeded},
+		{method: http.MethodGet, err: &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}},
+	} {
+		req := httptest.NewRequest(tc.method, "/tenants", nil)
+		var res *http.Response
+		if tc.err == nil {
+			res = &http.Response{StatusCode: tc.status}
+		}
+		if got := retryable(req, res, tc.err); got != tc.retryable {
+			t.Errorf("%s answered with %d, %v: expected retryable %t, got %t", tc.method, tc.status, tc.err, tc.retryable, got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"0":    0,
+		"3":    3 * time.Second,
+		"-1":   -1,
+		"":     -1,
+		"soon": -1,
+		time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat): 0,
+	} {
+		delay, ok := parseRetryAfter(value)
+		if expected < 0 {
+			if ok {
+				t.Errorf("%q: expected no delay, got %s", value, delay)
+			}
+			continue
+		}
+		if !ok || delay != expected {
+			t.Errorf("%q: expected %s, got %s (%t)", value, expected, delay, ok)
+		}
+	}
+
+	if delay, ok := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || delay < 59*time.Minute {
+		t.Errorf("expected an HTTP date to be waited for, got %s (%t)", delay, ok)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	var attempts int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"tenant":"acme"}` {
+			t.Errorf("expected every attempt to send the body, got %q", body)
+		}
+		if atomic.AddInt64(&attempts, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport, attempts: 3, backoff: time.Hour, maxWait: time.Hour}}
+	res, err := client.Post(server.URL, "application/json", strings.NewReader(`{"tenant":"acme"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated || attempts != 3 {
+		t.Errorf("expected the third attempt to succeed, got %d after %d attempts", res.StatusCode, attempts)
+	}
+
+	// The last attempt is returned as is.
+	atomic.StoreInt64(&attempts, 0)
+	client.Transport.(*retryTransport).attempts = 2
+	res, err = client.Post(server.URL, "application/json", strings.NewReader(`{"tenant":"acme"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusTooManyRequests || attempts != 2 {
+		t.Errorf("expected to give up after two attempts, got %d after %d attempts", res.StatusCode, attempts)
+	}
+
+	// Waits end with the context.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err = (&http.Client{Transport: &retryTransport{next: failingTransport{}, attempts: 5, backoff: time.Hour, maxWait: time.Hour}}).Do(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context to end the wait, got %v", err)
+	}
+}
+
+// failingTransport fails every request as if the connection was reset.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, io.ErrUnexpectedEOF
+}
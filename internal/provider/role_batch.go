@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// roleBatch serves the reads of roles from a single list of the roles of
+// their tenant when batch_reads is set, so refreshing many roles of a tenant
+// costs one paginated list instead of a request per role.
+//
+// The lists live only as long as operations reading roles through them are in
+// flight, such as the refreshes Terraform runs concurrently, and are dropped
+// once the last of them is done, see join. Meanwhile every listed role is
+// served at most once and the lists are dropped whenever the provider
+// changes anything, see forget, so only reads racing a change made outside
+// of Terraform may see a role as it was listed. Roles missing from the list,
+// such as the ones deleted since, are read individually.
+type roleBatch struct {
+	mu      sync.Mutex
+	tenants map[string]*tenantRoleList
+
+	// readers counts the reads in flight, see join.
+	readers int
+}
+
+// tenantRoleList is the list of the roles of a tenant, keyed by name. ready
+// is closed once it has been fetched.
+type tenantRoleList struct {
+	ready chan struct{}
+	roles map[string]readRoleResponse
+	err   error
+}
+
+// readRole returns the role name of tenant from the list of the roles of
+// tenant, listing them first unless that was done already. It reports false
+// when the role has to be read individually, which is always the case outside
+// of an operation.
+func (p *ProviderData) readRole(ctx context.Context, tenant string, name string) (readRoleResponse, bool) {
+	if !p.roleBatch.join(ctx) {
+		return readRoleResponse{}, false
+	}
+
+	p.roleBatch.mu.Lock()
+	list, ok := p.roleBatch.tenants[tenant]
+	if !ok {
+		list = &tenantRoleList{ready: make(chan struct{})}
+		if p.roleBatch.tenants == nil {
+			p.roleBatch.tenants = map[string]*tenantRoleList{}
+		}
+		p.roleBatch.tenants[tenant] = list
+	}
+	p.roleBatch.mu.Unlock()
+
+	if !ok {
+		list.roles, list.err = p.listRoles(ctx, tenant)
+		close(list.ready)
+	}
+	select {
+	case <-ctx.Done():
+		return readRoleResponse{}, false
+	case <-list.ready:
+	}
+	if list.err != nil {
+		tflog.Debug(ctx, "could not list the roles of the tenant, reading roles individually", map[string]interface{}{
+			"tenant": tenant,
+			"error":  list.err.Error(),
+		})
+		return readRoleResponse{}, false
+	}
+
+	p.roleBatch.mu.Lock()
+	defer p.roleBatch.mu.Unlock()
+
+	role, ok := list.roles[name]
+	if ok {
+		delete(list.roles, name)
+		p.stats.addCacheHit()
+	}
+	return role, ok
+}
+
+// listRoles lists the roles of tenant, leaving out the ones lacking an ID or
+// name so reading them individually reports the broken response.
+func (p *ProviderData) listRoles(ctx context.Context, tenant string) (map[string]readRoleResponse, error) {
+	roles, err := listAll[readRoleResponse](ctx, p, tenantRolesPath(tenant), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]readRoleResponse, len(roles))
+	for _, role := range roles {
+		if role.ID != "" && role.Name != "" {
+			byName[role.Name] = role
+		}
+	}
+	return byName, nil
+}
+
+// join counts a read until the operation ctx belongs to is done. The lists are
+// dropped once no read is left. It reports false when ctx belongs to no
+// operation, as the lists would never be dropped then.
+func (b *roleBatch) join(ctx context.Context) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !onOperationDone(ctx, b.leave) {
+		return false
+	}
+	b.readers++
+	return true
+}
+
+// leave ends a read counted by join.
+func (b *roleBatch) leave() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.readers--
+	if b.readers == 0 {
+		b.tenants = nil
+	}
+}
+
+// forget drops the lists of roles, which send does before every request
+// changing something.
+func (b *roleBatch) forget() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tenants = nil
+}
@@ -0,0 +1,265 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/planmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RoleBindingResource{}
+var _ resource.ResourceWithImportState = &RoleBindingResource{}
+
+func NewRoleBindingResource() resource.Resource {
+	return &RoleBindingResource{}
+}
+
+// RoleBindingResource defines the resource implementation.
+type RoleBindingResource struct {
+	BaseResource
+}
+
+// RoleBindingResourceModel describes the resource data model.
+type RoleBindingResourceModel struct {
+	ID            types.String    `tfsdk:"id"`
+	Tenant        tenantNameValue `tfsdk:"tenant"`
+	Role          types.String    `tfsdk:"role"`
+	PrincipalType types.String    `tfsdk:"principal_type"`
+	PrincipalID   types.String    `tfsdk:"principal_id"`
+	PrincipalName types.String    `tfsdk:"principal_name"`
+	GrantedAt     types.String    `tfsdk:"granted_at"`
+}
+
+type roleBindingCreateRequest struct {
+	PrincipalType string `json:"principal_type"`
+	PrincipalID   string `json:"principal_id"`
+}
+
+func (r *RoleBindingResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role_binding"
+}
+
+func (r *RoleBindingResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Grants a role of a tenant to a principal. Bindings cannot be updated, changing any argument replaces the binding",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the binding of the form `tenant/role/principal_type/principal_id`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the role belongs to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("a binding cannot move to another tenant"),
+				},
+			},
+			"role": schema.StringAttribute{
+				MarkdownDescription: "Name of the role to grant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot update bindings, granting another role creates a new binding"),
+				},
+			},
+			"principal_type": schema.StringAttribute{
+				MarkdownDescription: "Kind of principal, one of `user`, `group` and `service_account`",
+				Required:            true,
+				Validators: []validator.String{
+					stringOneOf(principalTypes...),
+				},
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot update bindings, granting the role to another principal creates a new binding"),
+				},
+			},
+			"principal_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the principal the role is granted to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot update bindings, granting the role to another principal creates a new binding"),
+				},
+			},
+			"principal_name": schema.StringAttribute{
+				MarkdownDescription: "Name of the principal",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"granted_at": schema.StringAttribute{
+				MarkdownDescription: "RFC 3339 timestamp of when the role was granted",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// roleBindingsPath returns the path of the bindings collection of the role
+// of tenant.
+func roleBindingsPath(tenant string, role string) string {
+	return fmt.Sprintf("/tenants/%s/roles/%s/bindings", url.PathEscape(tenant), url.PathEscape(role))
+}
+
+// roleBindingPath returns the path of the binding of the role of tenant to
+// the principal of principalType with principalID.
+func roleBindingPath(tenant string, role string, principalType string, principalID string) string {
+	return fmt.Sprintf("%s/%s/%s", roleBindingsPath(tenant, role), url.PathEscape(principalType), url.PathEscape(principalID))
+}
+
+// bindingsPath returns the path of the bindings collection of the role.
+func (data *RoleBindingResourceModel) bindingsPath() string {
+	return roleBindingsPath(data.Tenant.ValueString(), data.Role.ValueString())
+}
+
+// bindingPath returns the path of the binding described by data.
+func (data *RoleBindingResourceModel) bindingPath() string {
+	return roleBindingPath(data.Tenant.ValueString(), data.Role.ValueString(), data.PrincipalType.ValueString(), data.PrincipalID.ValueString())
+}
+
+// setBinding copies the attributes authproxy returned for a binding into data.
+func (data *RoleBindingResourceModel) setBinding(binding roleBindingResponse) {
+	data.ID = types.StringValue(strings.Join([]string{
+		data.Tenant.ValueString(),
+		data.Role.ValueString(),
+		binding.PrincipalType,
+		binding.PrincipalID,
+	}, "/"))
+	data.PrincipalType = types.StringValue(binding.PrincipalType)
+	data.PrincipalID = types.StringValue(binding.PrincipalID)
+	data.PrincipalName = types.StringValue(binding.PrincipalName)
+	data.GrantedAt = types.StringValue(binding.GrantedAt)
+}
+
+func (r *RoleBindingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RoleBindingResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var binding roleBindingResponse
+	err := r.doJSON(ctx, "POST", data.bindingsPath(), roleBindingCreateRequest{
+		PrincipalType: data.PrincipalType.ValueString(),
+		PrincipalID:   data.PrincipalID.ValueString(),
+	}, &binding)
+	if isStatus(err, http.StatusNotFound) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("role"),
+			"Role Not Found",
+			fmt.Sprintf("No role named %q exists in tenant %q.", data.Role.ValueString(), data.Tenant.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create role binding", err)
+		return
+	}
+
+	data.setBinding(binding)
+
+	tflog.Trace(ctx, "created a role binding resource", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RoleBindingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *RoleBindingResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var binding roleBindingResponse
+	err := r.doJSON(ctx, "GET", data.bindingPath(), nil, &binding)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "role binding no longer exists, removing it from state", map[string]interface{}{
+			"id": data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read role binding", err)
+		return
+	}
+
+	data.setBinding(binding)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RoleBindingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every argument requires replacement, there is nothing to update.
+	resp.Diagnostics.AddError(
+		"Unexpected Update",
+		"Role bindings cannot be updated in place. Please report this issue to the provider developers.",
+	)
+}
+
+func (r *RoleBindingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *RoleBindingResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", data.bindingPath(), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete role binding", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a role binding resource")
+}
+
+func (r *RoleBindingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" || parts[3] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/role/principal_type/principal_id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("principal_type"), parts[2])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("principal_id"), parts[3])...)
+}
@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccRoleBindingBackend is an in-memory stand-in for the bindings
+// endpoints of the "admin" role of the "acme" tenant.
+type testAccRoleBindingBackend struct {
+	mu       sync.Mutex
+	bindings map[string]roleBindingResponse
+}
+
+func (b *testAccRoleBindingBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	const prefix = "/tenants/acme/roles/admin/bindings"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case key == "" && r.Method == http.MethodPost:
+		var create roleBindingCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		binding := roleBindingResponse{
+			PrincipalType: create.PrincipalType,
+			PrincipalID:   create.PrincipalID,
+			PrincipalName: "name-of-" + create.PrincipalID,
+			GrantedAt:     "2023-01-01T00:00:00Z",
+		}
+		b.bindings[create.PrincipalType+"/"+create.PrincipalID] = binding
+		_ = json.NewEncoder(w).Encode(binding)
+	case key != "" && r.Method == http.MethodGet:
+		binding, ok := b.bindings[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(binding)
+	case key != "" && r.Method == http.MethodDelete:
+		if _, ok := b.bindings[key]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.bindings, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAccRoleBindingResource(t *testing.T) {
+	backend := &testAccRoleBindingBackend{bindings: map[string]roleBindingResponse{}}
+	server := testBackendServer(t, backend, "/tenants/acme/roles/admin/bindings")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if len(backend.bindings) != 0 {
+				return fmt.Errorf("expected every binding to be deleted, %d left", len(backend.bindings))
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRoleBindingResourceConfig("u1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_role_binding.user", "id", "acme/admin/user/u1"),
+					resource.TestCheckResourceAttr("authproxy_role_binding.user", "principal_name", "name-of-u1"),
+					resource.TestCheckResourceAttr("authproxy_role_binding.group", "id", "acme/admin/group/g1"),
+					resource.TestCheckResourceAttr("authproxy_role_binding.service_account", "id", "acme/admin/service_account/s1"),
+					resource.TestCheckResourceAttr("authproxy_role_binding.service_account", "granted_at", "2023-01-01T00:00:00Z"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_role_binding.user",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "authproxy_role_binding.group",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "authproxy_role_binding.service_account",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  "authproxy_role_binding.user",
+				ImportState:   true,
+				ImportStateId: "acme/admin/u1",
+				ExpectError:   regexp.MustCompile(`Unexpected Import Identifier`),
+			},
+			// Replace testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRoleBindingResourceConfig("u2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_role_binding.user", "id", "acme/admin/user/u2"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if _, ok := backend.bindings["user/u1"]; ok {
+							return fmt.Errorf("expected the replaced binding to be deleted")
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccRoleBindingResource_removedOutOfBand(t *testing.T) {
+	backend := &testAccRoleBindingBackend{bindings: map[string]roleBindingResponse{}}
+	server := testBackendServer(t, backend, "/tenants/acme/roles/admin/bindings")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRoleBindingResourceConfig("u1"),
+				Check: func(s *terraform.State) error {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					delete(backend.bindings, "group/g1")
+					return nil
+				},
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccRoleBindingResourceConfig(userID string) string {
+	return fmt.Sprintf(`
+resource "authproxy_role_binding" "user" {
+  tenant         = "acme"
+  role           = "admin"
+  principal_type = "user"
+  principal_id   = %[1]q
+}
+
+resource "authproxy_role_binding" "group" {
+  tenant         = "acme"
+  role           = "admin"
+  principal_type = "group"
+  principal_id   = "g1"
+}
+
+resource "authproxy_role_binding" "service_account" {
+  tenant         = "acme"
+  role           = "admin"
+  principal_type = "service_account"
+  principal_id   = "s1"
+}
+`, userID)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// principalTypes are the kinds of principals a role can be bound to.
+var principalTypes = []string{"user", "group", "service_account"}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RoleBindingsDataSource{}
+
+func NewRoleBindingsDataSource() datasource.DataSource {
+	return &RoleBindingsDataSource{}
+}
+
+// RoleBindingsDataSource defines the data source implementation.
+type RoleBindingsDataSource struct {
+	BaseDataSource
+}
+
+// RoleBindingsDataSourceModel describes the data source data model.
+type RoleBindingsDataSourceModel struct {
+	Tenant        tenantNameValue    `tfsdk:"tenant"`
+	Role          types.String       `tfsdk:"role"`
+	PrincipalType types.String       `tfsdk:"principal_type"`
+	PageSize      types.Int64        `tfsdk:"page_size"`
+	MaxItems      types.Int64        `tfsdk:"max_items"`
+	Bindings      []RoleBindingModel `tfsdk:"bindings"`
+	Timeouts      *ReadTimeoutsModel `tfsdk:"timeouts"`
+}
+
+// RoleBindingModel describes a single principal bound to the role.
+type RoleBindingModel struct {
+	PrincipalType types.String `tfsdk:"principal_type"`
+	PrincipalID   types.String `tfsdk:"principal_id"`
+	PrincipalName types.String `tfsdk:"principal_name"`
+	GrantedAt     types.String `tfsdk:"granted_at"`
+}
+
+type roleBindingResponse struct {
+	PrincipalType string `json:"principal_type"`
+	PrincipalID   string `json:"principal_id"`
+	PrincipalName string `json:"principal_name"`
+	GrantedAt     string `json:"granted_at"`
+}
+
+func (d *RoleBindingsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role_bindings"
+}
+
+func (d *RoleBindingsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Principals bound to a role",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the role belongs to",
+				Required:            true,
+			},
+			"role": schema.StringAttribute{
+				MarkdownDescription: "Name of the role",
+				Required:            true,
+			},
+			"principal_type": schema.StringAttribute{
+				MarkdownDescription: "Only return bindings of this principal type, one of `user`, `group` or `service_account`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringOneOf(principalTypes...),
+				},
+			},
+			"page_size": pageSizeAttribute(),
+			"max_items": maxItemsAttribute(),
+			"bindings": schema.ListNestedAttribute{
+				MarkdownDescription: "Principals bound to the role",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"principal_type": schema.StringAttribute{
+							MarkdownDescription: "Type of the principal",
+							Computed:            true,
+						},
+						"principal_id": schema.StringAttribute{
+							MarkdownDescription: "ID of the principal",
+							Computed:            true,
+						},
+						"principal_name": schema.StringAttribute{
+							MarkdownDescription: "Display name of the principal",
+							Computed:            true,
+						},
+						"granted_at": schema.StringAttribute{
+							MarkdownDescription: "When the role was granted to the principal",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *RoleBindingsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RoleBindingsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_role_bindings", data.Timeouts)
+	defer cancel()
+
+	tenant := data.Tenant.ValueString()
+	role := data.Role.ValueString()
+	bindings, truncated, err := listPages[roleBindingResponse](ctx, d.providerData, fmt.Sprintf("/tenants/%s/roles/%s/bindings", url.PathEscape(tenant), url.PathEscape(role)), nil, listOptionsFrom(data.PageSize, data.MaxItems))
+	if isStatus(err, http.StatusNotFound) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("role"),
+			"Role Not Found",
+			fmt.Sprintf("No role named %q exists in tenant %q.", role, tenant),
+		)
+		return
+	}
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "list role bindings", err)
+		return
+	}
+	if truncated {
+		addTruncationWarning(&resp.Diagnostics, "role bindings", data.MaxItems.ValueInt64())
+	}
+
+	data.Bindings = make([]RoleBindingModel, 0, len(bindings))
+	for _, binding := range bindings {
+		if !data.PrincipalType.IsNull() && binding.PrincipalType != data.PrincipalType.ValueString() {
+			continue
+		}
+		data.Bindings = append(data.Bindings, RoleBindingModel{
+			PrincipalType: types.StringValue(binding.PrincipalType),
+			PrincipalID:   types.StringValue(binding.PrincipalID),
+			PrincipalName: types.StringValue(binding.PrincipalName),
+			GrantedAt:     types.StringValue(binding.GrantedAt),
+		})
+	}
+
+	tflog.Trace(ctx, "read role bindings data source", map[string]interface{}{
+		"count": len(data.Bindings),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
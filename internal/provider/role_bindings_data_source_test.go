@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccRoleBindingsServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants/acme/roles/admin/bindings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"items":[
+				{"principal_type":"user","principal_id":"u1","principal_name":"alice","granted_at":"2023-01-01T00:00:00Z"},
+				{"principal_type":"group","principal_id":"g1","principal_name":"ops","granted_at":"2023-01-02T00:00:00Z"}
+			],"next_cursor":"2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[
+			{"principal_type":"service_account","principal_id":"s1","principal_name":"ci","granted_at":"2023-01-03T00:00:00Z"},
+			{"principal_type":"user","principal_id":"u2","principal_name":"bob","granted_at":"2023-01-04T00:00:00Z"}
+		]}`)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestAccRoleBindingsDataSource(t *testing.T) {
+	server := testAccRoleBindingsServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_role_bindings" "all" {
+  tenant = "acme"
+  role   = "admin"
+}
+
+data "authproxy_role_bindings" "users" {
+  tenant         = "acme"
+  role           = "admin"
+  principal_type = "user"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_role_bindings.all", "bindings.#", "4"),
+					resource.TestCheckResourceAttr("data.authproxy_role_bindings.all", "bindings.1.principal_type", "group"),
+					resource.TestCheckResourceAttr("data.authproxy_role_bindings.all", "bindings.2.principal_type", "service_account"),
+					resource.TestCheckResourceAttr("data.authproxy_role_bindings.users", "bindings.#", "2"),
+					resource.TestCheckResourceAttr("data.authproxy_role_bindings.users", "bindings.0.principal_name", "alice"),
+					resource.TestCheckResourceAttr("data.authproxy_role_bindings.users", "bindings.1.principal_name", "bob"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRoleBindingsDataSource_missingRole(t *testing.T) {
+	server := testAccRoleBindingsServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_role_bindings" "test" {
+  tenant = "acme"
+  role   = "auditor"
+}
+`,
+				ExpectError: regexp.MustCompile(`Role Not Found`),
+			},
+		},
+	})
+}
+
+func TestAccRoleBindingsDataSource_invalidPrincipalType(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig("http://localhost") + `
+data "authproxy_role_bindings" "test" {
+  tenant         = "acme"
+  role           = "admin"
+  principal_type = "robot"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+		},
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// roleConsistencyAttempts bounds the reads of a written role waiting
+	// for authproxy to answer with the write.
+	roleConsistencyAttempts = 5
+
+	// The reads start roleConsistencyMinInterval apart and back off up to
+	// roleConsistencyMaxInterval.
+	roleConsistencyMinInterval = 100 * time.Millisecond
+	roleConsistencyMaxInterval = 2 * time.Second
+)
+
+// waitForConsistency reports whether writes of the role in data are read back
+// until authproxy answers with them, as the role configures or else the
+// provider.
+func (r *RoleResource) waitForConsistency(data *RoleResourceModel) bool {
+	if !data.WaitForConsistency.IsNull() {
+		return data.WaitForConsistency.ValueBool()
+	}
+	return r.providerData.waitForConsistency
+}
+
+// awaitConsistency reads the role name of tenant until authproxy answers with
+// scopes, which deployments replicating roles across regions only do once the
+// write propagated. Reads not finding the role count as stale. It makes at
+// most roleConsistencyAttempts reads, backing off between them, and returns
+// the last role found and whether it matched.
+func (r *RoleResource) awaitConsistency(ctx context.Context, tenant string, name string, scopes []string) (readRoleResponse, bool, error) {
+	written := append([]string{}, scopes...)
+	sort.Strings(written)
+
+	var role readRoleResponse
+	interval := roleConsistencyMinInterval
+	for attempt := 1; ; attempt++ {
+		var read readRoleResponse
+		err := r.doObjectJSON(ctx, "GET", tenantRolePath(tenant, name), nil, &read, "id", "name")
+		switch {
+		case isStatus(err, http.StatusNotFound):
+		case err != nil:
+			return role, false, err
+		default:
+			role = read
+			if sameScopes(read, written) {
+				return role, true, nil
+			}
+		}
+		if attempt == roleConsistencyAttempts {
+			return role, false, nil
+		}
+		tflog.Debug(ctx, "authproxy does not answer with the written role yet, reading it again", map[string]interface{}{
+			"tenant":  tenant,
+			"name":    name,
+			"attempt": attempt,
+		})
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return role, false, ctx.Err()
+		case <-timer.C:
+		}
+		if interval *= 2; interval > roleConsistencyMaxInterval {
+			interval = roleConsistencyMaxInterval
+		}
+	}
+}
+
+// sameScopes reports whether role has the sorted scopes written.
+func sameScopes(role readRoleResponse, written []string) bool {
+	scopes := role.Permissions
+	if scopes == nil {
+		scopes = role.Scopes
+	}
+	if len(scopes) != len(written) {
+		return false
+	}
+	scopes = append([]string{}, scopes...)
+	sort.Strings(scopes)
+	for i := range scopes {
+		if scopes[i] != written[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// addInconsistentRoleWarning warns that authproxy acknowledged the write, the
+// creation or update, of the role name of tenant but kept answering with an
+// older version.
+func addInconsistentRoleWarning(diags *diag.Diagnostics, write string, tenant string, name string) {
+	diags.AddWarning(
+		"Role Not Yet Consistent",
+		fmt.Sprintf("Authproxy acknowledged the %s of the role %q in tenant %q but did not answer with the written scopes within %d reads. "+
+			"Deployments replicating roles across regions may take longer to propagate writes. "+
+			"The role is stored as configured and read again on the next refresh.", write, name, tenant, roleConsistencyAttempts),
+	)
+}
@@ -1,26 +1,35 @@
 package provider
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/planmodifiers"
+	"github.com/4thel00z/terraform-provider-authproxy/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"io"
-	"net/http"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &RoleResource{}
 var _ resource.ResourceWithImportState = &RoleResource{}
+var _ resource.ResourceWithConfigValidators = &RoleResource{}
+var _ resource.ResourceWithModifyPlan = &RoleResource{}
+var _ resource.ResourceWithUpgradeState = &RoleResource{}
+
+// rolePermissionsFeature is the feature flag of authproxy v2, which renamed
+// the scopes of roles to permissions and only accepts the new name.
+const rolePermissionsFeature = "role_permissions"
 
 func NewRoleResource() resource.Resource {
 	return &RoleResource{}
@@ -28,49 +37,80 @@ func NewRoleResource() resource.Resource {
 
 // RoleResource defines the resource implementation.
 type RoleResource struct {
-	providerData *ProviderData
+	BaseResource
 }
 
 // RoleResourceModel describes the resource data model.
 type RoleResourceModel struct {
-	ID     types.String `tfsdk:"id"`
-	Name   types.String `tfsdk:"name"`
-	Tenant types.String `tfsdk:"tenant"`
-	Scopes types.List   `tfsdk:"scopes"`
+	ID                 types.String    `tfsdk:"id"`
+	Name               types.String    `tfsdk:"name"`
+	Tenant             tenantNameValue `tfsdk:"tenant"`
+	Scopes             types.List      `tfsdk:"scopes"`
+	Permissions        types.List      `tfsdk:"permissions"`
+	OnDestroy          types.String    `tfsdk:"on_destroy"`
+	WaitForConsistency types.Bool      `tfsdk:"wait_for_consistency"`
+}
+
+// roleResourceModelV0 describes the state of schema version 0, before scopes
+// were renamed to permissions.
+type roleResourceModelV0 struct {
+	ID     types.String    `tfsdk:"id"`
+	Name   types.String    `tfsdk:"name"`
+	Tenant tenantNameValue `tfsdk:"tenant"`
+	Scopes types.List      `tfsdk:"scopes"`
 }
 
 func (r *RoleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_tenant"
+	resp.TypeName = req.ProviderTypeName + "_role"
 }
 
 func (r *RoleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "Tenant resource",
+		MarkdownDescription: "Role of a tenant, granting its permissions to the users and groups bound to it",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Name of the role",
 				Optional:            false,
 				Required:            true,
+				Validators: []validator.String{
+					validators.RoleName(),
+				},
 			},
 			"tenant": schema.StringAttribute{
-				MarkdownDescription: "Tenant in which to create the role",
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant in which to create the role. Changing it recreates the role",
 				Optional:            false,
 				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot move roles between tenants"),
+				},
 			},
 			"scopes": schema.ListAttribute{
 				ElementType:         types.StringType,
-				Required:            true,
-				Optional:            false,
-				Computed:            false,
-				Sensitive:           false,
-				MarkdownDescription: "The scopes of the role",
-				// default is an empty list
-				Default: listdefault.StaticValue(types.ListValueMust(
-					types.StringType,
-					[]attr.Value{},
-				)),
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The scopes of the role. Deprecated, use `permissions` instead",
+				DeprecationMessage:  "authproxy v2 renamed the scopes of roles to permissions. Configure permissions instead, which accepts the same values.",
+				Validators: []validator.List{
+					validators.ListOf(validators.Scope()),
+				},
+			},
+			"permissions": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The permissions of the role, called scopes before authproxy v2",
+				Validators: []validator.List{
+					validators.ListOf(validators.Scope()),
+				},
+			},
+			"on_destroy": onDestroyAttribute("role"),
+			"wait_for_consistency": schema.BoolAttribute{
+				MarkdownDescription: "Whether creating and updating the role reads it back until authproxy answers with the written scopes, for deployments replicating roles across regions. Warns rather than fails when authproxy keeps answering with an older version. Defaults to the provider's `wait_for_consistency`",
+				Optional:            true,
 			},
 			// "defaulted": schema.StringAttribute{
 			// 	MarkdownDescription: "Example configurable attribute with default value",
@@ -89,52 +129,191 @@ func (r *RoleResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 	}
 }
 
-func (r *RoleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
-	if req.ProviderData == nil {
+func (r *RoleResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		atLeastOneOf("permissions", "scopes"),
+		atMostOneOf("permissions", "scopes"),
+	}
+}
+
+// ModifyPlan plans permissions and its deprecated alias scopes with the same
+// value, whichever of them is configured, so migrating a configuration from
+// one to the other plans no changes. Updates removing scopes are warned
+// about, as they revoke them from everyone bound to the role.
+func (r *RoleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is destroyed.
+	if req.Plan.Raw.IsNull() {
 		return
 	}
 
-	data, ok := req.ProviderData.(*ProviderData)
+	var config *RoleResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+	configured := path.Root("permissions")
+	switch {
+	case !config.Permissions.IsNull():
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("scopes"), config.Permissions)...)
+	case !config.Scopes.IsNull():
+		configured = path.Root("scopes")
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("permissions"), config.Scopes)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.validatePlan(ctx, req, resp, configured)
+
+	if req.State.Raw.IsNull() || resp.Diagnostics.HasError() {
+		return
+	}
+	r.warnRevokedScopes(ctx, req, resp, configured)
+}
+
+// validatePlan submits the planned role to authproxy for validation when
+// plan_validation is set. Violations of the scopes are reported at
+// attribute, the configured one of permissions and scopes.
+func (r *RoleResource) validatePlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, attribute path.Path) {
+	// Nothing to validate when the role is left unchanged.
+	if r.providerData == nil || !r.providerData.planValidation.enabled || resp.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	var plan *RoleResourceModel
+	var scopes []string
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plannedKnown(ctx, plan.Tenant, plan.Name, plan.Permissions) {
+		return
+	}
+	resp.Diagnostics.Append(plan.Permissions.ElementsAs(ctx, &scopes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	requested, err := r.providerData.roleScopes(ctx, scopes)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Plan Validation Failed",
+			fmt.Sprintf("The planned role could not be validated by authproxy, so policy violations only surface during apply: %s", err),
 		)
+		return
+	}
+
+	operation := "update"
+	if req.State.Raw.IsNull() {
+		operation = "create"
+	}
+	r.providerData.validatePlanned(ctx, &resp.Diagnostics, operation, "role", plan.Tenant.ValueString(), createRoleRequest{
+		Name:       plan.Name.ValueString(),
+		roleScopes: requested,
+	}, map[string]path.Path{
+		"name":        path.Root("name"),
+		"scopes":      attribute,
+		"permissions": attribute,
+	})
+}
+
+// warnRevokedScopes warns at attribute about the scopes the planned update
+// removes from the role. With count_role_bindings set, the warning also
+// tells how many users and groups lose them.
+func (r *RoleResource) warnRevokedScopes(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, attribute path.Path) {
+	var state, plan *RoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Permissions.IsUnknown() || state.Permissions.IsUnknown() {
+		return
+	}
+
+	var prior, planned []string
+	resp.Diagnostics.Append(state.Permissions.ElementsAs(ctx, &prior, false)...)
+	resp.Diagnostics.Append(plan.Permissions.ElementsAs(ctx, &planned, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	kept := make(map[string]bool, len(planned))
+	for _, scope := range planned {
+		kept[scope] = true
+	}
+	var revoked []string
+	for _, scope := range prior {
+		if !kept[scope] {
+			revoked = append(revoked, strconv.Quote(scope))
+			kept[scope] = true
+		}
+	}
+	if len(revoked) == 0 {
 		return
 	}
 
-	r.providerData = data
+	tenant, name := state.Tenant.ValueString(), state.Name.ValueString()
+	detail := fmt.Sprintf("The update removes %s from role %q of tenant %q, revoking access from every user and group bound to it.",
+		strings.Join(revoked, ", "), name, tenant)
+	if r.providerData != nil && r.providerData.countRoleBindings {
+		bindings, err := listAll[roleBindingResponse](ctx, r.providerData, tenantRolePath(tenant, name)+"/bindings", nil)
+		if err != nil {
+			detail += fmt.Sprintf(" The bindings of the role could not be counted: %s", err)
+		} else {
+			detail += fmt.Sprintf(" Bindings of the role affected: %d.", len(bindings))
+		}
+	}
+
+	resp.Diagnostics.AddAttributeWarning(attribute, "Role Scopes Revoked", detail)
+}
+
+// roleScopes holds the scopes of a role under the name the server uses for
+// them, only one of the fields being set.
+type roleScopes struct {
+	Scopes      *[]string `json:"scopes,omitempty"`
+	Permissions *[]string `json:"permissions,omitempty"`
+}
+
+// roleScopes returns scopes as sent to the server: as permissions to servers
+// advertising rolePermissionsFeature, as scopes to older ones.
+func (p *ProviderData) roleScopes(ctx context.Context, scopes []string) (roleScopes, error) {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	v2, err := p.supports(ctx, rolePermissionsFeature)
+	if err != nil {
+		return roleScopes{}, err
+	}
+	if v2 {
+		return roleScopes{Permissions: &scopes}, nil
+	}
+	return roleScopes{Scopes: &scopes}, nil
 }
 
 type createRoleRequest struct {
-	Name   string   `json:"name"`
-	Tenant string   `json:"tenant"`
-	Scopes []string `json:"scopes"`
+	Name string `json:"name"`
+	roleScopes
 }
 
 type createRoleResponse struct {
 	ID string `json:"id"`
 }
 
+// updateRoleRequest is sent to the path of the role's current name, Name
+// renames it.
 type updateRoleRequest struct {
-	Name      string `json:"name"`
-	Tenant    string `json:"tenant"`
-	NewName   string `json:"new_name"`
-	NewScopes string `json:"new_scopes"`
+	Name string `json:"name"`
+	roleScopes
 }
 
 type updateRoleResponse struct {
-	ID string `json:"id"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
+// readRoleResponse is a role as read from servers of either version, only
+// one of Scopes and Permissions being set.
 type readRoleResponse struct {
-	ID     string   `json:"id"`
-	Name   string   `json:"name"`
-	Tenant string   `json:"tenant"`
-	Scopes []string `json:"scopes"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Tenant      string   `json:"tenant"`
+	Scopes      []string `json:"scopes"`
+	Permissions []string `json:"permissions"`
 }
 
 type deleteRoleResponse struct {
@@ -146,76 +325,46 @@ func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, r
 	var scopes []string
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-	resp.Diagnostics.Append(data.Scopes.ElementsAs(ctx, &scopes, false)...)
+	resp.Diagnostics.Append(data.Permissions.ElementsAs(ctx, &scopes, false)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider providerData data and make a call using it.
-	// httpResp, err := r.providerData.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create example, got error: %s", err))
-	//     return
-	// }
-
-	// For the purposes of this example code, hardcoding a response value to
-	// save into the Terraform state.
-	marshalled, err := json.Marshal(createRoleRequest{
-		Name:   data.Name.ValueString(),
-		Tenant: data.Tenant.ValueString(),
-		Scopes: scopes,
-	})
+	requested, err := r.providerData.roleScopes(ctx, scopes)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create role, got error: %s", err.Error()))
+		addClientError(&resp.Diagnostics, "probe server capabilities", err)
 		return
 	}
-	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/roles", r.providerData.endpoint), bytes.NewReader(marshalled))
-
+	var cr createRoleResponse
+	err = r.doObjectJSON(ctx, "POST", tenantRolesPath(data.Tenant.ValueString()), createRoleRequest{
+		Name:       data.Name.ValueString(),
+		roleScopes: requested,
+	}, &cr, "id")
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create role, got error: %s", err.Error()))
+		addClientError(&resp.Diagnostics, "create role", err)
 		return
 	}
-	tflog.Debug(ctx, "Setting basic auth")
-	request.SetBasicAuth(r.providerData.username, r.providerData.password)
-	tflog.Debug(ctx, "Making request")
 
-	res, err := r.providerData.client.Do(request)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create role, got error: %s", err))
-		return
-	}
-	defer res.Body.Close()
+	data.ID = types.StringValue(cr.ID)
 
-	if res.StatusCode != 200 {
-		resBody, err := io.ReadAll(res.Body)
+	if r.waitForConsistency(data) {
+		_, consistent, err := r.awaitConsistency(ctx, data.Tenant.ValueString(), data.Name.ValueString(), scopes)
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to handle non 200 status code on role creation, got error: %s", err))
+			// The role exists, keep it in the state so Terraform replaces
+			// it rather than losing track of it.
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			addClientError(&resp.Diagnostics, "read role", err)
 			return
 		}
-		tflog.Error(ctx, "could not create role", map[string]interface{}{
-			"body": string(resBody),
-		})
-		return
-	}
-	resBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create role, got error: %s", err.Error()))
-		return
-	}
-	var cr createRoleResponse
-	err = json.Unmarshal(resBody, &cr)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create role, got error: %s", err.Error()))
-		return
+		if !consistent {
+			addInconsistentRoleWarning(&resp.Diagnostics, "creation", data.Tenant.ValueString(), data.Name.ValueString())
+		}
 	}
 
-	data.ID = types.StringValue(cr.ID)
-
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
-	tflog.Debug(ctx, "created a role resource, response was", map[string]interface{}{
+	tflog.Trace(ctx, "created a role resource", map[string]interface{}{
 		"id": cr.ID,
 	})
 
@@ -223,68 +372,57 @@ func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, r
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// setRole copies the attributes authproxy returned for a role into data, its
+// scopes into both scopes and permissions.
+func (data *RoleResourceModel) setRole(ctx context.Context, role readRoleResponse) diag.Diagnostics {
+	scopes := role.Permissions
+	if scopes == nil {
+		scopes = role.Scopes
+	}
+	if scopes == nil {
+		scopes = []string{}
+	}
+	data.ID = types.StringValue(role.ID)
+	data.Name = types.StringValue(role.Name)
+	listValue, diags := types.ListValueFrom(ctx, types.StringType, scopes)
+	data.Scopes = listValue
+	data.Permissions = listValue
+	return diags
+}
+
 func (r *RoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data *RoleResourceModel
-	var scopes []string
+
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-	resp.Diagnostics.Append(data.Scopes.ElementsAs(ctx, &scopes, false)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	//	/tenants/{tenant}/roles/{name}
-	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/tenants/%s/roles/%s", r.providerData.endpoint, data.Tenant.ValueString(), data.Name.ValueString()), nil)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
-		return
+	var newRole readRoleResponse
+	var err error
+	listed := false
+	if r.providerData.batchReads {
+		newRole, listed = r.providerData.readRole(ctx, data.Tenant.ValueString(), data.Name.ValueString())
 	}
-	tflog.Debug(ctx, "Setting basic auth")
-	request.SetBasicAuth(r.providerData.username, r.providerData.password)
-	tflog.Debug(ctx, "Making request")
-
-	res, err := r.providerData.client.Do(request)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read role, got error: %s", err))
-		return
+	if !listed {
+		err = r.doObjectJSON(ctx, "GET", tenantRolePath(data.Tenant.ValueString(), data.Name.ValueString()), nil, &newRole, "id", "name")
 	}
-	defer res.Body.Close()
-
-	if res.StatusCode != 200 {
-		resBody, err := io.ReadAll(res.Body)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to handle non 200 status code on role read, got error: %s", err))
-			return
-		}
-		tflog.Error(ctx, "could not read role", map[string]interface{}{
-			"body": string(resBody),
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "role no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"name":   data.Name.ValueString(),
 		})
+		resp.State.RemoveResource(ctx)
 		return
 	}
-	resBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read role, got error: %s", err))
-		return
-	}
-	var newRole readRoleResponse
-	err = json.Unmarshal(resBody, &newRole)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read role", err)
 		return
 	}
-	data.ID = types.StringValue(newRole.ID)
-	listValue, diagnostics := types.ListValueFrom(ctx, types.StringType, scopes)
-	resp.Diagnostics.Append(diagnostics...)
-	data.Scopes = listValue
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider providerData data and make a call using it.
-	// httpResp, err := r.providerData.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
-	//     return
-	// }
+	resp.Diagnostics.Append(data.setRole(ctx, newRole)...)
+	data.OnDestroy = onDestroyValue(data.OnDestroy)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -293,6 +431,7 @@ func (r *RoleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data *RoleResourceModel
 	var old *RoleResourceModel
+	var scopes []string
 
 	// Read Terraform old data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &old)...)
@@ -303,63 +442,63 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider providerData data and make a call using it.
-	// httpResp, err := r.providerData.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update example, got error: %s", err))
-	//     return
-	// }
-
-	marshalled, err := json.Marshal(updateRequest{Name: old.Name.ValueString(), NewName: data.Name.ValueString()})
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
+	resp.Diagnostics.Append(data.Permissions.ElementsAs(ctx, &scopes, false)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	request, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/tenants", r.providerData.endpoint), bytes.NewReader(marshalled))
-
+	requested, err := r.providerData.roleScopes(ctx, scopes)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
+		addClientError(&resp.Diagnostics, "probe server capabilities", err)
 		return
 	}
-	tflog.Debug(ctx, "Setting basic auth")
-	request.SetBasicAuth(r.providerData.username, r.providerData.password)
-	tflog.Debug(ctx, "Making request")
 
-	res, err := r.providerData.client.Do(request)
+	// The role is addressed by its current name, the body carries the new
+	// one.
+	var cr updateRoleResponse
+	err = r.doObjectJSON(ctx, "PATCH", tenantRolePath(old.Tenant.ValueString(), old.Name.ValueString()), updateRoleRequest{
+		Name:       data.Name.ValueString(),
+		roleScopes: requested,
+	}, &cr, "id")
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
+		addClientError(&resp.Diagnostics, "update role", err)
 		return
 	}
-	defer res.Body.Close()
+	data.ID = types.StringValue(cr.ID)
 
-	if res.StatusCode != 200 {
-		resBody, err := io.ReadAll(res.Body)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to handle non 200 status code on tenant update, got error: %s", err))
+	// Reading the role back reveals changes older authproxy builds dropped
+	// silently. It is read by the name authproxy answered with, which is
+	// still the old one when the rename was dropped.
+	name := cr.Name
+	if name == "" {
+		name = data.Name.ValueString()
+	}
+	var role readRoleResponse
+	if r.waitForConsistency(data) {
+		var consistent bool
+		role, consistent, err = r.awaitConsistency(ctx, data.Tenant.ValueString(), name, scopes)
+		if err == nil && !consistent {
+			// Whether the update propagates late or was dropped cannot be
+			// told apart, so the update is trusted.
+			addInconsistentRoleWarning(&resp.Diagnostics, "update", data.Tenant.ValueString(), data.Name.ValueString())
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 			return
 		}
-		tflog.Error(ctx, "could not update tenant", map[string]interface{}{
-			"body": string(resBody),
-		})
-		return
+	} else {
+		err = r.doObjectJSON(ctx, "GET", tenantRolePath(data.Tenant.ValueString(), name), nil, &role, "id", "name")
 	}
-	resBody, err := io.ReadAll(res.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read role", err)
 		return
 	}
-	var cr updateResponse
-	err = json.Unmarshal(resBody, &cr)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
+	read := *data
+	resp.Diagnostics.Append(read.setRole(ctx, role)...)
+	if resp.Diagnostics.HasError() || !checkUpdateApplied(ctx, req, resp, "role", &read) {
 		return
 	}
-	data.ID = types.StringValue(cr.ID)
 
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
-	tflog.Trace(ctx, "updated a tenant resource")
+	tflog.Trace(ctx, "updated a role resource")
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -374,58 +513,76 @@ func (r *RoleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider providerData data and make a call using it.
-	// httpResp, err := r.providerData.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete example, got error: %s", err))
-	//     return
-	// }
-
-	request, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/tenants/%s", r.providerData.endpoint, r.name), nil)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
+	if abandon(ctx, data.OnDestroy, "role", map[string]interface{}{
+		"tenant": data.Tenant.ValueString(),
+		"name":   data.Name.ValueString(),
+	}) {
 		return
 	}
-	tflog.Debug(ctx, "Setting basic auth")
-	request.SetBasicAuth(r.providerData.username, r.providerData.password)
-	tflog.Debug(ctx, "Making request")
 
-	res, err := r.providerData.client.Do(request)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete tenant, got error: %s", err))
+	// Roles that are gone already need no deleting.
+	err := r.doJSON(ctx, "DELETE", tenantRolePath(data.Tenant.ValueString(), data.Name.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete role", err)
 		return
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != 200 {
-		resBody, err := io.ReadAll(res.Body)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to handle non 200 status code on tenant deletion, got error: %s", err))
-			return
-		}
-		tflog.Error(ctx, "could not delete tenant", map[string]interface{}{
-			"body": string(resBody),
-		})
-		return
-	}
-	resBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete tenant, got error: %s", err))
-		return
-	}
-	var newTenant readResponse
-	err = json.Unmarshal(resBody, &newTenant)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete tenant, got error: %s", err))
+	tflog.Trace(ctx, "deleted a role resource")
+}
+
+func (r *RoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, name, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || name == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/name, got: %q", req.ID),
+		)
 		return
 	}
-	data.ID = types.StringValue(newTenant.ID)
-	data.Name = types.StringValue(newTenant.Name)
 
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
 }
 
-func (r *RoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+// UpgradeState migrates state from version 0, which only had scopes, by
+// copying them into permissions.
+func (r *RoleResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"name": schema.StringAttribute{
+						Required: true,
+					},
+					"tenant": schema.StringAttribute{
+						CustomType: tenantNameType{},
+						Required:   true,
+					},
+					"scopes": schema.ListAttribute{
+						ElementType: types.StringType,
+						Required:    true,
+					},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior roleResourceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, RoleResourceModel{
+					ID:          prior.ID,
+					Name:        prior.Name,
+					Tenant:      prior.Tenant,
+					Scopes:      prior.Scopes,
+					Permissions: prior.Scopes,
+					OnDestroy:   types.StringValue(onDestroyDelete),
+				})...)
+			},
+		},
+	}
 }
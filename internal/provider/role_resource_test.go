@@ -0,0 +1,812 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccRoleResource(t *testing.T) {
+	server := testserver.New(t)
+	server.CreateTenant("acme")
+	seen := 0
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			func(s *terraform.State) error {
+				if roles := server.Roles("acme"); len(roles) != 0 {
+					return fmt.Errorf("expected every role to be deleted, got %v", roles)
+				}
+				return testAccCheckRoleRequests(server, &seen, "DELETE /tenants/acme/roles/editor")(s)
+			},
+			testAccCheckDestroyed(server.URL, "authproxy_role", func(attributes map[string]string) string {
+				return tenantRolePath(attributes["tenant"], attributes["name"])
+			}),
+		),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRoleResourceConfig("viewer", `"billing:read"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_role.test", "tenant", "acme"),
+					resource.TestCheckResourceAttr("authproxy_role.test", "name", "viewer"),
+					resource.TestCheckResourceAttr("authproxy_role.test", "scopes.#", "1"),
+					resource.TestCheckResourceAttr("authproxy_role.test", "scopes.0", "billing:read"),
+					resource.TestCheckResourceAttrSet("authproxy_role.test", "id"),
+					testAccCheckRoleRequests(server, &seen, "POST /tenants/acme/roles"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_role.test",
+				ImportState:       true,
+				ImportStateId:     "acme/viewer",
+				ImportStateVerify: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					requests := server.Requests()
+					if last := requests[len(requests)-1].String(); last != "GET /tenants/acme/roles/viewer" {
+						return fmt.Errorf("expected the role to be imported by tenant and name, got %s", last)
+					}
+					return nil
+				},
+			},
+			// Update and Read testing, the role is renamed through its old name
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRoleResourceConfig("editor", `"billing:read", "billing:write"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_role.test", "name", "editor"),
+					resource.TestCheckResourceAttr("authproxy_role.test", "scopes.#", "2"),
+					resource.TestCheckResourceAttr("authproxy_role.test", "scopes.1", "billing:write"),
+					testAccCheckRoleRequests(server, &seen, "PATCH /tenants/acme/roles/viewer"),
+					func(s *terraform.State) error {
+						roles := server.Roles("acme")
+						if len(roles) != 1 || roles[0].Name != "editor" || roles[0].ID != s.RootModule().Resources["authproxy_role.test"].Primary.ID {
+							return fmt.Errorf("expected the role to be renamed in authproxy, got %v", roles)
+						}
+						return nil
+					},
+				),
+			},
+			// Scopes changed outside of Terraform are detected
+			{
+				PreConfig: func() {
+					role := server.Roles("acme")[0]
+					role.Scopes = append(role.Scopes, "billing:export")
+					server.PutRole("acme", role)
+				},
+				Config:             testAccProviderConfig(server.URL) + testAccRoleResourceConfig("editor", `"billing:read", "billing:write"`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and reverted
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRoleResourceConfig("editor", `"billing:read", "billing:write"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_role.test", "scopes.#", "2"),
+					testAccCheckRoleRequests(server, &seen, "PATCH /tenants/acme/roles/editor"),
+					func(s *terraform.State) error {
+						if scopes := strings.Join(server.Roles("acme")[0].Scopes, ","); scopes != "billing:read,billing:write" {
+							return fmt.Errorf("expected the scopes to be reverted in authproxy, got %s", scopes)
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// testAccCheckRoleRequests returns a check ensuring every request server
+// received since the previous check addressed the roles of the acme tenant,
+// and that the requests besides reads were exactly writes. seen tracks the
+// requests already checked.
+func testAccCheckRoleRequests(server *testserver.Server, seen *int, writes ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		requests := server.Requests()
+		since := requests[*seen:]
+		*seen = len(requests)
+
+		var got []string
+		for _, request := range since {
+			if !strings.HasPrefix(request.Path, tenantRolesPath("acme")) {
+				return fmt.Errorf("expected only the roles of acme to be requested, got %s", request)
+			}
+			if request.Method != http.MethodGet {
+				got = append(got, request.String())
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(writes, "\n") {
+			return fmt.Errorf("expected the writes %v, got %v", writes, got)
+		}
+		return nil
+	}
+}
+
+func testAccRoleResourceConfig(name string, scopes string) string {
+	return fmt.Sprintf(`
+resource "authproxy_role" "test" {
+  tenant = "acme"
+  name   = %q
+  scopes = [%s]
+}
+`, name, scopes)
+}
+
+func TestRoleResource_validateConfig(t *testing.T) {
+	server := providerserver.NewProtocol6(New("test")())()
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+
+	for name, tc := range map[string]struct {
+		config   map[string]tftypes.Value
+		warnings []string
+		errors   []string
+	}{
+		"permissions": {
+			config: map[string]tftypes.Value{"permissions": stringList("billing:read")},
+		},
+		"deprecated scopes": {
+			config:   map[string]tftypes.Value{"scopes": stringList("billing:read")},
+			warnings: []string{"scopes"},
+		},
+		"unknown scopes": {
+			config: map[string]tftypes.Value{"scopes": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue)},
+		},
+		"both": {
+			config:   map[string]tftypes.Value{"scopes": stringList("billing:read"), "permissions": stringList("billing:read")},
+			warnings: []string{"scopes"},
+			errors:   []string{"scopes"},
+		},
+		"neither": {
+			config: map[string]tftypes.Value{},
+			errors: []string{"permissions"},
+		},
+		"malformed scopes": {
+			config:   map[string]tftypes.Value{"scopes": stringList("billing:read", "billing", "users:write", "ops:")},
+			warnings: []string{"scopes"},
+			errors:   []string{"scopes[1]", "scopes[3]"},
+		},
+		"malformed permissions": {
+			config: map[string]tftypes.Value{"permissions": stringList("billing", "billing:read", "users", "Billing:Read", "ops:")},
+			errors: []string{"permissions[0]", "permissions[2]", "permissions[3]", "permissions[4]"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tc.config["tenant"] = str("acme")
+			tc.config["name"] = str("viewer")
+			resp, err := server.ValidateResourceConfig(context.Background(), &tfprotov6.ValidateResourceConfigRequest{
+				TypeName: "authproxy_role",
+				Config:   testResourceValue(t, server, "authproxy_role", tc.config),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var warnings, errors []string
+			for _, diagnostic := range resp.Diagnostics {
+				attribute := fmt.Sprint(diagnostic.Attribute)
+				if diagnostic.Attribute != nil {
+					attribute = ""
+					for _, step := range diagnostic.Attribute.Steps() {
+						switch step := step.(type) {
+						case tftypes.AttributeName:
+							attribute += string(step)
+						case tftypes.ElementKeyInt:
+							attribute += fmt.Sprintf("[%d]", step)
+						}
+					}
+				}
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityWarning {
+					warnings = append(warnings, attribute)
+				} else {
+					errors = append(errors, attribute)
+				}
+			}
+			if fmt.Sprint(warnings) != fmt.Sprint(tc.warnings) || fmt.Sprint(errors) != fmt.Sprint(tc.errors) {
+				t.Errorf("expected warnings about %v and errors about %v, got %v", tc.warnings, tc.errors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// TestRoleResource_permissions manages roles configured with either scopes
+// or permissions against servers before and after authproxy v2, which
+// reject the field they do not know.
+func TestRoleResource_permissions(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+
+	for name, tc := range map[string]struct {
+		attribute string
+		v2        bool
+	}{
+		"old config, old server": {attribute: "scopes"},
+		"old config, new server": {attribute: "scopes", v2: true},
+		"new config, old server": {attribute: "permissions"},
+		"new config, new server": {attribute: "permissions", v2: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := testserver.New(t)
+			backend.CreateTenant("acme")
+			backend.SetFeatures(map[string]bool{testserver.RolePermissionsFeature: tc.v2})
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint": str(backend.URL),
+				"username": str(testserver.Username),
+				"password": str(testserver.Password),
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			config := map[string]tftypes.Value{"tenant": str("acme"), "name": str("viewer"), tc.attribute: stringList("billing:read")}
+			state := testProviderApply(t, server, "authproxy_role", nil, config)
+			if roles := backend.Roles("acme"); len(roles) != 1 || strings.Join(roles[0].Scopes, ",") != "billing:read" {
+				t.Fatalf("expected the role to be created with its scopes, got %v", roles)
+			}
+
+			config[tc.attribute] = stringList("billing:read", "billing:write")
+			state = testProviderApply(t, server, "authproxy_role", state, config)
+			if roles := backend.Roles("acme"); len(roles) != 1 || strings.Join(roles[0].Scopes, ",") != "billing:read,billing:write" {
+				t.Fatalf("expected the scopes of the role to be updated, got %v", roles)
+			}
+
+			state = testProviderRefresh(t, server, "authproxy_role", state)
+			for _, attribute := range []string{"scopes", "permissions"} {
+				if !state[attribute].Equal(stringList("billing:read", "billing:write")) {
+					t.Errorf("expected %s to hold the scopes of the role, got %s", attribute, state[attribute])
+				}
+			}
+
+			// Moving the scopes to the other attribute plans no changes.
+			other := map[string]tftypes.Value{"tenant": str("acme"), "name": str("viewer")}
+			for _, attribute := range []string{"scopes", "permissions"} {
+				if attribute != tc.attribute {
+					other[attribute] = config[tc.attribute]
+				}
+			}
+			resp := testProviderPlan(t, server, "authproxy_role", state, other)
+			if len(resp.Diagnostics) != 0 {
+				t.Fatalf("unexpected plan diagnostics: %v", resp.Diagnostics)
+			}
+			planned := testResourceValues(t, server, "authproxy_role", resp.PlannedState)
+			for attribute, value := range state {
+				if !planned[attribute].Equal(value) {
+					t.Errorf("expected %s to be kept, got %s", attribute, planned[attribute])
+				}
+			}
+		})
+	}
+}
+
+func TestRoleResource_upgradeState(t *testing.T) {
+	server := providerserver.NewProtocol6(New("test")())()
+	// Like Terraform, load the schemas before upgrading any state.
+	if _, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.UpgradeResourceState(context.Background(), &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "authproxy_role",
+		Version:  0,
+		RawState: &tfprotov6.RawState{JSON: []byte(`{"id":"8b2e4c6d-1f3a-4e5b-8c7d-9a0b1c2d3e4f","name":"viewer","tenant":"acme","scopes":["billing:read","billing:write"]}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	upgraded := testResourceValues(t, server, "authproxy_role", resp.UpgradedState)
+	got := make(map[string]interface{}, len(upgraded))
+	for attribute, value := range upgraded {
+		got[attribute] = contractValue(t, value)
+	}
+	expected := map[string]interface{}{
+		"id":                   "8b2e4c6d-1f3a-4e5b-8c7d-9a0b1c2d3e4f",
+		"name":                 "viewer",
+		"tenant":               "acme",
+		"scopes":               []interface{}{"billing:read", "billing:write"},
+		"permissions":          []interface{}{"billing:read", "billing:write"},
+		"on_destroy":           "delete",
+		"wait_for_consistency": nil,
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+// TestRoleResource_onDestroy destroys roles in either mode, switching the
+// mode of created roles in place first.
+func TestRoleResource_onDestroy(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+
+	for name, tc := range map[string]struct {
+		onDestroy tftypes.Value
+		deleted   bool
+	}{
+		"default": {onDestroy: tftypes.NewValue(tftypes.String, nil), deleted: true},
+		"delete":  {onDestroy: str("delete"), deleted: true},
+		"abandon": {onDestroy: str("abandon")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := testserver.New(t)
+			backend.CreateTenant("acme")
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint": str(backend.URL),
+				"username": str(testserver.Username),
+				"password": str(testserver.Password),
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			config := map[string]tftypes.Value{"tenant": str("acme"), "name": str("viewer"), "permissions": stringList("billing:read")}
+			state := testProviderApply(t, server, "authproxy_role", nil, config)
+			if !state["on_destroy"].Equal(str("delete")) {
+				t.Errorf("expected on_destroy to default to delete, got %s", state["on_destroy"])
+			}
+
+			config["on_destroy"] = tc.onDestroy
+			if resp := testProviderPlan(t, server, "authproxy_role", state, config); len(resp.RequiresReplace) != 0 {
+				t.Errorf("expected on_destroy to change in place, got replacement for %v", resp.RequiresReplace)
+			}
+			state = testProviderApply(t, server, "authproxy_role", state, config)
+
+			if state = testProviderApply(t, server, "authproxy_role", state, nil); len(state) != 0 {
+				t.Errorf("expected the role to be removed from state, got %v", state)
+			}
+			deletes := 0
+			for _, request := range backend.Requests() {
+				if request.Method == http.MethodDelete {
+					deletes++
+				}
+			}
+			if tc.deleted {
+				if deletes != 1 || len(backend.Roles("acme")) != 0 {
+					t.Errorf("expected the role to be deleted, got %d DELETE requests and %v", deletes, backend.Roles("acme"))
+				}
+				return
+			}
+			if deletes != 0 || len(backend.Roles("acme")) != 1 {
+				t.Errorf("expected the role to be abandoned, got %d DELETE requests and %v", deletes, backend.Roles("acme"))
+			}
+		})
+	}
+}
+
+func TestRoleResource_revokedScopesWarning(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	configure := func(t *testing.T, countBindings bool) (*testserver.Server, tfprotov6.ProviderServer) {
+		t.Helper()
+		backend := testserver.New(t)
+		backend.CreateTenant("acme")
+		server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+			"endpoint":            str(backend.URL),
+			"username":            str(testserver.Username),
+			"password":            str(testserver.Password),
+			"count_role_bindings": tftypes.NewValue(tftypes.Bool, countBindings),
+		})
+		if len(diags) != 0 {
+			t.Fatalf("unexpected configure diagnostics: %v", diags)
+		}
+		return backend, server
+	}
+	config := func(attribute string, scopes ...string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{"tenant": str("acme"), "name": str("editor"), attribute: stringList(scopes...)}
+	}
+
+	_, server := configure(t, false)
+	state := testProviderApply(t, server, "authproxy_role", nil, config("permissions", "billing:read", "billing:write", "billing:export"))
+
+	for name, tc := range map[string]struct {
+		config   map[string]tftypes.Value
+		expected string
+	}{
+		"unchanged":  {config: config("permissions", "billing:read", "billing:write", "billing:export")},
+		"reordered":  {config: config("permissions", "billing:export", "billing:read", "billing:write")},
+		"addition":   {config: config("permissions", "billing:read", "billing:write", "billing:export", "audit:read")},
+		"deprecated": {config: config("scopes", "billing:read", "billing:write", "billing:export")},
+		"removal": {
+			config:   config("permissions", "billing:read", "audit:read"),
+			expected: `The update removes "billing:write", "billing:export" from role "editor" of tenant "acme", revoking access from every user and group bound to it.`,
+		},
+		"removal of deprecated scopes": {
+			config:   config("scopes", "billing:read"),
+			expected: `The update removes "billing:write", "billing:export" from role "editor" of tenant "acme", revoking access from every user and group bound to it.`,
+		},
+		"removal of every scope": {
+			config:   config("permissions"),
+			expected: `The update removes "billing:read", "billing:write", "billing:export" from role`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := testProviderPlan(t, server, "authproxy_role", state, tc.config)
+			if tc.expected == "" {
+				if len(resp.Diagnostics) != 0 {
+					t.Errorf("expected no diagnostics, got %v", resp.Diagnostics)
+				}
+				return
+			}
+
+			attribute := "permissions"
+			if tc.config["permissions"].IsNull() {
+				attribute = "scopes"
+			}
+			if len(resp.Diagnostics) != 1 {
+				t.Fatalf("expected a single warning, got %v", resp.Diagnostics)
+			}
+			diagnostic := resp.Diagnostics[0]
+			if diagnostic.Severity != tfprotov6.DiagnosticSeverityWarning || !diagnostic.Attribute.Equal(tftypes.NewAttributePath().WithAttributeName(attribute)) {
+				t.Errorf("expected a warning about %s, got %v", attribute, diagnostic)
+			}
+			if !strings.HasPrefix(diagnostic.Detail, tc.expected) || strings.Contains(diagnostic.Detail, "Bindings") {
+				t.Errorf("expected the warning %q, got %q", tc.expected, diagnostic.Detail)
+			}
+		})
+	}
+
+	t.Run("bindings", func(t *testing.T) {
+		backend, server := configure(t, true)
+		backend.Handle(tenantRolePath("acme", "editor")+"/bindings", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"items":[{"principal_type":"user","principal_id":"42"},{"principal_type":"group","principal_id":"admins"}]}`)
+		}))
+		state := testProviderApply(t, server, "authproxy_role", nil, config("permissions", "billing:read", "billing:write"))
+
+		resp := testProviderPlan(t, server, "authproxy_role", state, config("permissions", "billing:read", "billing:write", "audit:read"))
+		if len(resp.Diagnostics) != 0 {
+			t.Errorf("expected no diagnostics for additions, got %v", resp.Diagnostics)
+		}
+		for _, request := range backend.Requests() {
+			if strings.HasSuffix(request.Path, "/bindings") {
+				t.Errorf("expected the bindings to only be counted when scopes are removed, got %s", request)
+			}
+		}
+
+		resp = testProviderPlan(t, server, "authproxy_role", state, config("permissions", "billing:read"))
+		if len(resp.Diagnostics) != 1 || !strings.HasSuffix(resp.Diagnostics[0].Detail, " Bindings of the role affected: 2.") {
+			t.Errorf("expected the warning to count the bindings, got %v", resp.Diagnostics)
+		}
+	})
+}
+
+// TestRoleResource_ignoredUpdate updates roles on a server which accepts
+// changes of some fields only to drop them, like older authproxy builds.
+func TestRoleResource_ignoredUpdate(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	backend := testserver.New(t)
+	backend.CreateTenant("acme")
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(backend.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	config := map[string]tftypes.Value{"tenant": str("acme"), "name": str("editor"), "permissions": stringList("billing:read")}
+	state := testProviderApply(t, server, "authproxy_role", nil, config)
+
+	for name, tc := range map[string]struct {
+		dropped   string
+		attribute string
+		value     tftypes.Value
+	}{
+		"scopes": {dropped: "scopes", attribute: "permissions", value: stringList("billing:read", "billing:write")},
+		"rename": {dropped: "name", attribute: "name", value: str("writer")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend.DropUpdates(tc.dropped)
+			changed := map[string]tftypes.Value{}
+			for attribute, value := range config {
+				changed[attribute] = value
+			}
+			changed[tc.attribute] = tc.value
+
+			resp := testProviderApplyResponse(t, server, "authproxy_role", state, changed)
+			if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Update Ignored by Authproxy" {
+				t.Fatalf("expected an ignored update error, got %v", resp.Diagnostics)
+			}
+			if expected := "ignored the changes to " + tc.attribute + ", which"; !strings.Contains(resp.Diagnostics[0].Detail, expected) {
+				t.Errorf("expected the error to name %s, got %q", tc.attribute, resp.Diagnostics[0].Detail)
+			}
+
+			// The state holds the role as authproxy reports it.
+			newState := testResourceValues(t, server, "authproxy_role", resp.NewState)
+			if !newState[tc.attribute].Equal(state[tc.attribute]) {
+				t.Errorf("expected %s to keep %s in state, got %s", tc.attribute, state[tc.attribute], newState[tc.attribute])
+			}
+		})
+	}
+
+	// Servers applying the update do not fail it.
+	backend.DropUpdates()
+	config["permissions"] = stringList("billing:read", "billing:write")
+	state = testProviderApply(t, server, "authproxy_role", state, config)
+	if !state["permissions"].Equal(config["permissions"]) {
+		t.Errorf("expected the permissions to be updated, got %s", state["permissions"])
+	}
+}
+
+func TestRoleResource_batchReads(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+
+	for name, tc := range map[string]struct {
+		batchReads tftypes.Value
+		// lists and gets count the requests of the first refresh of every
+		// role, of which one is deleted outside of Terraform.
+		lists, gets int
+	}{
+		"batched":   {batchReads: tftypes.NewValue(tftypes.Bool, true), lists: 1, gets: 1},
+		"unbatched": {batchReads: tftypes.NewValue(tftypes.Bool, nil), gets: 20},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := testserver.New(t)
+			backend.CreateTenant("acme")
+			backend.CreateTenant("globex")
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint":    str(backend.URL),
+				"username":    str(testserver.Username),
+				"password":    str(testserver.Password),
+				"batch_reads": tc.batchReads,
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			states := make([]*tfprotov6.DynamicValue, 0, 20)
+			for i := 0; i < 20; i++ {
+				role := backend.PutRole("acme", testserver.Role{Name: fmt.Sprintf("role-%d", i), Scopes: []string{"billing:read"}})
+				states = append(states, testResourceValue(t, server, "authproxy_role", map[string]tftypes.Value{
+					"id": str(role.ID), "tenant": str("acme"), "name": str(role.Name),
+				}))
+			}
+			backend.DeleteRole("acme", "role-19")
+
+			// Terraform refreshes resources concurrently. Slowing down the
+			// reads keeps all of them in flight until the list is served.
+			backend.SetChaos(testserver.Chaos{Rate: 1, Faults: []testserver.Fault{testserver.FaultSlow}, Methods: []string{http.MethodGet}, Delay: 200 * time.Millisecond})
+			responses := make([]*tfprotov6.ReadResourceResponse, len(states))
+			errs := make([]error, len(states))
+			var wg sync.WaitGroup
+			for i := range states {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					responses[i], errs[i] = server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+						TypeName:     "authproxy_role",
+						CurrentState: states[i],
+					})
+				}(i)
+			}
+			wg.Wait()
+			backend.SetChaos(testserver.Chaos{})
+			for i, resp := range responses {
+				if errs[i] != nil || len(resp.Diagnostics) != 0 {
+					t.Fatalf("unexpected read error %v and diagnostics %v", errs[i], resp.Diagnostics)
+				}
+				refreshed := testResourceValues(t, server, "authproxy_role", resp.NewState)
+				if i == 19 && len(refreshed) != 0 {
+					t.Errorf("expected the deleted role to be removed from the state, got %v", refreshed)
+				}
+				if i < 19 && (!refreshed["name"].Equal(str(fmt.Sprintf("role-%d", i))) || !refreshed["permissions"].Equal(stringList("billing:read"))) {
+					t.Errorf("unexpected state of role-%d: %v", i, refreshed)
+				}
+			}
+
+			countRequests := func() (lists int, gets int) {
+				for _, request := range backend.Requests() {
+					switch {
+					case request.Method != http.MethodGet:
+					case request.Path == tenantRolesPath("acme"):
+						lists++
+					case strings.HasPrefix(request.Path, tenantRolesPath("acme")+"/"):
+						gets++
+					}
+				}
+				return lists, gets
+			}
+			if lists, gets := countRequests(); lists != tc.lists || gets != tc.gets {
+				t.Errorf("expected %d lists and %d reads of single roles, got %d and %d", tc.lists, tc.gets, lists, gets)
+			}
+
+			// The list was dropped once the refreshes were done, so a later
+			// refresh lists the roles again and does not miss changes since.
+			backend.PutRole("acme", testserver.Role{Name: "role-0", Scopes: []string{"billing:write"}})
+			refreshed := testProviderRefresh(t, server, "authproxy_role", testResourceValues(t, server, "authproxy_role", responses[0].NewState))
+			if !refreshed["permissions"].Equal(stringList("billing:write")) {
+				t.Errorf("expected the changed permissions to be read, got %s", refreshed["permissions"])
+			}
+			lists, gets := countRequests()
+			if batched := tc.lists != 0; batched && (lists != tc.lists+1 || gets != tc.gets) || !batched && (lists != 0 || gets != tc.gets+1) {
+				t.Errorf("expected the role to be read anew, got %d lists and %d reads of single roles", lists, gets)
+			}
+		})
+	}
+}
+
+func TestRoleResource_batchReadsDroppedOnWrites(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	backend := testserver.New(t)
+	backend.CreateTenant("acme")
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint":    str(backend.URL),
+		"username":    str(testserver.Username),
+		"password":    str(testserver.Password),
+		"batch_reads": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	viewer := testProviderApply(t, server, "authproxy_role", nil, map[string]tftypes.Value{
+		"tenant": str("acme"), "name": str("viewer"), "permissions": stringList("billing:read"),
+	})
+	editor := map[string]tftypes.Value{"tenant": str("acme"), "name": str("editor"), "permissions": stringList("billing:write")}
+	editorState := testProviderApply(t, server, "authproxy_role", nil, editor)
+
+	// The first read lists the roles of the tenant. Updating the editor
+	// drops the list, so reading it afterwards does not serve the listed
+	// permissions.
+	testProviderRefresh(t, server, "authproxy_role", viewer)
+	editor["permissions"] = stringList("billing:read", "billing:write")
+	editorState = testProviderApply(t, server, "authproxy_role", editorState, editor)
+	if refreshed := testProviderRefresh(t, server, "authproxy_role", editorState); !refreshed["permissions"].Equal(editor["permissions"]) {
+		t.Errorf("expected the updated permissions, got %s", refreshed["permissions"])
+	}
+
+	lists := 0
+	for _, request := range backend.Requests() {
+		if request.Method == http.MethodGet && request.Path == tenantRolesPath("acme") {
+			lists++
+		}
+	}
+	if lists != 2 {
+		t.Errorf("expected the roles to be listed again after the update, got %d lists", lists)
+	}
+}
+
+// TestRoleResource_waitForConsistency writes roles on a server propagating
+// writes lazily, whose reads answer with the previous version of a role for a
+// while.
+func TestRoleResource_waitForConsistency(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	boolean := func(b bool) tftypes.Value { return tftypes.NewValue(tftypes.Bool, b) }
+	null := tftypes.NewValue(tftypes.Bool, nil)
+
+	for name, tc := range map[string]struct {
+		reads    int
+		provider tftypes.Value
+		role     tftypes.Value
+		warning  bool
+		problem  string
+	}{
+		"provider default": {reads: 2, provider: boolean(true), role: null},
+		"role override":    {reads: 2, provider: boolean(false), role: boolean(true)},
+		"never consistent": {reads: 100, provider: boolean(true), role: null, warning: true},
+		"not waiting":      {reads: 2, provider: boolean(true), role: boolean(false), problem: "Update Ignored by Authproxy"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := testserver.New(t)
+			backend.CreateTenant("acme")
+			backend.SetPropagationReads(tc.reads)
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint":             str(backend.URL),
+				"username":             str(testserver.Username),
+				"password":             str(testserver.Password),
+				"wait_for_consistency": tc.provider,
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+			config := map[string]tftypes.Value{"tenant": str("acme"), "name": str("viewer"), "permissions": stringList("billing:read"), "wait_for_consistency": tc.role}
+			checkDiagnostics := func(write string, diagnostics []*tfprotov6.Diagnostic) {
+				t.Helper()
+				switch {
+				case tc.warning:
+					if len(diagnostics) != 1 || diagnostics[0].Severity != tfprotov6.DiagnosticSeverityWarning || diagnostics[0].Summary != "Role Not Yet Consistent" {
+						t.Errorf("%s: expected a Role Not Yet Consistent warning, got %v", write, diagnostics)
+					}
+				case len(diagnostics) != 0:
+					t.Errorf("%s: unexpected diagnostics: %v", write, diagnostics)
+				}
+			}
+
+			resp := testProviderApplyResponse(t, server, "authproxy_role", nil, config)
+			checkDiagnostics("create", resp.Diagnostics)
+			state := testResourceValues(t, server, "authproxy_role", resp.NewState)
+
+			config["permissions"] = stringList("billing:read", "billing:write")
+			resp = testProviderApplyResponse(t, server, "authproxy_role", state, config)
+			if tc.problem != "" {
+				if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != tc.problem {
+					t.Errorf("update: expected a %q error, got %v", tc.problem, resp.Diagnostics)
+				}
+				return
+			}
+			checkDiagnostics("update", resp.Diagnostics)
+			state = testResourceValues(t, server, "authproxy_role", resp.NewState)
+			if !state["permissions"].Equal(stringList("billing:read", "billing:write")) {
+				t.Errorf("expected the written permissions in state, got %s", state["permissions"])
+			}
+			if roles := backend.Roles("acme"); len(roles) != 1 || len(roles[0].Scopes) != 2 {
+				t.Errorf("expected the role to be updated, got %v", roles)
+			}
+		})
+	}
+}
+
+func TestRoleResource_invalidResponses(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	state := map[string]tftypes.Value{"id": str("r1"), "tenant": str("acme"), "name": str("viewer"), "scopes": stringList("billing:read"), "permissions": stringList("billing:read")}
+
+	for name, tc := range map[string]struct {
+		body      string
+		operation string
+		prior     map[string]tftypes.Value
+		planned   map[string]tftypes.Value
+		problem   string
+	}{
+		"create with an empty object": {
+			body:      `{}`,
+			operation: "create",
+			planned:   map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, tftypes.UnknownValue), "tenant": str("acme"), "name": str("viewer"), "scopes": stringList("billing:read"), "permissions": stringList("billing:read")},
+			problem:   `lacks the "id" attribute`,
+		},
+		"read with a list": {
+			body:      `[{"id": "r1", "name": "viewer", "scopes": ["billing:read"]}]`,
+			operation: "read",
+			prior:     state,
+			problem:   "is not a JSON object",
+		},
+		"update with an empty body": {
+			operation: "update",
+			prior:     state,
+			planned:   map[string]tftypes.Value{"id": str("r1"), "tenant": str("acme"), "name": str("editor"), "scopes": stringList("billing:read"), "permissions": stringList("billing:read")},
+			problem:   "is not a JSON object",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/health" {
+					_, _ = w.Write([]byte(`{"status": "ok"}`))
+					return
+				}
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			newState, diags := contractOperation(t, NewRoleResource(), testProviderData(server.URL), tc.operation, tc.prior, tc.planned)
+			if diags.ErrorsCount() != 1 || diags[0].Summary() != "Unexpected Authproxy Response" {
+				t.Fatalf("expected an unexpected response error, got %v", diags)
+			}
+			detail := diags[0].Detail()
+			for _, expected := range []string{tc.problem, strconv.Quote(tc.body), "endpoint may be misconfigured"} {
+				if !strings.Contains(detail, expected) {
+					t.Errorf("expected the detail to contain %q, got %q", expected, detail)
+				}
+			}
+			if tc.operation == "create" && !newState.IsNull() {
+				t.Errorf("expected nothing to be stored, got %s", newState)
+			}
+		})
+	}
+}
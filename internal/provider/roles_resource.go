@@ -0,0 +1,435 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/planmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// rolesBatchFeature is the feature flag of servers implementing the bulk
+// role endpoint.
+const rolesBatchFeature = "roles_batch"
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RolesResource{}
+var _ resource.ResourceWithImportState = &RolesResource{}
+
+func NewRolesResource() resource.Resource {
+	return &RolesResource{}
+}
+
+// RolesResource defines the resource implementation. It manages many roles of
+// a tenant at once, leaving roles it does not manage alone.
+type RolesResource struct {
+	BaseResource
+}
+
+// RolesResourceModel describes the resource data model.
+type RolesResourceModel struct {
+	ID     types.String    `tfsdk:"id"`
+	Tenant tenantNameValue `tfsdk:"tenant"`
+	Roles  types.Map       `tfsdk:"roles"`
+}
+
+// RolesResourceRoleModel describes a single role of the roles map.
+type RolesResourceRoleModel struct {
+	Scopes      types.Set    `tfsdk:"scopes"`
+	Description types.String `tfsdk:"description"`
+}
+
+// rolesResourceRoleAttrTypes are the attribute types of
+// RolesResourceRoleModel.
+var rolesResourceRoleAttrTypes = map[string]attr.Type{
+	"scopes":      types.SetType{ElemType: types.StringType},
+	"description": types.StringType,
+}
+
+type roleWriteRequest struct {
+	Name        string   `json:"name,omitempty"`
+	Scopes      []string `json:"scopes"`
+	Description string   `json:"description"`
+}
+
+type roleResponse struct {
+	Name        string   `json:"name"`
+	Scopes      []string `json:"scopes"`
+	Description string   `json:"description"`
+}
+
+// rolesBatchRequest creates or updates the roles of Upsert and deletes the
+// roles named in Delete in a single transaction.
+type rolesBatchRequest struct {
+	Upsert []roleWriteRequest `json:"upsert"`
+	Delete []string           `json:"delete"`
+}
+
+func (r *RolesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_roles"
+}
+
+func (r *RolesResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Manages many roles of a tenant at once. Servers supporting it apply all changes in a single transaction, other servers role by role. Roles not in the map are left alone",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the tenant",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the roles belong to. Changing it recreates the roles",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot move roles between tenants"),
+				},
+			},
+			"roles": schema.MapNestedAttribute{
+				MarkdownDescription: "The managed roles keyed by name",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"scopes": schema.SetAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "The scopes of the role",
+							Required:            true,
+							Validators: []validator.Set{
+								scopeNames(),
+							},
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "What the role is for",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// tenantRolesPath returns the path of the roles collection of tenant.
+func tenantRolesPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/roles", url.PathEscape(tenant))
+}
+
+// tenantRolePath returns the path of the role of tenant named name.
+func tenantRolePath(tenant string, name string) string {
+	return tenantRolesPath(tenant) + "/" + url.PathEscape(name)
+}
+
+// roles returns the roles of data keyed by name with sorted scopes, nil when
+// they are null.
+func (data *RolesResourceModel) roles(ctx context.Context) (map[string]roleWriteRequest, diag.Diagnostics) {
+	if data.Roles.IsNull() {
+		return nil, nil
+	}
+
+	var models map[string]RolesResourceRoleModel
+	diags := data.Roles.ElementsAs(ctx, &models, false)
+
+	roles := make(map[string]roleWriteRequest, len(models))
+	for name, model := range models {
+		role := roleWriteRequest{
+			Scopes:      []string{},
+			Description: model.Description.ValueString(),
+		}
+		diags.Append(model.Scopes.ElementsAs(ctx, &role.Scopes, false)...)
+		sort.Strings(role.Scopes)
+		roles[name] = role
+	}
+
+	return roles, diags
+}
+
+// setRoles stores roles into data.
+func (data *RolesResourceModel) setRoles(ctx context.Context, roles map[string]roleWriteRequest) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	models := make(map[string]RolesResourceRoleModel, len(roles))
+	for name, role := range roles {
+		if role.Scopes == nil {
+			role.Scopes = []string{}
+		}
+		scopes, d := types.SetValueFrom(ctx, types.StringType, role.Scopes)
+		diags.Append(d...)
+		models[name] = RolesResourceRoleModel{
+			Scopes:      scopes,
+			Description: optionalString(role.Description),
+		}
+	}
+
+	value, d := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: rolesResourceRoleAttrTypes}, models)
+	diags.Append(d...)
+	data.Roles = value
+
+	return diags
+}
+
+// sameRole reports whether the role authproxy returned matches role.
+func sameRole(current roleResponse, role roleWriteRequest) bool {
+	scopes := append([]string(nil), current.Scopes...)
+	sort.Strings(scopes)
+	return current.Description == role.Description && strings.Join(scopes, "\x00") == strings.Join(role.Scopes, "\x00")
+}
+
+// sortedRoleNames returns the names of roles in a stable order.
+func sortedRoleNames(roles map[string]roleWriteRequest) []string {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// apply makes the managed roles of tenant match desired, deleting the roles
+// of managed that are no longer desired. It returns the roles that are
+// managed afterwards, which differ from desired when changes failed.
+//
+// Servers implementing the bulk endpoint apply every change or none. Other
+// servers are reconciled role by role, carrying on past failed roles so a
+// single bad role does not hold back the others. Every failure is reported.
+func (r *RolesResource) apply(ctx context.Context, tenant string, desired map[string]roleWriteRequest, managed map[string]roleWriteRequest, diags *diag.Diagnostics) map[string]roleWriteRequest {
+	var removed []string
+	for _, name := range sortedRoleNames(managed) {
+		if _, ok := desired[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	batch, err := r.providerData.supports(ctx, rolesBatchFeature)
+	if err != nil {
+		addClientError(diags, "probe server capabilities", err)
+		return managed
+	}
+
+	if batch {
+		body := rolesBatchRequest{
+			Upsert: make([]roleWriteRequest, 0, len(desired)),
+			Delete: []string{},
+		}
+		for _, name := range sortedRoleNames(desired) {
+			role := desired[name]
+			role.Name = name
+			body.Upsert = append(body.Upsert, role)
+		}
+		body.Delete = append(body.Delete, removed...)
+
+		if err := r.doJSON(ctx, "PUT", tenantRolesPath(tenant)+":batch", body, nil); err != nil {
+			addClientError(diags, "apply roles", err)
+			return managed
+		}
+		return desired
+	}
+
+	tflog.Debug(ctx, "server has no bulk role endpoint, applying roles one by one")
+
+	roles, err := listAll[roleResponse](ctx, r.providerData, tenantRolesPath(tenant), nil)
+	if err != nil {
+		addClientError(diags, "list roles", err)
+		return managed
+	}
+	current := make(map[string]roleResponse, len(roles))
+	for _, role := range roles {
+		current[role.Name] = role
+	}
+
+	applied := make(map[string]roleWriteRequest, len(managed)+len(desired))
+	for name, role := range managed {
+		applied[name] = role
+	}
+
+	for _, name := range sortedRoleNames(desired) {
+		role := desired[name]
+		existing, ok := current[name]
+		switch {
+		case ok && sameRole(existing, role):
+		case ok:
+			if err := r.doJSON(ctx, "PATCH", tenantRolePath(tenant, name), role, nil); err != nil {
+				addClientError(diags, fmt.Sprintf("update role %q", name), err)
+				continue
+			}
+		default:
+			create := role
+			create.Name = name
+			if err := r.doJSON(ctx, "POST", tenantRolesPath(tenant), create, nil); err != nil {
+				addClientError(diags, fmt.Sprintf("create role %q", name), err)
+				continue
+			}
+		}
+		applied[name] = role
+	}
+
+	for _, name := range removed {
+		err := r.doJSON(ctx, "DELETE", tenantRolePath(tenant, name), nil, nil)
+		if err != nil && !isStatus(err, http.StatusNotFound) {
+			addClientError(diags, fmt.Sprintf("delete role %q", name), err)
+			continue
+		}
+		delete(applied, name)
+	}
+
+	return applied
+}
+
+func (r *RolesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RolesResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired, diags := data.roles(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied := r.apply(ctx, data.Tenant.ValueString(), desired, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() && len(applied) == 0 {
+		return
+	}
+
+	// Roles that were created before a failure are tracked, otherwise they
+	// would be left behind.
+	data.ID = types.StringValue(data.Tenant.ValueString())
+	resp.Diagnostics.Append(data.setRoles(ctx, applied)...)
+
+	tflog.Trace(ctx, "created a roles resource", map[string]interface{}{
+		"tenant": data.Tenant.ValueString(),
+		"roles":  len(applied),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RolesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *RolesResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	roles, err := listAll[roleResponse](ctx, r.providerData, tenantRolesPath(data.Tenant.ValueString()), nil)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant no longer exists, removing its roles from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list roles", err)
+		return
+	}
+
+	managed, diags := data.roles(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Freshly imported resources own every role of the tenant, otherwise
+	// only the managed roles that still exist are reported.
+	current := make(map[string]roleWriteRequest, len(roles))
+	for _, role := range roles {
+		if _, ok := managed[role.Name]; managed != nil && !ok {
+			continue
+		}
+		scopes := append([]string{}, role.Scopes...)
+		sort.Strings(scopes)
+		current[role.Name] = roleWriteRequest{Scopes: scopes, Description: role.Description}
+	}
+
+	data.ID = types.StringValue(data.Tenant.ValueString())
+	resp.Diagnostics.Append(data.setRoles(ctx, current)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RolesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *RolesResourceModel
+	var old *RolesResourceModel
+
+	// Read Terraform old data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &old)...)
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired, diags := data.roles(ctx)
+	resp.Diagnostics.Append(diags...)
+	managed, diags := old.roles(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Whatever was applied is saved, failed roles are retried by the next
+	// apply.
+	applied := r.apply(ctx, data.Tenant.ValueString(), desired, managed, &resp.Diagnostics)
+	resp.Diagnostics.Append(data.setRoles(ctx, applied)...)
+
+	tflog.Trace(ctx, "updated a roles resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RolesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *RolesResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed, diags := data.roles(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, data.Tenant.ValueString(), nil, managed, &resp.Diagnostics)
+
+	tflog.Trace(ctx, "deleted a roles resource")
+}
+
+func (r *RolesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("tenant"), req, resp)
+}
@@ -0,0 +1,338 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccRolesBackend is an in-memory stand-in for the role endpoints of the
+// "acme" tenant, offering the bulk endpoint when batch is set. Writing any
+// role in failing fails, a batch containing one fails as a whole.
+type testAccRolesBackend struct {
+	mu      sync.Mutex
+	batch   bool
+	roles   map[string]roleResponse
+	failing map[string]bool
+	batches int
+	writes  int
+}
+
+func (b *testAccRolesBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prefix := tenantRolesPath("acme")
+	switch {
+	case r.URL.Path == prefix+":batch" && r.Method == http.MethodPut && b.batch:
+		var body rolesBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, role := range body.Upsert {
+			if b.failing[role.Name] {
+				http.Error(w, fmt.Sprintf("role %s is invalid, no role was changed", role.Name), http.StatusUnprocessableEntity)
+				return
+			}
+		}
+		for _, role := range body.Upsert {
+			b.roles[role.Name] = roleResponse{Name: role.Name, Scopes: role.Scopes, Description: role.Description}
+		}
+		for _, name := range body.Delete {
+			delete(b.roles, name)
+		}
+		b.batches++
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case !strings.HasPrefix(r.URL.Path, prefix):
+		http.NotFound(w, r)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	var body roleWriteRequest
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if b.failing[body.Name] || b.failing[name] {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		b.writes++
+	}
+
+	switch {
+	case name == "" && r.Method == http.MethodGet:
+		names := make([]string, 0, len(b.roles))
+		for name := range b.roles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		roles := make([]roleResponse, 0, len(names))
+		for _, name := range names {
+			roles = append(roles, b.roles[name])
+		}
+		_ = json.NewEncoder(w).Encode(roles)
+	case name == "" && r.Method == http.MethodPost:
+		if _, ok := b.roles[body.Name]; ok {
+			http.Error(w, "role already exists", http.StatusConflict)
+			return
+		}
+		b.roles[body.Name] = roleResponse{Name: body.Name, Scopes: body.Scopes, Description: body.Description}
+		_ = json.NewEncoder(w).Encode(b.roles[body.Name])
+	case name != "" && r.Method == http.MethodPatch:
+		if _, ok := b.roles[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.roles[name] = roleResponse{Name: name, Scopes: body.Scopes, Description: body.Description}
+		_ = json.NewEncoder(w).Encode(b.roles[name])
+	case name != "" && r.Method == http.MethodDelete:
+		if _, ok := b.roles[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.writes++
+		delete(b.roles, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// names returns the names of the roles in authproxy, sorted and comma
+// separated.
+func (b *testAccRolesBackend) names() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	names := make([]string, 0, len(b.roles))
+	for name := range b.roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// checkDestroy ensures exactly the managed roles were deleted, leaving the
+// owner role created outside of Terraform in place.
+func (b *testAccRolesBackend) checkDestroy(s *terraform.State) error {
+	if names := b.names(); names != "owner" {
+		return fmt.Errorf("expected only the unmanaged owner role to be left, got %s", names)
+	}
+	return nil
+}
+
+// serve starts a testserver.Server faking the role endpoints of the acme
+// tenant with b, advertising the bulk endpoint when b offers it.
+func (b *testAccRolesBackend) serve(t *testing.T) *testserver.Server {
+	server := testBackendServer(t, b, tenantRolesPath("acme"), tenantRolesPath("acme")+":batch")
+	server.SetFeatures(map[string]bool{rolesBatchFeature: b.batch})
+	return server
+}
+
+func newTestAccRolesBackend(batch bool) *testAccRolesBackend {
+	return &testAccRolesBackend{
+		batch: batch,
+		roles: map[string]roleResponse{
+			"owner": {Name: "owner", Scopes: []string{"tenants:admin"}},
+		},
+		failing: map[string]bool{},
+	}
+}
+
+func TestAccRolesResource(t *testing.T) {
+	for name, batch := range map[string]bool{"bulk endpoint": true, "role by role": false} {
+		t.Run(name, func(t *testing.T) {
+			backend := newTestAccRolesBackend(batch)
+			server := backend.serve(t)
+
+			resourcetest.Test(t, resourcetest.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				CheckDestroy:             backend.checkDestroy,
+				Steps: []resourcetest.TestStep{
+					// Create and Read testing
+					{
+						Config: testAccProviderConfig(server.URL) + testAccRolesResourceConfig(`
+    viewer = { scopes = ["billing:read"] }
+    editor = { scopes = ["billing:read", "billing:write"], description = "Edits invoices" }
+`),
+						Check: resourcetest.ComposeAggregateTestCheckFunc(
+							resourcetest.TestCheckResourceAttr("authproxy_roles.test", "id", "acme"),
+							resourcetest.TestCheckResourceAttr("authproxy_roles.test", "roles.%", "2"),
+							resourcetest.TestCheckResourceAttr("authproxy_roles.test", "roles.editor.scopes.#", "2"),
+							resourcetest.TestCheckResourceAttr("authproxy_roles.test", "roles.editor.description", "Edits invoices"),
+							resourcetest.TestCheckNoResourceAttr("authproxy_roles.test", "roles.viewer.description"),
+							func(s *terraform.State) error {
+								if names := backend.names(); names != "editor,owner,viewer" {
+									return fmt.Errorf("expected the roles to be created next to owner, got %s", names)
+								}
+								backend.mu.Lock()
+								defer backend.mu.Unlock()
+								if batch && (backend.batches != 1 || backend.writes != 0) {
+									return fmt.Errorf("expected a single batch, got %d batches and %d writes", backend.batches, backend.writes)
+								}
+								if !batch && backend.writes != 2 {
+									return fmt.Errorf("expected a write per role, got %d writes", backend.writes)
+								}
+								return nil
+							},
+						),
+					},
+					// ImportState testing, imported resources own every role
+					{
+						ResourceName:  "authproxy_roles.test",
+						ImportState:   true,
+						ImportStateId: "acme",
+						ImportStateCheck: func(states []*terraform.InstanceState) error {
+							if len(states) != 1 || states[0].Attributes["roles.%"] != "3" || states[0].Attributes["roles.owner.scopes.#"] != "1" {
+								return fmt.Errorf("expected the three roles of the tenant to be imported, got %v", states)
+							}
+							return nil
+						},
+					},
+					// Update and Read testing
+					{
+						Config: testAccProviderConfig(server.URL) + testAccRolesResourceConfig(`
+    editor  = { scopes = ["billing:write"], description = "Edits invoices" }
+    auditor = { scopes = ["audit:read"] }
+`),
+						Check: resourcetest.ComposeAggregateTestCheckFunc(
+							resourcetest.TestCheckResourceAttr("authproxy_roles.test", "roles.%", "2"),
+							resourcetest.TestCheckResourceAttr("authproxy_roles.test", "roles.editor.scopes.#", "1"),
+							resourcetest.TestCheckResourceAttr("authproxy_roles.test", "roles.auditor.scopes.#", "1"),
+							func(s *terraform.State) error {
+								if names := backend.names(); names != "auditor,editor,owner" {
+									return fmt.Errorf("expected viewer to be replaced by auditor, got %s", names)
+								}
+								return nil
+							},
+						),
+					},
+					// A role deleted outside of Terraform is created again
+					{
+						PreConfig: func() {
+							backend.mu.Lock()
+							defer backend.mu.Unlock()
+							delete(backend.roles, "auditor")
+						},
+						Config: testAccProviderConfig(server.URL) + testAccRolesResourceConfig(`
+    editor  = { scopes = ["billing:write"], description = "Edits invoices" }
+    auditor = { scopes = ["audit:read"] }
+`),
+						Check: func(s *terraform.State) error {
+							if names := backend.names(); names != "auditor,editor,owner" {
+								return fmt.Errorf("expected auditor to be created again, got %s", names)
+							}
+							return nil
+						},
+					},
+					// Delete testing automatically occurs in TestCase
+				},
+			})
+		})
+	}
+}
+
+func TestAccRolesResource_partialFailure(t *testing.T) {
+	backend := newTestAccRolesBackend(false)
+	backend.failing["broken"] = true
+	server := backend.serve(t)
+
+	config := testAccProviderConfig(server.URL) + testAccRolesResourceConfig(`
+    viewer = { scopes = ["billing:read"] }
+    broken = { scopes = ["billing:read"] }
+    editor = { scopes = ["billing:write"] }
+`)
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// The other roles are created despite the failing one
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`Unable to create role "broken"`),
+			},
+			{
+				PreConfig: func() {
+					if names := backend.names(); names != "editor,owner,viewer" {
+						t.Errorf("expected the roles next to the failing one to be created, got %s", names)
+					}
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					delete(backend.failing, "broken")
+				},
+				Config: config,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_roles.test", "roles.%", "3"),
+					func(s *terraform.State) error {
+						if names := backend.names(); names != "broken,editor,owner,viewer" {
+							return fmt.Errorf("expected every role to be created, got %s", names)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccRolesResource_batchFailure(t *testing.T) {
+	backend := newTestAccRolesBackend(true)
+	backend.failing["broken"] = true
+	server := backend.serve(t)
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// A failing batch leaves the tenant untouched
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRolesResourceConfig(`
+    viewer = { scopes = ["billing:read"] }
+    broken = { scopes = ["billing:read"] }
+`),
+				ExpectError: regexp.MustCompile(`Unable to apply roles`),
+			},
+			{
+				PreConfig: func() {
+					if names := backend.names(); names != "owner" {
+						t.Errorf("expected no role to be created by the failed batch, got %s", names)
+					}
+				},
+				Config: testAccProviderConfig(server.URL) + testAccRolesResourceConfig(`
+    viewer = { scopes = ["billing:read"] }
+`),
+				Check: resourcetest.TestCheckResourceAttr("authproxy_roles.test", "roles.%", "1"),
+			},
+		},
+	})
+}
+
+func testAccRolesResourceConfig(roles string) string {
+	return fmt.Sprintf(`
+resource "authproxy_roles" "test" {
+  tenant = "acme"
+  roles = {
+%s  }
+}
+`, roles)
+}
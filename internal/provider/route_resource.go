@@ -0,0 +1,390 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RouteResource{}
+var _ resource.ResourceWithImportState = &RouteResource{}
+
+func NewRouteResource() resource.Resource {
+	return &RouteResource{}
+}
+
+// RouteResource defines the resource implementation.
+type RouteResource struct {
+	BaseResource
+}
+
+// RouteResourceModel describes the resource data model.
+type RouteResourceModel struct {
+	ID             types.String    `tfsdk:"id"`
+	Tenant         tenantNameValue `tfsdk:"tenant"`
+	Service        types.String    `tfsdk:"service"`
+	PathPrefix     types.String    `tfsdk:"path_prefix"`
+	Methods        types.Set       `tfsdk:"methods"`
+	RequiredScopes types.Set       `tfsdk:"required_scopes"`
+	Priority       types.Int64     `tfsdk:"priority"`
+	StripPrefix    types.Bool      `tfsdk:"strip_prefix"`
+}
+
+type routeRequest struct {
+	Service        string   `json:"service"`
+	PathPrefix     string   `json:"path_prefix"`
+	Methods        []string `json:"methods"`
+	RequiredScopes []string `json:"required_scopes"`
+	Priority       int64    `json:"priority"`
+	StripPrefix    bool     `json:"strip_prefix"`
+}
+
+// routeResponse refers to the service by both its id and name, whichever of
+// them was configured.
+type routeResponse struct {
+	ID             string   `json:"id"`
+	ServiceID      string   `json:"service_id"`
+	ServiceName    string   `json:"service_name"`
+	PathPrefix     string   `json:"path_prefix"`
+	Methods        []string `json:"methods"`
+	RequiredScopes []string `json:"required_scopes"`
+	Priority       int64    `json:"priority"`
+	StripPrefix    bool     `json:"strip_prefix"`
+}
+
+// routeConflictResponse is the body authproxy answers with when a route
+// matches the same requests as an existing one.
+type routeConflictResponse struct {
+	ID         string   `json:"id"`
+	PathPrefix string   `json:"path_prefix"`
+	Methods    []string `json:"methods"`
+}
+
+// routePathPrefixPattern is the format of route path prefixes, absolute
+// paths without query or fragment.
+var routePathPrefixPattern = regexp.MustCompile(`^/[^?#\s]*$`)
+
+func (r *RouteResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_route"
+}
+
+func (r *RouteResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Routes requests below a path to a service, requiring scopes of the caller",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the route belongs to. Changing it recreates the route",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service": schema.StringAttribute{
+				MarkdownDescription: "Name or id of the service matching requests are proxied to",
+				Required:            true,
+			},
+			"path_prefix": schema.StringAttribute{
+				MarkdownDescription: "Requests whose path starts with the prefix match the route, such as `/api/billing/`",
+				Required:            true,
+				Validators: []validator.String{
+					stringMatchesValidator{
+						pattern:     routePathPrefixPattern,
+						description: `value must be an absolute path without query or fragment such as "/api/billing/"`,
+					},
+				},
+			},
+			"methods": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Request methods matching the route, all methods when unset",
+				Optional:            true,
+				Validators: []validator.Set{
+					httpMethods(),
+				},
+			},
+			"required_scopes": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scopes the caller's token has to carry, any authenticated caller is let through when unset",
+				Optional:            true,
+				Validators: []validator.Set{
+					scopeNames(),
+				},
+			},
+			"priority": schema.Int64Attribute{
+				MarkdownDescription: "Routes of higher priority are matched first when several routes match a request, the longest prefix wins among routes of equal priority. Defaults to `0`",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(0),
+			},
+			"strip_prefix": schema.BoolAttribute{
+				MarkdownDescription: "Whether to remove the path prefix before proxying requests to the service. Defaults to `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+// routesPath returns the path of the routes collection of tenant.
+func routesPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/routes", url.PathEscape(tenant))
+}
+
+// routePath returns the path of the route of tenant with the given id.
+func routePath(tenant string, id string) string {
+	return routesPath(tenant) + "/" + url.PathEscape(id)
+}
+
+// request builds the create and update request body from data.
+func (data *RouteResourceModel) request(ctx context.Context) (routeRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	route := routeRequest{
+		Service:        data.Service.ValueString(),
+		PathPrefix:     data.PathPrefix.ValueString(),
+		Methods:        []string{},
+		RequiredScopes: []string{},
+		Priority:       data.Priority.ValueInt64(),
+		StripPrefix:    data.StripPrefix.ValueBool(),
+	}
+	if !data.Methods.IsNull() {
+		diags.Append(data.Methods.ElementsAs(ctx, &route.Methods, false)...)
+		sort.Strings(route.Methods)
+	}
+	if !data.RequiredScopes.IsNull() {
+		diags.Append(data.RequiredScopes.ElementsAs(ctx, &route.RequiredScopes, false)...)
+		sort.Strings(route.RequiredScopes)
+	}
+
+	return route, diags
+}
+
+// setRoute copies the attributes authproxy returned for a route into data.
+// The service keeps being referred to the way it was configured.
+func (data *RouteResourceModel) setRoute(ctx context.Context, route routeResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(route.ID)
+	if service := data.Service.ValueString(); service != route.ServiceID && service != route.ServiceName {
+		data.Service = types.StringValue(route.ServiceName)
+	}
+	data.PathPrefix = types.StringValue(route.PathPrefix)
+	data.Priority = types.Int64Value(route.Priority)
+	data.StripPrefix = types.BoolValue(route.StripPrefix)
+
+	// Empty sets stay null unless they were configured, so leaving methods
+	// or required_scopes unset does not produce a diff.
+	if len(route.Methods) > 0 || !data.Methods.IsNull() {
+		if route.Methods == nil {
+			route.Methods = []string{}
+		}
+		methods, d := types.SetValueFrom(ctx, types.StringType, route.Methods)
+		diags.Append(d...)
+		data.Methods = methods
+	}
+	if len(route.RequiredScopes) > 0 || !data.RequiredScopes.IsNull() {
+		if route.RequiredScopes == nil {
+			route.RequiredScopes = []string{}
+		}
+		scopes, d := types.SetValueFrom(ctx, types.StringType, route.RequiredScopes)
+		diags.Append(d...)
+		data.RequiredScopes = scopes
+	}
+
+	return diags
+}
+
+// addConflictError reports a route authproxy refused because an existing
+// route matches the same requests, naming that route. It returns false for
+// any other error.
+func (data *RouteResourceModel) addConflictError(diags *diag.Diagnostics, err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		return false
+	}
+
+	var conflict routeConflictResponse
+	if json.Unmarshal([]byte(apiErr.Body), &conflict) != nil || conflict.ID == "" {
+		return false
+	}
+
+	methods := "all"
+	if len(conflict.Methods) > 0 {
+		sort.Strings(conflict.Methods)
+		methods = strings.Join(conflict.Methods, ", ")
+	}
+	diags.AddAttributeError(
+		path.Root("path_prefix"),
+		"Route Conflict",
+		fmt.Sprintf("The route %q of tenant %q already matches %s requests below %q. "+
+			"Routes must not match the same path prefix and method, change either or import the existing route:\n\n"+
+			"terraform import authproxy_route.<name> %s/%s",
+			conflict.ID, data.Tenant.ValueString(), methods, conflict.PathPrefix, data.Tenant.ValueString(), conflict.ID),
+	)
+	return true
+}
+
+func (r *RouteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RouteResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var route routeResponse
+	err := r.doJSON(ctx, "POST", routesPath(data.Tenant.ValueString()), body, &route)
+	if data.addConflictError(&resp.Diagnostics, err) {
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create route", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRoute(ctx, route)...)
+
+	tflog.Trace(ctx, "created a route resource", map[string]interface{}{
+		"id": route.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RouteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *RouteResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var route routeResponse
+	err := r.doJSON(ctx, "GET", routePath(data.Tenant.ValueString(), data.ID.ValueString()), nil, &route)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "route no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"id":     data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read route", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRoute(ctx, route)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RouteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *RouteResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var route routeResponse
+	err := r.doJSON(ctx, "PATCH", routePath(data.Tenant.ValueString(), data.ID.ValueString()), body, &route)
+	if data.addConflictError(&resp.Diagnostics, err) {
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update route", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRoute(ctx, route)...)
+
+	tflog.Trace(ctx, "updated a route resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RouteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *RouteResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", routePath(data.Tenant.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete route", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a route resource")
+}
+
+func (r *RouteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, id, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,327 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccRouteBackend is an in-memory stand-in for the route endpoints of the
+// "acme" tenant, which has the services billing (svc1) and reports (svc2).
+// Routes of the same path prefix sharing a method conflict.
+type testAccRouteBackend struct {
+	mu     sync.Mutex
+	routes map[string]routeResponse
+	nextID int
+}
+
+var testAccRouteServices = map[string]string{"svc1": "billing", "svc2": "reports"}
+
+func (b *testAccRouteBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prefix := routesPath("acme")
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	var route routeResponse
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		var body routeRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		route = routeResponse{
+			ID:             id,
+			PathPrefix:     body.PathPrefix,
+			Methods:        body.Methods,
+			RequiredScopes: body.RequiredScopes,
+			Priority:       body.Priority,
+			StripPrefix:    body.StripPrefix,
+		}
+		for serviceID, name := range testAccRouteServices {
+			if body.Service == serviceID || body.Service == name {
+				route.ServiceID, route.ServiceName = serviceID, name
+			}
+		}
+		if route.ServiceID == "" {
+			http.Error(w, "service not found", http.StatusUnprocessableEntity)
+			return
+		}
+		if existing, ok := b.conflicting(route); ok {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(routeConflictResponse{ID: existing.ID, PathPrefix: existing.PathPrefix, Methods: existing.Methods})
+			return
+		}
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		b.nextID++
+		route.ID = fmt.Sprintf("rt%d", b.nextID)
+		b.routes[route.ID] = route
+		_ = json.NewEncoder(w).Encode(route)
+	case id != "" && r.Method == http.MethodGet:
+		route, ok := b.routes[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(route)
+	case id != "" && r.Method == http.MethodPatch:
+		if _, ok := b.routes[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.routes[id] = route
+		_ = json.NewEncoder(w).Encode(route)
+	case id != "" && r.Method == http.MethodDelete:
+		if _, ok := b.routes[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.routes, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// conflicting returns another route of the same path prefix as route sharing
+// a method with it. No methods match every method.
+func (b *testAccRouteBackend) conflicting(route routeResponse) (routeResponse, bool) {
+	for _, existing := range b.routes {
+		if existing.ID == route.ID || existing.PathPrefix != route.PathPrefix {
+			continue
+		}
+		if len(existing.Methods) == 0 || len(route.Methods) == 0 {
+			return existing, true
+		}
+		for _, method := range existing.Methods {
+			for _, other := range route.Methods {
+				if method == other {
+					return existing, true
+				}
+			}
+		}
+	}
+	return routeResponse{}, false
+}
+
+// match returns the service a request for requestPath is routed to, the
+// matching route of the highest priority winning.
+func (b *testAccRouteBackend) match(requestPath string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var best *routeResponse
+	for _, route := range b.routes {
+		route := route
+		if !strings.HasPrefix(requestPath, route.PathPrefix) {
+			continue
+		}
+		if best == nil || route.Priority > best.Priority ||
+			route.Priority == best.Priority && len(route.PathPrefix) > len(best.PathPrefix) {
+			best = &route
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.ServiceName
+}
+
+func (b *testAccRouteBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.routes) != 0 {
+		return fmt.Errorf("expected every route to be deleted, %d left", len(b.routes))
+	}
+	return nil
+}
+
+func TestAccRouteResource(t *testing.T) {
+	backend := &testAccRouteBackend{routes: map[string]routeResponse{}}
+	server := testBackendServer(t, backend, routesPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRouteResourceConfig(`"billing:read"`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_route.test", "id", "rt1"),
+					resourcetest.TestCheckResourceAttr("authproxy_route.test", "service", "billing"),
+					resourcetest.TestCheckResourceAttr("authproxy_route.test", "methods.#", "2"),
+					resourcetest.TestCheckResourceAttr("authproxy_route.test", "required_scopes.#", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_route.test", "priority", "0"),
+					resourcetest.TestCheckResourceAttr("authproxy_route.test", "strip_prefix", "true"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_route.test",
+				ImportState:       true,
+				ImportStateId:     "acme/rt1",
+				ImportStateVerify: true,
+			},
+			// Changing the required scopes updates the route in place
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRouteResourceConfig(`"billing:read", "billing:admin"`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_route.test", "id", "rt1"),
+					resourcetest.TestCheckResourceAttr("authproxy_route.test", "required_scopes.#", "2"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_route.test", "required_scopes.*", "billing:admin"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccRouteResource_priority(t *testing.T) {
+	backend := &testAccRouteBackend{routes: map[string]routeResponse{}}
+	server := testBackendServer(t, backend, routesPath("acme"))
+
+	expectMatch := func(requestPath string, service string) resourcetest.TestCheckFunc {
+		return func(s *terraform.State) error {
+			if got := backend.match(requestPath); got != service {
+				return fmt.Errorf("expected %s to be routed to %s, got %q", requestPath, service, got)
+			}
+			return nil
+		}
+	}
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// The longer prefix wins among routes of equal priority
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRoutePriorityConfig(0, 0),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_route.reports", "service", "svc2"),
+					expectMatch("/api/reports/monthly", "reports"),
+					expectMatch("/api/invoices", "billing"),
+				),
+			},
+			// Raising the priority of the catch-all route reorders the routes
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRoutePriorityConfig(10, 0),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_route.api", "id", "rt1"),
+					resourcetest.TestCheckResourceAttr("authproxy_route.api", "priority", "10"),
+					expectMatch("/api/reports/monthly", "billing"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server.URL) + testAccRoutePriorityConfig(10, 20),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_route.reports", "id", "rt2"),
+					expectMatch("/api/reports/monthly", "reports"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccRouteResource_conflict(t *testing.T) {
+	backend := &testAccRouteBackend{routes: map[string]routeResponse{
+		"rt0": {ID: "rt0", ServiceID: "svc2", ServiceName: "reports", PathPrefix: "/billing/", Methods: []string{"POST"}},
+	}}
+	server := testBackendServer(t, backend, routesPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccRouteResourceConfig(`"billing:read"`),
+				ExpectError: regexp.MustCompile(`The route "rt0" of tenant "acme" already matches POST requests`),
+			},
+		},
+	})
+}
+
+func TestAccRouteResource_validation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		pathPrefix string
+		method     string
+		err        string
+	}{
+		"relative path": {pathPrefix: "billing/", method: "GET", err: `must be an absolute path`},
+		"query":         {pathPrefix: "/billing?v=2", method: "GET", err: `must be an absolute path`},
+		"method":        {pathPrefix: "/billing/", method: "get", err: `values must be HTTP methods`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resourcetest.Test(t, resourcetest.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resourcetest.TestStep{
+					{
+						Config: testAccProviderConfig("http://127.0.0.1:1") + fmt.Sprintf(`
+resource "authproxy_route" "test" {
+  tenant      = "acme"
+  service     = "billing"
+  path_prefix = %q
+  methods     = [%q]
+}
+`, tc.pathPrefix, tc.method),
+						ExpectError: regexp.MustCompile(tc.err),
+					},
+				},
+			})
+		})
+	}
+}
+
+func testAccRouteResourceConfig(requiredScopes string) string {
+	return fmt.Sprintf(`
+resource "authproxy_route" "test" {
+  tenant          = "acme"
+  service         = "billing"
+  path_prefix     = "/billing/"
+  methods         = ["GET", "POST"]
+  required_scopes = [%s]
+  strip_prefix    = true
+}
+`, requiredScopes)
+}
+
+func testAccRoutePriorityConfig(apiPriority int, reportsPriority int) string {
+	return fmt.Sprintf(`
+resource "authproxy_route" "api" {
+  tenant      = "acme"
+  service     = "billing"
+  path_prefix = "/api/"
+  priority    = %d
+}
+
+resource "authproxy_route" "reports" {
+  tenant      = "acme"
+  service     = "svc2"
+  path_prefix = "/api/reports/"
+  priority    = %d
+
+  depends_on = [authproxy_route.api]
+}
+`, apiPriority, reportsPriority)
+}
@@ -0,0 +1,350 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SAMLIdentityProviderResource{}
+var _ resource.ResourceWithImportState = &SAMLIdentityProviderResource{}
+
+func NewSAMLIdentityProviderResource() resource.Resource {
+	return &SAMLIdentityProviderResource{}
+}
+
+// SAMLIdentityProviderResource defines the resource implementation.
+type SAMLIdentityProviderResource struct {
+	BaseResource
+}
+
+// SAMLIdentityProviderResourceModel describes the resource data model.
+type SAMLIdentityProviderResourceModel struct {
+	ID                types.String    `tfsdk:"id"`
+	Tenant            tenantNameValue `tfsdk:"tenant"`
+	Name              types.String    `tfsdk:"name"`
+	EntityID          types.String    `tfsdk:"entity_id"`
+	SSOURL            types.String    `tfsdk:"sso_url"`
+	CertificatePEM    types.String    `tfsdk:"certificate_pem"`
+	AttributeMappings types.Map       `tfsdk:"attribute_mappings"`
+	Enabled           types.Bool      `tfsdk:"enabled"`
+	SPMetadataURL     types.String    `tfsdk:"sp_metadata_url"`
+	ACSURL            types.String    `tfsdk:"acs_url"`
+}
+
+type samlIdentityProviderRequest struct {
+	Type              string            `json:"type,omitempty"`
+	Name              string            `json:"name"`
+	EntityID          string            `json:"entity_id"`
+	SSOURL            string            `json:"sso_url"`
+	Certificate       string            `json:"certificate"`
+	AttributeMappings map[string]string `json:"attribute_mappings"`
+	Enabled           bool              `json:"enabled"`
+}
+
+type samlIdentityProviderResponse struct {
+	ID                string            `json:"id"`
+	Type              string            `json:"type"`
+	Name              string            `json:"name"`
+	EntityID          string            `json:"entity_id"`
+	SSOURL            string            `json:"sso_url"`
+	Certificate       string            `json:"certificate"`
+	AttributeMappings map[string]string `json:"attribute_mappings"`
+	Enabled           bool              `json:"enabled"`
+	SPMetadataURL     string            `json:"sp_metadata_url"`
+	ACSURL            string            `json:"acs_url"`
+}
+
+func (r *SAMLIdentityProviderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_saml_identity_provider"
+}
+
+func (r *SAMLIdentityProviderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Upstream SAML 2.0 identity provider of a tenant",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the identity provider belongs to. Changing it recreates the identity provider",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the identity provider shown on the login page",
+				Required:            true,
+			},
+			"entity_id": schema.StringAttribute{
+				MarkdownDescription: "Entity ID of the identity provider, the issuer of its assertions",
+				Required:            true,
+			},
+			"sso_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the single sign-on service of the identity provider authentication requests are sent to",
+				Required:            true,
+			},
+			"certificate_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded X.509 certificate the identity provider signs its assertions with",
+				Required:            true,
+				Validators: []validator.String{
+					certificatePEM(certificateExpiryWarning),
+				},
+			},
+			"attribute_mappings": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Maps assertion attributes of the identity provider, the keys, to authproxy user attributes such as `email`",
+				Optional:            true,
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether users can log in through the identity provider. Defaults to `true`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"sp_metadata_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the service provider metadata of authproxy, to be registered with the identity provider",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"acs_url": schema.StringAttribute{
+				MarkdownDescription: "Assertion consumer service URL of authproxy the identity provider posts assertions to",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// request builds the create and update request body from data.
+func (data *SAMLIdentityProviderResourceModel) request(ctx context.Context) (samlIdentityProviderRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	idp := samlIdentityProviderRequest{
+		Name:              data.Name.ValueString(),
+		EntityID:          data.EntityID.ValueString(),
+		SSOURL:            data.SSOURL.ValueString(),
+		Certificate:       data.CertificatePEM.ValueString(),
+		AttributeMappings: map[string]string{},
+		Enabled:           data.Enabled.ValueBool(),
+	}
+	if !data.AttributeMappings.IsNull() {
+		diags.Append(data.AttributeMappings.ElementsAs(ctx, &idp.AttributeMappings, false)...)
+	}
+
+	return idp, diags
+}
+
+// setIdentityProvider copies the attributes authproxy returned for an
+// identity provider into data.
+func (data *SAMLIdentityProviderResourceModel) setIdentityProvider(ctx context.Context, idp samlIdentityProviderResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(idp.ID)
+	data.Name = types.StringValue(idp.Name)
+	data.EntityID = types.StringValue(idp.EntityID)
+	data.SSOURL = types.StringValue(idp.SSOURL)
+	data.Enabled = types.BoolValue(idp.Enabled)
+	data.SPMetadataURL = types.StringValue(idp.SPMetadataURL)
+	data.ACSURL = types.StringValue(idp.ACSURL)
+
+	// Authproxy re-encodes certificates, the configured PEM is kept as long
+	// as it holds the same certificate.
+	if !samePEMCertificate(data.CertificatePEM.ValueString(), idp.Certificate) {
+		data.CertificatePEM = types.StringValue(idp.Certificate)
+	}
+
+	// No attribute mappings stay null unless they were configured as an
+	// empty map, so leaving attribute_mappings unset does not produce a diff.
+	if len(idp.AttributeMappings) > 0 || !data.AttributeMappings.IsNull() {
+		if idp.AttributeMappings == nil {
+			idp.AttributeMappings = map[string]string{}
+		}
+		attributeMappings, d := types.MapValueFrom(ctx, types.StringType, idp.AttributeMappings)
+		diags.Append(d...)
+		data.AttributeMappings = attributeMappings
+	}
+
+	return diags
+}
+
+// samePEMCertificate reports whether both PEM documents hold the same
+// certificate.
+func samePEMCertificate(a string, b string) bool {
+	if a == b {
+		return true
+	}
+	certificateA, err := parseCertificatePEM(a)
+	if err != nil {
+		return false
+	}
+	certificateB, err := parseCertificatePEM(b)
+	if err != nil {
+		return false
+	}
+	return certificateA.Equal(certificateB)
+}
+
+func (r *SAMLIdentityProviderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SAMLIdentityProviderResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	body.Type = "saml"
+
+	var idp samlIdentityProviderResponse
+	err := r.doJSON(ctx, "POST", fmt.Sprintf("/tenants/%s/idps", url.PathEscape(data.Tenant.ValueString())), body, &idp)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create identity provider", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setIdentityProvider(ctx, idp)...)
+
+	tflog.Trace(ctx, "created a saml identity provider resource", map[string]interface{}{
+		"id": idp.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SAMLIdentityProviderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SAMLIdentityProviderResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var idp samlIdentityProviderResponse
+	err := r.doJSON(ctx, "GET", identityProviderPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, &idp)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "identity provider no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"id":     data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read identity provider", err)
+		return
+	}
+	if idp.Type != "" && idp.Type != "saml" {
+		resp.Diagnostics.AddError(
+			"Unexpected Identity Provider Type",
+			fmt.Sprintf("The identity provider %q of tenant %q is of type %q, not a SAML identity provider.", data.ID.ValueString(), data.Tenant.ValueString(), idp.Type),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setIdentityProvider(ctx, idp)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SAMLIdentityProviderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SAMLIdentityProviderResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var idp samlIdentityProviderResponse
+	err := r.doJSON(ctx, "PATCH", identityProviderPath(data.Tenant.ValueString(), data.ID.ValueString()), body, &idp)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update identity provider", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setIdentityProvider(ctx, idp)...)
+
+	tflog.Trace(ctx, "updated a saml identity provider resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SAMLIdentityProviderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SAMLIdentityProviderResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", identityProviderPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete identity provider", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a saml identity provider resource")
+}
+
+func (r *SAMLIdentityProviderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, id, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
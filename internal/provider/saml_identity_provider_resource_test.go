@@ -0,0 +1,194 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccSAMLIdentityProviderBackend is an in-memory stand-in for the
+// identity provider endpoints of the "acme" tenant. Like authproxy it
+// re-encodes certificates, dropping the trailing newline.
+type testAccSAMLIdentityProviderBackend struct {
+	mu     sync.Mutex
+	idps   map[string]samlIdentityProviderRequest
+	nextID int
+}
+
+func (b *testAccSAMLIdentityProviderBackend) respond(w http.ResponseWriter, id string) {
+	idp := b.idps[id]
+	_ = json.NewEncoder(w).Encode(samlIdentityProviderResponse{
+		ID:                id,
+		Type:              idp.Type,
+		Name:              idp.Name,
+		EntityID:          idp.EntityID,
+		SSOURL:            idp.SSOURL,
+		Certificate:       strings.TrimSpace(idp.Certificate),
+		AttributeMappings: idp.AttributeMappings,
+		Enabled:           idp.Enabled,
+		SPMetadataURL:     "https://auth.acme.io/saml/acme/" + id + "/metadata",
+		ACSURL:            "https://auth.acme.io/saml/acme/" + id + "/acs",
+	})
+}
+
+func (b *testAccSAMLIdentityProviderBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	const prefix = "/tenants/acme/idps"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		var create samlIdentityProviderRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.nextID++
+		id = fmt.Sprintf("i%d", b.nextID)
+		b.idps[id] = create
+		b.respond(w, id)
+	case id != "" && r.Method == http.MethodGet:
+		if _, ok := b.idps[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.respond(w, id)
+	case id != "" && r.Method == http.MethodPatch:
+		existing, ok := b.idps[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var update samlIdentityProviderRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		update.Type = existing.Type
+		b.idps[id] = update
+		b.respond(w, id)
+	case id != "" && r.Method == http.MethodDelete:
+		if _, ok := b.idps[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.idps, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAccSAMLIdentityProviderResource(t *testing.T) {
+	backend := &testAccSAMLIdentityProviderBackend{idps: map[string]samlIdentityProviderRequest{}}
+	server := testBackendServer(t, backend, "/tenants/acme/idps")
+
+	certificate := testCertificatePEM(t, "okta.acme.io", time.Now().Add(365*24*time.Hour))
+	rotated := testCertificatePEM(t, "okta.acme.io", time.Now().Add(2*365*24*time.Hour))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if len(backend.idps) != 0 {
+				return fmt.Errorf("expected every identity provider to be deleted, %d left", len(backend.idps))
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, the service provider URLs are
+			// populated
+			{
+				Config: testAccProviderConfig(server.URL) + testAccSAMLIdentityProviderResourceConfig(certificate, ""),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "id", "i1"),
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "certificate_pem", certificate),
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "enabled", "true"),
+					resourcetest.TestCheckNoResourceAttr("authproxy_saml_identity_provider.test", "attribute_mappings"),
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "sp_metadata_url", "https://auth.acme.io/saml/acme/i1/metadata"),
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "acs_url", "https://auth.acme.io/saml/acme/i1/acs"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if backend.idps["i1"].Type != "saml" {
+							return fmt.Errorf("expected a saml identity provider, got type %q", backend.idps["i1"].Type)
+						}
+						return nil
+					},
+				),
+			},
+			// ImportState testing, the imported certificate is authproxy's
+			// encoding of it
+			{
+				ResourceName:            "authproxy_saml_identity_provider.test",
+				ImportState:             true,
+				ImportStateId:           "acme/i1",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"certificate_pem"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccSAMLIdentityProviderResourceConfig(rotated, `
+  enabled = false
+
+  attribute_mappings = {
+    "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress" = "email"
+  }
+`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "id", "i1"),
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "certificate_pem", rotated),
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "enabled", "false"),
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "attribute_mappings.%", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_saml_identity_provider.test", "acs_url", "https://auth.acme.io/saml/acme/i1/acs"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccSAMLIdentityProviderResource_invalidCertificate(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config:      testAccProviderConfig("http://127.0.0.1:1") + testAccSAMLIdentityProviderResourceConfig("MIIC8DCCAdigAwIBAgIQ", ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`no PEM block found`),
+			},
+		},
+	})
+}
+
+func testAccSAMLIdentityProviderResourceConfig(certificate string, extra string) string {
+	return fmt.Sprintf(`
+resource "authproxy_saml_identity_provider" "test" {
+  tenant          = "acme"
+  name            = "okta"
+  entity_id       = "http://www.okta.com/exk1fcia6d6EMsf0Y357"
+  sso_url         = "https://acme.okta.com/app/acme_authproxy/exk1fcia6d6EMsf0Y357/sso/saml"
+  certificate_pem = %q
+%s}
+`, certificate, extra)
+}
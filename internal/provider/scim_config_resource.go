@@ -0,0 +1,347 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SCIMConfigResource{}
+var _ resource.ResourceWithImportState = &SCIMConfigResource{}
+var _ resource.ResourceWithModifyPlan = &SCIMConfigResource{}
+
+func NewSCIMConfigResource() resource.Resource {
+	return &SCIMConfigResource{}
+}
+
+// SCIMConfigResource defines the resource implementation. Every tenant has
+// exactly one SCIM configuration, the resource manages it rather than
+// creating one.
+type SCIMConfigResource struct {
+	BaseResource
+}
+
+// SCIMConfigResourceModel describes the resource data model.
+type SCIMConfigResourceModel struct {
+	ID                   types.String    `tfsdk:"id"`
+	Tenant               tenantNameValue `tfsdk:"tenant"`
+	Enabled              types.Bool      `tfsdk:"enabled"`
+	AllowedIPs           types.Set       `tfsdk:"allowed_ips"`
+	TokenRotationVersion types.Int64     `tfsdk:"token_rotation_version"`
+	SCIMBaseURL          types.String    `tfsdk:"scim_base_url"`
+	BearerToken          types.String    `tfsdk:"bearer_token"`
+}
+
+type scimConfigRequest struct {
+	Enabled    bool     `json:"enabled"`
+	AllowedIPs []string `json:"allowed_ips"`
+}
+
+// scimConfigResponse is the SCIM configuration of a tenant. BearerToken is
+// only set in responses that generated a new token, authproxy returns it
+// redacted otherwise.
+type scimConfigResponse struct {
+	Enabled     bool     `json:"enabled"`
+	AllowedIPs  []string `json:"allowed_ips"`
+	BaseURL     string   `json:"base_url"`
+	BearerToken string   `json:"bearer_token"`
+}
+
+// redactedSecret is what authproxy returns in place of secrets it does not
+// reveal.
+const redactedSecret = "**REDACTED**"
+
+type scimTokenResponse struct {
+	BearerToken string `json:"bearer_token"`
+}
+
+func (r *SCIMConfigResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_scim_config"
+}
+
+func (r *SCIMConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "SCIM provisioning of the users of a tenant. Every tenant has exactly one SCIM configuration, destroying the resource disables SCIM and revokes its bearer token",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the tenant",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant users are provisioned into. Changing it disables SCIM for the previous tenant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the SCIM endpoint accepts requests. Disabling it revokes the bearer token. Defaults to `true`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"allowed_ips": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "IP addresses and CIDR ranges SCIM requests are accepted from, any address when unset",
+				Optional:            true,
+				Validators: []validator.Set{
+					ipRanges(),
+				},
+			},
+			"token_rotation_version": schema.Int64Attribute{
+				MarkdownDescription: "Changing this value rotates the bearer token. It has no meaning of its own, incrementing it is the usual way of rotating",
+				Optional:            true,
+			},
+			"scim_base_url": schema.StringAttribute{
+				MarkdownDescription: "Base URL of the SCIM endpoint of the tenant, to be configured in the identity provider",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bearer_token": schema.StringAttribute{
+				MarkdownDescription: "Token the identity provider authenticates SCIM requests with. Authproxy only reveals it when it is generated, it is null for imported configurations until the token is rotated, and while SCIM is disabled",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan plans a new bearer token whenever authproxy generates one,
+// because token_rotation_version changed or SCIM is enabled again, and no
+// token while SCIM is disabled.
+func (r *SCIMConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Creation plans an unknown token already, destruction nothing at all.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state *SCIMConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case !plan.Enabled.IsUnknown() && !plan.Enabled.ValueBool():
+		plan.BearerToken = types.StringNull()
+	case plan.Enabled.IsUnknown() || !state.Enabled.ValueBool() || !plan.TokenRotationVersion.Equal(state.TokenRotationVersion):
+		plan.BearerToken = types.StringUnknown()
+	default:
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// scimConfigPath returns the path of the SCIM configuration of tenant.
+func scimConfigPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/scim", url.PathEscape(tenant))
+}
+
+// request builds the request body from data.
+func (data *SCIMConfigResourceModel) request(ctx context.Context) (scimConfigRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	config := scimConfigRequest{
+		Enabled:    data.Enabled.ValueBool(),
+		AllowedIPs: []string{},
+	}
+	if !data.AllowedIPs.IsNull() {
+		diags.Append(data.AllowedIPs.ElementsAs(ctx, &config.AllowedIPs, false)...)
+		sort.Strings(config.AllowedIPs)
+	}
+
+	return config, diags
+}
+
+// setSCIMConfig copies the settings authproxy returned into data. The bearer
+// token is left alone, it is never read back.
+func (data *SCIMConfigResourceModel) setSCIMConfig(ctx context.Context, config scimConfigResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(data.Tenant.ValueString())
+	data.Enabled = types.BoolValue(config.Enabled)
+	data.SCIMBaseURL = types.StringValue(config.BaseURL)
+
+	// No allowed IPs stay null unless they were configured as an empty set,
+	// so leaving allowed_ips unset does not produce a diff.
+	if len(config.AllowedIPs) > 0 || !data.AllowedIPs.IsNull() {
+		if config.AllowedIPs == nil {
+			config.AllowedIPs = []string{}
+		}
+		allowedIPs, d := types.SetValueFrom(ctx, types.StringType, config.AllowedIPs)
+		diags.Append(d...)
+		data.AllowedIPs = allowedIPs
+	}
+
+	return diags
+}
+
+// apply sends the configuration in data to authproxy and stores the result,
+// rotating the bearer token when data plans a new one that authproxy did
+// not generate on its own.
+func (r *SCIMConfigResource) apply(ctx context.Context, data *SCIMConfigResourceModel, diags *diag.Diagnostics) {
+	body, d := data.request(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+
+	var config scimConfigResponse
+	err := r.doJSON(ctx, "PUT", scimConfigPath(data.Tenant.ValueString()), body, &config)
+	if err != nil {
+		addClientError(diags, "set SCIM configuration", err)
+		return
+	}
+
+	diags.Append(data.setSCIMConfig(ctx, config)...)
+
+	switch {
+	case !config.Enabled:
+		data.BearerToken = types.StringNull()
+	case !data.BearerToken.IsUnknown():
+		// No new token is planned, the one in state is still valid.
+	case config.BearerToken != "" && config.BearerToken != redactedSecret:
+		data.BearerToken = types.StringValue(config.BearerToken)
+	default:
+		var token scimTokenResponse
+		err := r.doJSON(ctx, "POST", scimConfigPath(data.Tenant.ValueString())+"/rotate", nil, &token)
+		if err != nil {
+			addClientError(diags, "rotate SCIM bearer token", err)
+			return
+		}
+		data.BearerToken = types.StringValue(token.BearerToken)
+	}
+}
+
+func (r *SCIMConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SCIMConfigResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a SCIM configuration resource", map[string]interface{}{
+		"tenant": data.Tenant.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SCIMConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SCIMConfigResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config scimConfigResponse
+	err := r.doJSON(ctx, "GET", scimConfigPath(data.Tenant.ValueString()), nil, &config)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant no longer exists, removing its SCIM configuration from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read SCIM configuration", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setSCIMConfig(ctx, config)...)
+
+	// Authproxy only returns the token redacted, the stored one is kept
+	// unless SCIM was disabled and the token revoked with it.
+	if !config.Enabled {
+		data.BearerToken = types.StringNull()
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SCIMConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SCIMConfigResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "updated a SCIM configuration resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SCIMConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SCIMConfigResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting the configuration disables SCIM and revokes the token.
+	err := r.doJSON(ctx, "DELETE", scimConfigPath(data.Tenant.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "disable SCIM", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a SCIM configuration resource")
+}
+
+func (r *SCIMConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("tenant"), req, resp)
+}
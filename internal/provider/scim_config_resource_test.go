@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccSCIMBackend is an in-memory stand-in for the SCIM endpoints of the
+// "acme" tenant. Like authproxy it generates a token when SCIM is enabled
+// and only reveals it in that response, returning it redacted otherwise.
+type testAccSCIMBackend struct {
+	mu         sync.Mutex
+	enabled    bool
+	allowedIPs []string
+	token      string
+	generated  int
+}
+
+// generate replaces the token of the backend.
+func (b *testAccSCIMBackend) generate() string {
+	b.generated++
+	b.token = fmt.Sprintf("scim-token-%d", b.generated)
+	return b.token
+}
+
+func (b *testAccSCIMBackend) response(token string) scimConfigResponse {
+	config := scimConfigResponse{
+		Enabled:     b.enabled,
+		AllowedIPs:  b.allowedIPs,
+		BaseURL:     "https://auth.acme.io/scim/v2/acme",
+		BearerToken: token,
+	}
+	if token == "" && b.token != "" {
+		config.BearerToken = "**REDACTED**"
+	}
+	return config
+}
+
+func (b *testAccSCIMBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case r.URL.Path == scimConfigPath("acme") && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(b.response(""))
+	case r.URL.Path == scimConfigPath("acme") && r.Method == http.MethodPut:
+		var update scimConfigRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.enabled = update.Enabled
+		b.allowedIPs = update.AllowedIPs
+		var token string
+		if !b.enabled {
+			b.token = ""
+		} else if b.token == "" {
+			token = b.generate()
+		}
+		_ = json.NewEncoder(w).Encode(b.response(token))
+	case r.URL.Path == scimConfigPath("acme")+"/rotate" && r.Method == http.MethodPost:
+		if !b.enabled {
+			http.Error(w, "SCIM is disabled", http.StatusConflict)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(scimTokenResponse{BearerToken: b.generate()})
+	case r.URL.Path == scimConfigPath("acme") && r.Method == http.MethodDelete:
+		b.enabled = false
+		b.allowedIPs = nil
+		b.token = ""
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (b *testAccSCIMBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.enabled || b.token != "" {
+		return fmt.Errorf("expected SCIM to be disabled and its token revoked, got enabled=%t token=%q", b.enabled, b.token)
+	}
+	return nil
+}
+
+// testAccCheckSCIMToken checks the token authproxy currently accepts.
+func testAccCheckSCIMToken(backend *testAccSCIMBackend, token string) resourcetest.TestCheckFunc {
+	return func(s *terraform.State) error {
+		backend.mu.Lock()
+		defer backend.mu.Unlock()
+		if backend.token != token {
+			return fmt.Errorf("expected authproxy to accept the token %q, got %q", token, backend.token)
+		}
+		return nil
+	}
+}
+
+func TestAccSCIMConfigResource(t *testing.T) {
+	backend := &testAccSCIMBackend{}
+	server := testBackendServer(t, backend, scimConfigPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, enabling SCIM generates a token
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_scim_config" "test" {
+  tenant      = "acme"
+  allowed_ips = ["10.0.0.0/8", "203.0.113.7"]
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "id", "acme"),
+					resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "enabled", "true"),
+					resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "allowed_ips.#", "2"),
+					resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "scim_base_url", "https://auth.acme.io/scim/v2/acme"),
+					resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "bearer_token", "scim-token-1"),
+					testAccCheckSCIMToken(backend, "scim-token-1"),
+				),
+			},
+			// The token survives a refresh although authproxy redacts it
+			{
+				RefreshState: true,
+				Check:        resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "bearer_token", "scim-token-1"),
+			},
+			// ImportState testing, the token cannot be imported
+			{
+				ResourceName:            "authproxy_scim_config.test",
+				ImportState:             true,
+				ImportStateId:           "acme",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"bearer_token"},
+			},
+			// Changing token_rotation_version rotates the token
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_scim_config" "test" {
+  tenant                 = "acme"
+  allowed_ips            = ["10.0.0.0/8", "203.0.113.7"]
+  token_rotation_version = 1
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "bearer_token", "scim-token-2"),
+					testAccCheckSCIMToken(backend, "scim-token-2"),
+				),
+			},
+			// Other changes keep the token
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_scim_config" "test" {
+  tenant                 = "acme"
+  token_rotation_version = 1
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckNoResourceAttr("authproxy_scim_config.test", "allowed_ips"),
+					resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "bearer_token", "scim-token-2"),
+					testAccCheckSCIMToken(backend, "scim-token-2"),
+				),
+			},
+			// Disabling SCIM revokes the token
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_scim_config" "test" {
+  tenant                 = "acme"
+  enabled                = false
+  token_rotation_version = 1
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckNoResourceAttr("authproxy_scim_config.test", "bearer_token"),
+					testAccCheckSCIMToken(backend, ""),
+				),
+			},
+			// Enabling it again generates a new one
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_scim_config" "test" {
+  tenant                 = "acme"
+  token_rotation_version = 1
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "bearer_token", "scim-token-3"),
+					testAccCheckSCIMToken(backend, "scim-token-3"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccSCIMConfigResource_alreadyEnabled(t *testing.T) {
+	backend := &testAccSCIMBackend{enabled: true}
+	backend.generate()
+	server := testBackendServer(t, backend, scimConfigPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// The unknown token of the existing configuration is rotated
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_scim_config" "test" {
+  tenant = "acme"
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_scim_config.test", "bearer_token", "scim-token-2"),
+					testAccCheckSCIMToken(backend, "scim-token-2"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ScopeResource{}
+var _ resource.ResourceWithImportState = &ScopeResource{}
+
+func NewScopeResource() resource.Resource {
+	return &ScopeResource{}
+}
+
+// ScopeResource defines the resource implementation.
+type ScopeResource struct {
+	BaseResource
+}
+
+// ScopeResourceModel describes the resource data model.
+type ScopeResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Deprecated  types.Bool   `tfsdk:"deprecated"`
+}
+
+type scopeWriteRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description"`
+	Deprecated  bool   `json:"deprecated"`
+}
+
+// scopeConflictResponse is the body authproxy answers with when a scope that
+// is still granted by roles is deleted.
+type scopeConflictResponse struct {
+	Roles []string `json:"roles"`
+}
+
+// scopePath returns the path of the scope named name.
+func scopePath(name string) string {
+	return "/scopes/" + url.PathEscape(name)
+}
+
+func (r *ScopeResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_scope"
+}
+
+func (r *ScopeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Custom scope of the authproxy scope catalog",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the scope",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the scope such as `billing:read`. Changing it recreates the scope",
+				Required:            true,
+				Validators: []validator.String{
+					validators.Scope(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "What the scope grants access to",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"deprecated": schema.BoolAttribute{
+				MarkdownDescription: "Whether the scope is deprecated, warning everyone still requesting it. Defaults to `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+// setScope copies the attributes authproxy returned for a scope into data.
+func (data *ScopeResourceModel) setScope(scope scopeResponse) {
+	data.ID = types.StringValue(scope.Name)
+	data.Name = types.StringValue(scope.Name)
+	data.Description = types.StringValue(scope.Description)
+	data.Deprecated = types.BoolValue(scope.Deprecated)
+}
+
+func (r *ScopeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *ScopeResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var scope scopeResponse
+	err := r.doJSON(ctx, "POST", "/scopes", scopeWriteRequest{
+		Name:        data.Name.ValueString(),
+		Description: data.Description.ValueString(),
+		Deprecated:  data.Deprecated.ValueBool(),
+	}, &scope)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create scope", err)
+		return
+	}
+
+	data.setScope(scope)
+
+	tflog.Trace(ctx, "created a scope resource", map[string]interface{}{
+		"name": scope.Name,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ScopeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ScopeResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var scope scopeResponse
+	err := r.doJSON(ctx, "GET", scopePath(data.Name.ValueString()), nil, &scope)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "scope no longer exists, removing it from state", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read scope", err)
+		return
+	}
+
+	data.setScope(scope)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ScopeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *ScopeResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var scope scopeResponse
+	err := r.doJSON(ctx, "PATCH", scopePath(data.Name.ValueString()), scopeWriteRequest{
+		Description: data.Description.ValueString(),
+		Deprecated:  data.Deprecated.ValueBool(),
+	}, &scope)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update scope", err)
+		return
+	}
+
+	data.setScope(scope)
+
+	tflog.Trace(ctx, "updated a scope resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ScopeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *ScopeResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", scopePath(data.Name.ValueString()), nil, nil)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		var conflict scopeConflictResponse
+		if json.Unmarshal([]byte(apiErr.Body), &conflict) == nil && len(conflict.Roles) > 0 {
+			sort.Strings(conflict.Roles)
+			resp.Diagnostics.AddError(
+				"Scope In Use",
+				fmt.Sprintf("The scope %q cannot be deleted while it is granted by the following roles: %s. "+
+					"Remove it from these roles first.", data.Name.ValueString(), strings.Join(conflict.Roles, ", ")),
+			)
+			return
+		}
+	}
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete scope", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a scope resource")
+}
+
+func (r *ScopeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
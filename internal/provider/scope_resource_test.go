@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccScopeBackend is an in-memory stand-in for the scope catalog. Scopes
+// listed in grantedBy cannot be deleted, like scopes still granted by roles.
+type testAccScopeBackend struct {
+	mu        sync.Mutex
+	scopes    map[string]scopeResponse
+	grantedBy map[string][]string
+}
+
+func (b *testAccScopeBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, "/scopes") {
+		http.NotFound(w, r)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/scopes"), "/")
+
+	switch {
+	case name == "" && r.Method == http.MethodPost:
+		var create scopeWriteRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := b.scopes[create.Name]; ok {
+			http.Error(w, "scope exists", http.StatusConflict)
+			return
+		}
+		scope := scopeResponse{Name: create.Name, Description: create.Description, Deprecated: create.Deprecated}
+		b.scopes[scope.Name] = scope
+		_ = json.NewEncoder(w).Encode(scope)
+	case name != "" && r.Method == http.MethodGet:
+		scope, ok := b.scopes[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(scope)
+	case name != "" && r.Method == http.MethodPatch:
+		scope, ok := b.scopes[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var update scopeWriteRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		scope.Description = update.Description
+		scope.Deprecated = update.Deprecated
+		b.scopes[name] = scope
+		_ = json.NewEncoder(w).Encode(scope)
+	case name != "" && r.Method == http.MethodDelete:
+		if _, ok := b.scopes[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if roles := b.grantedBy[name]; len(roles) > 0 {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(scopeConflictResponse{Roles: roles})
+			return
+		}
+		delete(b.scopes, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAccScopeResource(t *testing.T) {
+	backend := &testAccScopeBackend{scopes: map[string]scopeResponse{}, grantedBy: map[string][]string{}}
+	server := testBackendServer(t, backend, "/scopes")
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if len(backend.scopes) != 0 {
+				return fmt.Errorf("expected every scope to be deleted, %d left", len(backend.scopes))
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccScopeResourceConfig(false),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_scope.test", "id", "billing:read"),
+					resourcetest.TestCheckResourceAttr("authproxy_scope.test", "description", "Read invoices"),
+					resourcetest.TestCheckResourceAttr("authproxy_scope.test", "deprecated", "false"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_scope.test",
+				ImportState:       true,
+				ImportStateId:     "billing:read",
+				ImportStateVerify: true,
+			},
+			// Deprecating the scope updates it in place
+			{
+				Config: testAccProviderConfig(server.URL) + testAccScopeResourceConfig(true),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_scope.test", "deprecated", "true"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if !backend.scopes["billing:read"].Deprecated {
+							return fmt.Errorf("expected the scope to be deprecated in authproxy")
+						}
+						return nil
+					},
+				),
+			},
+			// Undeprecating it again
+			{
+				Config: testAccProviderConfig(server.URL) + testAccScopeResourceConfig(false),
+				Check:  resourcetest.TestCheckResourceAttr("authproxy_scope.test", "deprecated", "false"),
+			},
+			// Deleting a scope that roles still grant is refused
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					backend.grantedBy["billing:read"] = []string{"billing-viewer", "accountant"}
+				},
+				Config:      testAccProviderConfig(server.URL) + testAccScopeResourceConfig(false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`granted by the following roles: accountant, billing-viewer`),
+			},
+			// Delete testing automatically occurs in TestCase
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					delete(backend.grantedBy, "billing:read")
+				},
+				Config: testAccProviderConfig(server.URL) + testAccScopeResourceConfig(false),
+			},
+		},
+	})
+}
+
+func TestAccScopeResource_invalidName(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config: testAccProviderConfig("http://127.0.0.1:1") + `
+resource "authproxy_scope" "test" {
+  name = "Billing Read"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+		},
+	})
+}
+
+func testAccScopeResourceConfig(deprecated bool) string {
+	return fmt.Sprintf(`
+resource "authproxy_scope" "test" {
+  name        = "billing:read"
+  description = "Read invoices"
+  deprecated  = %t
+}
+`, deprecated)
+}
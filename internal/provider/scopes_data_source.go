@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ScopesDataSource{}
+
+func NewScopesDataSource() datasource.DataSource {
+	return &ScopesDataSource{}
+}
+
+// ScopesDataSource defines the data source implementation.
+type ScopesDataSource struct {
+	BaseDataSource
+}
+
+// ScopesDataSourceModel describes the data source data model.
+type ScopesDataSourceModel struct {
+	Service  types.String       `tfsdk:"service"`
+	Names    types.List         `tfsdk:"names"`
+	Scopes   []ScopeModel       `tfsdk:"scopes"`
+	Timeouts *ReadTimeoutsModel `tfsdk:"timeouts"`
+}
+
+// ScopeModel describes a single entry of the scope catalog.
+type ScopeModel struct {
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Deprecated  types.Bool   `tfsdk:"deprecated"`
+}
+
+type scopeResponse struct {
+	Name        string `json:"name"`
+	Service     string `json:"service"`
+	Description string `json:"description"`
+	Deprecated  bool   `json:"deprecated"`
+}
+
+func (d *ScopesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_scopes"
+}
+
+func (d *ScopesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Scope catalog of the authproxy instance",
+
+		Attributes: map[string]schema.Attribute{
+			"service": schema.StringAttribute{
+				MarkdownDescription: "Only return scopes belonging to this service",
+				Optional:            true,
+			},
+			"names": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scope names that must exist in the catalog. Unknown names are an error, deprecated ones produce a warning and only the named scopes are returned",
+				Optional:            true,
+			},
+			"scopes": schema.ListNestedAttribute{
+				MarkdownDescription: "Scopes sorted by name",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the scope",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Description of the scope",
+							Computed:            true,
+						},
+						"deprecated": schema.BoolAttribute{
+							MarkdownDescription: "Whether the scope is deprecated",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *ScopesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ScopesDataSourceModel
+	var names []string
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if !data.Names.IsNull() {
+		resp.Diagnostics.Append(data.Names.ElementsAs(ctx, &names, false)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_scopes", data.Timeouts)
+	defer cancel()
+
+	// Servers that support it filter by service themselves, the client-side
+	// filter below covers the ones that ignore the parameter.
+	query := url.Values{}
+	service := data.Service.ValueString()
+	if service != "" {
+		query.Set("service", service)
+	}
+
+	scopes, err := listAll[scopeResponse](ctx, d.providerData, "/scopes", query)
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "list scopes", err)
+		return
+	}
+
+	byName := make(map[string]scopeResponse, len(scopes))
+	for _, scope := range scopes {
+		if service != "" && scope.Service != service && !strings.HasPrefix(scope.Name, service+":") {
+			continue
+		}
+		byName[scope.Name] = scope
+	}
+
+	if names != nil {
+		var unknown []string
+		selected := make(map[string]scopeResponse, len(names))
+		for _, name := range names {
+			scope, ok := byName[name]
+			if !ok {
+				unknown = append(unknown, name)
+				continue
+			}
+			if scope.Deprecated {
+				resp.Diagnostics.AddAttributeWarning(
+					path.Root("names"),
+					"Deprecated Scope",
+					fmt.Sprintf("The scope %q is deprecated and may be removed from authproxy in a future release.", name),
+				)
+			}
+			selected[name] = scope
+		}
+		if len(unknown) > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("names"),
+				"Unknown Scopes",
+				fmt.Sprintf("The following scopes do not exist in the authproxy scope catalog: %s", strings.Join(unknown, ", ")),
+			)
+			return
+		}
+		byName = selected
+	}
+
+	data.Scopes = make([]ScopeModel, 0, len(byName))
+	for _, scope := range byName {
+		data.Scopes = append(data.Scopes, ScopeModel{
+			Name:        types.StringValue(scope.Name),
+			Description: types.StringValue(scope.Description),
+			Deprecated:  types.BoolValue(scope.Deprecated),
+		})
+	}
+	sort.Slice(data.Scopes, func(i, j int) bool {
+		return data.Scopes[i].Name.ValueString() < data.Scopes[j].Name.ValueString()
+	})
+
+	tflog.Trace(ctx, "read scopes data source", map[string]interface{}{
+		"count": len(data.Scopes),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
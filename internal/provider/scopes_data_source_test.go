@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccScopesServer serves a two page scope catalog and ignores the service
+// query parameter like older authproxy releases do.
+func testAccScopesServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(testserver.ValidateRequests(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"items":[
+				{"name":"users:write","description":"Manage users"},
+				{"name":"billing:write","description":"Manage invoices"}
+			],"next_cursor":"page-2"}`)
+		case "page-2":
+			fmt.Fprint(w, `{"items":[
+				{"name":"billing:read","description":"Read invoices"},
+				{"name":"billing:legacy","description":"Old billing scope","deprecated":true}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})))
+}
+
+func TestAccScopesDataSource(t *testing.T) {
+	server := testAccScopesServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_scopes" "test" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_scopes.test", "scopes.#", "4"),
+					resource.TestCheckResourceAttr("data.authproxy_scopes.test", "scopes.0.name", "billing:legacy"),
+					resource.TestCheckResourceAttr("data.authproxy_scopes.test", "scopes.0.deprecated", "true"),
+					resource.TestCheckResourceAttr("data.authproxy_scopes.test", "scopes.3.name", "users:write"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccScopesDataSource_service(t *testing.T) {
+	server := testAccScopesServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_scopes" "test" {
+  service = "billing"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_scopes.test", "scopes.#", "3"),
+					resource.TestCheckResourceAttr("data.authproxy_scopes.test", "scopes.0.name", "billing:legacy"),
+					resource.TestCheckResourceAttr("data.authproxy_scopes.test", "scopes.1.name", "billing:read"),
+					resource.TestCheckResourceAttr("data.authproxy_scopes.test", "scopes.2.name", "billing:write"),
+				),
+			},
+		},
+	})
+}
+
+func TestScopesDataSource_deprecatedWarning(t *testing.T) {
+	server := testAccScopesServer(t)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewScopesDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"names": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "billing:read"),
+			tftypes.NewValue(tftypes.String, "billing:legacy"),
+		}),
+	})
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+	if warnings := resp.Diagnostics.Warnings(); len(warnings) != 1 || warnings[0].Summary() != "Deprecated Scope" {
+		t.Fatalf("expected a single deprecated scope warning, got: %v", warnings)
+	}
+}
+
+func TestScopesDataSource_unknownScope(t *testing.T) {
+	server := testAccScopesServer(t)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewScopesDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"names": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "billing:delete"),
+		}),
+	})
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an unknown scope")
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// NewProtocol6 returns a function creating protocol version 6 servers of the
+// provider, see providerServer.
+func NewProtocol6(version string) func() tfprotov6.ProviderServer {
+	return func() tfprotov6.ProviderServer {
+		p := New(version)().(*AuthProxy)
+		return &providerServer{
+			ProviderServer: providerserver.NewProtocol6(p)(),
+			stats:          p.stats,
+		}
+	}
+}
+
+// providerServer wraps the protocol version 6 server of the framework. It
+// labels the context of every data source and resource operation with the
+// operation, see withOperation. Once the operation is done it logs the API
+// call counters of the provider at DEBUG level and adds the warnings its
+// requests collected to the response, see checkSlowRequest and
+// checkServerVersion. Panics of operations are recovered from, see
+// panicDiagnostic.
+type providerServer struct {
+	tfprotov6.ProviderServer
+	stats *apiStats
+}
+
+// operationKey carries the label of the Terraform operation the requests
+// made under a context belong to.
+type operationKey struct{}
+
+// withOperation returns a context labeled with operation, such as
+// "authproxy_role.update" or "data.authproxy_tenant.read".
+func withOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// warningsKey carries the *operationWarnings of the operation a context
+// belongs to.
+type warningsKey struct{}
+
+// operationWarnings collects warnings about the requests of an operation,
+// such as the ones taking longer than the slow_request_threshold of the
+// provider.
+type operationWarnings struct {
+	mu       sync.Mutex
+	warnings []*tfprotov6.Diagnostic
+}
+
+// addWarning adds a warning to the operation ctx belongs to and reports
+// whether ctx belongs to one.
+func addWarning(ctx context.Context, summary string, detail string) bool {
+	w, ok := ctx.Value(warningsKey{}).(*operationWarnings)
+	if !ok {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.warnings = append(w.warnings, &tfprotov6.Diagnostic{
+		Severity: tfprotov6.DiagnosticSeverityWarning,
+		Summary:  summary,
+		Detail:   detail,
+	})
+	return true
+}
+
+// doneKey carries the *operationHooks of the operation a context belongs to.
+type doneKey struct{}
+
+// operationHooks collects the functions to call once an operation is done.
+type operationHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+// onOperationDone arranges for fn to be called once the operation ctx belongs
+// to is done and reports whether ctx belongs to one.
+func onOperationDone(ctx context.Context, fn func()) bool {
+	h, ok := ctx.Value(doneKey{}).(*operationHooks)
+	if !ok {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = append(h.hooks, fn)
+	return true
+}
+
+// begin labels ctx with operation and returns the function to call when it
+// is done, which calls the functions registered with onOperationDone, logs
+// the API call counters and returns the warnings about the requests of the
+// operation.
+func (s *providerServer) begin(ctx context.Context, operation string) (context.Context, func() []*tfprotov6.Diagnostic) {
+	w := &operationWarnings{}
+	h := &operationHooks{}
+	operationCtx := context.WithValue(withOperation(ctx, operation), warningsKey{}, w)
+	operationCtx = context.WithValue(operationCtx, doneKey{}, h)
+	return operationCtx, func() []*tfprotov6.Diagnostic {
+		h.mu.Lock()
+		hooks := h.hooks
+		h.hooks = nil
+		h.mu.Unlock()
+		for _, hook := range hooks {
+			hook()
+		}
+
+		fields := s.stats.fields()
+		fields["operation"] = operation
+		tflog.Debug(ctx, "authproxy API calls", fields)
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.warnings
+	}
+}
+
+// panicDiagnostic converts the value recovered from a panic of operation into
+// an error diagnostic, logging the stack trace at ERROR level. Without it a
+// panic would crash the provider and Terraform would only report that.
+func panicDiagnostic(ctx context.Context, operation string, recovered interface{}) *tfprotov6.Diagnostic {
+	tflog.Error(ctx, "recovered from a panic", map[string]interface{}{
+		"operation": operation,
+		"panic":     fmt.Sprint(recovered),
+		"stack":     string(debug.Stack()),
+	})
+	return &tfprotov6.Diagnostic{
+		Severity: tfprotov6.DiagnosticSeverityError,
+		Summary:  "Provider Panic",
+		Detail: fmt.Sprintf("The provider panicked during %s: %v\n\n"+
+			"The state was left unchanged. This is a bug in the provider, please report it along with the Terraform logs holding the stack trace.", operation, recovered),
+	}
+}
+
+func (s *providerServer) ValidateResourceConfig(ctx context.Context, req *tfprotov6.ValidateResourceConfigRequest) (resp *tfprotov6.ValidateResourceConfigResponse, err error) {
+	operation := req.TypeName + ".validate"
+	ctx, done := s.begin(ctx, operation)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			resp = &tfprotov6.ValidateResourceConfigResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{panicDiagnostic(ctx, operation, recovered)},
+			}
+		}
+		if warnings := done(); resp != nil {
+			resp.Diagnostics = append(resp.Diagnostics, warnings...)
+		}
+	}()
+	return s.ProviderServer.ValidateResourceConfig(ctx, req)
+}
+
+func (s *providerServer) UpgradeResourceState(ctx context.Context, req *tfprotov6.UpgradeResourceStateRequest) (resp *tfprotov6.UpgradeResourceStateResponse, err error) {
+	operation := req.TypeName + ".upgrade"
+	ctx, done := s.begin(ctx, operation)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			resp = &tfprotov6.UpgradeResourceStateResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{panicDiagnostic(ctx, operation, recovered)},
+			}
+		}
+		if warnings := done(); resp != nil {
+			resp.Diagnostics = append(resp.Diagnostics, warnings...)
+		}
+	}()
+	return s.ProviderServer.UpgradeResourceState(ctx, req)
+}
+
+func (s *providerServer) ReadResource(ctx context.Context, req *tfprotov6.ReadResourceRequest) (resp *tfprotov6.ReadResourceResponse, err error) {
+	operation := req.TypeName + ".read"
+	ctx, done := s.begin(ctx, operation)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			resp = &tfprotov6.ReadResourceResponse{
+				NewState:    req.CurrentState,
+				Diagnostics: []*tfprotov6.Diagnostic{panicDiagnostic(ctx, operation, recovered)},
+				Private:     req.Private,
+			}
+		}
+		if warnings := done(); resp != nil {
+			resp.Diagnostics = append(resp.Diagnostics, warnings...)
+		}
+	}()
+	return s.ProviderServer.ReadResource(ctx, req)
+}
+
+func (s *providerServer) PlanResourceChange(ctx context.Context, req *tfprotov6.PlanResourceChangeRequest) (resp *tfprotov6.PlanResourceChangeResponse, err error) {
+	operation := req.TypeName + ".plan"
+	ctx, done := s.begin(ctx, operation)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			resp = &tfprotov6.PlanResourceChangeResponse{
+				PlannedState: req.PriorState,
+				Diagnostics:  []*tfprotov6.Diagnostic{panicDiagnostic(ctx, operation, recovered)},
+			}
+		}
+		if warnings := done(); resp != nil {
+			resp.Diagnostics = append(resp.Diagnostics, warnings...)
+		}
+	}()
+	return s.ProviderServer.PlanResourceChange(ctx, req)
+}
+
+func (s *providerServer) ApplyResourceChange(ctx context.Context, req *tfprotov6.ApplyResourceChangeRequest) (resp *tfprotov6.ApplyResourceChangeResponse, err error) {
+	operation := req.TypeName + ".update"
+	switch {
+	case isNullDynamicValue(req.PriorState):
+		operation = req.TypeName + ".create"
+	case isNullDynamicValue(req.PlannedState):
+		operation = req.TypeName + ".delete"
+	}
+	ctx, done := s.begin(ctx, operation)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			resp = &tfprotov6.ApplyResourceChangeResponse{
+				NewState:    req.PriorState,
+				Diagnostics: []*tfprotov6.Diagnostic{panicDiagnostic(ctx, operation, recovered)},
+				Private:     req.PlannedPrivate,
+			}
+		}
+		if warnings := done(); resp != nil {
+			resp.Diagnostics = append(resp.Diagnostics, warnings...)
+		}
+	}()
+	return s.ProviderServer.ApplyResourceChange(ctx, req)
+}
+
+func (s *providerServer) ImportResourceState(ctx context.Context, req *tfprotov6.ImportResourceStateRequest) (resp *tfprotov6.ImportResourceStateResponse, err error) {
+	operation := req.TypeName + ".import"
+	ctx, done := s.begin(ctx, operation)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			resp = &tfprotov6.ImportResourceStateResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{panicDiagnostic(ctx, operation, recovered)},
+			}
+		}
+		if warnings := done(); resp != nil {
+			resp.Diagnostics = append(resp.Diagnostics, warnings...)
+		}
+	}()
+	return s.ProviderServer.ImportResourceState(ctx, req)
+}
+
+func (s *providerServer) ReadDataSource(ctx context.Context, req *tfprotov6.ReadDataSourceRequest) (resp *tfprotov6.ReadDataSourceResponse, err error) {
+	operation := "data." + req.TypeName + ".read"
+	ctx, done := s.begin(ctx, operation)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			resp = &tfprotov6.ReadDataSourceResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{panicDiagnostic(ctx, operation, recovered)},
+			}
+		}
+		if warnings := done(); resp != nil {
+			resp.Diagnostics = append(resp.Diagnostics, warnings...)
+		}
+	}()
+	return s.ProviderServer.ReadDataSource(ctx, req)
+}
+
+// isNullDynamicValue reports whether v encodes a null value, such as the
+// prior state of a resource being created.
+func isNullDynamicValue(v *tfprotov6.DynamicValue) bool {
+	if v == nil {
+		return true
+	}
+	if len(v.MsgPack) != 0 {
+		// msgpack encodes null as nil.
+		return bytes.Equal(v.MsgPack, []byte{0xc0})
+	}
+	return len(v.JSON) == 0 || string(v.JSON) == "null"
+}
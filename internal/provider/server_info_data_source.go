@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ServerInfoDataSource{}
+
+func NewServerInfoDataSource() datasource.DataSource {
+	return &ServerInfoDataSource{}
+}
+
+// ServerInfoDataSource defines the data source implementation.
+type ServerInfoDataSource struct {
+	BaseDataSource
+}
+
+// ServerInfoDataSourceModel describes the data source data model.
+type ServerInfoDataSourceModel struct {
+	Version     types.String `tfsdk:"version"`
+	APIVersions types.List   `tfsdk:"api_versions"`
+	Features    types.Map    `tfsdk:"features"`
+	Healthy     types.Bool   `tfsdk:"healthy"`
+}
+
+type serverInfoResponse struct {
+	Status      string          `json:"status"`
+	Version     *string         `json:"version"`
+	APIVersions []string        `json:"api_versions"`
+	Features    map[string]bool `json:"features"`
+}
+
+func (d *ServerInfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_info"
+}
+
+func (d *ServerInfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Version, supported API versions and feature flags of the authproxy instance",
+
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Authproxy server version, null for servers that do not report it",
+				Computed:            true,
+			},
+			"api_versions": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "API versions served by the instance, null for servers that do not report them",
+				Computed:            true,
+			},
+			"features": schema.MapAttribute{
+				ElementType:         types.BoolType,
+				MarkdownDescription: "Feature flags and whether they are enabled, null for servers that do not report them",
+				Computed:            true,
+			},
+			"healthy": schema.BoolAttribute{
+				MarkdownDescription: "Whether the health endpoint reported the instance as healthy",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ServerInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ServerInfoDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+
+	healthy := true
+	resBody, err := d.providerData.do(ctx, "GET", "/health", nil)
+	if err != nil {
+		// A failing health check still answers with a body worth decoding,
+		// anything else (including being unreachable) is an error.
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode < 500 {
+			addClientError(&resp.Diagnostics, "read server info", err)
+			return
+		}
+		healthy = false
+	}
+
+	data.Version = types.StringNull()
+	if version, ok := d.providerData.detectedServerVersion(); ok {
+		data.Version = types.StringValue(version)
+	}
+	data.APIVersions = types.ListNull(types.StringType)
+	data.Features = types.MapNull(types.BoolType)
+
+	var info serverInfoResponse
+	if err := json.Unmarshal(resBody, &info); err != nil {
+		// Legacy servers answer with a plain-text "ok" and nothing else.
+		tflog.Debug(ctx, "health endpoint did not return JSON, assuming legacy server", map[string]interface{}{
+			"body": string(resBody),
+		})
+		data.Healthy = types.BoolValue(healthy && strings.EqualFold(strings.TrimSpace(string(resBody)), "ok"))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	if info.Status != "" && !strings.EqualFold(info.Status, "ok") {
+		healthy = false
+	}
+	data.Healthy = types.BoolValue(healthy)
+	if info.Version != nil {
+		data.Version = types.StringValue(*info.Version)
+	}
+	if info.APIVersions != nil {
+		apiVersions, diags := types.ListValueFrom(ctx, types.StringType, info.APIVersions)
+		resp.Diagnostics.Append(diags...)
+		data.APIVersions = apiVersions
+	}
+	if info.Features != nil {
+		features, diags := types.MapValueFrom(ctx, types.BoolType, info.Features)
+		resp.Diagnostics.Append(diags...)
+		data.Features = features
+	}
+
+	tflog.Trace(ctx, "read server info data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
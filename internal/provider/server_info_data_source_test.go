@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccServerInfoDataSource(t *testing.T) {
+	server := httptest.NewServer(testserver.ValidateRequests(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"ok","version":"2.3.1","api_versions":["v1","v2"],"features":{"scim":true,"saml":false}}`)
+	})))
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccServerInfoDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_server_info.test", "version", "2.3.1"),
+					resource.TestCheckResourceAttr("data.authproxy_server_info.test", "api_versions.#", "2"),
+					resource.TestCheckResourceAttr("data.authproxy_server_info.test", "api_versions.1", "v2"),
+					resource.TestCheckResourceAttr("data.authproxy_server_info.test", "features.scim", "true"),
+					resource.TestCheckResourceAttr("data.authproxy_server_info.test", "features.saml", "false"),
+					resource.TestCheckResourceAttr("data.authproxy_server_info.test", "healthy", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccServerInfoDataSource_legacy(t *testing.T) {
+	server := httptest.NewServer(testserver.ValidateRequests(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok\n")
+	})))
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccServerInfoDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_server_info.test", "healthy", "true"),
+					resource.TestCheckNoResourceAttr("data.authproxy_server_info.test", "version"),
+					resource.TestCheckNoResourceAttr("data.authproxy_server_info.test", "api_versions.#"),
+					resource.TestCheckNoResourceAttr("data.authproxy_server_info.test", "features.%"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccServerInfoDataSource_unreachable(t *testing.T) {
+	server := httptest.NewServer(testserver.ValidateRequests(t, http.NotFoundHandler()))
+	endpoint := server.URL
+	server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(endpoint) + testAccServerInfoDataSourceConfig,
+				ExpectError: regexp.MustCompile(`Unable to read server info`),
+			},
+		},
+	})
+}
+
+const testAccServerInfoDataSourceConfig = `
+data "authproxy_server_info" "test" {}
+`
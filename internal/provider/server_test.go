@@ -0,0 +1,331 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestProviderServer_operationHeaders(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+
+	for name, tc := range map[string]struct {
+		sendOperationHeaders tftypes.Value
+		version              string
+	}{
+		"default":  {sendOperationHeaders: tftypes.NewValue(tftypes.Bool, nil), version: "test"},
+		"enabled":  {sendOperationHeaders: tftypes.NewValue(tftypes.Bool, true), version: "test"},
+		"disabled": {sendOperationHeaders: tftypes.NewValue(tftypes.Bool, false)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := testserver.New(t)
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint":               str(backend.URL),
+				"username":               str(testserver.Username),
+				"password":               str(testserver.Password),
+				"send_operation_headers": tc.sendOperationHeaders,
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			// expectOperation checks the requests since the previous check
+			// were labeled with operation.
+			var seen int
+			expectOperation := func(operation string) {
+				t.Helper()
+				requests := backend.Requests()[seen:]
+				seen += len(requests)
+				if len(requests) == 0 {
+					t.Fatalf("expected %s to make requests", operation)
+				}
+				if tc.version == "" {
+					operation = ""
+				}
+				for _, request := range requests {
+					if got := request.Header.Get("X-Terraform-Operation"); got != operation {
+						t.Errorf("expected %s to be labeled %q, got %q", request, operation, got)
+					}
+					if got := request.Header.Get("X-Terraform-Provider-Version"); got != tc.version {
+						t.Errorf("expected %s to carry the provider version %q, got %q", request, tc.version, got)
+					}
+				}
+			}
+
+			state := testProviderApply(t, server, "authproxy_tenant", nil, map[string]tftypes.Value{"name": str("acme")})
+			expectOperation("authproxy_tenant.create")
+
+			state = testProviderRefresh(t, server, "authproxy_tenant", state)
+			expectOperation("authproxy_tenant.read")
+
+			state = testProviderApply(t, server, "authproxy_tenant", state, map[string]tftypes.Value{"name": str("acme-eu")})
+			expectOperation("authproxy_tenant.update")
+
+			backend.CreateTenant("acme")
+			if diags := testProviderReadTenant(t, server); len(diags) != 0 {
+				t.Fatalf("unexpected read diagnostics: %v", diags)
+			}
+			expectOperation("data.authproxy_tenant.read")
+
+			testProviderApply(t, server, "authproxy_tenant", state, nil)
+			expectOperation("authproxy_tenant.delete")
+		})
+	}
+}
+
+func TestProviderServer_logsAPICalls(t *testing.T) {
+	server := testserver.New(t)
+	tenant := server.CreateTenant("acme")
+
+	provider, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": tftypes.NewValue(tftypes.String, server.URL),
+		"username": tftypes.NewValue(tftypes.String, testserver.Username),
+		"password": tftypes.NewValue(tftypes.String, testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	for i := 0; i < 2; i++ {
+		resp, err := provider.ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+			TypeName: "authproxy_tenant",
+			CurrentState: testResourceValue(t, provider, "authproxy_tenant", map[string]tftypes.Value{
+				"id":   tftypes.NewValue(tftypes.String, tenant.ID),
+				"name": tftypes.NewValue(tftypes.String, "acme"),
+			}),
+		})
+		if err != nil || len(resp.Diagnostics) != 0 {
+			t.Fatalf("unexpected read failure: %v %v", err, resp.Diagnostics)
+		}
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summaries []map[string]interface{}
+	for _, entry := range entries {
+		if entry["@message"] == "authproxy API calls" {
+			summaries = append(summaries, entry)
+		}
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expected a summary after each read, got %v", entries)
+	}
+	for i, summary := range summaries {
+		if summary["@level"] != "debug" || summary["operation"] != "authproxy_tenant.read" || summary["api_calls"] != float64(i+1) {
+			t.Errorf("unexpected summary %d: %v", i, summary)
+		}
+	}
+}
+
+func TestProviderServer_slowRequestWarnings(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"id": "t1", "name": "acme"}`))
+	}))
+	defer backend.Close()
+
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	for name, tc := range map[string]struct {
+		threshold tftypes.Value
+		warnings  int
+	}{
+		"above the threshold": {threshold: str("50ms"), warnings: 1},
+		"below the threshold": {threshold: str("1s")},
+		"below the default":   {threshold: tftypes.NewValue(tftypes.String, nil)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint":               str(backend.URL),
+				"username":               str(testserver.Username),
+				"password":               str(testserver.Password),
+				"slow_request_threshold": tc.threshold,
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			resp, err := server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+				TypeName:     "authproxy_tenant",
+				CurrentState: testResourceValue(t, server, "authproxy_tenant", map[string]tftypes.Value{"id": str("t1"), "name": str("acme")}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Diagnostics) != tc.warnings {
+				t.Fatalf("expected %d warnings, got %v", tc.warnings, resp.Diagnostics)
+			}
+			for _, diagnostic := range resp.Diagnostics {
+				if diagnostic.Severity != tfprotov6.DiagnosticSeverityWarning || diagnostic.Summary != "Slow Authproxy Request" ||
+					!strings.HasPrefix(diagnostic.Detail, "GET /tenants/acme took ") || !strings.Contains(diagnostic.Detail, "slow_request_threshold of 50ms") {
+					t.Errorf("unexpected warning %v", diagnostic)
+				}
+			}
+			if resp.NewState == nil || isNullDynamicValue(resp.NewState) {
+				t.Error("expected the read to succeed")
+			}
+		})
+	}
+}
+
+// testPanicProvider serves the authproxy_panic resource, whose every
+// operation panics.
+type testPanicProvider struct{}
+
+func (p *testPanicProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "authproxy"
+}
+
+func (p *testPanicProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+}
+
+func (p *testPanicProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+}
+
+func (p *testPanicProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{func() resource.Resource { return &testPanicResource{} }}
+}
+
+func (p *testPanicProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+type testPanicResource struct {
+	BaseResource
+}
+
+func (r *testPanicResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_panic"
+}
+
+func (r *testPanicResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{Optional: true},
+		},
+	}
+}
+
+func (r *testPanicResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	panic("validate")
+}
+
+func (r *testPanicResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			panic("upgrade")
+		}},
+	}
+}
+
+func (r *testPanicResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// The resource is not configured, so this dereferences nil.
+	_ = r.providerData.endpoint
+}
+
+func (r *testPanicResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var ids []string
+	resp.State.RemoveResource(ctx)
+	_ = ids[len(ids)]
+}
+
+func (r *testPanicResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	panic("update")
+}
+
+func (r *testPanicResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	panic("delete")
+}
+
+func TestProviderServer_recoversPanics(t *testing.T) {
+	server := &providerServer{ProviderServer: providerserver.NewProtocol6(&testPanicProvider{})(), stats: &apiStats{}}
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	state := map[string]tftypes.Value{"id": str("p1")}
+
+	// expectPanic checks diagnostics hold the error of the recovered panic.
+	expectPanic := func(diagnostics []*tfprotov6.Diagnostic, operation string, value string) {
+		t.Helper()
+		if len(diagnostics) != 1 || diagnostics[0].Severity != tfprotov6.DiagnosticSeverityError || diagnostics[0].Summary != "Provider Panic" ||
+			!strings.HasPrefix(diagnostics[0].Detail, "The provider panicked during "+operation+": "+value) {
+			t.Errorf("expected the panic of %s to be reported, got %v", operation, diagnostics)
+		}
+	}
+
+	createResp := testProviderApplyResponse(t, server, "authproxy_panic", nil, state)
+	expectPanic(createResp.Diagnostics, "authproxy_panic.create", "runtime error: invalid memory address or nil pointer dereference")
+	if !isNullDynamicValue(createResp.NewState) {
+		t.Errorf("expected nothing to be stored, got %v", createResp.NewState)
+	}
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	current := testResourceValue(t, server, "authproxy_panic", state)
+	readResp, err := server.ReadResource(ctx, &tfprotov6.ReadResourceRequest{TypeName: "authproxy_panic", CurrentState: current})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectPanic(readResp.Diagnostics, "authproxy_panic.read", "runtime error: index out of range")
+	if readResp.NewState != current {
+		t.Errorf("expected the state to be left untouched, got %v", readResp.NewState)
+	}
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logged bool
+	for _, entry := range entries {
+		if entry["@level"] == "error" && entry["@message"] == "recovered from a panic" {
+			stack, _ := entry["stack"].(string)
+			logged = strings.Contains(stack, "(*testPanicResource).Read")
+		}
+	}
+	if !logged {
+		t.Errorf("expected the stack trace to be logged, got %v", entries)
+	}
+
+	updateResp := testProviderApplyResponse(t, server, "authproxy_panic", state, map[string]tftypes.Value{"id": str("p2")})
+	expectPanic(updateResp.Diagnostics, "authproxy_panic.update", "update")
+	if values := testResourceValues(t, server, "authproxy_panic", updateResp.NewState); !values["id"].Equal(str("p1")) {
+		t.Errorf("expected the state to be left untouched, got %v", values)
+	}
+
+	deleteResp := testProviderApplyResponse(t, server, "authproxy_panic", state, nil)
+	expectPanic(deleteResp.Diagnostics, "authproxy_panic.delete", "delete")
+
+	validateResp, err := server.ValidateResourceConfig(context.Background(), &tfprotov6.ValidateResourceConfigRequest{TypeName: "authproxy_panic", Config: current})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectPanic(validateResp.Diagnostics, "authproxy_panic.validate", "validate")
+
+	upgradeResp, err := server.UpgradeResourceState(context.Background(), &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "authproxy_panic",
+		Version:  0,
+		RawState: &tfprotov6.RawState{JSON: []byte(`{"id":"p1"}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectPanic(upgradeResp.Diagnostics, "authproxy_panic.upgrade", "upgrade")
+}
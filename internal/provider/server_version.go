@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// serverVersionHeader is the response header authproxy reports its version
+// in.
+const serverVersionHeader = "X-Authproxy-Version"
+
+// minTestedServerVersion and maxTestedServerVersion bound the authproxy
+// releases the provider is tested against. Patch releases of the newest
+// tested minor release are considered tested as well.
+const (
+	minTestedServerVersion = "1.2.0"
+	maxTestedServerVersion = "2.4"
+)
+
+// serverVersion is the authproxy version detected by the first successful
+// API call of a provider instance.
+type serverVersion struct {
+	mu       sync.Mutex
+	detected bool
+	version  string
+	// warned is set once the version has been warned about, Terraform
+	// starts a provider instance for every plan or apply.
+	warned bool
+}
+
+// checkServerVersion records header, the serverVersionHeader of a successful
+// response, as the server version unless one was detected already, and
+// warns about it in the operation ctx belongs to when it is outside of the
+// tested range.
+func (p *ProviderData) checkServerVersion(ctx context.Context, header string) {
+	p.serverVersion.mu.Lock()
+	defer p.serverVersion.mu.Unlock()
+
+	if !p.serverVersion.detected {
+		if header == "" {
+			return
+		}
+		p.serverVersion.detected = true
+		p.serverVersion.version = header
+		tflog.Debug(ctx, "detected authproxy version", map[string]interface{}{
+			"version": header,
+		})
+	}
+	if p.serverVersion.warned {
+		return
+	}
+
+	problem, err := serverVersionProblem(p.serverVersion.version)
+	if err != nil {
+		tflog.Warn(ctx, "could not parse the authproxy version", map[string]interface{}{
+			"version": p.serverVersion.version,
+			"error":   err.Error(),
+		})
+		p.serverVersion.warned = true
+		return
+	}
+	if problem == "" {
+		p.serverVersion.warned = true
+		return
+	}
+	if addWarning(ctx, "Untested Authproxy Version", fmt.Sprintf("Authproxy %s is %s than the versions this provider is tested against, %s to %s. "+
+		"Some resources may not work as expected.", p.serverVersion.version, problem, minTestedServerVersion, maxTestedServerVersion)) {
+		p.serverVersion.warned = true
+	}
+}
+
+// detectedServerVersion returns the server version detected by
+// checkServerVersion, or false if none was.
+func (p *ProviderData) detectedServerVersion() (string, bool) {
+	p.serverVersion.mu.Lock()
+	defer p.serverVersion.mu.Unlock()
+
+	return p.serverVersion.version, p.serverVersion.detected
+}
+
+// serverVersionProblem returns "older" or "newer" when version is outside of
+// the tested range, or an empty string when it is within.
+func serverVersionProblem(version string) (string, error) {
+	v, err := goversion.NewVersion(version)
+	if err != nil {
+		return "", err
+	}
+	// Pre-releases of a tested release count as tested.
+	v = v.Core()
+
+	if v.LessThan(goversion.Must(goversion.NewVersion(minTestedServerVersion))) {
+		return "older", nil
+	}
+	max := goversion.Must(goversion.NewVersion(maxTestedServerVersion))
+	if v.Segments()[0] > max.Segments()[0] || v.Segments()[0] == max.Segments()[0] && v.Segments()[1] > max.Segments()[1] {
+		return "newer", nil
+	}
+	return "", nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestProviderServer_serverVersionWarnings(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	for name, tc := range map[string]struct {
+		header  string
+		problem string
+	}{
+		"in range":              {header: "2.1.0"},
+		"oldest tested":         {header: "1.2.0"},
+		"patch of newest":       {header: "2.4.7"},
+		"pre-release in range":  {header: "2.4.0-rc.1"},
+		"too old":               {header: "1.1.9", problem: "older"},
+		"too new":               {header: "2.5.0", problem: "newer"},
+		"next major":            {header: "3.0.0", problem: "newer"},
+		"no version reported":   {},
+		"unparseable version":   {header: "nightly"},
+		"version with v prefix": {header: "v2.0.1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.header != "" {
+					w.Header().Set(serverVersionHeader, tc.header)
+				}
+				_, _ = w.Write([]byte(`{"id": "t1", "name": "acme"}`))
+			}))
+			defer backend.Close()
+
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint": str(backend.URL),
+				"username": str(testserver.Username),
+				"password": str(testserver.Password),
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			// Only the first operation warns, once per provider instance.
+			for i := 0; i < 2; i++ {
+				resp, err := server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+					TypeName:     "authproxy_tenant",
+					CurrentState: testResourceValue(t, server, "authproxy_tenant", map[string]tftypes.Value{"id": str("t1"), "name": str("acme")}),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				warnings := 0
+				if tc.problem != "" && i == 0 {
+					warnings = 1
+				}
+				if len(resp.Diagnostics) != warnings {
+					t.Fatalf("expected %d warnings on read %d, got %v", warnings, i+1, resp.Diagnostics)
+				}
+				for _, diagnostic := range resp.Diagnostics {
+					if diagnostic.Severity != tfprotov6.DiagnosticSeverityWarning || diagnostic.Summary != "Untested Authproxy Version" ||
+						!strings.HasPrefix(diagnostic.Detail, "Authproxy "+tc.header+" is "+tc.problem+" than ") ||
+						!strings.Contains(diagnostic.Detail, minTestedServerVersion+" to "+maxTestedServerVersion) {
+						t.Errorf("unexpected warning %v", diagnostic)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestProviderData_detectedServerVersion(t *testing.T) {
+	version := "2.0.0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(serverVersionHeader, version)
+		switch r.URL.Path {
+		case "/health":
+			_, _ = w.Write([]byte("ok"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	providerData := testProviderData(server.URL)
+
+	// Failed calls do not detect the version.
+	if _, err := providerData.do(context.Background(), "GET", "/tenants/acme", nil); err == nil {
+		t.Fatal("expected the read of a missing tenant to fail")
+	}
+	if detected, ok := providerData.detectedServerVersion(); ok {
+		t.Fatalf("expected no version to be detected, got %q", detected)
+	}
+
+	// Legacy health endpoints do not report the version in their body, the
+	// data source falls back to the detected one.
+	resp := testDataSourceRead(t, &ServerInfoDataSource{}, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	var data ServerInfoDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	if !data.Version.Equal(types.StringValue("2.0.0")) {
+		t.Errorf("expected the detected version, got %s", data.Version)
+	}
+
+	// The version is detected once per provider instance.
+	version = "2.1.0"
+	if _, err := providerData.do(context.Background(), "GET", "/health", nil); err != nil {
+		t.Fatal(err)
+	}
+	if detected, _ := providerData.detectedServerVersion(); detected != "2.0.0" {
+		t.Errorf("expected the first detected version to be kept, got %q", detected)
+	}
+}
+
+func TestProviderData_supportsDetectsServerVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status": "ok", "version": "1.3.2", "features": {"scim": true}}`))
+	}))
+	defer server.Close()
+	providerData := testProviderData(server.URL)
+
+	if supported, err := providerData.supports(context.Background(), "scim"); err != nil || !supported {
+		t.Fatalf("expected scim to be supported, got %t and error %v", supported, err)
+	}
+	if detected, ok := providerData.detectedServerVersion(); !ok || detected != "1.3.2" {
+		t.Errorf("expected the version of the health endpoint to be detected, got %q", detected)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ServiceResource{}
+var _ resource.ResourceWithImportState = &ServiceResource{}
+
+func NewServiceResource() resource.Resource {
+	return &ServiceResource{}
+}
+
+// ServiceResource defines the resource implementation.
+type ServiceResource struct {
+	BaseResource
+}
+
+// ServiceResourceModel describes the resource data model.
+type ServiceResourceModel struct {
+	ID              types.String    `tfsdk:"id"`
+	Tenant          tenantNameValue `tfsdk:"tenant"`
+	Name            types.String    `tfsdk:"name"`
+	UpstreamURL     types.String    `tfsdk:"upstream_url"`
+	HealthCheckPath types.String    `tfsdk:"health_check_path"`
+	TimeoutSeconds  types.Int64     `tfsdk:"timeout_seconds"`
+}
+
+type serviceRequest struct {
+	Name            string `json:"name"`
+	UpstreamURL     string `json:"upstream_url"`
+	HealthCheckPath string `json:"health_check_path"`
+	TimeoutSeconds  int64  `json:"timeout_seconds"`
+}
+
+type serviceResponse struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	UpstreamURL     string `json:"upstream_url"`
+	HealthCheckPath string `json:"health_check_path"`
+	TimeoutSeconds  int64  `json:"timeout_seconds"`
+}
+
+// healthCheckPathPattern is the format of health check paths, absolute paths
+// such as "/healthz" that may carry a query.
+var healthCheckPathPattern = regexp.MustCompile(`^/[^#\s]*$`)
+
+func (r *ServiceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service"
+}
+
+func (r *ServiceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Upstream service protected by authproxy",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the service belongs to. Changing it recreates the service",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the service. Changing it renames the service",
+				Required:            true,
+			},
+			"upstream_url": schema.StringAttribute{
+				MarkdownDescription: "URL requests are proxied to, such as `http://billing.internal:8080`",
+				Required:            true,
+				Validators: []validator.String{
+					upstreamURL(),
+				},
+			},
+			"health_check_path": schema.StringAttribute{
+				MarkdownDescription: "Path of the upstream authproxy polls to tell whether the service is healthy, such as `/healthz`. No health checks when unset",
+				Optional:            true,
+				Validators: []validator.String{
+					stringMatchesValidator{
+						pattern:     healthCheckPathPattern,
+						description: `value must be an absolute path such as "/healthz"`,
+					},
+				},
+			},
+			"timeout_seconds": schema.Int64Attribute{
+				MarkdownDescription: "How long to wait for the upstream to respond. Defaults to `30`",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(30),
+				Validators: []validator.Int64{
+					int64Between(1, 300),
+				},
+			},
+		},
+	}
+}
+
+// servicesPath returns the path of the services collection of tenant.
+func servicesPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/services", url.PathEscape(tenant))
+}
+
+// servicePath returns the path of the service of tenant named name.
+func servicePath(tenant string, name string) string {
+	return servicesPath(tenant) + "/" + url.PathEscape(name)
+}
+
+// request builds the create and update request body from data.
+func (data *ServiceResourceModel) request() serviceRequest {
+	return serviceRequest{
+		Name:            data.Name.ValueString(),
+		UpstreamURL:     data.UpstreamURL.ValueString(),
+		HealthCheckPath: data.HealthCheckPath.ValueString(),
+		TimeoutSeconds:  data.TimeoutSeconds.ValueInt64(),
+	}
+}
+
+// setService copies the attributes authproxy returned for a service into
+// data.
+func (data *ServiceResourceModel) setService(service serviceResponse) {
+	data.ID = types.StringValue(service.ID)
+	data.Name = types.StringValue(service.Name)
+	data.UpstreamURL = types.StringValue(service.UpstreamURL)
+	data.HealthCheckPath = optionalString(service.HealthCheckPath)
+	data.TimeoutSeconds = types.Int64Value(service.TimeoutSeconds)
+}
+
+func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *ServiceResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var service serviceResponse
+	err := r.doJSON(ctx, "POST", servicesPath(data.Tenant.ValueString()), data.request(), &service)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create service", err)
+		return
+	}
+
+	data.setService(service)
+
+	tflog.Trace(ctx, "created a service resource", map[string]interface{}{
+		"id": service.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ServiceResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var service serviceResponse
+	err := r.doJSON(ctx, "GET", servicePath(data.Tenant.ValueString(), data.Name.ValueString()), nil, &service)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "service no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"name":   data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read service", err)
+		return
+	}
+
+	data.setService(service)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *ServiceResourceModel
+	var old *ServiceResourceModel
+
+	// Read Terraform old data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &old)...)
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The service is addressed by its previous name, a different name in the
+	// body renames it.
+	var service serviceResponse
+	err := r.doJSON(ctx, "PATCH", servicePath(data.Tenant.ValueString(), old.Name.ValueString()), data.request(), &service)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update service", err)
+		return
+	}
+
+	data.setService(service)
+
+	tflog.Trace(ctx, "updated a service resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *ServiceResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", servicePath(data.Tenant.ValueString(), data.Name.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete service", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a service resource")
+}
+
+func (r *ServiceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, name, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || name == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/name, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
@@ -0,0 +1,282 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SessionPolicyResource{}
+var _ resource.ResourceWithImportState = &SessionPolicyResource{}
+var _ resource.ResourceWithValidateConfig = &SessionPolicyResource{}
+
+func NewSessionPolicyResource() resource.Resource {
+	return &SessionPolicyResource{}
+}
+
+// SessionPolicyResource defines the resource implementation. Every tenant
+// has exactly one session policy, the resource manages it rather than
+// creating one.
+type SessionPolicyResource struct {
+	BaseResource
+}
+
+// SessionPolicyResourceModel describes the resource data model.
+type SessionPolicyResourceModel struct {
+	ID                    types.String    `tfsdk:"id"`
+	Tenant                tenantNameValue `tfsdk:"tenant"`
+	IdleTimeoutMinutes    types.Int64     `tfsdk:"idle_timeout_minutes"`
+	MaxLifetimeHours      types.Int64     `tfsdk:"max_lifetime_hours"`
+	MaxConcurrentSessions types.Int64     `tfsdk:"max_concurrent_sessions"`
+	RememberMeEnabled     types.Bool      `tfsdk:"remember_me_enabled"`
+}
+
+// sessionPolicyRequest leaves out unconfigured settings, authproxy applies
+// its defaults for them.
+type sessionPolicyRequest struct {
+	IdleTimeoutMinutes    *int64 `json:"idle_timeout_minutes,omitempty"`
+	MaxLifetimeHours      *int64 `json:"max_lifetime_hours,omitempty"`
+	MaxConcurrentSessions *int64 `json:"max_concurrent_sessions,omitempty"`
+	RememberMeEnabled     *bool  `json:"remember_me_enabled,omitempty"`
+}
+
+type sessionPolicyResponse struct {
+	IdleTimeoutMinutes    int64 `json:"idle_timeout_minutes"`
+	MaxLifetimeHours      int64 `json:"max_lifetime_hours"`
+	MaxConcurrentSessions int64 `json:"max_concurrent_sessions"`
+	RememberMeEnabled     bool  `json:"remember_me_enabled"`
+}
+
+func (r *SessionPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_session_policy"
+}
+
+func (r *SessionPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Session policy of a tenant. Every tenant has exactly one, destroying the resource resets it to the authproxy defaults",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the tenant",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the session policy applies to. Changing it resets the policy of the previous tenant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"idle_timeout_minutes": schema.Int64Attribute{
+				MarkdownDescription: "Minutes of inactivity after which a session ends. Must not exceed `max_lifetime_hours`. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64Between(1, 525600),
+				},
+			},
+			"max_lifetime_hours": schema.Int64Attribute{
+				MarkdownDescription: "Hours after which a session ends regardless of activity. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64Between(1, 8760),
+				},
+			},
+			"max_concurrent_sessions": schema.Int64Attribute{
+				MarkdownDescription: "Number of sessions a user may have at the same time, `0` for no limit. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64Between(0, 1000),
+				},
+			},
+			"remember_me_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether users may choose to stay logged in across browser restarts. Defaults to the authproxy default",
+				Optional:            true,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *SessionPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data SessionPolicyResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values that are not known yet are validated once they are.
+	if data.IdleTimeoutMinutes.IsNull() || data.IdleTimeoutMinutes.IsUnknown() ||
+		data.MaxLifetimeHours.IsNull() || data.MaxLifetimeHours.IsUnknown() {
+		return
+	}
+
+	if data.IdleTimeoutMinutes.ValueInt64() > data.MaxLifetimeHours.ValueInt64()*60 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("idle_timeout_minutes"),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("The idle timeout of %d minutes exceeds the maximum session lifetime of %d hours, sessions would never time out for inactivity.",
+				data.IdleTimeoutMinutes.ValueInt64(), data.MaxLifetimeHours.ValueInt64()),
+		)
+	}
+}
+
+// sessionPolicyPath returns the path of the session policy of tenant.
+func sessionPolicyPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/session-policy", url.PathEscape(tenant))
+}
+
+// request builds the request body from the known settings of data.
+func (data *SessionPolicyResourceModel) request() sessionPolicyRequest {
+	var policy sessionPolicyRequest
+	if !data.IdleTimeoutMinutes.IsUnknown() {
+		policy.IdleTimeoutMinutes = data.IdleTimeoutMinutes.ValueInt64Pointer()
+	}
+	if !data.MaxLifetimeHours.IsUnknown() {
+		policy.MaxLifetimeHours = data.MaxLifetimeHours.ValueInt64Pointer()
+	}
+	if !data.MaxConcurrentSessions.IsUnknown() {
+		policy.MaxConcurrentSessions = data.MaxConcurrentSessions.ValueInt64Pointer()
+	}
+	if !data.RememberMeEnabled.IsUnknown() {
+		policy.RememberMeEnabled = data.RememberMeEnabled.ValueBoolPointer()
+	}
+	return policy
+}
+
+// setSessionPolicy copies the settings authproxy returned into data.
+func (data *SessionPolicyResourceModel) setSessionPolicy(policy sessionPolicyResponse) {
+	data.ID = types.StringValue(data.Tenant.ValueString())
+	data.IdleTimeoutMinutes = types.Int64Value(policy.IdleTimeoutMinutes)
+	data.MaxLifetimeHours = types.Int64Value(policy.MaxLifetimeHours)
+	data.MaxConcurrentSessions = types.Int64Value(policy.MaxConcurrentSessions)
+	data.RememberMeEnabled = types.BoolValue(policy.RememberMeEnabled)
+}
+
+func (r *SessionPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SessionPolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy sessionPolicyResponse
+	err := r.doJSON(ctx, "PUT", sessionPolicyPath(data.Tenant.ValueString()), data.request(), &policy)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "set session policy", err)
+		return
+	}
+
+	data.setSessionPolicy(policy)
+
+	tflog.Trace(ctx, "created a session policy resource", map[string]interface{}{
+		"tenant": data.Tenant.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SessionPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SessionPolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy sessionPolicyResponse
+	err := r.doJSON(ctx, "GET", sessionPolicyPath(data.Tenant.ValueString()), nil, &policy)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant no longer exists, removing its session policy from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read session policy", err)
+		return
+	}
+
+	data.setSessionPolicy(policy)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SessionPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SessionPolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy sessionPolicyResponse
+	err := r.doJSON(ctx, "PUT", sessionPolicyPath(data.Tenant.ValueString()), data.request(), &policy)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update session policy", err)
+		return
+	}
+
+	data.setSessionPolicy(policy)
+
+	tflog.Trace(ctx, "updated a session policy resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SessionPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SessionPolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting the policy resets it to the authproxy defaults.
+	err := r.doJSON(ctx, "DELETE", sessionPolicyPath(data.Tenant.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "reset session policy", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a session policy resource")
+}
+
+func (r *SessionPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("tenant"), req, resp)
+}
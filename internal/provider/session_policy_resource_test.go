@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+var testAccDefaultSessionPolicy = sessionPolicyResponse{IdleTimeoutMinutes: 30, MaxLifetimeHours: 24, RememberMeEnabled: true}
+
+// testAccSessionPolicyBackend is an in-memory stand-in for the session
+// policy of the "acme" tenant.
+type testAccSessionPolicyBackend struct {
+	mu     sync.Mutex
+	policy sessionPolicyResponse
+}
+
+func (b *testAccSessionPolicyBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.URL.Path != sessionPolicyPath("acme") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var update sessionPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.policy = testAccDefaultSessionPolicy
+		if update.IdleTimeoutMinutes != nil {
+			b.policy.IdleTimeoutMinutes = *update.IdleTimeoutMinutes
+		}
+		if update.MaxLifetimeHours != nil {
+			b.policy.MaxLifetimeHours = *update.MaxLifetimeHours
+		}
+		if update.MaxConcurrentSessions != nil {
+			b.policy.MaxConcurrentSessions = *update.MaxConcurrentSessions
+		}
+		if update.RememberMeEnabled != nil {
+			b.policy.RememberMeEnabled = *update.RememberMeEnabled
+		}
+	case http.MethodDelete:
+		b.policy = testAccDefaultSessionPolicy
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(b.policy)
+}
+
+func TestAccSessionPolicyResource(t *testing.T) {
+	backend := &testAccSessionPolicyBackend{policy: testAccDefaultSessionPolicy}
+	server := testBackendServer(t, backend, sessionPolicyPath("acme"))
+
+	updated := testAccProviderConfig(server.URL) + `
+resource "authproxy_session_policy" "test" {
+  tenant                  = "acme"
+  idle_timeout_minutes    = 15
+  max_lifetime_hours      = 8
+  max_concurrent_sessions = 3
+  remember_me_enabled     = false
+}
+`
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if backend.policy != testAccDefaultSessionPolicy {
+				return fmt.Errorf("expected the session policy to be reset, got %+v", backend.policy)
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_session_policy" "test" {
+  tenant               = "acme"
+  idle_timeout_minutes = 20
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_session_policy.test", "id", "acme"),
+					resourcetest.TestCheckResourceAttr("authproxy_session_policy.test", "idle_timeout_minutes", "20"),
+					resourcetest.TestCheckResourceAttr("authproxy_session_policy.test", "max_lifetime_hours", "24"),
+					resourcetest.TestCheckResourceAttr("authproxy_session_policy.test", "remember_me_enabled", "true"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_session_policy.test",
+				ImportState:       true,
+				ImportStateId:     "acme",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: updated,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_session_policy.test", "idle_timeout_minutes", "15"),
+					resourcetest.TestCheckResourceAttr("authproxy_session_policy.test", "max_lifetime_hours", "8"),
+					resourcetest.TestCheckResourceAttr("authproxy_session_policy.test", "max_concurrent_sessions", "3"),
+					resourcetest.TestCheckResourceAttr("authproxy_session_policy.test", "remember_me_enabled", "false"),
+				),
+			},
+			// Changes made outside of Terraform are detected
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					backend.policy.RememberMeEnabled = true
+				},
+				Config:             updated,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccSessionPolicyResource_idleTimeoutExceedsLifetime(t *testing.T) {
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config: testAccProviderConfig("http://127.0.0.1:1") + `
+resource "authproxy_session_policy" "test" {
+  tenant               = "acme"
+  idle_timeout_minutes = 180
+  max_lifetime_hours   = 2
+}
+`,
+				ExpectError: regexp.MustCompile(`exceeds the maximum session lifetime`),
+			},
+		},
+	})
+}
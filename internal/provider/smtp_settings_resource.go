@@ -0,0 +1,319 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SMTPSettingsResource{}
+var _ resource.ResourceWithImportState = &SMTPSettingsResource{}
+
+func NewSMTPSettingsResource() resource.Resource {
+	return &SMTPSettingsResource{}
+}
+
+// SMTPSettingsResource defines the resource implementation. Every tenant
+// sends its emails through exactly one mailer, the resource manages it
+// rather than creating one.
+type SMTPSettingsResource struct {
+	BaseResource
+}
+
+// SMTPSettingsResourceModel describes the resource data model.
+type SMTPSettingsResourceModel struct {
+	ID              types.String    `tfsdk:"id"`
+	Tenant          tenantNameValue `tfsdk:"tenant"`
+	Host            types.String    `tfsdk:"host"`
+	Port            types.Int64     `tfsdk:"port"`
+	Username        types.String    `tfsdk:"username"`
+	Password        types.String    `tfsdk:"password"`
+	FromAddress     types.String    `tfsdk:"from_address"`
+	STARTTLS        types.Bool      `tfsdk:"starttls"`
+	SendTestEmailTo types.String    `tfsdk:"send_test_email_to"`
+}
+
+type smtpSettingsRequest struct {
+	Host        string `json:"host"`
+	Port        int64  `json:"port"`
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	FromAddress string `json:"from_address"`
+	STARTTLS    bool   `json:"starttls"`
+}
+
+// smtpSettingsResponse leaves out the password, authproxy only ever returns
+// it redacted.
+type smtpSettingsResponse struct {
+	Host        string `json:"host"`
+	Port        int64  `json:"port"`
+	Username    string `json:"username"`
+	FromAddress string `json:"from_address"`
+	STARTTLS    bool   `json:"starttls"`
+}
+
+type smtpTestRequest struct {
+	To string `json:"to"`
+}
+
+func (r *SMTPSettingsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_smtp_settings"
+}
+
+func (r *SMTPSettingsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "SMTP server a tenant sends invitation and password reset emails through. Destroying the resource switches the tenant back to the authproxy mailer",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the tenant",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant whose emails are sent. Changing it resets the settings of the previous tenant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"host": schema.StringAttribute{
+				MarkdownDescription: "Host name of the SMTP server",
+				Required:            true,
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "Port of the SMTP server. Defaults to `587`",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(587),
+				Validators: []validator.Int64{
+					int64Between(1, 65535),
+				},
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Username to authenticate with, no authentication when unset",
+				Optional:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password to authenticate with. Authproxy never returns it, so changes made outside of Terraform are not detected",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"from_address": schema.StringAttribute{
+				MarkdownDescription: "Address emails are sent from",
+				Required:            true,
+				Validators: []validator.String{
+					validators.Email(),
+				},
+			},
+			"starttls": schema.BoolAttribute{
+				MarkdownDescription: "Whether to upgrade the connection with STARTTLS. Defaults to `true`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"send_test_email_to": schema.StringAttribute{
+				MarkdownDescription: "Address to send a test email to whenever the settings are created or updated. The apply fails when the email cannot be sent",
+				Optional:            true,
+				Validators: []validator.String{
+					validators.Email(),
+				},
+			},
+		},
+	}
+}
+
+// smtpSettingsPath returns the path of the SMTP settings of tenant.
+func smtpSettingsPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/smtp", url.PathEscape(tenant))
+}
+
+// request builds the request body from data.
+func (data *SMTPSettingsResourceModel) request() smtpSettingsRequest {
+	return smtpSettingsRequest{
+		Host:        data.Host.ValueString(),
+		Port:        data.Port.ValueInt64(),
+		Username:    data.Username.ValueString(),
+		Password:    data.Password.ValueString(),
+		FromAddress: data.FromAddress.ValueString(),
+		STARTTLS:    data.STARTTLS.ValueBool(),
+	}
+}
+
+// setSMTPSettings copies the settings authproxy returned into data. The
+// password keeps its prior value.
+func (data *SMTPSettingsResourceModel) setSMTPSettings(settings smtpSettingsResponse) {
+	data.ID = types.StringValue(data.Tenant.ValueString())
+	data.Host = types.StringValue(settings.Host)
+	data.Port = types.Int64Value(settings.Port)
+	data.Username = optionalString(settings.Username)
+	data.FromAddress = types.StringValue(settings.FromAddress)
+	data.STARTTLS = types.BoolValue(settings.STARTTLS)
+}
+
+// sendTestEmail asks authproxy to send a test email with the settings of
+// data if send_test_email_to is set.
+func (r *SMTPSettingsResource) sendTestEmail(ctx context.Context, data *SMTPSettingsResourceModel) error {
+	if data.SendTestEmailTo.IsNull() {
+		return nil
+	}
+	return r.doJSON(ctx, "POST", smtpSettingsPath(data.Tenant.ValueString())+"/test", smtpTestRequest{
+		To: data.SendTestEmailTo.ValueString(),
+	}, nil)
+}
+
+// addTestEmailError reports a test email that could not be sent.
+func (data *SMTPSettingsResourceModel) addTestEmailError(diags *diag.Diagnostics, detail string, err error) {
+	diags.AddAttributeError(
+		path.Root("send_test_email_to"),
+		"SMTP Test Email Failed",
+		fmt.Sprintf("Authproxy could not send a test email to %s through %s:%d, %s: %s",
+			data.SendTestEmailTo.ValueString(), data.Host.ValueString(), data.Port.ValueInt64(), detail, err),
+	)
+}
+
+func (r *SMTPSettingsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SMTPSettingsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var settings smtpSettingsResponse
+	err := r.doJSON(ctx, "PUT", smtpSettingsPath(data.Tenant.ValueString()), data.request(), &settings)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "set SMTP settings", err)
+		return
+	}
+
+	if err := r.sendTestEmail(ctx, data); err != nil {
+		// Reset the settings again, the tenant would send its emails through
+		// a broken mailer without the settings being tracked in state
+		// otherwise.
+		detail := "the SMTP settings were not applied"
+		if resetErr := r.doJSON(ctx, "DELETE", smtpSettingsPath(data.Tenant.ValueString()), nil, nil); resetErr != nil {
+			detail = fmt.Sprintf("resetting the SMTP settings again failed as well (%s), reset them manually", resetErr)
+		}
+		data.addTestEmailError(&resp.Diagnostics, detail, err)
+		return
+	}
+
+	data.setSMTPSettings(settings)
+
+	tflog.Trace(ctx, "created an SMTP settings resource", map[string]interface{}{
+		"tenant": data.Tenant.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SMTPSettingsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SMTPSettingsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var settings smtpSettingsResponse
+	err := r.doJSON(ctx, "GET", smtpSettingsPath(data.Tenant.ValueString()), nil, &settings)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "SMTP settings no longer exist, removing them from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read SMTP settings", err)
+		return
+	}
+
+	data.setSMTPSettings(settings)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SMTPSettingsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SMTPSettingsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var settings smtpSettingsResponse
+	err := r.doJSON(ctx, "PUT", smtpSettingsPath(data.Tenant.ValueString()), data.request(), &settings)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update SMTP settings", err)
+		return
+	}
+
+	data.setSMTPSettings(settings)
+
+	// The settings are applied already, they are saved even when the test
+	// email fails so that state matches authproxy.
+	if err := r.sendTestEmail(ctx, data); err != nil {
+		data.addTestEmailError(&resp.Diagnostics, "the SMTP settings were applied nonetheless", err)
+	}
+
+	tflog.Trace(ctx, "updated an SMTP settings resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SMTPSettingsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SMTPSettingsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting the settings switches the tenant back to the authproxy
+	// mailer.
+	err := r.doJSON(ctx, "DELETE", smtpSettingsPath(data.Tenant.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "reset SMTP settings", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted an SMTP settings resource")
+}
+
+func (r *SMTPSettingsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("tenant"), req, resp)
+}
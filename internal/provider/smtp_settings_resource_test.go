@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccSMTPBackend is an in-memory stand-in for the SMTP endpoints of the
+// "acme" tenant, settings being nil while the authproxy mailer is used. Like
+// authproxy it only returns passwords redacted, and test emails sent through
+// hosts below unreachable.example.com fail.
+type testAccSMTPBackend struct {
+	mu       sync.Mutex
+	settings *smtpSettingsRequest
+	sent     []string
+}
+
+func (b *testAccSMTPBackend) respond(w http.ResponseWriter) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"host":         b.settings.Host,
+		"port":         b.settings.Port,
+		"username":     b.settings.Username,
+		"password":     "**REDACTED**",
+		"from_address": b.settings.FromAddress,
+		"starttls":     b.settings.STARTTLS,
+	})
+}
+
+func (b *testAccSMTPBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case r.URL.Path == smtpSettingsPath("acme") && r.Method == http.MethodGet:
+		if b.settings == nil {
+			http.NotFound(w, r)
+			return
+		}
+		b.respond(w)
+	case r.URL.Path == smtpSettingsPath("acme") && r.Method == http.MethodPut:
+		var update smtpSettingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.settings = &update
+		b.respond(w)
+	case r.URL.Path == smtpSettingsPath("acme") && r.Method == http.MethodDelete:
+		b.settings = nil
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Path == smtpSettingsPath("acme")+"/test" && r.Method == http.MethodPost:
+		var test smtpTestRequest
+		if err := json.NewDecoder(r.Body).Decode(&test); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if b.settings == nil || strings.HasSuffix(b.settings.Host, "unreachable.example.com") {
+			http.Error(w, "dial tcp: connection refused", http.StatusBadGateway)
+			return
+		}
+		b.sent = append(b.sent, test.To)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (b *testAccSMTPBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.settings != nil {
+		return fmt.Errorf("expected the tenant to use the authproxy mailer again, got %+v", *b.settings)
+	}
+	return nil
+}
+
+func TestAccSMTPSettingsResource(t *testing.T) {
+	backend := &testAccSMTPBackend{}
+	server := testBackendServer(t, backend, smtpSettingsPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, a test email is sent
+			{
+				Config: testAccProviderConfig(server.URL) + testAccSMTPSettingsResourceConfig("smtp.acme.io", 587),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_smtp_settings.test", "id", "acme"),
+					resourcetest.TestCheckResourceAttr("authproxy_smtp_settings.test", "password", "hunter2"),
+					resourcetest.TestCheckResourceAttr("authproxy_smtp_settings.test", "starttls", "true"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if len(backend.sent) != 1 || backend.sent[0] != "ops@acme.io" {
+							return fmt.Errorf("expected a test email to ops@acme.io, got %v", backend.sent)
+						}
+						return nil
+					},
+				),
+			},
+			// The password survives a refresh although authproxy redacts it
+			{
+				RefreshState: true,
+				Check:        resourcetest.TestCheckResourceAttr("authproxy_smtp_settings.test", "password", "hunter2"),
+			},
+			// ImportState testing, the password cannot be imported
+			{
+				ResourceName:            "authproxy_smtp_settings.test",
+				ImportState:             true,
+				ImportStateId:           "acme",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "send_test_email_to"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccSMTPSettingsResourceConfig("smtp.acme.io", 465),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_smtp_settings.test", "port", "465"),
+					resourcetest.TestCheckResourceAttr("authproxy_smtp_settings.test", "password", "hunter2"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if backend.settings.Password != "hunter2" || len(backend.sent) != 2 {
+							return fmt.Errorf("expected the password to be kept and a second test email, got %+v and %v", *backend.settings, backend.sent)
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccSMTPSettingsResource_testEmailFails(t *testing.T) {
+	backend := &testAccSMTPBackend{}
+	server := testBackendServer(t, backend, smtpSettingsPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Settings that fail the test email on creation are reset again
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccSMTPSettingsResourceConfig("smtp.unreachable.example.com", 587),
+				ExpectError: regexp.MustCompile(`SMTP Test Email Failed`),
+			},
+			{
+				Config: testAccProviderConfig(server.URL) + testAccSMTPSettingsResourceConfig("smtp.acme.io", 587),
+				Check:  resourcetest.TestCheckResourceAttr("authproxy_smtp_settings.test", "host", "smtp.acme.io"),
+			},
+			// Settings that fail the test email on update fail the apply
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccSMTPSettingsResourceConfig("smtp.unreachable.example.com", 587),
+				ExpectError: regexp.MustCompile(`SMTP Test Email Failed`),
+			},
+		},
+	})
+}
+
+func testAccSMTPSettingsResourceConfig(host string, port int) string {
+	return fmt.Sprintf(`
+resource "authproxy_smtp_settings" "test" {
+  tenant             = "acme"
+  host               = %q
+  port               = %d
+  username           = "mailer"
+  password           = "hunter2"
+  from_address       = "noreply@acme.io"
+  send_test_email_to = "ops@acme.io"
+}
+`, host, port)
+}
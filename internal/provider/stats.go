@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"sync"
+)
+
+// apiStats counts the authproxy API calls of a provider instance. Terraform
+// starts a provider instance for every plan or apply, so the counters logged
+// after its last operation sum up the run.
+type apiStats struct {
+	mu sync.Mutex
+	// calls counts the API calls by method, retries not included.
+	calls map[string]int64
+	// retries counts the additional attempts of calls, rateLimitWaits the
+	// ones waiting out a 429 response.
+	retries        int64
+	rateLimitWaits int64
+	// cacheHits counts the API calls saved by sharing responses, see
+	// readGroup and capabilities.
+	cacheHits int64
+}
+
+// addCall records an API call made with method and the attempts it took.
+func (s *apiStats) addCall(method string, attempts *requestAttempts) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.calls == nil {
+		s.calls = map[string]int64{}
+	}
+	s.calls[method]++
+	s.retries += int64(attempts.count - 1)
+	s.rateLimitWaits += int64(attempts.rateLimited)
+}
+
+// addCacheHit records an API call saved by a cached response.
+func (s *apiStats) addCacheHit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cacheHits++
+}
+
+// fields returns the counters as log fields.
+func (s *apiStats) fields() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total int64
+	calls := make(map[string]int64, len(s.calls))
+	for method, count := range s.calls {
+		calls[method] = count
+		total += count
+	}
+	return map[string]interface{}{
+		"api_calls":           total,
+		"api_calls_by_method": calls,
+		"retries":             s.retries,
+		"rate_limit_waits":    s.rateLimitWaits,
+		"cache_hits":          s.cacheHits,
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAPIStats(t *testing.T) {
+	var throttled, failed int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/health":
+			_, _ = w.Write([]byte(`{"features": {}}`))
+		case r.Method == http.MethodPost && atomic.AddInt32(&throttled, 1) == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/tenants/flaky" && atomic.AddInt32(&failed, 1) == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	providerData := testProviderData(server.URL)
+	providerData.client = &http.Client{Transport: &retryTransport{next: http.DefaultTransport, attempts: 3}}
+	ctx := context.Background()
+
+	// The second probe of the capabilities is a cache hit.
+	for i := 0; i < 2; i++ {
+		if _, err := providerData.supports(ctx, "bulk_roles"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A create waiting out a 429 and a read retried after a 503.
+	if err := providerData.doJSON(ctx, "POST", "/tenants", map[string]string{"name": "acme"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := providerData.do(ctx, "GET", "/tenants/flaky", nil); err != nil {
+		t.Fatal(err)
+	}
+	// Concurrent reads, none of them deduplicated.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := providerData.do(ctx, "GET", "/tenants/acme", nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	expected := map[string]interface{}{
+		"api_calls":           int64(13),
+		"api_calls_by_method": map[string]int64{"GET": 12, "POST": 1},
+		"retries":             int64(2),
+		"rate_limit_waits":    int64(1),
+		"cache_hits":          int64(1),
+	}
+	if fields := providerData.stats.fields(); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected the counters %v, got %v", expected, fields)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TenantAliasResource{}
+var _ resource.ResourceWithImportState = &TenantAliasResource{}
+
+func NewTenantAliasResource() resource.Resource {
+	return &TenantAliasResource{}
+}
+
+// TenantAliasResource defines the resource implementation.
+type TenantAliasResource struct {
+	BaseResource
+}
+
+// TenantAliasResourceModel describes the resource data model.
+type TenantAliasResourceModel struct {
+	ID     types.String    `tfsdk:"id"`
+	Tenant tenantNameValue `tfsdk:"tenant"`
+	Alias  types.String    `tfsdk:"alias"`
+}
+
+type tenantAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+type tenantAliasResponse struct {
+	Alias string `json:"alias"`
+}
+
+// tenantAliasConflictResponse is the body authproxy answers with when an
+// alias is already taken, either as the name of Tenant or as one of its
+// aliases.
+type tenantAliasConflictResponse struct {
+	Kind   string `json:"kind"`
+	Tenant string `json:"tenant"`
+}
+
+func (r *TenantAliasResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tenant_alias"
+}
+
+func (r *TenantAliasResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Alternate name of a tenant, such as a legacy slug, that routes logins to the tenant",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the alias of the form `tenant/alias`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the alias routes to. Changing it recreates the alias",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"alias": schema.StringAttribute{
+				MarkdownDescription: "The alternate name, following the same rules as tenant names. Changing it recreates the alias",
+				Required:            true,
+				Validators: []validator.String{
+					validators.TenantName(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// tenantAliasesPath returns the path of the aliases collection of tenant.
+func tenantAliasesPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/aliases", url.PathEscape(tenant))
+}
+
+// tenantAliasPath returns the path of the alias of tenant.
+func tenantAliasPath(tenant string, alias string) string {
+	return tenantAliasesPath(tenant) + "/" + url.PathEscape(alias)
+}
+
+// addConflictError reports an alias authproxy refused because it is already
+// taken, naming the tenant that owns it. It returns false for any other
+// error.
+func (data *TenantAliasResourceModel) addConflictError(diags *diag.Diagnostics, err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		return false
+	}
+
+	var conflict tenantAliasConflictResponse
+	if json.Unmarshal([]byte(apiErr.Body), &conflict) != nil || conflict.Tenant == "" {
+		return false
+	}
+
+	alias, tenant := data.Alias.ValueString(), data.Tenant.ValueString()
+	var detail string
+	switch {
+	case conflict.Kind == "tenant":
+		detail = fmt.Sprintf("The alias %q is the name of the tenant %q.", alias, conflict.Tenant)
+	case conflict.Tenant == tenant:
+		detail = fmt.Sprintf("The alias %q already routes to tenant %q. Import it instead of creating it:\n\n"+
+			"terraform import authproxy_tenant_alias.<name> %s/%s", alias, tenant, tenant, alias)
+	default:
+		detail = fmt.Sprintf("The alias %q is already an alias of tenant %q.", alias, conflict.Tenant)
+	}
+	diags.AddAttributeError(path.Root("alias"), "Tenant Alias Taken", detail)
+	return true
+}
+
+func (r *TenantAliasResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *TenantAliasResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var alias tenantAliasResponse
+	err := r.doJSON(ctx, "POST", tenantAliasesPath(data.Tenant.ValueString()), tenantAliasRequest{
+		Alias: data.Alias.ValueString(),
+	}, &alias)
+	if data.addConflictError(&resp.Diagnostics, err) {
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create tenant alias", err)
+		return
+	}
+
+	data.ID = types.StringValue(data.Tenant.ValueString() + "/" + data.Alias.ValueString())
+
+	tflog.Trace(ctx, "created a tenant alias resource", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TenantAliasResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *TenantAliasResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var alias tenantAliasResponse
+	err := r.doJSON(ctx, "GET", tenantAliasPath(data.Tenant.ValueString(), data.Alias.ValueString()), nil, &alias)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant alias no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"alias":  data.Alias.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read tenant alias", err)
+		return
+	}
+
+	data.ID = types.StringValue(data.Tenant.ValueString() + "/" + data.Alias.ValueString())
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TenantAliasResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every argument requires replacement, there is nothing to update.
+	resp.Diagnostics.AddError(
+		"Unexpected Update",
+		"Tenant aliases cannot be updated in place. Please report this issue to the provider developers.",
+	)
+}
+
+func (r *TenantAliasResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *TenantAliasResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", tenantAliasPath(data.Tenant.ValueString(), data.Alias.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete tenant alias", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a tenant alias resource")
+}
+
+func (r *TenantAliasResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, alias, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || alias == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/alias, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("alias"), alias)...)
+}
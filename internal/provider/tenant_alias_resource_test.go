@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccTenantAliasBackend is an in-memory stand-in for the alias endpoints
+// of the tenants "acme" and "globex". Aliases maps every alias to the tenant
+// it routes to.
+type testAccTenantAliasBackend struct {
+	mu      sync.Mutex
+	aliases map[string]string
+}
+
+func (b *testAccTenantAliasBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tenants := map[string]bool{"acme": true, "globex": true}
+
+	var tenant, alias string
+	for name := range tenants {
+		if prefix := tenantAliasesPath(name); strings.HasPrefix(r.URL.Path, prefix) {
+			tenant = name
+			alias = strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		}
+	}
+	if tenant == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case alias == "" && r.Method == http.MethodPost:
+		var body tenantAliasRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if tenants[body.Alias] {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(tenantAliasConflictResponse{Kind: "tenant", Tenant: body.Alias})
+			return
+		}
+		if owner, ok := b.aliases[body.Alias]; ok {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(tenantAliasConflictResponse{Kind: "alias", Tenant: owner})
+			return
+		}
+		b.aliases[body.Alias] = tenant
+		_ = json.NewEncoder(w).Encode(tenantAliasResponse{Alias: body.Alias})
+	case alias != "" && r.Method == http.MethodGet:
+		if b.aliases[alias] != tenant {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(tenantAliasResponse{Alias: alias})
+	case alias != "" && r.Method == http.MethodDelete:
+		if b.aliases[alias] != tenant {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.aliases, alias)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (b *testAccTenantAliasBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if tenant, ok := b.aliases["acme-legacy"]; ok {
+		return fmt.Errorf("expected the alias acme-legacy to be deleted, it still routes to %s", tenant)
+	}
+	return nil
+}
+
+func TestAccTenantAliasResource(t *testing.T) {
+	backend := &testAccTenantAliasBackend{aliases: map[string]string{}}
+	server := testBackendServer(t, backend, tenantAliasesPath("acme"), tenantAliasesPath("globex"))
+
+	config := testAccProviderConfig(server.URL) + testAccTenantAliasResourceConfig("acme-legacy")
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: config,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_tenant_alias.test", "id", "acme/acme-legacy"),
+					resourcetest.TestCheckResourceAttr("authproxy_tenant_alias.test", "alias", "acme-legacy"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_tenant_alias.test",
+				ImportState:       true,
+				ImportStateId:     "acme/acme-legacy",
+				ImportStateVerify: true,
+			},
+			// An alias removed outside of Terraform is created again
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					delete(backend.aliases, "acme-legacy")
+				},
+				Config: config,
+				Check: func(s *terraform.State) error {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					if backend.aliases["acme-legacy"] != "acme" {
+						return fmt.Errorf("expected the alias to be created again")
+					}
+					return nil
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccTenantAliasResource_collision(t *testing.T) {
+	backend := &testAccTenantAliasBackend{aliases: map[string]string{"initech": "globex"}}
+	server := testBackendServer(t, backend, tenantAliasesPath("acme"), tenantAliasesPath("globex"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccTenantAliasResourceConfig("globex"),
+				ExpectError: regexp.MustCompile(`The alias "globex" is the name of the tenant "globex"`),
+			},
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccTenantAliasResourceConfig("initech"),
+				ExpectError: regexp.MustCompile(`The alias "initech" is already an alias of tenant "globex"`),
+			},
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccTenantAliasResourceConfig("Acme_Legacy"),
+				ExpectError: regexp.MustCompile(`value must be a tenant name`),
+			},
+		},
+	})
+}
+
+func testAccTenantAliasResourceConfig(alias string) string {
+	return fmt.Sprintf(`
+resource "authproxy_tenant_alias" "test" {
+  tenant = "acme"
+  alias  = %q
+}
+`, alias)
+}
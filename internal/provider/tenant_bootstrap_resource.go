@@ -0,0 +1,544 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/planmodifiers"
+	"github.com/4thel00z/terraform-provider-authproxy/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tenantBootstrapFeature is the feature flag of authproxy builds serving
+// tenantBootstrapPath, which creates a tenant along with its admin role,
+// admin user and binding all or nothing.
+const tenantBootstrapFeature = "tenant_bootstrap"
+
+const tenantBootstrapPath = "/bootstrap"
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TenantBootstrapResource{}
+var _ resource.ResourceWithModifyPlan = &TenantBootstrapResource{}
+
+func NewTenantBootstrapResource() resource.Resource {
+	return &TenantBootstrapResource{}
+}
+
+// TenantBootstrapResource defines the resource implementation.
+type TenantBootstrapResource struct {
+	BaseResource
+}
+
+// TenantBootstrapResourceModel describes the resource data model.
+type TenantBootstrapResourceModel struct {
+	ID                   types.String         `tfsdk:"id"`
+	Tenant               tenantNameValue      `tfsdk:"tenant"`
+	AdminRole            types.String         `tfsdk:"admin_role"`
+	AdminScopes          types.List           `tfsdk:"admin_scopes"`
+	AdminUsername        types.String         `tfsdk:"admin_username"`
+	AdminEmail           types.String         `tfsdk:"admin_email"`
+	AdminInitialPassword types.String         `tfsdk:"admin_initial_password"`
+	TenantID             types.String         `tfsdk:"tenant_id"`
+	AdminRoleID          types.String         `tfsdk:"admin_role_id"`
+	AdminUserID          types.String         `tfsdk:"admin_user_id"`
+	AdminBindingID       types.String         `tfsdk:"admin_binding_id"`
+	Timeouts             *CreateTimeoutsModel `tfsdk:"timeouts"`
+}
+
+type tenantBootstrapRequest struct {
+	Tenant    string            `json:"tenant"`
+	AdminRole createRoleRequest `json:"admin_role"`
+	AdminUser userCreateRequest `json:"admin_user"`
+}
+
+type tenantBootstrapResponse struct {
+	TenantID    string `json:"tenant_id"`
+	AdminRoleID string `json:"admin_role_id"`
+	AdminUserID string `json:"admin_user_id"`
+}
+
+func (r *TenantBootstrapResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tenant_bootstrap"
+}
+
+func (r *TenantBootstrapResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "A tenant along with its admin role, its admin user and the binding of the role to the user, created all or nothing. " +
+			"Authproxy builds advertising the `tenant_bootstrap` feature create them in a single request, with older ones the provider creates them one after another and deletes what it created when a step fails. " +
+			"Parts deleted outside of Terraform are created again on the next apply. The admin settings only apply when a part is created, manage later changes of the role and user with `authproxy_role` and `authproxy_user` after importing them",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid of the tenant, same as `tenant_id`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Name of the tenant. Changing it recreates the tenant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("the tenant is bootstrapped under its name"),
+				},
+			},
+			"admin_role": schema.StringAttribute{
+				MarkdownDescription: "Name of the admin role. Changing it recreates the tenant. Defaults to `\"admin\"`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("admin"),
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("the admin role is only created when bootstrapping the tenant"),
+				},
+			},
+			"admin_scopes": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scopes the admin role is created with. Changing them later has no effect",
+				Required:            true,
+				Validators: []validator.List{
+					validators.ListOf(validators.Scope()),
+				},
+			},
+			"admin_username": schema.StringAttribute{
+				MarkdownDescription: "Username of the admin user. Changing it recreates the tenant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("the admin user is only created when bootstrapping the tenant"),
+				},
+			},
+			"admin_email": schema.StringAttribute{
+				MarkdownDescription: "Email address the admin user is created with. Changing it later has no effect",
+				Required:            true,
+			},
+			"admin_initial_password": schema.StringAttribute{
+				MarkdownDescription: "Password the admin user is created with. It is never read back, so changing it later has no effect",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"tenant_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid of the tenant",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"admin_role_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid of the admin role",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"admin_user_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The ID of the admin user",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"admin_binding_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the binding of the admin role to the admin user, of the form `tenant/role/user/user_id` like the one of `authproxy_role_binding`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": createTimeoutsBlock(defaultTenantCreateTimeout),
+		},
+	}
+}
+
+// ModifyPlan plans to create the parts of a bootstrapped tenant again that
+// Read found missing, so their IDs show up as known after apply.
+func (r *TenantBootstrapResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state TenantBootstrapResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for attribute, id := range map[string]types.String{
+		"admin_role_id":    state.AdminRoleID,
+		"admin_user_id":    state.AdminUserID,
+		"admin_binding_id": state.AdminBindingID,
+	} {
+		if id.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attribute), types.StringUnknown())...)
+		}
+	}
+}
+
+// setBindingID sets the ID of the binding of the admin role to the admin user
+// of data, which has userID.
+func (data *TenantBootstrapResourceModel) setBindingID(userID string) {
+	data.AdminBindingID = types.StringValue(strings.Join([]string{data.Tenant.ValueString(), data.AdminRole.ValueString(), "user", userID}, "/"))
+}
+
+func (r *TenantBootstrapResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *TenantBootstrapResourceModel
+	var scopes []string
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(data.AdminScopes.ElementsAs(ctx, &scopes, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout := createTimeout(data.Timeouts, defaultTenantCreateTimeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	atomic, err := r.providerData.supports(ctx, tenantBootstrapFeature)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "probe server capabilities", err)
+		return
+	}
+	requested, err := r.providerData.roleScopes(ctx, scopes)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "prepare the admin role scopes", err)
+		return
+	}
+	role := createRoleRequest{Name: data.AdminRole.ValueString(), roleScopes: requested}
+	user := data.userCreateRequest()
+
+	if atomic {
+		var created tenantBootstrapResponse
+		err = r.doObjectJSON(ctx, "POST", tenantBootstrapPath, tenantBootstrapRequest{
+			Tenant:    data.Tenant.ValueString(),
+			AdminRole: role,
+			AdminUser: user,
+		}, &created, "tenant_id", "admin_role_id", "admin_user_id")
+		// The tenant is the only object of the request that can exist
+		// already, the others belong to it.
+		if isStatus(err, http.StatusConflict) {
+			err = errTenantExists
+		}
+		if err == nil {
+			data.TenantID = types.StringValue(created.TenantID)
+			data.AdminRoleID = types.StringValue(created.AdminRoleID)
+			data.AdminUserID = types.StringValue(created.AdminUserID)
+			data.setBindingID(created.AdminUserID)
+		}
+	} else {
+		err = r.bootstrap(ctx, data, role, user, &resp.Diagnostics)
+	}
+	if errors.Is(err, errTenantExists) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("tenant"),
+			"Tenant Already Exists",
+			fmt.Sprintf("A tenant named %q exists already, so it cannot be bootstrapped. "+
+				"Manage it with authproxy_tenant, authproxy_role, authproxy_user and authproxy_role_binding instead.", data.Tenant.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "bootstrap tenant", err)
+		return
+	}
+	data.ID = data.TenantID
+
+	tflog.Trace(ctx, "created a tenant bootstrap resource", map[string]interface{}{
+		"id":     data.ID.ValueString(),
+		"atomic": atomic,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// userCreateRequest returns the request the admin user of data is created
+// with.
+func (data *TenantBootstrapResourceModel) userCreateRequest() userCreateRequest {
+	return userCreateRequest{
+		Username: data.AdminUsername.ValueString(),
+		Email:    data.AdminEmail.ValueString(),
+		Enabled:  true,
+		Password: data.AdminInitialPassword.ValueString(),
+	}
+}
+
+// errTenantExists is returned when the tenant to bootstrap exists already.
+var errTenantExists = errors.New("the tenant exists already")
+
+// bootstrap creates the tenant, admin role, admin user and binding of data
+// one after another for authproxy builds without tenantBootstrapPath, setting
+// their IDs. When a step fails it deletes what it created before, see
+// rollback. It returns errTenantExists when creating the tenant conflicts,
+// conflicts of the later steps are returned as they are.
+func (r *TenantBootstrapResource) bootstrap(ctx context.Context, data *TenantBootstrapResourceModel, role createRoleRequest, user userCreateRequest, diags *diag.Diagnostics) error {
+	tenant := data.Tenant.ValueString()
+	var created []string
+	failed := func(action string, err error) error {
+		r.rollback(ctx, tenant, created, diags)
+		return fmt.Errorf("%s: %w", action, err)
+	}
+
+	var createdTenant createResponse
+	if err := r.doObjectJSON(ctx, "POST", "/tenants", createRequest{Name: tenant}, &createdTenant, "id"); err != nil {
+		if isStatus(err, http.StatusConflict) {
+			return errTenantExists
+		}
+		return fmt.Errorf("create the tenant: %w", err)
+	}
+	created = append(created, fmt.Sprintf("/tenants/%s", tenant))
+	data.TenantID = types.StringValue(createdTenant.ID)
+
+	// Roles cannot be created in tenants still provisioning.
+	if !tenantReady(createdTenant.Status) {
+		if _, err := r.providerData.waitForReady(ctx, tenant); err != nil {
+			return failed("wait for the tenant to become active", err)
+		}
+	}
+
+	var createdRole createRoleResponse
+	if err := r.doObjectJSON(ctx, "POST", tenantRolesPath(tenant), role, &createdRole, "id"); err != nil {
+		return failed("create the admin role", err)
+	}
+	created = append(created, tenantRolePath(tenant, role.Name))
+	data.AdminRoleID = types.StringValue(createdRole.ID)
+
+	var createdUser userResponse
+	if err := r.doObjectJSON(ctx, "POST", usersPath(tenant), user, &createdUser, "id"); err != nil {
+		return failed("create the admin user", err)
+	}
+	created = append(created, userPath(tenant, user.Username))
+	data.AdminUserID = types.StringValue(createdUser.ID)
+
+	if err := r.doJSON(ctx, "POST", roleBindingsPath(tenant, role.Name), roleBindingCreateRequest{PrincipalType: "user", PrincipalID: createdUser.ID}, nil); err != nil {
+		return failed("bind the admin role to the admin user", err)
+	}
+	data.setBindingID(createdUser.ID)
+	return nil
+}
+
+// rollback deletes the objects at the paths created while bootstrapping
+// tenant, the newest first. It adds an error to diags listing the objects it
+// could not delete, which are left behind in authproxy. The deletions are
+// bounded by the request timeout of the provider rather than by ctx, so a
+// bootstrap running out of time still cleans up.
+func (r *TenantBootstrapResource) rollback(ctx context.Context, tenant string, created []string, diags *diag.Diagnostics) {
+	ctx, cancel := r.providerData.detached(ctx)
+	defer cancel()
+
+	var left []string
+	for i := len(created) - 1; i >= 0; i-- {
+		err := r.doJSON(ctx, "DELETE", created[i], nil, nil)
+		if err != nil && !isStatus(err, http.StatusNotFound) {
+			left = append(left, fmt.Sprintf("  DELETE %s: %s", created[i], err))
+		}
+	}
+	if len(left) > 0 {
+		diags.AddError(
+			"Tenant Bootstrap Rollback Failed",
+			fmt.Sprintf("Bootstrapping the tenant %q failed, and deleting what was created before failed as well. "+
+				"Delete these objects by hand before applying again:\n\n%s", tenant, strings.Join(left, "\n")),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "rolled back the failed bootstrap of the tenant", map[string]interface{}{
+		"tenant":  tenant,
+		"deleted": len(created),
+	})
+}
+
+func (r *TenantBootstrapResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *TenantBootstrapResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tenant := data.Tenant.ValueString()
+	var existing readResponse
+	err := r.doObjectJSON(ctx, "GET", fmt.Sprintf("/tenants/%s", tenant), nil, &existing, "id", "name")
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "bootstrapped tenant not found, removing it from the state", map[string]interface{}{
+			"name": tenant,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read tenant", err)
+		return
+	}
+	data.TenantID = types.StringValue(existing.ID)
+	data.ID = data.TenantID
+
+	// Parts deleted outside of Terraform lose their ID, which ModifyPlan
+	// plans to create them again.
+	var role readRoleResponse
+	err = r.doObjectJSON(ctx, "GET", tenantRolePath(tenant, data.AdminRole.ValueString()), nil, &role, "id", "name")
+	switch {
+	case isStatus(err, http.StatusNotFound):
+		data.AdminRoleID = types.StringNull()
+	case err != nil:
+		addClientError(&resp.Diagnostics, "read admin role", err)
+		return
+	default:
+		data.AdminRoleID = types.StringValue(role.ID)
+	}
+
+	var user userResponse
+	err = r.doObjectJSON(ctx, "GET", userPath(tenant, data.AdminUsername.ValueString()), nil, &user, "id")
+	switch {
+	case isStatus(err, http.StatusNotFound):
+		data.AdminUserID = types.StringNull()
+	case err != nil:
+		addClientError(&resp.Diagnostics, "read admin user", err)
+		return
+	default:
+		data.AdminUserID = types.StringValue(user.ID)
+	}
+
+	data.AdminBindingID = types.StringNull()
+	if !data.AdminRoleID.IsNull() && !data.AdminUserID.IsNull() {
+		err = r.doJSON(ctx, "GET", roleBindingPath(tenant, data.AdminRole.ValueString(), "user", user.ID), nil, nil)
+		switch {
+		case isStatus(err, http.StatusNotFound):
+		case err != nil:
+			addClientError(&resp.Diagnostics, "read admin role binding", err)
+			return
+		default:
+			data.setBindingID(user.ID)
+		}
+	}
+
+	for part, id := range map[string]types.String{"admin role": data.AdminRoleID, "admin user": data.AdminUserID, "admin role binding": data.AdminBindingID} {
+		if id.IsNull() {
+			tflog.Warn(ctx, fmt.Sprintf("the %s of the bootstrapped tenant was deleted outside of Terraform", part), map[string]interface{}{
+				"tenant": tenant,
+			})
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update creates the parts of the bootstrapped tenant again that were deleted
+// outside of Terraform. Changes of the other attributes only apply to parts
+// created later.
+func (r *TenantBootstrapResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *TenantBootstrapResourceModel
+	var scopes []string
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(data.AdminScopes.ElementsAs(ctx, &scopes, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tenant := data.Tenant.ValueString()
+	created, err := r.recreate(ctx, data, scopes)
+	// The parts created before a failure are kept in the state, the
+	// missing ones are planned again.
+	for _, id := range []*types.String{&data.AdminRoleID, &data.AdminUserID, &data.AdminBindingID} {
+		if id.IsUnknown() {
+			*id = types.StringNull()
+		}
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "recreate the parts of the bootstrapped tenant", err)
+		return
+	}
+
+	tflog.Trace(ctx, "updated a tenant bootstrap resource", map[string]interface{}{
+		"tenant":  tenant,
+		"created": created,
+	})
+}
+
+// recreate creates the parts of data whose ID is unknown, as planned by
+// ModifyPlan, and sets their IDs. It returns the parts it created.
+func (r *TenantBootstrapResource) recreate(ctx context.Context, data *TenantBootstrapResourceModel, scopes []string) ([]string, error) {
+	tenant := data.Tenant.ValueString()
+	var created []string
+
+	if data.AdminRoleID.IsUnknown() {
+		requested, err := r.providerData.roleScopes(ctx, scopes)
+		if err != nil {
+			return created, err
+		}
+		var role createRoleResponse
+		if err := r.doObjectJSON(ctx, "POST", tenantRolesPath(tenant), createRoleRequest{Name: data.AdminRole.ValueString(), roleScopes: requested}, &role, "id"); err != nil {
+			return created, fmt.Errorf("create the admin role: %w", err)
+		}
+		data.AdminRoleID = types.StringValue(role.ID)
+		created = append(created, "admin role")
+	}
+
+	if data.AdminUserID.IsUnknown() {
+		var user userResponse
+		if err := r.doObjectJSON(ctx, "POST", usersPath(tenant), data.userCreateRequest(), &user, "id"); err != nil {
+			return created, fmt.Errorf("create the admin user: %w", err)
+		}
+		data.AdminUserID = types.StringValue(user.ID)
+		created = append(created, "admin user")
+	}
+
+	if data.AdminBindingID.IsUnknown() {
+		userID := data.AdminUserID.ValueString()
+		if err := r.doJSON(ctx, "POST", roleBindingsPath(tenant, data.AdminRole.ValueString()), roleBindingCreateRequest{PrincipalType: "user", PrincipalID: userID}, nil); err != nil {
+			return created, fmt.Errorf("bind the admin role to the admin user: %w", err)
+		}
+		data.setBindingID(userID)
+		created = append(created, "admin role binding")
+	}
+	return created, nil
+}
+
+// Delete deletes the tenant, which authproxy deletes the admin role, admin
+// user and binding along with.
+func (r *TenantBootstrapResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *TenantBootstrapResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", fmt.Sprintf("/tenants/%s", data.Tenant.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete tenant", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a tenant bootstrap resource", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+}
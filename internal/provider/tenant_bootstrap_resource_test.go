@@ -0,0 +1,384 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testBootstrapBackend fakes the users and the bindings of the admin role of
+// the acme tenant, which testserver does not implement, and the atomic
+// bootstrap endpoint on top of a testserver.
+type testBootstrapBackend struct {
+	*testserver.Server
+
+	mu       sync.Mutex
+	users    map[string]userResponse
+	bindings map[string]bool
+	// bindingStatus, unless zero, makes binding the admin role fail with it.
+	bindingStatus int
+}
+
+func newTestBootstrapBackend(t *testing.T, atomic bool) *testBootstrapBackend {
+	b := &testBootstrapBackend{Server: testserver.New(t), users: map[string]userResponse{}, bindings: map[string]bool{}}
+	b.SetFeatures(map[string]bool{tenantBootstrapFeature: atomic})
+	b.Handle("/tenants/acme/users", http.HandlerFunc(b.serveUsers))
+	b.Handle("/tenants/acme/users/", http.HandlerFunc(b.serveUsers))
+	b.Handle("/tenants/acme/roles/admin/bindings", http.HandlerFunc(b.serveBindings))
+	b.Handle("/tenants/acme/roles/admin/bindings/", http.HandlerFunc(b.serveBindings))
+	if atomic {
+		b.Handle(tenantBootstrapPath, http.HandlerFunc(b.serveBootstrap))
+	}
+	return b
+}
+
+// exists reports whether the acme tenant and, unless role is empty, its
+// admin role exist, as users and bindings are deleted along with them.
+func (b *testBootstrapBackend) exists(role bool) bool {
+	if _, ok := b.Tenant("acme"); !ok {
+		b.users = map[string]userResponse{}
+		b.bindings = map[string]bool{}
+		return false
+	}
+	if !role {
+		return true
+	}
+	for _, stored := range b.Roles("acme") {
+		if stored.Name == "admin" {
+			return true
+		}
+	}
+	b.bindings = map[string]bool{}
+	return false
+}
+
+func (b *testBootstrapBackend) serveUsers(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	username := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/tenants/acme/users"), "/")
+	if !b.exists(false) {
+		http.Error(w, `{"error":"tenant not found"}`, http.StatusNotFound)
+		return
+	}
+	switch {
+	case username == "" && r.Method == http.MethodPost:
+		var create userCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		user := b.createUser(create)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(user)
+	case username != "" && r.Method == http.MethodGet:
+		user, ok := b.users[username]
+		if !ok {
+			http.Error(w, `{"error":"user not found"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(user)
+	case username != "" && r.Method == http.MethodDelete:
+		user, ok := b.users[username]
+		if !ok {
+			http.Error(w, `{"error":"user not found"}`, http.StatusNotFound)
+			return
+		}
+		delete(b.users, username)
+		delete(b.bindings, user.ID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+// createUser stores the user create asks for. b.mu must be held.
+func (b *testBootstrapBackend) createUser(create userCreateRequest) userResponse {
+	user := userResponse{ID: "u-" + create.Username, Username: create.Username, Email: create.Email, Enabled: create.Enabled}
+	b.users[create.Username] = user
+	return user
+}
+
+func (b *testBootstrapBackend) serveBindings(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/tenants/acme/roles/admin/bindings"), "/")
+	if !b.exists(true) {
+		http.Error(w, `{"error":"role not found"}`, http.StatusNotFound)
+		return
+	}
+	switch {
+	case key == "" && r.Method == http.MethodPost:
+		if b.bindingStatus != 0 {
+			http.Error(w, `{"error":"binding failed"}`, b.bindingStatus)
+			return
+		}
+		var create roleBindingCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.bindings[create.PrincipalID] = true
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(roleBindingResponse{PrincipalType: create.PrincipalType, PrincipalID: create.PrincipalID})
+	case key != "" && r.Method == http.MethodGet:
+		userID := strings.TrimPrefix(key, "user/")
+		if !b.bindings[userID] {
+			http.Error(w, `{"error":"binding not found"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(roleBindingResponse{PrincipalType: "user", PrincipalID: userID})
+	default:
+		http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+func (b *testBootstrapBackend) serveBootstrap(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var body struct {
+		Tenant    string            `json:"tenant"`
+		AdminRole testserver.Role   `json:"admin_role"`
+		AdminUser userCreateRequest `json:"admin_user"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, ok := b.Tenant(body.Tenant); ok {
+		http.Error(w, `{"error":"tenant already exists"}`, http.StatusConflict)
+		return
+	}
+	tenant := b.CreateTenant(body.Tenant)
+	role := b.PutRole(body.Tenant, body.AdminRole)
+	user := b.createUser(body.AdminUser)
+	b.bindings[user.ID] = true
+
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(tenantBootstrapResponse{TenantID: tenant.ID, AdminRoleID: role.ID, AdminUserID: user.ID})
+}
+
+// writes returns the requests changing something the backend received.
+func (b *testBootstrapBackend) writes() []string {
+	var writes []string
+	for _, request := range b.Requests() {
+		if request.Method != http.MethodGet {
+			writes = append(writes, request.String())
+		}
+	}
+	return writes
+}
+
+func testTenantBootstrapConfig() map[string]tftypes.Value {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	return map[string]tftypes.Value{
+		"tenant":         str("acme"),
+		"admin_scopes":   stringList("tenants:admin"),
+		"admin_username": str("alice"),
+		"admin_email":    str("alice@acme.io"),
+	}
+}
+
+func testTenantBootstrapServer(t *testing.T, backend *testBootstrapBackend) tfprotov6.ProviderServer {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(backend.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+	return server
+}
+
+func TestTenantBootstrapResource(t *testing.T) {
+	for name, tc := range map[string]struct {
+		atomic bool
+		writes []string
+	}{
+		"atomic": {
+			atomic: true,
+			writes: []string{"POST /bootstrap"},
+		},
+		"sequential": {
+			writes: []string{"POST /tenants", "POST /tenants/acme/roles", "POST /tenants/acme/users", "POST /tenants/acme/roles/admin/bindings"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := newTestBootstrapBackend(t, tc.atomic)
+			server := testTenantBootstrapServer(t, backend)
+			config := testTenantBootstrapConfig()
+
+			state := testProviderApply(t, server, "authproxy_tenant_bootstrap", nil, config)
+			if writes := backend.writes(); !reflect.DeepEqual(writes, tc.writes) {
+				t.Errorf("expected the writes %v, got %v", tc.writes, writes)
+			}
+
+			tenant, ok := backend.Tenant("acme")
+			if !ok {
+				t.Fatal("expected the tenant to be created")
+			}
+			roles := backend.Roles("acme")
+			if len(roles) != 1 || roles[0].Name != "admin" || strings.Join(roles[0].Scopes, ",") != "tenants:admin" {
+				t.Fatalf("expected the admin role to be created, got %v", roles)
+			}
+			expected := map[string]string{
+				"id":               tenant.ID,
+				"tenant_id":        tenant.ID,
+				"admin_role":       "admin",
+				"admin_role_id":    roles[0].ID,
+				"admin_user_id":    "u-alice",
+				"admin_binding_id": "acme/admin/user/u-alice",
+			}
+			for attribute, value := range expected {
+				if !state[attribute].Equal(tftypes.NewValue(tftypes.String, value)) {
+					t.Errorf("expected %s to be %q, got %s", attribute, value, state[attribute])
+				}
+			}
+
+			if refreshed := testProviderRefresh(t, server, "authproxy_tenant_bootstrap", state); !reflect.DeepEqual(refreshed, state) {
+				t.Errorf("expected refreshing to change nothing, got %v", refreshed)
+			}
+
+			if state = testProviderApply(t, server, "authproxy_tenant_bootstrap", state, nil); len(state) != 0 {
+				t.Errorf("expected the resource to be removed from state, got %v", state)
+			}
+			if _, ok := backend.Tenant("acme"); ok {
+				t.Error("expected the tenant to be deleted")
+			}
+		})
+	}
+}
+
+func TestTenantBootstrapResource_drift(t *testing.T) {
+	backend := newTestBootstrapBackend(t, false)
+	server := testTenantBootstrapServer(t, backend)
+	config := testTenantBootstrapConfig()
+
+	state := testProviderApply(t, server, "authproxy_tenant_bootstrap", nil, config)
+	roleID := state["admin_role_id"]
+
+	// Deleting the admin user deletes its binding, but leaves the role.
+	backend.mu.Lock()
+	delete(backend.users, "alice")
+	delete(backend.bindings, "u-alice")
+	backend.mu.Unlock()
+
+	state = testProviderRefresh(t, server, "authproxy_tenant_bootstrap", state)
+	if !state["admin_role_id"].Equal(roleID) {
+		t.Errorf("expected the admin role to be kept, got %s", state["admin_role_id"])
+	}
+	for _, attribute := range []string{"admin_user_id", "admin_binding_id"} {
+		if !state[attribute].IsNull() {
+			t.Errorf("expected %s to be null once deleted, got %s", attribute, state[attribute])
+		}
+	}
+
+	resp := testProviderPlan(t, server, "authproxy_tenant_bootstrap", state, config)
+	if len(resp.RequiresReplace) != 0 {
+		t.Errorf("expected the missing parts to be created in place, got replacement for %v", resp.RequiresReplace)
+	}
+	plannedValues := testResourceValues(t, server, "authproxy_tenant_bootstrap", resp.PlannedState)
+	for attribute, known := range map[string]bool{"admin_role_id": true, "admin_user_id": false, "admin_binding_id": false} {
+		if plannedValues[attribute].IsKnown() != known {
+			t.Errorf("expected %s to be known %t in the plan, got %s", attribute, known, plannedValues[attribute])
+		}
+	}
+
+	before := len(backend.writes())
+	state = testProviderApply(t, server, "authproxy_tenant_bootstrap", state, config)
+	expected := []string{"POST /tenants/acme/users", "POST /tenants/acme/roles/admin/bindings"}
+	if writes := backend.writes()[before:]; !reflect.DeepEqual(writes, expected) {
+		t.Errorf("expected only the missing parts to be created, got %v", writes)
+	}
+	if !state["admin_user_id"].Equal(tftypes.NewValue(tftypes.String, "u-alice")) || !state["admin_binding_id"].Equal(tftypes.NewValue(tftypes.String, "acme/admin/user/u-alice")) {
+		t.Errorf("expected the recreated parts in the state, got %v", state)
+	}
+
+	backend.DeleteTenant("acme")
+	if state = testProviderRefresh(t, server, "authproxy_tenant_bootstrap", state); len(state) != 0 {
+		t.Errorf("expected a deleted tenant to be removed from state, got %v", state)
+	}
+}
+
+func TestTenantBootstrapResource_rollback(t *testing.T) {
+	backend := newTestBootstrapBackend(t, false)
+	backend.bindingStatus = http.StatusInternalServerError
+	server := testTenantBootstrapServer(t, backend)
+
+	resp := testProviderApplyResponse(t, server, "authproxy_tenant_bootstrap", nil, testTenantBootstrapConfig())
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Severity != tfprotov6.DiagnosticSeverityError || !strings.Contains(resp.Diagnostics[0].Detail, "bind the admin role to the admin user") {
+		t.Fatalf("expected the failed step to be reported, got %v", resp.Diagnostics)
+	}
+
+	expected := []string{
+		"POST /tenants", "POST /tenants/acme/roles", "POST /tenants/acme/users", "POST /tenants/acme/roles/admin/bindings",
+		"DELETE /tenants/acme/users/alice", "DELETE /tenants/acme/roles/admin", "DELETE /tenants/acme",
+	}
+	if writes := backend.writes(); !reflect.DeepEqual(writes, expected) {
+		t.Errorf("expected what was created to be deleted, newest first, got %v", writes)
+	}
+	if _, ok := backend.Tenant("acme"); ok {
+		t.Error("expected the tenant to be deleted")
+	}
+	if len(backend.users) != 0 {
+		t.Errorf("expected the admin user to be deleted, got %v", backend.users)
+	}
+	if state := testResourceValues(t, server, "authproxy_tenant_bootstrap", resp.NewState); len(state) != 0 {
+		t.Errorf("expected nothing to be stored in the state, got %v", state)
+	}
+}
+
+// TestTenantBootstrapResource_conflict bootstraps tenants with conflicts,
+// only the one of the tenant itself being reported as an existing tenant.
+func TestTenantBootstrapResource_conflict(t *testing.T) {
+	for name, tc := range map[string]struct {
+		atomic  bool
+		binding bool
+		summary string
+		detail  string
+	}{
+		"atomic existing tenant": {
+			atomic:  true,
+			summary: "Tenant Already Exists",
+			detail:  `A tenant named "acme" exists already`,
+		},
+		"sequential existing tenant": {
+			summary: "Tenant Already Exists",
+			detail:  `A tenant named "acme" exists already`,
+		},
+		"sequential conflicting binding": {
+			binding: true,
+			summary: "Conflict",
+			detail:  "bind the admin role to the admin user",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := newTestBootstrapBackend(t, tc.atomic)
+			if tc.binding {
+				backend.bindingStatus = http.StatusConflict
+			} else {
+				backend.CreateTenant("acme")
+			}
+			server := testTenantBootstrapServer(t, backend)
+
+			resp := testProviderApplyResponse(t, server, "authproxy_tenant_bootstrap", nil, testTenantBootstrapConfig())
+			if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != tc.summary || !strings.Contains(resp.Diagnostics[0].Detail, tc.detail) {
+				t.Fatalf("expected a %q error mentioning %q, got %v", tc.summary, tc.detail, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -5,13 +5,14 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -25,10 +26,7 @@ func NewTenantDataSource() datasource.DataSource {
 
 // TenantDataSource defines the data source implementation.
 type TenantDataSource struct {
-	client   *http.Client
-	endpoint string
-	username string
-	password string
+	BaseDataSource
 }
 
 type tenantDataReadResponse struct {
@@ -36,10 +34,17 @@ type tenantDataReadResponse struct {
 	Name string `json:"name"`
 }
 
+type tenantRoleResponse struct {
+	Name string `json:"name"`
+}
+
 // TenantDataSourceModel describes the data source data model.
 type TenantDataSourceModel struct {
-	ID   types.String `tfsdk:"id"`
-	Name types.String `tfsdk:"name"`
+	ID           types.String       `tfsdk:"id"`
+	Name         tenantNameValue    `tfsdk:"name"`
+	IncludeRoles types.Bool         `tfsdk:"include_roles"`
+	RoleNames    types.List         `tfsdk:"role_names"`
+	Timeouts     *ReadTimeoutsModel `tfsdk:"timeouts"`
 }
 
 func (d *TenantDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -53,6 +58,7 @@ func (d *TenantDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
+				CustomType:          tenantNameType{},
 				MarkdownDescription: "Name of the tenant",
 				Optional:            false,
 				Required:            true,
@@ -61,32 +67,22 @@ func (d *TenantDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 				MarkdownDescription: "ID of the tenant",
 				Computed:            true,
 			},
+			"include_roles": schema.BoolAttribute{
+				MarkdownDescription: "Whether to also read the names of the roles of the tenant into `role_names`. Costs additional API calls, defaults to `false`",
+				Optional:            true,
+			},
+			"role_names": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Sorted names of the roles of the tenant, null unless `include_roles` is set",
+				Computed:            true,
+			},
 			// TODO: add created_at
 		},
-	}
-}
 
-func (d *TenantDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
-	if req.ProviderData == nil {
-		return
-	}
-
-	data, ok := req.ProviderData.(*ProviderData)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected ProviderData Source Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
-		return
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
 	}
-
-	d.client = data.client
-	d.endpoint = data.endpoint
-	d.username = data.username
-	d.password = data.password
 }
 
 func (d *TenantDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -99,58 +95,46 @@ func (d *TenantDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider providerData data and make a call using it.
-	// httpResp, err := d.providerData.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
-	//     return
-	// }
-
-	// For the purposes of this example code, hardcoding a response value to
-	// save into the Terraform state.
-
-	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/tenants/%s", d.endpoint, data.Name.ValueString()), nil)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
-		return
-	}
-	tflog.Debug(ctx, "Setting basic auth")
-	request.SetBasicAuth(d.username, d.password)
-	tflog.Debug(ctx, "Making request")
+	// Many modules commonly read the same tenant, share the round trip.
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_tenant", data.Timeouts)
+	defer cancel()
 
-	res, err := d.client.Do(request)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
+	var newTenant tenantDataReadResponse
+	if err := d.doJSON(ctx, "GET", fmt.Sprintf("/tenants/%s", data.Name.ValueString()), nil, &newTenant); err != nil {
+		addReadError(ctx, &resp.Diagnostics, "read tenant", err)
 		return
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != 200 {
-		resBody, err := io.ReadAll(res.Body)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to handle non 200 status code on tenant read, got error: %s", err))
+	data.ID = types.StringValue(newTenant.ID)
+	data.RoleNames = types.ListNull(types.StringType)
+
+	if data.IncludeRoles.ValueBool() {
+		roles, err := listAll[tenantRoleResponse](ctx, d.providerData, fmt.Sprintf("/tenants/%s/roles", url.PathEscape(data.Name.ValueString())), nil)
+		switch {
+		case isStatus(err, http.StatusForbidden):
+			// Credentials scoped to tenants only should not break the read.
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("include_roles"),
+				"Roles Not Readable",
+				fmt.Sprintf("The configured credentials may not list the roles of tenant %q, role_names is left empty.", data.Name.ValueString()),
+			)
+		case err != nil:
+			addReadError(ctx, &resp.Diagnostics, "list tenant roles", err)
 			return
+		default:
+			names := make([]string, 0, len(roles))
+			for _, role := range roles {
+				names = append(names, role.Name)
+			}
+			sort.Strings(names)
+
+			roleNames, diags := types.ListValueFrom(ctx, types.StringType, names)
+			resp.Diagnostics.Append(diags...)
+			data.RoleNames = roleNames
 		}
-		tflog.Error(ctx, "could not read tenant", map[string]interface{}{
-			"body": string(resBody),
-		})
-		return
-	}
-	resBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
-		return
-	}
-	var newTenant tenantDataReadResponse
-	err = json.Unmarshal(resBody, &newTenant)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
-		return
 	}
 
-	data.ID = types.StringValue(newTenant.ID)
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "read a data source")
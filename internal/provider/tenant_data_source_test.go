@@ -4,8 +4,14 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -30,3 +36,93 @@ data "scaffolding_example" "test" {
   configurable_attribute = "example"
 }
 `
+
+// testTenantServer serves the "acme" tenant with roles spread over two pages
+// and the "locked" tenant whose roles the credentials may not list. It counts
+// the role list requests in roleRequests.
+func testTenantServer(t *testing.T, roleRequests *int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants/acme", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"t1","name":"acme"}`)
+	})
+	mux.HandleFunc("/tenants/acme/roles", func(w http.ResponseWriter, r *http.Request) {
+		*roleRequests++
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"items":[{"name":"viewer"},{"name":"admin"}],"next_cursor":"2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"name":"editor"}]}`)
+	})
+	mux.HandleFunc("/tenants/locked", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"t2","name":"locked"}`)
+	})
+	mux.HandleFunc("/tenants/locked/roles", func(w http.ResponseWriter, r *http.Request) {
+		*roleRequests++
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestTenantDataSource_roles(t *testing.T) {
+	testCases := map[string]struct {
+		tenant       string
+		includeRoles interface{}
+		roleNames    []string
+		roleRequests int
+		warnings     int
+	}{
+		"default": {
+			tenant:       "acme",
+			includeRoles: nil,
+		},
+		"include": {
+			tenant:       "acme",
+			includeRoles: true,
+			roleNames:    []string{"admin", "editor", "viewer"},
+			roleRequests: 2,
+		},
+		"forbidden": {
+			tenant:       "locked",
+			includeRoles: true,
+			roleRequests: 1,
+			warnings:     1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var roleRequests int
+			server := testTenantServer(t, &roleRequests)
+			defer server.Close()
+
+			resp := testDataSourceRead(t, NewTenantDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+				"name":          tftypes.NewValue(tftypes.String, testCase.tenant),
+				"include_roles": tftypes.NewValue(tftypes.Bool, testCase.includeRoles),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+			}
+			if resp.Diagnostics.WarningsCount() != testCase.warnings {
+				t.Errorf("expected %d warnings, got: %v", testCase.warnings, resp.Diagnostics)
+			}
+			if roleRequests != testCase.roleRequests {
+				t.Errorf("expected %d role list requests, got %d", testCase.roleRequests, roleRequests)
+			}
+
+			var data TenantDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+			if testCase.roleNames == nil {
+				if !data.RoleNames.IsNull() {
+					t.Errorf("expected null role_names, got %s", data.RoleNames)
+				}
+				return
+			}
+
+			var roleNames []string
+			resp.Diagnostics.Append(data.RoleNames.ElementsAs(context.Background(), &roleNames, false)...)
+			if fmt.Sprint(roleNames) != fmt.Sprint(testCase.roleNames) {
+				t.Errorf("expected role_names %v, got %v", testCase.roleNames, roleNames)
+			}
+		})
+	}
+}
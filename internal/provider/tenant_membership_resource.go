@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/planmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TenantMembershipResource{}
+var _ resource.ResourceWithImportState = &TenantMembershipResource{}
+
+func NewTenantMembershipResource() resource.Resource {
+	return &TenantMembershipResource{}
+}
+
+// TenantMembershipResource defines the resource implementation.
+type TenantMembershipResource struct {
+	BaseResource
+}
+
+// TenantMembershipResourceModel describes the resource data model.
+type TenantMembershipResourceModel struct {
+	ID       types.String    `tfsdk:"id"`
+	Tenant   tenantNameValue `tfsdk:"tenant"`
+	UserID   types.String    `tfsdk:"user_id"`
+	BaseRole types.String    `tfsdk:"base_role"`
+}
+
+type tenantMemberCreateRequest struct {
+	UserID   string `json:"user_id"`
+	BaseRole string `json:"base_role"`
+}
+
+type tenantMemberUpdateRequest struct {
+	BaseRole string `json:"base_role"`
+}
+
+type tenantMemberResponse struct {
+	UserID   string `json:"user_id"`
+	BaseRole string `json:"base_role"`
+}
+
+// tenantMembersPath returns the path of the members collection of tenant.
+func tenantMembersPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/members", url.PathEscape(tenant))
+}
+
+// tenantMemberPath returns the path of the membership of userID in tenant.
+func tenantMemberPath(tenant string, userID string) string {
+	return tenantMembersPath(tenant) + "/" + url.PathEscape(userID)
+}
+
+func (r *TenantMembershipResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tenant_membership"
+}
+
+func (r *TenantMembershipResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Makes a user a member of a tenant with a base role. Membership is independent of the role bindings granted within the tenant",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the membership of the form `tenant/user_id`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the user becomes a member of. Changing it recreates the membership",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot update memberships, adding the user to another tenant creates a new membership"),
+				},
+			},
+			"user_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the member. Changing it recreates the membership",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot update memberships, adding another user to the tenant creates a new membership"),
+				},
+			},
+			"base_role": schema.StringAttribute{
+				MarkdownDescription: "Role the user holds in the tenant by virtue of being a member",
+				Required:            true,
+			},
+		},
+	}
+}
+
+// setMember copies the attributes authproxy returned for a membership into
+// data.
+func (data *TenantMembershipResourceModel) setMember(member tenantMemberResponse) {
+	data.ID = types.StringValue(data.Tenant.ValueString() + "/" + member.UserID)
+	data.UserID = types.StringValue(member.UserID)
+	data.BaseRole = types.StringValue(member.BaseRole)
+}
+
+func (r *TenantMembershipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *TenantMembershipResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var member tenantMemberResponse
+	err := r.doJSON(ctx, "POST", tenantMembersPath(data.Tenant.ValueString()), tenantMemberCreateRequest{
+		UserID:   data.UserID.ValueString(),
+		BaseRole: data.BaseRole.ValueString(),
+	}, &member)
+	if isStatus(err, http.StatusConflict) {
+		resp.Diagnostics.AddError(
+			"Already a Tenant Member",
+			fmt.Sprintf("The user %q is already a member of tenant %q. Import the existing membership instead of creating it:\n\n"+
+				"terraform import authproxy_tenant_membership.<name> %s/%s",
+				data.UserID.ValueString(), data.Tenant.ValueString(), data.Tenant.ValueString(), data.UserID.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "add tenant member", err)
+		return
+	}
+
+	data.setMember(member)
+
+	tflog.Trace(ctx, "created a tenant membership resource", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TenantMembershipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *TenantMembershipResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var member tenantMemberResponse
+	err := r.doJSON(ctx, "GET", tenantMemberPath(data.Tenant.ValueString(), data.UserID.ValueString()), nil, &member)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant membership no longer exists, removing it from state", map[string]interface{}{
+			"tenant":  data.Tenant.ValueString(),
+			"user_id": data.UserID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read tenant member", err)
+		return
+	}
+
+	data.setMember(member)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TenantMembershipResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *TenantMembershipResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var member tenantMemberResponse
+	err := r.doJSON(ctx, "PATCH", tenantMemberPath(data.Tenant.ValueString(), data.UserID.ValueString()), tenantMemberUpdateRequest{
+		BaseRole: data.BaseRole.ValueString(),
+	}, &member)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update tenant member", err)
+		return
+	}
+
+	data.setMember(member)
+
+	tflog.Trace(ctx, "updated a tenant membership resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TenantMembershipResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *TenantMembershipResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", tenantMemberPath(data.Tenant.ValueString(), data.UserID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "remove tenant member", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a tenant membership resource")
+}
+
+func (r *TenantMembershipResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, userID, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || userID == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/user_id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_id"), userID)...)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccTenantMemberBackend is an in-memory stand-in for the members
+// endpoints of the "acme" tenant, mapping user IDs to base roles.
+type testAccTenantMemberBackend struct {
+	mu      sync.Mutex
+	members map[string]string
+}
+
+func (b *testAccTenantMemberBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prefix := tenantMembersPath("acme")
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	userID := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case userID == "" && r.Method == http.MethodPost:
+		var create tenantMemberCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := b.members[create.UserID]; ok {
+			http.Error(w, "user is already a member", http.StatusConflict)
+			return
+		}
+		b.members[create.UserID] = create.BaseRole
+		_ = json.NewEncoder(w).Encode(tenantMemberResponse{UserID: create.UserID, BaseRole: create.BaseRole})
+	case userID != "" && r.Method == http.MethodGet:
+		baseRole, ok := b.members[userID]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(tenantMemberResponse{UserID: userID, BaseRole: baseRole})
+	case userID != "" && r.Method == http.MethodPatch:
+		if _, ok := b.members[userID]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var update tenantMemberUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b.members[userID] = update.BaseRole
+		_ = json.NewEncoder(w).Encode(tenantMemberResponse{UserID: userID, BaseRole: update.BaseRole})
+	case userID != "" && r.Method == http.MethodDelete:
+		if _, ok := b.members[userID]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.members, userID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// testAccCheckTenantMember checks the base role of userID in backend, an
+// empty baseRole expecting the user not to be a member.
+func testAccCheckTenantMember(backend *testAccTenantMemberBackend, userID string, baseRole string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		backend.mu.Lock()
+		defer backend.mu.Unlock()
+		if actual := backend.members[userID]; actual != baseRole {
+			return fmt.Errorf("expected base role %q of member %s, got %q", baseRole, userID, actual)
+		}
+		return nil
+	}
+}
+
+func TestAccTenantMembershipResource(t *testing.T) {
+	backend := &testAccTenantMemberBackend{members: map[string]string{"u0": "owner"}}
+	server := testBackendServer(t, backend, tenantMembersPath("acme"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckTenantMember(backend, "u0", "owner"),
+			testAccCheckTenantMember(backend, "u1", ""),
+		),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccTenantMembershipResourceConfig("u1", "member"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_tenant_membership.test", "id", "acme/u1"),
+					resource.TestCheckResourceAttr("authproxy_tenant_membership.test", "base_role", "member"),
+					testAccCheckTenantMember(backend, "u1", "member"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_tenant_membership.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccTenantMembershipResourceConfig("u1", "admin"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_tenant_membership.test", "id", "acme/u1"),
+					resource.TestCheckResourceAttr("authproxy_tenant_membership.test", "base_role", "admin"),
+					testAccCheckTenantMember(backend, "u1", "admin"),
+				),
+			},
+			// Removal outside of Terraform is detected and repaired
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					delete(backend.members, "u1")
+				},
+				Config: testAccProviderConfig(server.URL) + testAccTenantMembershipResourceConfig("u1", "admin"),
+				Check:  testAccCheckTenantMember(backend, "u1", "admin"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccTenantMembershipResource_alreadyMember(t *testing.T) {
+	backend := &testAccTenantMemberBackend{members: map[string]string{"u0": "owner"}}
+	server := testBackendServer(t, backend, tenantMembersPath("acme"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccTenantMembershipResourceConfig("u0", "member"),
+				ExpectError: regexp.MustCompile(`terraform import authproxy_tenant_membership.<name> acme/u0`),
+			},
+		},
+	})
+}
+
+func testAccTenantMembershipResourceConfig(userID string, baseRole string) string {
+	return fmt.Sprintf(`
+resource "authproxy_tenant_membership" "test" {
+  tenant    = "acme"
+  user_id   = %q
+  base_role = %q
+}
+`, userID, baseRole)
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/tenantname"
+	"github.com/4thel00z/terraform-provider-authproxy/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// canonicalTenantName returns name the way authproxy stores it, trimmed and
+// lowercased, "Acme" becoming "acme".
+func canonicalTenantName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+var _ basetypes.StringTypable = tenantNameType{}
+var _ xattr.TypeWithValidate = tenantNameType{}
+
+// tenantNameType is a string type holding the name of a tenant. authproxy
+// lowercases tenant names, so names that only differ in case are
+// semantically equal and configuring "Acme" does not cause drift against
+// the "acme" authproxy answers with.
+type tenantNameType struct {
+	basetypes.StringType
+}
+
+func (t tenantNameType) Equal(o attr.Type) bool {
+	other, ok := o.(tenantNameType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t tenantNameType) String() string {
+	return "tenantNameType"
+}
+
+func (t tenantNameType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return tenantNameValue{StringValue: in}, nil
+}
+
+func (t tenantNameType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return tenantNameValue{StringValue: stringValue}, nil
+}
+
+func (t tenantNameType) ValueType(ctx context.Context) attr.Value {
+	return tenantNameValue{}
+}
+
+// Validate rejects values that are not tenant names in any case, failing
+// the plan rather than the apply.
+func (t tenantNameType) Validate(ctx context.Context, in tftypes.Value, path path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !in.IsKnown() || in.IsNull() {
+		return diags
+	}
+
+	var name string
+	if err := in.As(&name); err != nil {
+		diags.AddAttributeError(
+			path,
+			"Invalid Tenant Name",
+			fmt.Sprintf("Unable to convert the value to a string: %s", err),
+		)
+		return diags
+	}
+
+	if !validators.IsTenantName(strings.ToLower(name)) {
+		diags.AddAttributeError(
+			path,
+			"Invalid Tenant Name",
+			fmt.Sprintf("Attribute %s must be a tenant name of at most %d letters, digits and hyphens such as \"acme-eu\", got: %q", path, tenantname.MaxLength, name),
+		)
+	}
+
+	return diags
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = tenantNameValue{}
+
+// tenantNameValue is the value of a tenantNameType attribute.
+type tenantNameValue struct {
+	basetypes.StringValue
+}
+
+// tenantNameStringValue returns a known tenantNameValue holding name.
+func tenantNameStringValue(name string) tenantNameValue {
+	return tenantNameValue{StringValue: basetypes.NewStringValue(name)}
+}
+
+// tenantNameNull returns a null tenantNameValue.
+func tenantNameNull() tenantNameValue {
+	return tenantNameValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v tenantNameValue) Equal(o attr.Value) bool {
+	other, ok := o.(tenantNameValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v tenantNameValue) Type(ctx context.Context) attr.Type {
+	return tenantNameType{}
+}
+
+// StringSemanticEquals reports whether both values name the same tenant,
+// ignoring case and surrounding whitespace.
+func (v tenantNameValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(tenantNameValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	return canonicalTenantName(v.ValueString()) == canonicalTenantName(newValue.ValueString()), diags
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestTenantNameValue_semanticEquals(t *testing.T) {
+	cases := map[string]struct {
+		prior    string
+		proposed string
+		equal    bool
+	}{
+		"identical":  {"acme", "acme", true},
+		"case":       {"Acme", "acme", true},
+		"whitespace": {" acme ", "acme", true},
+		"tenant":     {"acme", "lidl", false},
+		"hyphen":     {"acme-eu", "acmeeu", false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			equal, diags := tenantNameStringValue(c.prior).StringSemanticEquals(context.Background(), tenantNameStringValue(c.proposed))
+			if diags.HasError() {
+				t.Fatalf("unexpected error diagnostics: %v", diags)
+			}
+			if equal != c.equal {
+				t.Errorf("expected semantic equality %t, got %t", c.equal, equal)
+			}
+		})
+	}
+}
+
+func TestTenantNameType_validate(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"acme":    true,
+		"Acme":    true,
+		"ACME-EU": true,
+		"":        false,
+		" acme":   false,
+		"acme_eu": false,
+		"-acme":   false,
+		"café":    false,
+	} {
+		diags := tenantNameType{}.Validate(context.Background(), tftypes.NewValue(tftypes.String, name), path.Root("tenant"))
+		if diags.HasError() == valid {
+			t.Errorf("expected %q to be valid=%t, got diagnostics: %v", name, valid, diags)
+		}
+	}
+}
+
+func TestResourcePlan_tenantNameCase(t *testing.T) {
+	backend := testserver.New(t)
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(backend.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	t.Run("tenant", func(t *testing.T) {
+		config := map[string]tftypes.Value{"name": str("Acme")}
+		state := testProviderApply(t, server, "authproxy_tenant", nil, config)
+		if _, ok := backend.Tenant("acme"); !ok {
+			t.Fatalf("expected authproxy to store the tenant as acme, got %v", backend.Tenants())
+		}
+
+		// authproxy answers with "acme", which is kept as configured.
+		state = testProviderRefresh(t, server, "authproxy_tenant", state)
+		if !state["name"].Equal(str("Acme")) {
+			t.Errorf("expected the configured name to be kept, got %s", state["name"])
+		}
+		resp := testProviderPlan(t, server, "authproxy_tenant", state, config)
+		if planned := testResourceValues(t, server, "authproxy_tenant", resp.PlannedState); !planned["name"].Equal(state["name"]) || !planned["id"].Equal(state["id"]) {
+			t.Errorf("expected no changes, got %v", planned)
+		}
+
+		// Renaming the tenant is still planned.
+		resp = testProviderPlan(t, server, "authproxy_tenant", state, map[string]tftypes.Value{"name": str("lidl")})
+		if planned := testResourceValues(t, server, "authproxy_tenant", resp.PlannedState); !planned["name"].Equal(str("lidl")) {
+			t.Errorf("expected the rename to be planned, got %v", planned)
+		}
+	})
+
+	t.Run("role", func(t *testing.T) {
+		config := map[string]tftypes.Value{
+			"tenant": str("ACME"),
+			"name":   str("viewer"),
+			"scopes": stringList("billing:read"),
+		}
+		state := testProviderApply(t, server, "authproxy_role", nil, config)
+		state = testProviderRefresh(t, server, "authproxy_role", state)
+		if !state["tenant"].Equal(str("ACME")) {
+			t.Errorf("expected the configured tenant to be kept, got %s", state["tenant"])
+		}
+		resp := testProviderPlan(t, server, "authproxy_role", state, config)
+		if len(resp.RequiresReplace) != 0 || len(resp.Diagnostics) != 0 {
+			t.Errorf("expected no replacement and no diagnostics, got %v and %v", resp.RequiresReplace, resp.Diagnostics)
+		}
+
+		// Imported roles hold the tenant as authproxy answers with it, which
+		// only differs from the configuration in case.
+		state["tenant"] = str("acme")
+		resp = testProviderPlan(t, server, "authproxy_role", state, config)
+		if len(resp.RequiresReplace) != 0 || len(resp.Diagnostics) != 0 {
+			t.Errorf("expected no replacement and no diagnostics, got %v and %v", resp.RequiresReplace, resp.Diagnostics)
+		}
+
+		config["tenant"] = str("lidl")
+		resp = testProviderPlan(t, server, "authproxy_role", state, config)
+		if len(resp.RequiresReplace) != 1 {
+			t.Errorf("expected moving the role to another tenant to replace it, got %v", resp.RequiresReplace)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tenantStatusActive is the status of tenants that can be used. Deployments
+// provisioning tenants asynchronously report them as "provisioning" first,
+// older authproxy builds report no status at all.
+const tenantStatusActive = "active"
+
+const (
+	// defaultTenantCreateTimeout bounds creating a tenant, waiting for it
+	// to become active included, unless timeouts.create is configured.
+	defaultTenantCreateTimeout = 5 * time.Minute
+
+	// The reads of a provisioning tenant start tenantReadyMinInterval apart
+	// and back off up to tenantReadyMaxInterval.
+	tenantReadyMinInterval = 100 * time.Millisecond
+	tenantReadyMaxInterval = 5 * time.Second
+)
+
+// tenantReady reports whether a tenant of status can be used.
+func tenantReady(status string) bool {
+	return status == "" || status == tenantStatusActive
+}
+
+// tenantStatusValue is the status attribute of a tenant of status, null for
+// authproxy builds not reporting one.
+func tenantStatusValue(status string) types.String {
+	if status == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(status)
+}
+
+// waitForReady reads the tenant named name until it is ready, backing off
+// between the reads, and returns the last tenant read. It fails once ctx is
+// done, so the caller must bound it with a deadline.
+func (p *ProviderData) waitForReady(ctx context.Context, name string) (readResponse, error) {
+	var tenant readResponse
+	interval := tenantReadyMinInterval
+	for {
+		if err := p.doObjectJSON(ctx, "GET", fmt.Sprintf("/tenants/%s", name), nil, &tenant, "id", "name"); err != nil {
+			return tenant, err
+		}
+		if tenantReady(tenant.Status) {
+			return tenant, nil
+		}
+		tflog.Debug(ctx, "waiting for the tenant to become active", map[string]interface{}{
+			"name":   name,
+			"status": tenant.Status,
+		})
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return tenant, ctx.Err()
+		case <-timer.C:
+		}
+		if interval *= 2; interval > tenantReadyMaxInterval {
+			interval = tenantReadyMaxInterval
+		}
+	}
+}
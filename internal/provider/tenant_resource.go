@@ -4,24 +4,30 @@
 package provider
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/4thel00z/terraform-provider-authproxy/internal/validators"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"io"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &TenantResource{}
 var _ resource.ResourceWithImportState = &TenantResource{}
+var _ resource.ResourceWithUpgradeState = &TenantResource{}
+var _ resource.ResourceWithModifyPlan = &TenantResource{}
 
 func NewTenantResource() resource.Resource {
 	return &TenantResource{}
@@ -29,14 +35,69 @@ func NewTenantResource() resource.Resource {
 
 // TenantResource defines the resource implementation.
 type TenantResource struct {
-	providerData *ProviderData
-	name         string
+	BaseResource
 }
 
 // TenantResourceModel describes the resource data model.
 type TenantResourceModel struct {
-	Name types.String `tfsdk:"name"`
-	ID   types.String `tfsdk:"id"`
+	Name          tenantNameValue      `tfsdk:"name"`
+	ID            types.String         `tfsdk:"id"`
+	Contact       *TenantContactModel  `tfsdk:"contact"`
+	AdoptExisting types.Bool           `tfsdk:"adopt_existing"`
+	OnDestroy     types.String         `tfsdk:"on_destroy"`
+	WaitForReady  types.Bool           `tfsdk:"wait_for_ready"`
+	Status        types.String         `tfsdk:"status"`
+	Timeouts      *CreateTimeoutsModel `tfsdk:"timeouts"`
+}
+
+// TenantContactModel describes the contact of a tenant.
+type TenantContactModel struct {
+	Email types.String `tfsdk:"email"`
+	Name  types.String `tfsdk:"name"`
+	Phone types.String `tfsdk:"phone"`
+}
+
+// request returns the contact as sent to authproxy. Fields that are not
+// known yet are left to authproxy by omitting them.
+func (m *TenantContactModel) request() *tenantContact {
+	contact := &tenantContact{}
+	if !m.Email.IsUnknown() {
+		contact.Email = m.Email.ValueStringPointer()
+	}
+	if !m.Name.IsUnknown() {
+		contact.Name = m.Name.ValueStringPointer()
+	}
+	if !m.Phone.IsUnknown() {
+		contact.Phone = m.Phone.ValueStringPointer()
+	}
+	return contact
+}
+
+// tenantContactModel returns the model of the contact authproxy answered
+// with, fields it does not set being empty.
+func tenantContactModel(contact *tenantContact) *TenantContactModel {
+	if contact == nil {
+		contact = &tenantContact{}
+	}
+	return &TenantContactModel{
+		Email: types.StringValue(stringValue(contact.Email)),
+		Name:  types.StringValue(stringValue(contact.Name)),
+		Phone: types.StringValue(stringValue(contact.Phone)),
+	}
+}
+
+// hasContact reports whether contact has any field set. Authproxy answers
+// with an empty contact for tenants whose contact was cleared, which counts
+// as none.
+func hasContact(contact *tenantContact) bool {
+	return contact != nil && *contact != (tenantContact{})
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
 }
 
 func (r *TenantResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -47,9 +108,11 @@ func (r *TenantResource) Schema(ctx context.Context, req resource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Tenant resource",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
+				CustomType:          tenantNameType{},
 				MarkdownDescription: "Name of the tenant",
 				Optional:            false,
 				Required:            true,
@@ -67,50 +130,139 @@ func (r *TenantResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Whether creating the tenant adopts a tenant of the same name that already exists, as long as it matches the configuration, rather than failing. Eases bringing tenants created by hand under management without importing each of them. Defaults to `false`",
+				Optional:            true,
+			},
+			"on_destroy": onDestroyAttribute("tenant"),
+			"wait_for_ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether creating the tenant waits until authproxy reports it `active`, for deployments provisioning tenants asynchronously in which roles cannot be created right away. Bounded by `timeouts.create`. Defaults to `true`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The provisioning status of the tenant, `provisioning` or `active`. Null for authproxy builds not reporting one",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"contact": schema.SingleNestedAttribute{
+				MarkdownDescription: "Contact of the tenant. Fields left out keep the value authproxy has, removing the attribute clears the contact",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"email": schema.StringAttribute{
+						MarkdownDescription: "Email address of the contact",
+						Optional:            true,
+						Computed:            true,
+						Validators: []validator.String{
+							validators.Email(),
+						},
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Name of the contact",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"phone": schema.StringAttribute{
+						MarkdownDescription: "Phone number of the contact",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": createTimeoutsBlock(defaultTenantCreateTimeout),
 		},
 	}
 }
 
-func (r *TenantResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
-	if req.ProviderData == nil {
+// ModifyPlan submits planned tenants to authproxy for validation when
+// plan_validation is set, so policy violations surface before apply.
+func (r *TenantResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate when the tenant is destroyed or left unchanged.
+	if req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) || r.providerData == nil || !r.providerData.planValidation.enabled {
 		return
 	}
 
-	data, ok := req.ProviderData.(*ProviderData)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
+	// Contacts only known during apply cannot be decoded into the model.
+	var contact types.Object
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("contact"), &contact)...)
+	if resp.Diagnostics.HasError() || !plannedKnown(ctx, contact) {
+		return
+	}
+	var plan *TenantResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plannedKnown(ctx, plan.Name) {
 		return
 	}
 
-	r.providerData = data
+	operation := "update"
+	if req.State.Raw.IsNull() {
+		operation = "create"
+	}
+	object := createRequest{Name: plan.Name.ValueString()}
+	if plan.Contact != nil {
+		object.Contact = plan.Contact.request()
+	}
+	r.providerData.validatePlanned(ctx, &resp.Diagnostics, operation, "tenant", "", object, map[string]path.Path{
+		"tenant":        path.Root("name"),
+		"contact":       path.Root("contact"),
+		"contact.email": path.Root("contact").AtName("email"),
+		"contact.name":  path.Root("contact").AtName("name"),
+		"contact.phone": path.Root("contact").AtName("phone"),
+	})
+}
+
+// tenantContact is the contact of a tenant in the API. Fields omitted from
+// updates are left unchanged.
+type tenantContact struct {
+	Email *string `json:"email,omitempty"`
+	Name  *string `json:"name,omitempty"`
+	Phone *string `json:"phone,omitempty"`
 }
 
 type createRequest struct {
-	Name string `json:"tenant"`
+	Name    string         `json:"tenant"`
+	Contact *tenantContact `json:"contact,omitempty"`
 }
 
 type createResponse struct {
-	ID string `json:"id"`
+	ID      string         `json:"id"`
+	Status  string         `json:"status"`
+	Contact *tenantContact `json:"contact"`
 }
 
+// updateRequest renames a tenant. Contact is omitted to leave the contact
+// unchanged and null to clear it.
 type updateRequest struct {
-	Name    string `json:"tenant"`
-	NewName string `json:"new_tenant"`
+	Name    string          `json:"tenant"`
+	NewName string          `json:"new_tenant"`
+	Contact json.RawMessage `json:"contact,omitempty"`
 }
 
 type updateResponse struct {
-	ID string `json:"id"`
+	ID      string         `json:"id"`
+	Name    string         `json:"name"`
+	Contact *tenantContact `json:"contact"`
 }
 
 type readResponse struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID      string         `json:"id"`
+	Name    string         `json:"name"`
+	Status  string         `json:"status"`
+	Contact *tenantContact `json:"contact"`
 }
 
 type deleteResponse struct {
@@ -127,66 +279,48 @@ func (r *TenantResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider providerData data and make a call using it.
-	// httpResp, err := r.providerData.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create example, got error: %s", err))
-	//     return
-	// }
+	timeout := createTimeout(data.Timeouts, defaultTenantCreateTimeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	// For the purposes of this example code, hardcoding a response value to
-	// save into the Terraform state.
-	marshalled, err := json.Marshal(createRequest{Name: data.Name.ValueString()})
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create tenant, got error: %s", err))
-		return
-	}
-	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/tenants", r.providerData.endpoint), bytes.NewReader(marshalled))
-
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create tenant, tenantdata: %#v got error: %s", r.providerData, err))
-		return
-	}
-	tflog.Debug(ctx, "Setting basic auth")
-	request.SetBasicAuth(r.providerData.username, r.providerData.password)
-	tflog.Debug(ctx, "Making request")
-
-	res, err := r.providerData.client.Do(request)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create tenant, got error: %s", err))
-		return
+	body := createRequest{Name: data.Name.ValueString()}
+	if data.Contact != nil {
+		body.Contact = data.Contact.request()
 	}
-	defer res.Body.Close()
-
-	if res.StatusCode != 200 {
-		resBody, err := io.ReadAll(res.Body)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to handle non 200 status code on tenant creation, got error: %s", err))
+	var cr createResponse
+	err := r.doObjectJSON(ctx, "POST", "/tenants", body, &cr, "id")
+	if isStatus(err, http.StatusConflict) {
+		if data.AdoptExisting.ValueBool() {
+			r.adopt(ctx, data, timeout, resp)
 			return
 		}
-		tflog.Error(ctx, "could not create tenant", map[string]interface{}{
-			"body": string(resBody),
-		})
-		return
-	}
-	resBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create tenant, got error: %s", err))
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Tenant Already Exists",
+			fmt.Sprintf("A tenant named %q exists already. Import it instead of creating it:\n\n"+
+				"terraform import authproxy_tenant.<name> %s\n\n"+
+				"or set adopt_existing = true to adopt it when it matches the configuration.", data.Name.ValueString(), data.Name.ValueString()),
+		)
 		return
 	}
-	var cr createResponse
-	err = json.Unmarshal(resBody, &cr)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create tenant, got error: %s", err))
+		addClientError(&resp.Diagnostics, "create tenant", err)
 		return
 	}
 
 	data.ID = types.StringValue(cr.ID)
+	data.Status = tenantStatusValue(cr.Status)
+	if data.Contact != nil {
+		data.Contact = tenantContactModel(cr.Contact)
+	}
+
+	if !r.awaitReady(ctx, data, cr.Status, "created", timeout, resp) {
+		return
+	}
 
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
-	tflog.Debug(ctx, "created a tenant resource, response was", map[string]interface{}{
+	tflog.Trace(ctx, "created a tenant resource", map[string]interface{}{
 		"id": cr.ID,
 	})
 
@@ -194,64 +328,149 @@ func (r *TenantResource) Create(ctx context.Context, req resource.CreateRequest,
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (r *TenantResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data *TenantResourceModel
+// awaitReady waits for the tenant in data, whose status is status, to become
+// ready unless wait_for_ready is off, and reports whether Create may go on.
+// A tenant not ready in time is kept in the state, so Terraform replaces it
+// rather than losing track of it. action tells how the tenant came to be
+// managed, such as "created", for the error.
+func (r *TenantResource) awaitReady(ctx context.Context, data *TenantResourceModel, status string, action string, timeout time.Duration, resp *resource.CreateResponse) bool {
+	// Roles cannot be created in tenants still provisioning, so dependent
+	// resources only work on the first apply once the tenant is active.
+	if !data.WaitForReady.ValueBool() || tenantReady(status) {
+		return true
+	}
 
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	tenant, err := r.providerData.waitForReady(ctx, data.Name.ValueString())
+	data.Status = tenantStatusValue(tenant.Status)
+	if err == nil {
+		return true
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		addClientError(&resp.Diagnostics, "read tenant", err)
+		return false
+	}
+	resp.Diagnostics.AddError(
+		"Tenant Not Ready",
+		fmt.Sprintf("The tenant %q was %s but authproxy still reported it as %q after %s. "+
+			"Raise timeouts.create if provisioning tenants takes longer in this deployment, or set wait_for_ready = false to not wait for it.", data.Name.ValueString(), action, tenant.Status, timeout),
+	)
+	return false
+}
 
-	if resp.Diagnostics.HasError() {
+// adopt takes the existing tenant planned in data into the state, failing
+// with the differences when it does not match the plan. Like a created
+// tenant, the adopted one is waited for until it is ready.
+func (r *TenantResource) adopt(ctx context.Context, data *TenantResourceModel, timeout time.Duration, resp *resource.CreateResponse) {
+	var existing readResponse
+	if err := r.doObjectJSON(ctx, "GET", fmt.Sprintf("/tenants/%s", data.Name.ValueString()), nil, &existing, "id", "name"); err != nil {
+		addClientError(&resp.Diagnostics, "read existing tenant", err)
 		return
 	}
 
-	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/tenants/%s", r.providerData.endpoint, data.Name.ValueString()), nil)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
+	if differences := data.adoptionDifferences(existing); len(differences) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("adopt_existing"),
+			"Existing Tenant Differs",
+			fmt.Sprintf("The tenant %q exists already but does not match the configuration, so it was not adopted:\n\n%s\n\n"+
+				"Change the configuration or the tenant so they match.", data.Name.ValueString(), strings.Join(differences, "\n")),
+		)
 		return
 	}
-	tflog.Debug(ctx, "Setting basic auth")
-	request.SetBasicAuth(r.providerData.username, r.providerData.password)
-	tflog.Debug(ctx, "Making request")
 
-	res, err := r.providerData.client.Do(request)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
+	data.ID = types.StringValue(existing.ID)
+	data.Status = tenantStatusValue(existing.Status)
+	if data.Contact != nil {
+		data.Contact = tenantContactModel(existing.Contact)
+	}
+	if !r.awaitReady(ctx, data, existing.Status, "adopted", timeout, resp) {
 		return
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != 200 {
-		resBody, err := io.ReadAll(res.Body)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to handle non 200 status code on tenant read, got error: %s", err))
-			return
+	tflog.Info(ctx, "adopted an existing tenant", map[string]interface{}{
+		"id":   existing.ID,
+		"name": data.Name.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// adoptionDifferences lists the planned attributes of data the existing
+// tenant does not match, one line per attribute. Attributes left to
+// authproxy match any value.
+func (data *TenantResourceModel) adoptionDifferences(existing readResponse) []string {
+	var differences []string
+	if data.Contact == nil {
+		if hasContact(existing.Contact) {
+			differences = append(differences, "  contact: configured none, authproxy has one")
 		}
-		tflog.Error(ctx, "could not read tenant", map[string]interface{}{
-			"body": string(resBody),
-		})
-		return
+		return differences
 	}
-	resBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
+
+	contact := existing.Contact
+	if contact == nil {
+		contact = &tenantContact{}
+	}
+	for _, field := range []struct {
+		name     string
+		planned  types.String
+		existing *string
+	}{
+		{name: "email", planned: data.Contact.Email, existing: contact.Email},
+		{name: "name", planned: data.Contact.Name, existing: contact.Name},
+		{name: "phone", planned: data.Contact.Phone, existing: contact.Phone},
+	} {
+		if field.planned.IsUnknown() || field.planned.ValueString() == stringValue(field.existing) {
+			continue
+		}
+		differences = append(differences, fmt.Sprintf("  contact.%s: configured %q, authproxy has %q", field.name, field.planned.ValueString(), stringValue(field.existing)))
+	}
+	return differences
+}
+
+// setTenant copies the attributes authproxy returned for a tenant into data.
+// An empty contact is no contact unless data has one: a configured contact
+// may have none of its fields set.
+func (data *TenantResourceModel) setTenant(tenant readResponse) {
+	data.ID = types.StringValue(tenant.ID)
+	data.Name = tenantNameStringValue(tenant.Name)
+	data.Status = tenantStatusValue(tenant.Status)
+	if hasContact(tenant.Contact) || (data.Contact != nil && tenant.Contact != nil) {
+		data.Contact = tenantContactModel(tenant.Contact)
+	} else {
+		data.Contact = nil
+	}
+}
+
+func (r *TenantResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *TenantResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
+
 	var newTenant readResponse
-	err = json.Unmarshal(resBody, &newTenant)
+	err := r.doObjectJSON(ctx, "GET", fmt.Sprintf("/tenants/%s", data.Name.ValueString()), nil, &newTenant, "id", "name")
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant no longer exists, removing it from state", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read tenant", err)
 		return
 	}
-	data.ID = types.StringValue(newTenant.ID)
-
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider providerData data and make a call using it.
-	// httpResp, err := r.providerData.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
-	//     return
-	// }
+	data.setTenant(newTenant)
+	data.OnDestroy = onDestroyValue(data.OnDestroy)
+	if data.WaitForReady.IsNull() {
+		data.WaitForReady = types.BoolValue(true)
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -270,59 +489,45 @@ func (r *TenantResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider providerData data and make a call using it.
-	// httpResp, err := r.providerData.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update example, got error: %s", err))
-	//     return
-	// }
-
-	marshalled, err := json.Marshal(updateRequest{Name: old.Name.ValueString(), NewName: data.Name.ValueString()})
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
-		return
+	body := updateRequest{Name: old.Name.ValueString(), NewName: data.Name.ValueString()}
+	switch {
+	case data.Contact != nil:
+		contact, err := json.Marshal(data.Contact.request())
+		if err != nil {
+			addClientError(&resp.Diagnostics, "update tenant", err)
+			return
+		}
+		body.Contact = contact
+	case old.Contact != nil:
+		body.Contact = json.RawMessage("null")
 	}
-	request, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/tenants", r.providerData.endpoint), bytes.NewReader(marshalled))
-
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
+	var cr updateResponse
+	if err := r.doObjectJSON(ctx, "PATCH", "/tenants", body, &cr, "id"); err != nil {
+		addClientError(&resp.Diagnostics, "update tenant", err)
 		return
 	}
-	tflog.Debug(ctx, "Setting basic auth")
-	request.SetBasicAuth(r.providerData.username, r.providerData.password)
-	tflog.Debug(ctx, "Making request")
-
-	res, err := r.providerData.client.Do(request)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
-		return
+	data.ID = types.StringValue(cr.ID)
+	if data.Contact != nil {
+		data.Contact = tenantContactModel(cr.Contact)
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != 200 {
-		resBody, err := io.ReadAll(res.Body)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to handle non 200 status code on tenant update, got error: %s", err))
-			return
-		}
-		tflog.Error(ctx, "could not update tenant", map[string]interface{}{
-			"body": string(resBody),
-		})
-		return
+	// Reading the tenant back reveals changes older authproxy builds
+	// dropped silently. It is read by the name authproxy answered with,
+	// which is still the old one when the rename was dropped.
+	name := cr.Name
+	if name == "" {
+		name = data.Name.ValueString()
 	}
-	resBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
+	var tenant readResponse
+	if err := r.doObjectJSON(ctx, "GET", fmt.Sprintf("/tenants/%s", name), nil, &tenant, "id", "name"); err != nil {
+		addClientError(&resp.Diagnostics, "read tenant", err)
 		return
 	}
-	var cr updateResponse
-	err = json.Unmarshal(resBody, &cr)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update tenant, got error: %s", err))
+	read := *data
+	read.setTenant(tenant)
+	if !checkUpdateApplied(ctx, req, resp, "tenant", &read) {
 		return
 	}
-	data.ID = types.StringValue(cr.ID)
 
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
@@ -341,58 +546,73 @@ func (r *TenantResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider providerData data and make a call using it.
-	// httpResp, err := r.providerData.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete example, got error: %s", err))
-	//     return
-	// }
-
-	request, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/tenants/%s", r.providerData.endpoint, r.name), nil)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read tenant, got error: %s", err))
+	if abandon(ctx, data.OnDestroy, "tenant", map[string]interface{}{"name": data.Name.ValueString()}) {
 		return
 	}
-	tflog.Debug(ctx, "Setting basic auth")
-	request.SetBasicAuth(r.providerData.username, r.providerData.password)
-	tflog.Debug(ctx, "Making request")
 
-	res, err := r.providerData.client.Do(request)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete tenant, got error: %s", err))
+	// authproxy answers with the deleted tenant. Tenants that are gone
+	// already need no deleting.
+	var deleted readResponse
+	err := r.doJSON(ctx, "DELETE", fmt.Sprintf("/tenants/%s", data.Name.ValueString()), nil, &deleted)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete tenant", err)
 		return
 	}
-	defer res.Body.Close()
-
-	if res.StatusCode != 200 {
-		resBody, err := io.ReadAll(res.Body)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to handle non 200 status code on tenant deletion, got error: %s", err))
-			return
-		}
-		tflog.Error(ctx, "could not delete tenant", map[string]interface{}{
-			"body": string(resBody),
-		})
-		return
-	}
-	resBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete tenant, got error: %s", err))
-		return
-	}
-	var newTenant readResponse
-	err = json.Unmarshal(resBody, &newTenant)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete tenant, got error: %s", err))
-		return
-	}
-	data.ID = types.StringValue(newTenant.ID)
-	data.Name = types.StringValue(newTenant.Name)
 
+	tflog.Trace(ctx, "deleted a tenant resource", map[string]interface{}{
+		"id": deleted.ID,
+	})
 }
 
 func (r *TenantResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
+
+// UpgradeState migrates state from version 0, which kept the tenant contact
+// in flat owner_email, owner_name and owner_phone attributes if at all. The
+// raw state is decoded as JSON so either form upgrades.
+func (r *TenantResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior struct {
+					ID         *string `json:"id"`
+					Name       *string `json:"name"`
+					OwnerEmail *string `json:"owner_email"`
+					OwnerName  *string `json:"owner_name"`
+					OwnerPhone *string `json:"owner_phone"`
+				}
+				if req.RawState == nil || req.RawState.JSON == nil {
+					resp.Diagnostics.AddError(
+						"Unable to Upgrade Resource State",
+						"The tenant state of version 0 is not stored as JSON. Please report this issue to the provider developers.",
+					)
+					return
+				}
+				if err := json.Unmarshal(req.RawState.JSON, &prior); err != nil {
+					resp.Diagnostics.AddError(
+						"Unable to Upgrade Resource State",
+						fmt.Sprintf("Unable to decode the tenant state of version 0, got error: %s", err),
+					)
+					return
+				}
+
+				upgraded := TenantResourceModel{
+					ID:           types.StringPointerValue(prior.ID),
+					Name:         tenantNameValue{StringValue: types.StringPointerValue(prior.Name)},
+					OnDestroy:    types.StringValue(onDestroyDelete),
+					WaitForReady: types.BoolValue(true),
+				}
+				if stringValue(prior.OwnerEmail) != "" || stringValue(prior.OwnerName) != "" || stringValue(prior.OwnerPhone) != "" {
+					upgraded.Contact = tenantContactModel(&tenantContact{
+						Email: prior.OwnerEmail,
+						Name:  prior.OwnerName,
+						Phone: prior.OwnerPhone,
+					})
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+			},
+		},
+	}
 }
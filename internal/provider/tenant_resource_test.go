@@ -4,46 +4,721 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestTenantResource(t *testing.T) {
+	server := testserver.New(t)
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckDestroyed(server.URL, "authproxy_tenant", func(attributes map[string]string) string {
+				return "/tenants/" + url.PathEscape(attributes["name"])
+			}),
+			func(s *terraform.State) error {
+				if tenants := server.Tenants(); len(tenants) != 0 {
+					return fmt.Errorf("expected every tenant to be deleted, got %v", tenants)
+				}
+				return nil
+			},
+		),
 		Steps: []resource.TestStep{
 			// Create and Read testing
 			{
-				Config: tenantResourceExampleConfig("lidl"),
+				Config: testAccProviderConfig(server.URL) + tenantResourceExampleConfig("lidl"),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("tenant.test", "name", "lidl"),
+					resource.TestCheckResourceAttr("authproxy_tenant.test", "name", "lidl"),
+					resource.TestCheckResourceAttrSet("authproxy_tenant.test", "id"),
 				),
 			},
 			// ImportState testing
 			{
-				ResourceName:      "tenant.test",
+				ResourceName:      "authproxy_tenant.test",
 				ImportState:       true,
+				ImportStateId:     "lidl",
 				ImportStateVerify: true,
 			},
 			// Update and Read testing
 			{
-				Config: tenantResourceExampleConfig("lidl"),
+				Config: testAccProviderConfig(server.URL) + tenantResourceExampleConfig("lidl-eu"),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("tenant.test", "name", "lidl"),
+					resource.TestCheckResourceAttr("authproxy_tenant.test", "name", "lidl-eu"),
+					func(s *terraform.State) error {
+						tenant, ok := server.Tenant("lidl-eu")
+						if !ok || tenant.ID != s.RootModule().Resources["authproxy_tenant.test"].Primary.ID {
+							return fmt.Errorf("expected the tenant to be renamed in authproxy, got %v", server.Tenants())
+						}
+						return nil
+					},
 				),
 			},
+			// A tenant deleted outside of Terraform is created again
+			{
+				PreConfig: func() {
+					server.DeleteTenant("lidl-eu")
+				},
+				Config: testAccProviderConfig(server.URL) + tenantResourceExampleConfig("lidl-eu"),
+				Check: func(s *terraform.State) error {
+					if _, ok := server.Tenant("lidl-eu"); !ok {
+						return fmt.Errorf("expected the tenant to be created again, got %v", server.Tenants())
+					}
+					return nil
+				},
+			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
 }
 
+func TestTenantResource_contact(t *testing.T) {
+	server := testserver.New(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_tenant" "test" {
+  name = "acme"
+  contact = {
+    email = "ops@acme.example"
+    name  = "Ops"
+    phone = "+1 555 0100"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_tenant.test", "contact.email", "ops@acme.example"),
+					resource.TestCheckResourceAttr("authproxy_tenant.test", "contact.name", "Ops"),
+					resource.TestCheckResourceAttr("authproxy_tenant.test", "contact.phone", "+1 555 0100"),
+				),
+			},
+			// Fields left out keep their value.
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_tenant" "test" {
+  name = "acme"
+  contact = {
+    email = "noc@acme.example"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_tenant.test", "contact.email", "noc@acme.example"),
+					resource.TestCheckResourceAttr("authproxy_tenant.test", "contact.name", "Ops"),
+					resource.TestCheckResourceAttr("authproxy_tenant.test", "contact.phone", "+1 555 0100"),
+				),
+			},
+			// Removing the contact clears it.
+			{
+				Config: testAccProviderConfig(server.URL) + tenantResourceExampleConfig("acme"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("authproxy_tenant.test", "contact.email"),
+					func(s *terraform.State) error {
+						if tenant, _ := server.Tenant("acme"); tenant.Contact != nil {
+							return fmt.Errorf("expected the contact to be cleared in authproxy, got %+v", tenant.Contact)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestTenantResource_contactLifecycle(t *testing.T) {
+	backend := testserver.New(t)
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	null := tftypes.NewValue(tftypes.String, nil)
+	contact := func(email, name, phone tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"email": tftypes.String,
+			"name":  tftypes.String,
+			"phone": tftypes.String,
+		}}, map[string]tftypes.Value{"email": email, "name": name, "phone": phone})
+	}
+	backendContact := func() testserver.Contact {
+		t.Helper()
+		tenant, ok := backend.Tenant("acme")
+		if !ok || tenant.Contact == nil {
+			t.Fatalf("expected acme to have a contact, got %v", backend.Tenants())
+		}
+		return *tenant.Contact
+	}
+
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(backend.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	config := map[string]tftypes.Value{"name": str("acme"), "contact": contact(str("ops@acme.example"), str("Ops"), null)}
+	state := testProviderApply(t, server, "authproxy_tenant", nil, config)
+	if got := backendContact(); got != (testserver.Contact{Email: "ops@acme.example", Name: "Ops"}) {
+		t.Errorf("expected the contact to be created, got %+v", got)
+	}
+	if expected := contact(str("ops@acme.example"), str("Ops"), str("")); !state["contact"].Equal(expected) {
+		t.Errorf("expected the contact %s in state, got %s", expected, state["contact"])
+	}
+
+	// Fields left out of the configuration keep their value.
+	config["contact"] = contact(str("noc@acme.example"), null, null)
+	state = testProviderApply(t, server, "authproxy_tenant", state, config)
+	if got := backendContact(); got != (testserver.Contact{Email: "noc@acme.example", Name: "Ops"}) {
+		t.Errorf("expected only the email to change, got %+v", got)
+	}
+	resp := testProviderPlan(t, server, "authproxy_tenant", state, config)
+	if planned := testResourceValues(t, server, "authproxy_tenant", resp.PlannedState); !planned["contact"].Equal(state["contact"]) {
+		t.Errorf("expected no changes, got %s", planned["contact"])
+	}
+
+	// Changes made outside of Terraform are refreshed.
+	backend.SetContact("acme", &testserver.Contact{Email: "noc@acme.example", Phone: "+1 555 0100"})
+	state = testProviderRefresh(t, server, "authproxy_tenant", state)
+	if expected := contact(str("noc@acme.example"), str(""), str("+1 555 0100")); !state["contact"].Equal(expected) {
+		t.Errorf("expected the contact %s in state, got %s", expected, state["contact"])
+	}
+
+	delete(config, "contact")
+	state = testProviderApply(t, server, "authproxy_tenant", state, config)
+	if tenant, _ := backend.Tenant("acme"); tenant.Contact != nil {
+		t.Errorf("expected the contact to be cleared, got %+v", tenant.Contact)
+	}
+	if !state["contact"].IsNull() {
+		t.Errorf("expected no contact in state, got %s", state["contact"])
+	}
+}
+
+func TestTenantResource_adoptExisting(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	null := tftypes.NewValue(tftypes.String, nil)
+	contact := func(email, name, phone tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"email": tftypes.String,
+			"name":  tftypes.String,
+			"phone": tftypes.String,
+		}}, map[string]tftypes.Value{"email": email, "name": name, "phone": phone})
+	}
+	adopt := tftypes.NewValue(tftypes.Bool, true)
+
+	for name, tc := range map[string]struct {
+		config  map[string]tftypes.Value
+		summary string
+		details []string
+	}{
+		"default": {
+			config:  map[string]tftypes.Value{"name": str("acme")},
+			summary: "Tenant Already Exists",
+			details: []string{"terraform import authproxy_tenant.<name> acme", "adopt_existing = true"},
+		},
+		"adoption": {
+			config: map[string]tftypes.Value{"name": str("acme"), "adopt_existing": adopt, "contact": contact(str("ops@acme.example"), null, null)},
+		},
+		"differing contact": {
+			config:  map[string]tftypes.Value{"name": str("acme"), "adopt_existing": adopt, "contact": contact(str("noc@acme.example"), str("Ops"), str("+1 555 0199"))},
+			summary: "Existing Tenant Differs",
+			details: []string{`contact.email: configured "noc@acme.example", authproxy has "ops@acme.example"`, `contact.phone: configured "+1 555 0199", authproxy has "+1 555 0100"`},
+		},
+		"missing contact": {
+			config:  map[string]tftypes.Value{"name": str("acme"), "adopt_existing": adopt},
+			summary: "Existing Tenant Differs",
+			details: []string{"contact: configured none, authproxy has one"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := testserver.New(t)
+			existing := backend.CreateTenant("acme")
+			backend.SetContact("acme", &testserver.Contact{Email: "ops@acme.example", Name: "Ops", Phone: "+1 555 0100"})
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint": str(backend.URL),
+				"username": str(testserver.Username),
+				"password": str(testserver.Password),
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			resp := testProviderApplyResponse(t, server, "authproxy_tenant", nil, tc.config)
+			if tc.summary != "" {
+				if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != tc.summary {
+					t.Fatalf("expected a %q error, got %v", tc.summary, resp.Diagnostics)
+				}
+				for _, detail := range tc.details {
+					if !strings.Contains(resp.Diagnostics[0].Detail, detail) {
+						t.Errorf("expected the error to contain %q, got %q", detail, resp.Diagnostics[0].Detail)
+					}
+				}
+				if tenant, _ := backend.Tenant("acme"); tenant.Contact == nil || tenant.Contact.Email != "ops@acme.example" {
+					t.Errorf("expected the existing tenant to be left alone, got %+v", tenant)
+				}
+				return
+			}
+
+			if len(resp.Diagnostics) != 0 {
+				t.Fatalf("unexpected apply diagnostics: %v", resp.Diagnostics)
+			}
+			state := testResourceValues(t, server, "authproxy_tenant", resp.NewState)
+			if !state["id"].Equal(str(existing.ID)) {
+				t.Errorf("expected the ID %s of the existing tenant, got %s", existing.ID, state["id"])
+			}
+			if expected := contact(str("ops@acme.example"), str("Ops"), str("+1 555 0100")); !state["contact"].Equal(expected) {
+				t.Errorf("expected the contact %s in state, got %s", expected, state["contact"])
+			}
+
+			// The adopted tenant is managed like a created one.
+			planResp := testProviderPlan(t, server, "authproxy_tenant", testProviderRefresh(t, server, "authproxy_tenant", state), tc.config)
+			if len(planResp.RequiresReplace) != 0 || !testResourceValues(t, server, "authproxy_tenant", planResp.PlannedState)["contact"].Equal(state["contact"]) {
+				t.Errorf("expected no changes after adopting the tenant, got %v", planResp)
+			}
+		})
+	}
+}
+
+// TestTenantResource_adoptProvisioning adopts a tenant that is still
+// provisioning, which is waited for like a created one.
+func TestTenantResource_adoptProvisioning(t *testing.T) {
+	backend := testserver.New(t)
+	backend.SetProvisioningReads(3)
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(backend.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	// Another configuration created the tenant without waiting for it.
+	testProviderApply(t, server, "authproxy_tenant", nil, map[string]tftypes.Value{"name": str("acme"), "wait_for_ready": tftypes.NewValue(tftypes.Bool, false)})
+
+	resp := testProviderApplyResponse(t, server, "authproxy_tenant", nil, map[string]tftypes.Value{"name": str("acme"), "adopt_existing": tftypes.NewValue(tftypes.Bool, true)})
+	if len(resp.Diagnostics) != 0 {
+		t.Fatalf("unexpected apply diagnostics: %v", resp.Diagnostics)
+	}
+	if state := testResourceValues(t, server, "authproxy_tenant", resp.NewState); !state["status"].Equal(str(testserver.TenantActive)) {
+		t.Errorf("expected the adopted tenant to be waited for until active, got %s", state["status"])
+	}
+}
+
+// TestTenantResource_emptyContact reads tenants for which authproxy answers
+// with an empty contact, which is no contact unless one is configured.
+func TestTenantResource_emptyContact(t *testing.T) {
+	backend := testserver.New(t)
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	null := tftypes.NewValue(tftypes.String, nil)
+	contact := func(email tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"email": tftypes.String,
+			"name":  tftypes.String,
+			"phone": tftypes.String,
+		}}, map[string]tftypes.Value{"email": email, "name": null, "phone": null})
+	}
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(backend.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	for name, config := range map[string]map[string]tftypes.Value{
+		"no contact": {"name": str("acme")},
+		"contact":    {"name": str("acme"), "contact": contact(null)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend.DeleteTenant("acme")
+			state := testProviderApply(t, server, "authproxy_tenant", nil, config)
+			backend.SetContact("acme", &testserver.Contact{})
+
+			state = testProviderRefresh(t, server, "authproxy_tenant", state)
+			if state["contact"].IsNull() != config["contact"].IsNull() {
+				t.Errorf("expected a contact in state only when one is configured, got %s", state["contact"])
+			}
+			planResp := testProviderPlan(t, server, "authproxy_tenant", state, config)
+			if planned := testResourceValues(t, server, "authproxy_tenant", planResp.PlannedState); !planned["contact"].Equal(state["contact"]) {
+				t.Errorf("expected no changes to the contact, planned %s", planned["contact"])
+			}
+		})
+	}
+}
+
+func TestTenantResource_upgradeState(t *testing.T) {
+	server := providerserver.NewProtocol6(New("test")())()
+	// Like Terraform, load the schemas before upgrading any state.
+	if _, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		prior    string
+		expected map[string]interface{}
+	}{
+		"name only": {
+			prior: `{"id":"3f0c8e52-6a1d-4c8e-9b7a-0d2e4f6a8b1c","name":"acme"}`,
+			expected: map[string]interface{}{
+				"id":             "3f0c8e52-6a1d-4c8e-9b7a-0d2e4f6a8b1c",
+				"name":           "acme",
+				"contact":        nil,
+				"adopt_existing": nil,
+				"on_destroy":     "delete",
+				"wait_for_ready": true,
+				"status":         nil,
+				"timeouts":       nil,
+			},
+		},
+		"flat contact": {
+			prior: `{"id":"3f0c8e52-6a1d-4c8e-9b7a-0d2e4f6a8b1c","name":"acme","owner_email":"ops@acme.example","owner_name":"Ops","owner_phone":null}`,
+			expected: map[string]interface{}{
+				"id":             "3f0c8e52-6a1d-4c8e-9b7a-0d2e4f6a8b1c",
+				"name":           "acme",
+				"contact":        map[string]interface{}{"email": "ops@acme.example", "name": "Ops", "phone": ""},
+				"adopt_existing": nil,
+				"on_destroy":     "delete",
+				"wait_for_ready": true,
+				"status":         nil,
+				"timeouts":       nil,
+			},
+		},
+		"empty flat contact": {
+			prior: `{"id":"3f0c8e52-6a1d-4c8e-9b7a-0d2e4f6a8b1c","name":"acme","owner_email":"","owner_name":null}`,
+			expected: map[string]interface{}{
+				"id":             "3f0c8e52-6a1d-4c8e-9b7a-0d2e4f6a8b1c",
+				"name":           "acme",
+				"contact":        nil,
+				"adopt_existing": nil,
+				"on_destroy":     "delete",
+				"wait_for_ready": true,
+				"status":         nil,
+				"timeouts":       nil,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := server.UpgradeResourceState(context.Background(), &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "authproxy_tenant",
+				Version:  0,
+				RawState: &tfprotov6.RawState{JSON: []byte(tc.prior)},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Diagnostics) != 0 {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			upgraded := testResourceValues(t, server, "authproxy_tenant", resp.UpgradedState)
+			got := make(map[string]interface{}, len(upgraded))
+			for attribute, value := range upgraded {
+				got[attribute] = contractValue(t, value)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
 func tenantResourceExampleConfig(name string) string {
 	return fmt.Sprintf(`
-resource "tenant" "test" {
+resource "authproxy_tenant" "test" {
   name = %[1]q
 }
 `, name)
 }
+
+// TestTenantResource_onDestroy destroys tenants in either mode, switching the
+// mode of created tenants in place first.
+func TestTenantResource_onDestroy(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+
+	for name, tc := range map[string]struct {
+		onDestroy tftypes.Value
+		deleted   bool
+	}{
+		"default": {onDestroy: tftypes.NewValue(tftypes.String, nil), deleted: true},
+		"delete":  {onDestroy: str("delete"), deleted: true},
+		"abandon": {onDestroy: str("abandon")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := testserver.New(t)
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint": str(backend.URL),
+				"username": str(testserver.Username),
+				"password": str(testserver.Password),
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			config := map[string]tftypes.Value{"name": str("acme")}
+			state := testProviderApply(t, server, "authproxy_tenant", nil, config)
+			if !state["on_destroy"].Equal(str("delete")) {
+				t.Errorf("expected on_destroy to default to delete, got %s", state["on_destroy"])
+			}
+
+			config["on_destroy"] = tc.onDestroy
+			if resp := testProviderPlan(t, server, "authproxy_tenant", state, config); len(resp.RequiresReplace) != 0 {
+				t.Errorf("expected on_destroy to change in place, got replacement for %v", resp.RequiresReplace)
+			}
+			state = testProviderApply(t, server, "authproxy_tenant", state, config)
+
+			if state = testProviderApply(t, server, "authproxy_tenant", state, nil); len(state) != 0 {
+				t.Errorf("expected the tenant to be removed from state, got %v", state)
+			}
+			deletes := 0
+			for _, request := range backend.Requests() {
+				if request.Method == http.MethodDelete {
+					deletes++
+				}
+			}
+			_, exists := backend.Tenant("acme")
+			if tc.deleted {
+				if deletes != 1 || exists {
+					t.Errorf("expected the tenant to be deleted, got %d DELETE requests", deletes)
+				}
+				return
+			}
+			if deletes != 0 || !exists {
+				t.Errorf("expected the tenant to be abandoned, got %d DELETE requests", deletes)
+			}
+		})
+	}
+}
+
+// TestTenantResource_deleteGone destroys a tenant deleted outside of
+// Terraform since it was last refreshed.
+func TestTenantResource_deleteGone(t *testing.T) {
+	backend := testserver.New(t)
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(backend.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	state := testProviderApply(t, server, "authproxy_tenant", nil, map[string]tftypes.Value{"name": str("acme")})
+	backend.DeleteTenant("acme")
+
+	if resp := testProviderApplyResponse(t, server, "authproxy_tenant", state, nil); len(resp.Diagnostics) != 0 {
+		t.Fatalf("expected destroying a tenant that is gone to succeed, got %v", resp.Diagnostics)
+	}
+}
+
+// TestTenantResource_waitForReady creates tenants on a server provisioning
+// them asynchronously, in which roles can only be created once the tenant is
+// active.
+func TestTenantResource_waitForReady(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	timeouts := func(create string) tftypes.Value {
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"create": tftypes.String}}, map[string]tftypes.Value{"create": str(create)})
+	}
+
+	for name, tc := range map[string]struct {
+		reads   int
+		config  map[string]tftypes.Value
+		status  string
+		waited  bool
+		problem string
+	}{
+		"provisioning": {
+			reads:  2,
+			config: map[string]tftypes.Value{"name": str("acme")},
+			status: "active",
+			waited: true,
+		},
+		"synchronous": {
+			config: map[string]tftypes.Value{"name": str("acme")},
+			status: "active",
+		},
+		"not waiting": {
+			reads:  2,
+			config: map[string]tftypes.Value{"name": str("acme"), "wait_for_ready": tftypes.NewValue(tftypes.Bool, false)},
+			status: "provisioning",
+		},
+		"never ready": {
+			reads:   -1,
+			config:  map[string]tftypes.Value{"name": str("acme"), "timeouts": timeouts("1s")},
+			status:  "provisioning",
+			waited:  true,
+			problem: `authproxy still reported it as "provisioning" after 1s`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := testserver.New(t)
+			backend.SetProvisioningReads(tc.reads)
+			server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+				"endpoint": str(backend.URL),
+				"username": str(testserver.Username),
+				"password": str(testserver.Password),
+			})
+			if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			resp := testProviderApplyResponse(t, server, "authproxy_tenant", nil, tc.config)
+			state := testResourceValues(t, server, "authproxy_tenant", resp.NewState)
+			if tc.problem != "" {
+				if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Tenant Not Ready" || !strings.Contains(resp.Diagnostics[0].Detail, tc.problem) {
+					t.Fatalf("expected a Tenant Not Ready error mentioning %q, got %v", tc.problem, resp.Diagnostics)
+				}
+			} else if len(resp.Diagnostics) != 0 {
+				t.Fatalf("unexpected apply diagnostics: %v", resp.Diagnostics)
+			}
+
+			// The created tenant is kept in the state even when it did not
+			// become ready in time.
+			if tenant, _ := backend.Tenant("acme"); !state["id"].Equal(str(tenant.ID)) || !state["status"].Equal(str(tc.status)) {
+				t.Errorf("expected the tenant %s with status %s in state, got %v", tenant.ID, tc.status, state)
+			}
+			reads := 0
+			for _, request := range backend.Requests() {
+				if request.Method == http.MethodGet && request.Path == "/tenants/acme" {
+					reads++
+				}
+			}
+			if waited := reads > 0; waited != tc.waited {
+				t.Errorf("expected waiting for the tenant to be %t, got %d reads", tc.waited, reads)
+			}
+			if tc.problem != "" || !tc.waited {
+				return
+			}
+
+			// Roles of the tenant work on the same apply.
+			testProviderApply(t, server, "authproxy_role", nil, map[string]tftypes.Value{"tenant": str("acme"), "name": str("viewer"), "permissions": stringList("billing:read")})
+		})
+	}
+}
+
+// TestTenantResource_ignoredUpdate updates tenants on a server which accepts
+// changes of their contact only to drop them, like older authproxy builds.
+func TestTenantResource_ignoredUpdate(t *testing.T) {
+	backend := testserver.New(t)
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	null := tftypes.NewValue(tftypes.String, nil)
+	contact := func(email tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"email": tftypes.String,
+			"name":  tftypes.String,
+			"phone": tftypes.String,
+		}}, map[string]tftypes.Value{"email": email, "name": null, "phone": null})
+	}
+
+	server, diags := testProviderConfigure(t, map[string]tftypes.Value{
+		"endpoint": str(backend.URL),
+		"username": str(testserver.Username),
+		"password": str(testserver.Password),
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected configure diagnostics: %v", diags)
+	}
+
+	config := map[string]tftypes.Value{"name": str("acme"), "contact": contact(str("ops@acme.example"))}
+	state := testProviderApply(t, server, "authproxy_tenant", nil, config)
+
+	// Renames are applied, the contact is dropped.
+	backend.DropUpdates("contact")
+	config = map[string]tftypes.Value{"name": str("Lidl"), "contact": contact(str("ops@lidl.example"))}
+	resp := testProviderApplyResponse(t, server, "authproxy_tenant", state, config)
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Update Ignored by Authproxy" {
+		t.Fatalf("expected an ignored update error, got %v", resp.Diagnostics)
+	}
+	if detail := resp.Diagnostics[0].Detail; !strings.Contains(detail, "ignored the changes to contact, which") {
+		t.Errorf("expected the error to only name the contact, got %q", detail)
+	}
+	newState := testResourceValues(t, server, "authproxy_tenant", resp.NewState)
+	if !newState["name"].Equal(str("lidl")) || !newState["contact"].Equal(state["contact"]) {
+		t.Errorf("expected the renamed tenant with its old contact in state, got %v", newState)
+	}
+}
+
+func TestTenantResource_invalidResponses(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	unknown := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	state := map[string]tftypes.Value{"id": str("t1"), "name": str("acme")}
+
+	for name, tc := range map[string]struct {
+		body      string
+		operation string
+		prior     map[string]tftypes.Value
+		planned   map[string]tftypes.Value
+		problem   string
+	}{
+		"create with an empty object": {
+			body:      `{}`,
+			operation: "create",
+			planned:   map[string]tftypes.Value{"id": unknown, "name": str("acme")},
+			problem:   `lacks the "id" attribute`,
+		},
+		"create behind a login page": {
+			body:      `<html><body>Sign in</body></html>`,
+			operation: "create",
+			planned:   map[string]tftypes.Value{"id": unknown, "name": str("acme")},
+			problem:   "is not a JSON object",
+		},
+		"read with a wrapped tenant": {
+			body:      `{"tenant": {"id": "t1", "name": "acme"}}`,
+			operation: "read",
+			prior:     state,
+			problem:   `lacks the "id" attribute`,
+		},
+		"read without a name": {
+			body:      `{"id": "t1"}`,
+			operation: "read",
+			prior:     state,
+			problem:   `lacks the "name" attribute`,
+		},
+		"update with an empty object": {
+			body:      `{}`,
+			operation: "update",
+			prior:     state,
+			planned:   map[string]tftypes.Value{"id": str("t1"), "name": str("acme-eu")},
+			problem:   `lacks the "id" attribute`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			newState, diags := contractOperation(t, NewTenantResource(), testProviderData(server.URL), tc.operation, tc.prior, tc.planned)
+			if diags.ErrorsCount() != 1 || diags[0].Summary() != "Unexpected Authproxy Response" {
+				t.Fatalf("expected an unexpected response error, got %v", diags)
+			}
+			detail := diags[0].Detail()
+			for _, expected := range []string{tc.problem, strconv.Quote(tc.body), "endpoint may be misconfigured"} {
+				if !strings.Contains(detail, expected) {
+					t.Errorf("expected the detail to contain %q, got %q", expected, detail)
+				}
+			}
+			if tc.operation == "create" && !newState.IsNull() {
+				t.Errorf("expected nothing to be stored, got %s", newState)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	tenantSearchSingle = "single"
+	tenantSearchAll    = "all"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TenantSearchDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &TenantSearchDataSource{}
+
+func NewTenantSearchDataSource() datasource.DataSource {
+	return &TenantSearchDataSource{}
+}
+
+// TenantSearchDataSource defines the data source implementation.
+type TenantSearchDataSource struct {
+	BaseDataSource
+}
+
+// TenantSearchDataSourceModel describes the data source data model.
+type TenantSearchDataSourceModel struct {
+	Prefix            types.String              `tfsdk:"prefix"`
+	Regex             types.String              `tfsdk:"regex"`
+	MatchMode         types.String              `tfsdk:"match_mode"`
+	PageSize          types.Int64               `tfsdk:"page_size"`
+	IncludeRoleCounts types.Bool                `tfsdk:"include_role_counts"`
+	Tenant            *TenantSearchTenantModel  `tfsdk:"tenant"`
+	Tenants           []TenantSearchTenantModel `tfsdk:"tenants"`
+	Timeouts          *ReadTimeoutsModel        `tfsdk:"timeouts"`
+}
+
+// TenantSearchTenantModel describes a single matching tenant.
+type TenantSearchTenantModel struct {
+	ID        types.String    `tfsdk:"id"`
+	Name      tenantNameValue `tfsdk:"name"`
+	RoleCount types.Int64     `tfsdk:"role_count"`
+}
+
+func (d *TenantSearchDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tenant_search"
+}
+
+func (d *TenantSearchDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	tenantAttributes := map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			MarkdownDescription: "ID of the tenant",
+			Computed:            true,
+		},
+		"name": schema.StringAttribute{
+			CustomType:          tenantNameType{},
+			MarkdownDescription: "Name of the tenant",
+			Computed:            true,
+		},
+		"role_count": schema.Int64Attribute{
+			MarkdownDescription: "Number of roles of the tenant, null unless `include_role_counts` is set",
+			Computed:            true,
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Finds tenants by name prefix or regular expression",
+
+		Attributes: map[string]schema.Attribute{
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "Prefix the tenant names have to start with. Exactly one of `prefix` and `regex` must be set",
+				Optional:            true,
+			},
+			"regex": schema.StringAttribute{
+				MarkdownDescription: "[RE2](https://github.com/google/re2/wiki/Syntax) regular expression the tenant names have to match. Exactly one of `prefix` and `regex` must be set",
+				Optional:            true,
+			},
+			"match_mode": schema.StringAttribute{
+				MarkdownDescription: "`\"single\"` (default) fails unless exactly one tenant matches and sets `tenant`, `\"all\"` sets `tenants` to every match",
+				Optional:            true,
+				Validators: []validator.String{
+					stringOneOf(tenantSearchSingle, tenantSearchAll),
+				},
+			},
+			"page_size": pageSizeAttribute(),
+			"include_role_counts": schema.BoolAttribute{
+				MarkdownDescription: "Whether to set the `role_count` of the matching tenants, costing a request per tenant. The provider option `max_concurrent_requests` bounds how many of them run at once. Defaults to `false`",
+				Optional:            true,
+			},
+			"tenant": schema.SingleNestedAttribute{
+				MarkdownDescription: "The matching tenant in `\"single\"` mode",
+				Computed:            true,
+				Attributes:          tenantAttributes,
+			},
+			"tenants": schema.ListNestedAttribute{
+				MarkdownDescription: "The matching tenants sorted by name in `\"all\"` mode",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: tenantAttributes,
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *TenantSearchDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data TenantSearchDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values that are not known yet are validated once they are.
+	if data.Prefix.IsUnknown() || data.Regex.IsUnknown() {
+		return
+	}
+
+	if data.Prefix.IsNull() == data.Regex.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("prefix"),
+			"Invalid Attribute Combination",
+			"Exactly one of `prefix` and `regex` must be set.",
+		)
+		return
+	}
+
+	if !data.Regex.IsNull() {
+		if _, err := regexp.Compile(data.Regex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("regex"),
+				"Invalid Regular Expression",
+				fmt.Sprintf("The regex %q does not compile: %s", data.Regex.ValueString(), err),
+			)
+		}
+	}
+}
+
+func (d *TenantSearchDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TenantSearchDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_tenant_search", data.Timeouts)
+	defer cancel()
+
+	matches := func(name string) bool {
+		return strings.HasPrefix(name, data.Prefix.ValueString())
+	}
+	criterion := fmt.Sprintf("prefix %q", data.Prefix.ValueString())
+	if !data.Regex.IsNull() {
+		// The expression was validated by ValidateConfig already.
+		expression := regexp.MustCompile(data.Regex.ValueString())
+		matches = expression.MatchString
+		criterion = fmt.Sprintf("regex %q", data.Regex.ValueString())
+	}
+
+	tenants, _, err := listPages[tenantDataReadResponse](ctx, d.providerData, "/tenants", nil, listOptionsFrom(data.PageSize, types.Int64Null()))
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "list tenants", err)
+		return
+	}
+
+	found := make([]TenantSearchTenantModel, 0)
+	for _, tenant := range tenants {
+		if !matches(tenant.Name) {
+			continue
+		}
+		found = append(found, TenantSearchTenantModel{
+			ID:        types.StringValue(tenant.ID),
+			Name:      tenantNameStringValue(tenant.Name),
+			RoleCount: types.Int64Null(),
+		})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Name.ValueString() < found[j].Name.ValueString()
+	})
+
+	// Ambiguous and empty single searches fail below, so there is nothing to
+	// count for them.
+	if data.IncludeRoleCounts.ValueBool() && (data.MatchMode.ValueString() == tenantSearchAll || len(found) == 1) {
+		if err := d.countRoles(ctx, found); err != nil {
+			addReadError(ctx, &resp.Diagnostics, "count the roles of tenants", err)
+			return
+		}
+	}
+
+	if data.MatchMode.ValueString() == tenantSearchAll {
+		data.Tenants = found
+	} else {
+		switch len(found) {
+		case 0:
+			resp.Diagnostics.AddError(
+				"Tenant Not Found",
+				fmt.Sprintf("No tenant name matches the %s. Existing tenants: %s. Use match_mode = %q to allow empty results.", criterion, tenantCandidates(tenants), tenantSearchAll),
+			)
+			return
+		case 1:
+			data.Tenant = &found[0]
+		default:
+			names := make([]string, 0, len(found))
+			for _, tenant := range found {
+				names = append(names, fmt.Sprintf("%s (%s)", tenant.Name.ValueString(), tenant.ID.ValueString()))
+			}
+			resp.Diagnostics.AddError(
+				"Ambiguous Tenant Search",
+				fmt.Sprintf("The %s matches %d tenants: %s. Narrow the search or use match_mode = %q.", criterion, len(found), strings.Join(names, ", "), tenantSearchAll),
+			)
+			return
+		}
+	}
+
+	tflog.Trace(ctx, "read tenant search data source", map[string]interface{}{
+		"count": len(found),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// countRoles sets the RoleCount of every tenant, listing the roles of the
+// tenants concurrently. The error names every tenant whose roles could not be
+// listed.
+func (d *TenantSearchDataSource) countRoles(ctx context.Context, tenants []TenantSearchTenantModel) error {
+	return d.providerData.forEachConcurrently(ctx, len(tenants), func(ctx context.Context, i int) error {
+		name := tenants[i].Name.ValueString()
+		roles, err := listAll[roleResponse](ctx, d.providerData, tenantRolesPath(name), nil)
+		if err != nil {
+			return fmt.Errorf("tenant %q: %w", name, err)
+		}
+		tenants[i].RoleCount = types.Int64Value(int64(len(roles)))
+		return nil
+	})
+}
+
+// tenantSearchMaxCandidates caps the number of tenants listed when nothing
+// matched a search.
+const tenantSearchMaxCandidates = 10
+
+// tenantCandidates lists the sorted names of tenants, abbreviated after
+// tenantSearchMaxCandidates entries.
+func tenantCandidates(tenants []tenantDataReadResponse) string {
+	if len(tenants) == 0 {
+		return "none"
+	}
+
+	names := make([]string, 0, len(tenants))
+	for _, tenant := range tenants {
+		names = append(names, tenant.Name)
+	}
+	sort.Strings(names)
+
+	if len(names) > tenantSearchMaxCandidates {
+		return fmt.Sprintf("%s and %d more", strings.Join(names[:tenantSearchMaxCandidates], ", "), len(names)-tenantSearchMaxCandidates)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,280 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccTenantSearchServer serves the tenant list over two pages.
+func testAccTenantSearchServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"items":[
+				{"id":"t1","name":"cust-globex"},
+				{"id":"t2","name":"internal"},
+				{"id":"t3","name":"cust-acme"}
+			],"next_cursor":"2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[
+			{"id":"t4","name":"cust-initech"},
+			{"id":"t5","name":"cust-acme-staging"}
+		]}`)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestAccTenantSearchDataSource(t *testing.T) {
+	server := testAccTenantSearchServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_tenant_search" "initech" {
+  prefix = "cust-ini"
+}
+
+data "authproxy_tenant_search" "customers" {
+  regex      = "^cust-[a-z]+$"
+  match_mode = "all"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_tenant_search.initech", "tenant.id", "t4"),
+					resource.TestCheckResourceAttr("data.authproxy_tenant_search.customers", "tenants.#", "3"),
+					resource.TestCheckResourceAttr("data.authproxy_tenant_search.customers", "tenants.0.name", "cust-acme"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_tenant_search" "invalid" {
+  prefix = "cust-"
+  regex  = "^cust-"
+}
+`,
+				ExpectError: regexp.MustCompile(`Exactly\s+one\s+of\s+` + "`prefix`" + `\s+and\s+` + "`regex`"),
+			},
+		},
+	})
+}
+
+func TestTenantSearchDataSource(t *testing.T) {
+	testCases := map[string]struct {
+		config   map[string]tftypes.Value
+		tenant   string
+		tenants  []string
+		errorSum string
+		errorMsg string
+	}{
+		"unique prefix": {
+			config: map[string]tftypes.Value{
+				"prefix": tftypes.NewValue(tftypes.String, "cust-glo"),
+			},
+			tenant: "cust-globex",
+		},
+		"unique regex": {
+			config: map[string]tftypes.Value{
+				"regex": tftypes.NewValue(tftypes.String, "-staging$"),
+			},
+			tenant: "cust-acme-staging",
+		},
+		"ambiguous": {
+			config: map[string]tftypes.Value{
+				"prefix": tftypes.NewValue(tftypes.String, "cust-acme"),
+			},
+			errorSum: "Ambiguous Tenant Search",
+			errorMsg: "matches 2 tenants: cust-acme (t3), cust-acme-staging (t5)",
+		},
+		"empty": {
+			config: map[string]tftypes.Value{
+				"prefix": tftypes.NewValue(tftypes.String, "cust-hooli"),
+			},
+			errorSum: "Tenant Not Found",
+			errorMsg: "Existing tenants: cust-acme, cust-acme-staging, cust-globex, cust-initech, internal",
+		},
+		"all": {
+			config: map[string]tftypes.Value{
+				"prefix":     tftypes.NewValue(tftypes.String, "cust-"),
+				"match_mode": tftypes.NewValue(tftypes.String, "all"),
+			},
+			tenants: []string{"cust-acme", "cust-acme-staging", "cust-globex", "cust-initech"},
+		},
+		"all empty": {
+			config: map[string]tftypes.Value{
+				"regex":      tftypes.NewValue(tftypes.String, "^none$"),
+				"match_mode": tftypes.NewValue(tftypes.String, "all"),
+			},
+			tenants: []string{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := testAccTenantSearchServer(t)
+			defer server.Close()
+
+			resp := testDataSourceRead(t, NewTenantSearchDataSource(), testProviderData(server.URL), testCase.config)
+			if testCase.errorSum != "" {
+				if !resp.Diagnostics.HasError() {
+					t.Fatal("expected an error")
+				}
+				diagnostic := resp.Diagnostics.Errors()[0]
+				if diagnostic.Summary() != testCase.errorSum || !strings.Contains(diagnostic.Detail(), testCase.errorMsg) {
+					t.Errorf("unexpected diagnostic: %s: %s", diagnostic.Summary(), diagnostic.Detail())
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data TenantSearchDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+			if testCase.tenant != "" {
+				if data.Tenant == nil || data.Tenant.Name.ValueString() != testCase.tenant {
+					t.Errorf("expected tenant %q, got %v", testCase.tenant, data.Tenant)
+				}
+				return
+			}
+
+			names := make([]string, 0, len(data.Tenants))
+			for _, tenant := range data.Tenants {
+				names = append(names, tenant.Name.ValueString())
+			}
+			if data.Tenant != nil || fmt.Sprint(names) != fmt.Sprint(testCase.tenants) {
+				t.Errorf("expected tenants %v, got %v (tenant: %v)", testCase.tenants, names, data.Tenant)
+			}
+		})
+	}
+}
+
+// testTenantSearchRolesServer serves n tenants named tenant-00, tenant-01 and
+// so on, giving each tenant i%3 roles. Listing the roles takes delay and
+// fails for the tenants in failing. peak records the most role lists served
+// at once.
+func testTenantSearchRolesServer(t *testing.T, n int, delay time.Duration, failing map[string]bool, peak *int64) *httptest.Server {
+	var running int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants", func(w http.ResponseWriter, r *http.Request) {
+		items := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"t%d","name":"tenant-%02d"}`, i, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"items":[%s]}`, strings.Join(items, ","))
+	})
+	mux.HandleFunc("/tenants/", func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+		for {
+			seen := atomic.LoadInt64(peak)
+			if current <= seen || atomic.CompareAndSwapInt64(peak, seen, current) {
+				break
+			}
+		}
+		time.Sleep(delay)
+
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/tenants/"), "/roles")
+		if failing[name] {
+			http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
+			return
+		}
+		var index int
+		fmt.Sscanf(name, "tenant-%d", &index)
+		roles := make([]string, 0, index%3)
+		for i := 0; i < index%3; i++ {
+			roles = append(roles, fmt.Sprintf(`{"name":"role-%d","scopes":[]}`, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"items":[%s]}`, strings.Join(roles, ","))
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestTenantSearchDataSource_roleCounts(t *testing.T) {
+	const (
+		tenants = 50
+		delay   = 50 * time.Millisecond
+	)
+	var peak int64
+	server := testTenantSearchRolesServer(t, tenants, delay, nil, &peak)
+	defer server.Close()
+
+	start := time.Now()
+	resp := testDataSourceRead(t, NewTenantSearchDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"prefix":              tftypes.NewValue(tftypes.String, "tenant-"),
+		"match_mode":          tftypes.NewValue(tftypes.String, "all"),
+		"include_role_counts": tftypes.NewValue(tftypes.Bool, true),
+	})
+	elapsed := time.Since(start)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+
+	// Listing the roles one tenant after the other takes 2.5s.
+	if serial := tenants * delay; elapsed > serial/2 {
+		t.Errorf("expected the roles to be listed concurrently, reading took %s of %s serially", elapsed, serial)
+	}
+	if peak < 2 || peak > defaultMaxConcurrentRequests {
+		t.Errorf("expected up to %d concurrent role lists, got %d", defaultMaxConcurrentRequests, peak)
+	}
+
+	var data TenantSearchDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	if len(data.Tenants) != tenants {
+		t.Fatalf("expected %d tenants, got %d", tenants, len(data.Tenants))
+	}
+	for i, tenant := range data.Tenants {
+		if name := fmt.Sprintf("tenant-%02d", i); tenant.Name.ValueString() != name || tenant.RoleCount.ValueInt64() != int64(i%3) {
+			t.Errorf("expected %s with %d roles at %d, got %s with %s", name, i%3, i, tenant.Name.ValueString(), tenant.RoleCount)
+		}
+	}
+}
+
+func TestTenantSearchDataSource_roleCountsPartialFailure(t *testing.T) {
+	var peak int64
+	server := testTenantSearchRolesServer(t, 10, 0, map[string]bool{"tenant-07": true, "tenant-02": true}, &peak)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewTenantSearchDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"prefix":              tftypes.NewValue(tftypes.String, "tenant-"),
+		"match_mode":          tftypes.NewValue(tftypes.String, "all"),
+		"include_role_counts": tftypes.NewValue(tftypes.Bool, true),
+	})
+
+	if len(resp.Diagnostics) != 1 || !resp.Diagnostics.HasError() {
+		t.Fatalf("expected a single error, got %v", resp.Diagnostics)
+	}
+	detail := resp.Diagnostics[0].Detail()
+	for _, name := range []string{"tenant-02", "tenant-07"} {
+		if !strings.Contains(detail, fmt.Sprintf("tenant %q", name)) {
+			t.Errorf("expected the error to name %s, got %q", name, detail)
+		}
+	}
+	if strings.Contains(detail, "tenant-03") || strings.Index(detail, "tenant-02") > strings.Index(detail, "tenant-07") {
+		t.Errorf("expected only the failed tenants in order, got %q", detail)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("expected no state to be set")
+	}
+}
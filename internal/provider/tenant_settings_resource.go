@@ -0,0 +1,298 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TenantSettingsResource{}
+var _ resource.ResourceWithImportState = &TenantSettingsResource{}
+
+func NewTenantSettingsResource() resource.Resource {
+	return &TenantSettingsResource{}
+}
+
+// TenantSettingsResource defines the resource implementation. Every tenant
+// has exactly one settings document, the resource manages it rather than
+// creating one.
+type TenantSettingsResource struct {
+	BaseResource
+}
+
+// TenantSettingsResourceModel describes the resource data model.
+type TenantSettingsResourceModel struct {
+	ID                  types.String    `tfsdk:"id"`
+	Tenant              tenantNameValue `tfsdk:"tenant"`
+	SessionLength       types.String    `tfsdk:"session_length"`
+	AllowedLoginMethods types.Set       `tfsdk:"allowed_login_methods"`
+	SelfSignup          types.Bool      `tfsdk:"self_signup"`
+}
+
+// tenantSettingsResponse holds the settings managed by the resource. The
+// settings document may contain more, see rawTenantSettings.
+type tenantSettingsResponse struct {
+	SessionLength       string   `json:"session_length"`
+	AllowedLoginMethods []string `json:"allowed_login_methods"`
+	SelfSignup          bool     `json:"self_signup"`
+}
+
+// rawTenantSettings is the complete settings document. Writes start from the
+// document authproxy returned so that settings unknown to the provider, for
+// example ones added by newer servers, are passed through unchanged.
+type rawTenantSettings map[string]json.RawMessage
+
+// set replaces the setting named name with the JSON encoding of value.
+func (s rawTenantSettings) set(name string, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s[name] = encoded
+	return nil
+}
+
+func (r *TenantSettingsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tenant_settings"
+}
+
+func (r *TenantSettingsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Settings of a tenant. Every tenant has exactly one settings document, destroying the resource restores the authproxy defaults. Settings left unset keep their current value",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the tenant",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the settings belong to. Changing it restores the defaults of the previous tenant",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"session_length": schema.StringAttribute{
+				MarkdownDescription: "How long a login session lasts, such as `12h`",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"allowed_login_methods": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Login methods users of the tenant may use, such as `password` or `oidc`",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"self_signup": schema.BoolAttribute{
+				MarkdownDescription: "Whether users may sign up to the tenant themselves",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// tenantSettingsPath returns the path of the settings document of tenant.
+func tenantSettingsPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/settings", url.PathEscape(tenant))
+}
+
+// setTenantSettings copies the settings authproxy returned into data. A
+// session length authproxy normalized, such as 12h0m0s for 12h, keeps its
+// prior value.
+func (data *TenantSettingsResourceModel) setTenantSettings(ctx context.Context, settings tenantSettingsResponse) diag.Diagnostics {
+	data.ID = types.StringValue(data.Tenant.ValueString())
+
+	prior, priorErr := time.ParseDuration(data.SessionLength.ValueString())
+	current, currentErr := time.ParseDuration(settings.SessionLength)
+	if data.SessionLength.IsNull() || data.SessionLength.IsUnknown() || priorErr != nil || currentErr != nil || prior != current {
+		data.SessionLength = types.StringValue(settings.SessionLength)
+	}
+
+	if settings.AllowedLoginMethods == nil {
+		settings.AllowedLoginMethods = []string{}
+	}
+	allowedLoginMethods, diags := types.SetValueFrom(ctx, types.StringType, settings.AllowedLoginMethods)
+	data.AllowedLoginMethods = allowedLoginMethods
+
+	data.SelfSignup = types.BoolValue(settings.SelfSignup)
+
+	return diags
+}
+
+// write replaces the settings document of the tenant of data with the current
+// one updated by the configured settings of data.
+func (r *TenantSettingsResource) write(ctx context.Context, data *TenantSettingsResourceModel) (tenantSettingsResponse, error) {
+	var settings tenantSettingsResponse
+
+	raw := rawTenantSettings{}
+	if err := r.doJSON(ctx, "GET", tenantSettingsPath(data.Tenant.ValueString()), nil, &raw); err != nil {
+		return settings, err
+	}
+
+	if !data.SessionLength.IsUnknown() {
+		if err := raw.set("session_length", data.SessionLength.ValueString()); err != nil {
+			return settings, err
+		}
+	}
+	if !data.AllowedLoginMethods.IsUnknown() {
+		allowedLoginMethods := []string{}
+		for _, element := range data.AllowedLoginMethods.Elements() {
+			if method, ok := element.(types.String); ok {
+				allowedLoginMethods = append(allowedLoginMethods, method.ValueString())
+			}
+		}
+		sort.Strings(allowedLoginMethods)
+		if err := raw.set("allowed_login_methods", allowedLoginMethods); err != nil {
+			return settings, err
+		}
+	}
+	if !data.SelfSignup.IsUnknown() {
+		if err := raw.set("self_signup", data.SelfSignup.ValueBool()); err != nil {
+			return settings, err
+		}
+	}
+
+	err := r.doJSON(ctx, "PUT", tenantSettingsPath(data.Tenant.ValueString()), raw, &settings)
+	return settings, err
+}
+
+func (r *TenantSettingsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *TenantSettingsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, err := r.write(ctx, data)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update tenant settings", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setTenantSettings(ctx, settings)...)
+
+	tflog.Trace(ctx, "created a tenant settings resource", map[string]interface{}{
+		"tenant": data.Tenant.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TenantSettingsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *TenantSettingsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var settings tenantSettingsResponse
+	err := r.doJSON(ctx, "GET", tenantSettingsPath(data.Tenant.ValueString()), nil, &settings)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "tenant no longer exists, removing its settings from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read tenant settings", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setTenantSettings(ctx, settings)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TenantSettingsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *TenantSettingsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, err := r.write(ctx, data)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update tenant settings", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setTenantSettings(ctx, settings)...)
+
+	tflog.Trace(ctx, "updated a tenant settings resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TenantSettingsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *TenantSettingsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting the settings restores the authproxy defaults.
+	err := r.doJSON(ctx, "DELETE", tenantSettingsPath(data.Tenant.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "reset tenant settings", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a tenant settings resource")
+}
+
+func (r *TenantSettingsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("tenant"), req, resp)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccDefaultTenantSettings returns the settings of a fresh tenant,
+// including settings the provider does not know about.
+func testAccDefaultTenantSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"session_length":        "24h0m0s",
+		"allowed_login_methods": []interface{}{"password"},
+		"self_signup":           false,
+		"mfa":                   map[string]interface{}{"enforcement": "optional"},
+	}
+}
+
+// testAccTenantSettingsBackend is an in-memory stand-in for the settings
+// document of the "acme" tenant. Like authproxy PUT replaces the document
+// and session lengths are normalized.
+type testAccTenantSettingsBackend struct {
+	mu       sync.Mutex
+	settings map[string]interface{}
+}
+
+func (b *testAccTenantSettingsBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.URL.Path != tenantSettingsPath("acme") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var settings map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if sessionLength, ok := settings["session_length"].(string); ok {
+			if normalized, err := time.ParseDuration(sessionLength); err == nil {
+				settings["session_length"] = normalized.String()
+			}
+		}
+		b.settings = settings
+	case http.MethodDelete:
+		b.settings = testAccDefaultTenantSettings()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(b.settings)
+}
+
+// setting returns the current value of the named setting.
+func (b *testAccTenantSettingsBackend) setting(name string) interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.settings[name]
+}
+
+func TestAccTenantSettingsResource(t *testing.T) {
+	backend := &testAccTenantSettingsBackend{settings: testAccDefaultTenantSettings()}
+	server := testBackendServer(t, backend, tenantSettingsPath("acme"))
+
+	// The settings unknown to the provider must survive every write.
+	checkPreserved := func(s *terraform.State) error {
+		mfa := backend.setting("mfa")
+		if !reflect.DeepEqual(mfa, map[string]interface{}{"enforcement": "optional"}) {
+			return fmt.Errorf("expected the unknown mfa setting to be preserved, got %#v", mfa)
+		}
+		return nil
+	}
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if !reflect.DeepEqual(backend.settings, testAccDefaultTenantSettings()) {
+				return fmt.Errorf("expected the default settings to be restored, got %#v", backend.settings)
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing, unset settings keep their value
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_tenant_settings" "test" {
+  tenant         = "acme"
+  session_length = "12h"
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_tenant_settings.test", "id", "acme"),
+					resourcetest.TestCheckResourceAttr("authproxy_tenant_settings.test", "session_length", "12h"),
+					resourcetest.TestCheckResourceAttr("authproxy_tenant_settings.test", "allowed_login_methods.#", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_tenant_settings.test", "self_signup", "false"),
+					checkPreserved,
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "authproxy_tenant_settings.test",
+				ImportState:             true,
+				ImportStateId:           "acme",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"session_length"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_tenant_settings" "test" {
+  tenant                = "acme"
+  session_length        = "12h"
+  allowed_login_methods = ["password", "oidc"]
+  self_signup           = true
+}
+`,
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_tenant_settings.test", "allowed_login_methods.#", "2"),
+					resourcetest.TestCheckResourceAttr("authproxy_tenant_settings.test", "self_signup", "true"),
+					checkPreserved,
+				),
+			},
+			// Changes made outside of Terraform are detected
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					backend.settings["self_signup"] = false
+				},
+				Config: testAccProviderConfig(server.URL) + `
+resource "authproxy_tenant_settings" "test" {
+  tenant                = "acme"
+  session_length        = "12h"
+  allowed_login_methods = ["password", "oidc"]
+  self_signup           = true
+}
+`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tenantUsageFeature is the feature flag of servers implementing the usage
+// endpoint.
+const tenantUsageFeature = "tenant_usage"
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TenantUsageDataSource{}
+
+func NewTenantUsageDataSource() datasource.DataSource {
+	return &TenantUsageDataSource{}
+}
+
+// TenantUsageDataSource defines the data source implementation.
+type TenantUsageDataSource struct {
+	BaseDataSource
+}
+
+// TenantUsageDataSourceModel describes the data source data model.
+type TenantUsageDataSourceModel struct {
+	Tenant         tenantNameValue `tfsdk:"tenant"`
+	Users          types.Int64     `tfsdk:"users"`
+	Roles          types.Int64     `tfsdk:"roles"`
+	Groups         types.Int64     `tfsdk:"groups"`
+	UsersQuota     types.Int64     `tfsdk:"users_quota"`
+	RolesQuota     types.Int64     `tfsdk:"roles_quota"`
+	GroupsQuota    types.Int64     `tfsdk:"groups_quota"`
+	UsersRemaining types.Int64     `tfsdk:"users_remaining"`
+	RolesRemaining types.Int64     `tfsdk:"roles_remaining"`
+}
+
+type tenantUsageResponse struct {
+	Users  int64 `json:"users"`
+	Roles  int64 `json:"roles"`
+	Groups int64 `json:"groups"`
+	Quotas struct {
+		Users  *int64 `json:"users"`
+		Roles  *int64 `json:"roles"`
+		Groups *int64 `json:"groups"`
+	} `json:"quotas"`
+}
+
+func (d *TenantUsageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tenant_usage"
+}
+
+func (d *TenantUsageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "User, role and group counts of a tenant compared against its quotas",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Name of the tenant",
+				Required:            true,
+			},
+			"users": schema.Int64Attribute{
+				MarkdownDescription: "Number of users in the tenant",
+				Computed:            true,
+			},
+			"roles": schema.Int64Attribute{
+				MarkdownDescription: "Number of roles in the tenant",
+				Computed:            true,
+			},
+			"groups": schema.Int64Attribute{
+				MarkdownDescription: "Number of groups in the tenant",
+				Computed:            true,
+			},
+			"users_quota": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of users, null when unlimited or not reported by the server",
+				Computed:            true,
+			},
+			"roles_quota": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of roles, null when unlimited or not reported by the server",
+				Computed:            true,
+			},
+			"groups_quota": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of groups, null when unlimited or not reported by the server",
+				Computed:            true,
+			},
+			"users_remaining": schema.Int64Attribute{
+				MarkdownDescription: "Users that can still be created before reaching the quota, null without a quota",
+				Computed:            true,
+			},
+			"roles_remaining": schema.Int64Attribute{
+				MarkdownDescription: "Roles that can still be created before reaching the quota, null without a quota",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *TenantUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TenantUsageDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+
+	tenantPath := fmt.Sprintf("/tenants/%s", url.PathEscape(data.Tenant.ValueString()))
+
+	supported, err := d.providerData.supports(ctx, tenantUsageFeature)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "probe server capabilities", err)
+		return
+	}
+
+	var usage tenantUsageResponse
+	if supported {
+		if err := d.doJSON(ctx, "GET", tenantPath+"/usage", nil, &usage); err != nil {
+			addClientError(&resp.Diagnostics, "read tenant usage", err)
+			return
+		}
+	} else {
+		// Older servers have no usage endpoint and no quotas, count the
+		// objects through the list endpoints instead.
+		tflog.Debug(ctx, "server has no usage endpoint, counting through list endpoints")
+		counts := map[string]*int64{
+			"users":  &usage.Users,
+			"roles":  &usage.Roles,
+			"groups": &usage.Groups,
+		}
+		for kind, count := range counts {
+			items, err := listAll[json.RawMessage](ctx, d.providerData, tenantPath+"/"+kind, nil)
+			if err != nil {
+				addClientError(&resp.Diagnostics, "list tenant "+kind, err)
+				return
+			}
+			*count = int64(len(items))
+		}
+	}
+
+	data.Users = types.Int64Value(usage.Users)
+	data.Roles = types.Int64Value(usage.Roles)
+	data.Groups = types.Int64Value(usage.Groups)
+	data.UsersQuota = types.Int64PointerValue(usage.Quotas.Users)
+	data.RolesQuota = types.Int64PointerValue(usage.Quotas.Roles)
+	data.GroupsQuota = types.Int64PointerValue(usage.Quotas.Groups)
+	data.UsersRemaining = remaining(usage.Users, usage.Quotas.Users)
+	data.RolesRemaining = remaining(usage.Roles, usage.Quotas.Roles)
+
+	tflog.Trace(ctx, "read tenant usage data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// remaining returns how many objects fit below quota, never less than zero,
+// or null when there is no quota.
+func remaining(count int64, quota *int64) types.Int64 {
+	if quota == nil {
+		return types.Int64Null()
+	}
+	if count >= *quota {
+		return types.Int64Value(0)
+	}
+	return types.Int64Value(*quota - count)
+}
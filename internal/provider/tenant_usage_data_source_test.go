@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccTenantUsageServer(t *testing.T, withUsage bool, probes *int64) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(probes, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"ok","features":{"tenant_usage":%t}}`, withUsage)
+	})
+	mux.HandleFunc("/tenants/acme/usage", func(w http.ResponseWriter, r *http.Request) {
+		if !withUsage {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"users":25,"roles":4,"groups":2,"quotas":{"users":25,"roles":10}}`)
+	})
+	mux.HandleFunc("/tenants/acme/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"items":[{"id":"u1"},{"id":"u2"}],"next_cursor":"2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"id":"u3"}]}`)
+	})
+	mux.HandleFunc("/tenants/acme/roles", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"id":"r1"},{"id":"r2"}]`)
+	})
+	mux.HandleFunc("/tenants/acme/groups", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[]}`)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestAccTenantUsageDataSource_atQuota(t *testing.T) {
+	var probes int64
+	server := testAccTenantUsageServer(t, true, &probes)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccTenantUsageDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_tenant_usage.test", "users", "25"),
+					resource.TestCheckResourceAttr("data.authproxy_tenant_usage.test", "users_quota", "25"),
+					resource.TestCheckResourceAttr("data.authproxy_tenant_usage.test", "users_remaining", "0"),
+					resource.TestCheckResourceAttr("data.authproxy_tenant_usage.test", "roles_remaining", "6"),
+					resource.TestCheckNoResourceAttr("data.authproxy_tenant_usage.test", "groups_quota"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTenantUsageDataSource_listFallback(t *testing.T) {
+	var probes int64
+	server := testAccTenantUsageServer(t, false, &probes)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccTenantUsageDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_tenant_usage.test", "users", "3"),
+					resource.TestCheckResourceAttr("data.authproxy_tenant_usage.test", "roles", "2"),
+					resource.TestCheckResourceAttr("data.authproxy_tenant_usage.test", "groups", "0"),
+					resource.TestCheckNoResourceAttr("data.authproxy_tenant_usage.test", "users_remaining"),
+				),
+			},
+		},
+	})
+}
+
+func TestTenantUsageDataSource_backends(t *testing.T) {
+	for name, withUsage := range map[string]bool{"usage endpoint": true, "list fallback": false} {
+		t.Run(name, func(t *testing.T) {
+			var probes int64
+			server := testAccTenantUsageServer(t, withUsage, &probes)
+			defer server.Close()
+
+			providerData := testProviderData(server.URL)
+			config := map[string]tftypes.Value{
+				"tenant": tftypes.NewValue(tftypes.String, "acme"),
+			}
+			for i := 0; i < 2; i++ {
+				resp := testDataSourceRead(t, NewTenantUsageDataSource(), providerData, config)
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+				}
+
+				var data TenantUsageDataSourceModel
+				resp.State.Get(context.Background(), &data)
+				wantUsers := int64(3)
+				if withUsage {
+					wantUsers = 25
+				}
+				if data.Users.ValueInt64() != wantUsers {
+					t.Errorf("expected %d users, got %s", wantUsers, data.Users)
+				}
+			}
+
+			if probes != 1 {
+				t.Errorf("expected the capabilities to be probed once, got %d probes", probes)
+			}
+		})
+	}
+}
+
+const testAccTenantUsageDataSourceConfig = `
+data "authproxy_tenant_usage" "test" {
+  tenant = "acme"
+}
+`
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tenantname implements the rules of authproxy tenant names, which
+// follow those of DNS labels, and derives valid names from arbitrary display
+// names the way authproxy does.
+package tenantname
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the maximum length of a tenant name in bytes.
+const MaxLength = 63
+
+// Pattern is the format of tenant names such as "acme" or "acme-eu".
+var Pattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Valid reports whether name is a valid tenant name.
+func Valid(name string) bool {
+	return Pattern.MatchString(name)
+}
+
+// transliterations are the lowercase letters without a compatibility
+// decomposition into ASCII that still have a common ASCII spelling.
+var transliterations = map[rune]string{
+	'ß': "ss",
+	'æ': "ae",
+	'œ': "oe",
+	'ø': "o",
+	'đ': "d",
+	'ð': "d",
+	'ł': "l",
+	'þ': "th",
+	'ı': "i",
+}
+
+// Normalize derives a tenant name from s, such as "acme-gmbh-co" from
+// "Acme GmbH & Co.". Letters are lowercased and stripped of their accents,
+// every other run of characters besides ASCII letters and digits becomes a
+// single hyphen, and the result is trimmed of hyphens and cut to MaxLength.
+// An error is returned when s does not contain anything a name can be
+// derived from, such as only punctuation or letters of non-latin scripts.
+func Normalize(s string) (string, error) {
+	var b strings.Builder
+	separated := false
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+
+		spelled := string(r)
+		if transliteration, ok := transliterations[r]; ok {
+			spelled = transliteration
+		} else if !('a' <= r && r <= 'z' || '0' <= r && r <= '9') {
+			separated = b.Len() > 0
+			continue
+		}
+
+		if separated {
+			b.WriteByte('-')
+			separated = false
+		}
+		b.WriteString(spelled)
+	}
+
+	name := b.String()
+	if len(name) > MaxLength {
+		name = strings.TrimRight(name[:MaxLength], "-")
+	}
+	if !Valid(name) {
+		return "", fmt.Errorf("no tenant name can be derived from %q, it must contain latin letters or digits", s)
+	}
+	return name, nil
+}
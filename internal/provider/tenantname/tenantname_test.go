@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tenantname
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"acme":                            true,
+		"acme-eu":                         true,
+		"a":                               true,
+		"0":                               true,
+		"42-acme":                         true,
+		strings.Repeat("a", 63):           true,
+		"":                                false,
+		"-acme":                           false,
+		"acme-":                           false,
+		"Acme":                            false,
+		"acme_eu":                         false,
+		"acme.eu":                         false,
+		"acme eu":                         false,
+		"café":                            false,
+		strings.Repeat("a", 64):           false,
+		"acme-" + strings.Repeat("a", 58): true,
+	} {
+		if got := Valid(name); got != valid {
+			t.Errorf("Valid(%q): expected %t, got %t", name, valid, got)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		expected string
+	}{
+		// Names that are valid already are left alone.
+		{in: "acme", expected: "acme"},
+		{in: "acme-eu", expected: "acme-eu"},
+		{in: "42", expected: "42"},
+
+		// Letters are lowercased, everything else separates words.
+		{in: "ACME", expected: "acme"},
+		{in: "Acme GmbH & Co.", expected: "acme-gmbh-co"},
+		{in: "  acme  ", expected: "acme"},
+		{in: "acme__eu", expected: "acme-eu"},
+		{in: "acme--eu", expected: "acme-eu"},
+		{in: "-acme-", expected: "acme"},
+		{in: "acme.eu/west", expected: "acme-eu-west"},
+		{in: "Acme\tEU\nWest", expected: "acme-eu-west"},
+		{in: "O'Reilly Media, Inc.", expected: "o-reilly-media-inc"},
+		{in: "3M", expected: "3m"},
+
+		// Accents are stripped and ligatures spelled out.
+		{in: "Café Zürich", expected: "cafe-zurich"},
+		{in: "Crème Brûlée", expected: "creme-brulee"},
+		{in: "Straße", expected: "strasse"},
+		{in: "Ørsted A/S", expected: "orsted-a-s"},
+		{in: "Æther Œuvre", expected: "aether-oeuvre"},
+		{in: "Łódź", expected: "lodz"},
+		{in: "Þórr", expected: "thorr"},
+		{in: "ﬁnance", expected: "finance"},
+		{in: "ＡＣＭＥ", expected: "acme"},
+		{in: "Acme²", expected: "acme2"},
+		{in: "Ǆemal", expected: "dzemal"},
+		{in: "İstanbul", expected: "istanbul"},
+
+		// Non-latin scripts separate the latin parts.
+		{in: "Acme 東京", expected: "acme"},
+		{in: "東京 Acme 大阪", expected: "acme"},
+		{in: "acme🚀eu", expected: "acme-eu"},
+
+		// Long names are cut without leaving a trailing hyphen.
+		{in: strings.Repeat("a", 70), expected: strings.Repeat("a", 63)},
+		{in: strings.Repeat("a", 62) + " b", expected: strings.Repeat("a", 62)},
+		{in: strings.Repeat("ä", 70), expected: strings.Repeat("a", 63)},
+	} {
+		got, err := Normalize(tc.in)
+		if err != nil {
+			t.Errorf("Normalize(%q): unexpected error: %s", tc.in, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("Normalize(%q): expected %q, got %q", tc.in, tc.expected, got)
+		}
+		if !Valid(got) {
+			t.Errorf("Normalize(%q): %q is not a valid tenant name", tc.in, got)
+		}
+	}
+}
+
+func TestNormalize_errors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		" ",
+		"---",
+		"&.,!",
+		"東京",
+		"Москва",
+		"🚀",
+	} {
+		if got, err := Normalize(in); err == nil {
+			t.Errorf("Normalize(%q): expected an error, got %q", in, got)
+		}
+	}
+}
+
+func FuzzNormalize(f *testing.F) {
+	for _, seed := range []string{"Acme GmbH & Co.", "Café Zürich", "東京", strings.Repeat("a-", 40)} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, in string) {
+		name, err := Normalize(in)
+		if err != nil {
+			return
+		}
+		if !Valid(name) {
+			t.Fatalf("Normalize(%q) = %q is not a valid tenant name", in, name)
+		}
+		if again, err := Normalize(name); err != nil || again != name {
+			t.Fatalf("Normalize(%q) = %q is not stable, got %q, %v", in, name, again, err)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testserver
+
+import (
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Fault is a failure the chaos mode of Server injects into requests.
+type Fault int
+
+const (
+	// FaultInternalError answers with 500.
+	FaultInternalError Fault = iota
+	// FaultTooManyRequests answers with 429 and a Retry-After header.
+	FaultTooManyRequests
+	// FaultConnectionReset resets the connection without answering.
+	FaultConnectionReset
+	// FaultSlow delays the request by Chaos.Delay before serving it.
+	FaultSlow
+)
+
+// Chaos configures the faults Server injects to exercise the retries and
+// error handling of the provider. Faults are drawn from a random number
+// generator seeded with Seed, so a sequence of requests fails the same way
+// every time it is sent with the same seed.
+type Chaos struct {
+	// Seed seeds the faults, a seed derived from the current time is used
+	// when zero. The seed is logged either way.
+	Seed int64
+	// Rate is the probability of a request to fail, from 0 to 1.
+	Rate float64
+	// Faults are the faults to pick from, with equal probability.
+	Faults []Fault
+	// Methods restricts the faults to requests of these methods, every
+	// request may fail when empty.
+	Methods []string
+	// Delay is how long FaultSlow delays requests, 50ms when zero.
+	Delay time.Duration
+	// RetryAfter is the Retry-After header sent with FaultTooManyRequests,
+	// "0" when empty.
+	RetryAfter string
+}
+
+// chaos is the chaos mode state of a Server.
+type chaos struct {
+	Chaos
+	rand     *rand.Rand
+	injected int
+}
+
+// SetChaos enables chaos mode, replacing the previous configuration. Requests
+// failing with anything but FaultSlow never reach the fake authproxy and are
+// not recorded, as if a proxy in front of authproxy had failed.
+func (s *Server) SetChaos(c Chaos) {
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+	}
+	if c.Delay == 0 {
+		c.Delay = 50 * time.Millisecond
+	}
+	if c.RetryAfter == "" {
+		c.RetryAfter = "0"
+	}
+	s.t.Logf("testserver chaos mode seeded with %d", c.Seed)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chaos = &chaos{Chaos: c, rand: rand.New(rand.NewSource(c.Seed))}
+}
+
+// InjectedFaults returns how many faults chaos mode injected so far.
+func (s *Server) InjectedFaults() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.chaos == nil {
+		return 0
+	}
+	return s.chaos.injected
+}
+
+// injectFault draws the fault of r in chaos mode, failing r unless the fault
+// only slows it down. It reports whether r was failed.
+func (s *Server) injectFault(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	fault, ok := s.drawFault(r)
+	var c Chaos
+	if ok {
+		s.chaos.injected++
+		c = s.chaos.Chaos
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	switch fault {
+	case FaultInternalError:
+		writeError(w, http.StatusInternalServerError, "chaos: internal error")
+	case FaultTooManyRequests:
+		w.Header().Set("Retry-After", c.RetryAfter)
+		writeError(w, http.StatusTooManyRequests, "chaos: too many requests")
+	case FaultConnectionReset:
+		resetConnection(w)
+	case FaultSlow:
+		time.Sleep(c.Delay)
+		return false
+	}
+	return true
+}
+
+// drawFault draws whether r fails and how. s.mu must be held.
+func (s *Server) drawFault(r *http.Request) (Fault, bool) {
+	if s.chaos == nil || len(s.chaos.Faults) == 0 {
+		return 0, false
+	}
+	if len(s.chaos.Methods) > 0 {
+		matched := false
+		for _, method := range s.chaos.Methods {
+			matched = matched || method == r.Method
+		}
+		if !matched {
+			return 0, false
+		}
+	}
+	if s.chaos.rand.Float64() >= s.chaos.Rate {
+		return 0, false
+	}
+	return s.chaos.Faults[s.chaos.rand.Intn(len(s.chaos.Faults))], true
+}
+
+// resetConnection closes the connection of w without answering, making the
+// peer see a reset rather than an orderly shutdown.
+func resetConnection(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "chaos: "+err.Error())
+		return
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	conn.Close()
+}
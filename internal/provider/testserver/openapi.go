@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testserver
+
+import (
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// SpecPath is the path of the OpenAPI document of the authproxy admin API
+// requests are validated against.
+var SpecPath = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "testdata", "openapi.yaml")
+}()
+
+// spec is the router of the document at SpecPath, loaded once per test
+// binary.
+var spec struct {
+	once   sync.Once
+	router routers.Router
+	err    error
+}
+
+func loadSpec() (routers.Router, error) {
+	spec.once.Do(func() {
+		loader := openapi3.NewLoader()
+		doc, err := loader.LoadFromFile(SpecPath)
+		if err == nil {
+			err = doc.Validate(loader.Context)
+		}
+		if err == nil {
+			spec.router, err = gorillamux.NewRouter(doc)
+		}
+		spec.err = err
+	})
+	return spec.router, spec.err
+}
+
+// ValidateRequests wraps next, failing t with the validation error of every
+// request whose path, method, parameters or body do not conform to the
+// OpenAPI document at SpecPath. Such requests are answered with 400 and never
+// reach next. Credentials are left for next to check.
+func ValidateRequests(t testing.TB, next http.Handler) http.Handler {
+	router, err := loadSpec()
+	if err != nil {
+		t.Fatalf("invalid OpenAPI document %s: %s", SpecPath, err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := validateRequest(router, r); err != nil {
+			t.Errorf("%s %s does not conform to the OpenAPI document: %s", r.Method, r.URL.RequestURI(), err)
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateRequest validates r against the route of router it matches. The
+// body of r is restored for the handlers after it.
+func validateRequest(router routers.Router, r *http.Request) error {
+	route, pathParams, err := router.FindRoute(r)
+	if err != nil {
+		return err
+	}
+	return openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	})
+}
@@ -0,0 +1,790 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package testserver provides an in-process fake of the authproxy API for
+// acceptance tests. It keeps tenants and their roles in memory, enforces
+// basic authentication and answers with the status codes authproxy uses, so
+// resources can be tested end-to-end without a running authproxy.
+//
+// Every authenticated request is validated against the OpenAPI document of
+// the admin API at SpecPath, failing the test when the provider sends
+// something authproxy would not understand.
+//
+// Endpoints the fake does not implement can be mounted with Server.Handle,
+// which puts them behind the same authentication and validation.
+//
+// Server.SetChaos makes the server fail requests at random, reproducibly for
+// a given seed, to exercise the retries and error handling of the provider.
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Username and Password are the credentials the server accepts.
+const (
+	Username = "admin"
+	Password = "admin"
+)
+
+// The statuses of tenants. Tenants created through the API are provisioning
+// until they were read as often as SetProvisioningReads asks for.
+const (
+	TenantProvisioning = "provisioning"
+	TenantActive       = "active"
+)
+
+// Tenant is a tenant as returned by the API.
+type Tenant struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Contact *Contact `json:"contact,omitempty"`
+}
+
+// Contact is the contact of a tenant. Empty fields are not set.
+type Contact struct {
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+// contactUpdate is the contact of a tenant update. Fields it omits are left
+// unchanged, empty ones are cleared.
+type contactUpdate struct {
+	Email *string `json:"email"`
+	Name  *string `json:"name"`
+	Phone *string `json:"phone"`
+}
+
+// merge returns a copy of c with the fields present in update replaced.
+func (c *Contact) merge(update contactUpdate) *Contact {
+	merged := Contact{}
+	if c != nil {
+		merged = *c
+	}
+	if update.Email != nil {
+		merged.Email = *update.Email
+	}
+	if update.Name != nil {
+		merged.Name = *update.Name
+	}
+	if update.Phone != nil {
+		merged.Phone = *update.Phone
+	}
+	return &merged
+}
+
+// RolePermissionsFeature is the feature flag of authproxy v2, which renamed
+// the scopes of roles to permissions. Servers advertising it through
+// SetFeatures only accept and answer with permissions, the others only with
+// scopes.
+const RolePermissionsFeature = "role_permissions"
+
+// Role is a role of a tenant as returned by the API.
+type Role struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Scopes      []string `json:"scopes"`
+	Description string   `json:"description,omitempty"`
+}
+
+// Policy is what the validate endpoint checks proposed tenants and roles
+// against, see SetPolicy.
+type Policy struct {
+	// ReservedNames are the names tenants and roles cannot have.
+	ReservedNames []string
+	// Scopes are the scopes roles can have, any when empty.
+	Scopes []string
+}
+
+// Request is an authenticated request the server received.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+}
+
+// String formats the request as "METHOD /path".
+func (r Request) String() string {
+	return r.Method + " " + r.Path
+}
+
+// Server is a running fake authproxy. Its URL is meant to be used as the
+// endpoint of the provider.
+type Server struct {
+	*httptest.Server
+
+	t       testing.TB
+	mux     *http.ServeMux
+	handler http.Handler
+
+	mu           sync.Mutex
+	tenants      map[string]*tenant
+	requests     []Request
+	features     map[string]bool
+	dropped      map[string]bool
+	healthChecks int
+	chaos        *chaos
+	nextID       int
+	provisioning int
+	propagation  int
+	policy       Policy
+}
+
+// tenant is a stored tenant along with its roles keyed by name.
+// provisioningReads counts the reads left until a provisioning tenant turns
+// active, negative ones never do.
+type tenant struct {
+	Tenant
+	roles             map[string]Role
+	provisioningReads int
+	stale             map[string]staleRole
+}
+
+// staleRole is what reads of a role answer with until its last write
+// propagated: the version before the write, or not found for new roles.
+type staleRole struct {
+	role  *Role
+	reads int
+}
+
+// New starts a server which is closed once t and its subtests completed.
+func New(t testing.TB) *Server {
+	s := &Server{
+		t:        t,
+		mux:      http.NewServeMux(),
+		tenants:  map[string]*tenant{},
+		features: map[string]bool{},
+		dropped:  map[string]bool{},
+	}
+	s.mux.HandleFunc("/tenants", s.serveTenants)
+	s.mux.HandleFunc("/tenants/", s.serveTenant)
+	s.mux.HandleFunc("/validate", s.serveValidate)
+	s.handler = ValidateRequests(t, s.mux)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Handle mounts handler for pattern as http.ServeMux does, letting tests fake
+// endpoints the server does not implement. Requests reach handler only when
+// they are authenticated and conform to the OpenAPI document, so pattern must
+// be described by it.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// CreateTenant adds a tenant as if it was created outside of Terraform.
+func (s *Server) CreateTenant(name string) Tenant {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createTenant(name)
+}
+
+// DeleteTenant removes the tenant named name along with its roles as if it
+// was deleted outside of Terraform, reporting whether it existed.
+func (s *Server) DeleteTenant(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.tenants[name]
+	delete(s.tenants, name)
+	return ok
+}
+
+// SetContact replaces the contact of the tenant named name as if it was
+// changed outside of Terraform, reporting whether the tenant exists. A nil
+// contact clears it.
+func (s *Server) SetContact(name string, contact *Contact) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.tenants[name]
+	if !ok {
+		return false
+	}
+	stored.Contact = nil
+	if contact != nil {
+		stored.Contact = contact.merge(contactUpdate{})
+	}
+	return true
+}
+
+// Tenant returns the tenant named name.
+func (s *Server) Tenant(name string) (Tenant, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.tenants[name]
+	if !ok {
+		return Tenant{}, false
+	}
+	return stored.Tenant, true
+}
+
+// Tenants returns every tenant sorted by name.
+func (s *Server) Tenants() []Tenant {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tenants := make([]Tenant, 0, len(s.tenants))
+	for _, stored := range s.tenants {
+		tenants = append(tenants, stored.Tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants
+}
+
+// PutRole creates or replaces a role of the tenant named tenantName as if it
+// was changed outside of Terraform, creating the tenant if necessary.
+func (s *Server) PutRole(tenantName string, role Role) Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.tenants[tenantName]
+	if !ok {
+		s.createTenant(tenantName)
+		stored = s.tenants[tenantName]
+	}
+	if role.ID == "" {
+		role.ID = s.newID()
+	}
+	stored.roles[role.Name] = role
+	return role
+}
+
+// DeleteRole removes the role named name of the tenant named tenantName as
+// if it was deleted outside of Terraform, reporting whether it existed.
+func (s *Server) DeleteRole(tenantName string, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.tenants[tenantName]
+	if !ok {
+		return false
+	}
+	_, ok = stored.roles[name]
+	delete(stored.roles, name)
+	return ok
+}
+
+// SetFeatures replaces the optional features the health endpoint advertises.
+func (s *Server) SetFeatures(features map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.features = make(map[string]bool, len(features))
+	for feature, enabled := range features {
+		s.features[feature] = enabled
+	}
+}
+
+// DropUpdates makes the server accept updates of the named fields but
+// silently ignore them, as older authproxy builds do for some fields. Fields
+// are named as in the request bodies, "name", "scopes" and "description" of
+// roles and "new_tenant" and "contact" of tenants. "scopes" covers the
+// permissions of servers advertising RolePermissionsFeature.
+func (s *Server) DropUpdates(fields ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropped = make(map[string]bool, len(fields))
+	for _, field := range fields {
+		s.dropped[field] = true
+	}
+}
+
+// SetProvisioningReads makes tenants created through the API afterwards
+// provision asynchronously, like in some authproxy deployments: they are
+// provisioning for their first reads reads and active from then on, never
+// turning active for a negative reads. Roles cannot be created in
+// provisioning tenants.
+func (s *Server) SetProvisioningReads(reads int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provisioning = reads
+}
+
+// SetPropagationReads makes writes of roles through the API afterwards
+// propagate lazily, like in authproxy deployments replicating roles across
+// regions: the next reads reads of a written role answer with the version
+// before the write, or with 404 for new roles.
+func (s *Server) SetPropagationReads(reads int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.propagation = reads
+}
+
+// SetPolicy replaces the policy the validate endpoint checks proposed objects
+// against. Creating and updating objects is not subject to it.
+func (s *Server) SetPolicy(policy Policy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = policy
+}
+
+// HealthChecks returns how often the health endpoint was requested.
+func (s *Server) HealthChecks() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.healthChecks
+}
+
+// Requests returns the authenticated requests the server received so far, in
+// the order they arrived.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Roles returns the roles of the tenant named tenantName sorted by name.
+func (s *Server) Roles(tenantName string) []Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.tenants[tenantName]
+	if !ok {
+		return nil
+	}
+	return stored.sortedRoles()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.injectFault(w, r) {
+		return
+	}
+
+	// The health endpoint is public, like in authproxy.
+	if r.URL.Path == "/health" {
+		s.mu.Lock()
+		s.healthChecks++
+		features := s.features
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "features": features})
+		return
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok || username != Username || password != Password {
+		w.Header().Set("WWW-Authenticate", `Basic realm="authproxy"`)
+		writeError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.EscapedPath(), Header: r.Header.Clone()})
+	s.mu.Unlock()
+
+	s.handler.ServeHTTP(w, r)
+}
+
+// serveTenants serves the tenants collection. Tenants are created, renamed
+// and have their contact changed through it, lowercasing their names as
+// authproxy does.
+func (s *Server) serveTenants(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		tenants := make([]Tenant, 0, len(s.tenants))
+		for _, stored := range s.tenants {
+			tenants = append(tenants, stored.Tenant)
+		}
+		sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+		writeJSON(w, http.StatusOK, tenants)
+	case http.MethodPost:
+		var body struct {
+			Name    string   `json:"tenant"`
+			Contact *Contact `json:"contact"`
+		}
+		if !decode(w, r, &body) {
+			return
+		}
+		body.Name = strings.ToLower(body.Name)
+		if body.Name == "" {
+			writeError(w, http.StatusBadRequest, "tenant must not be empty")
+			return
+		}
+		if _, ok := s.tenants[body.Name]; ok {
+			writeError(w, http.StatusConflict, fmt.Sprintf("tenant %s already exists", body.Name))
+			return
+		}
+		s.createTenant(body.Name)
+		if s.provisioning != 0 {
+			s.tenants[body.Name].Status = TenantProvisioning
+			s.tenants[body.Name].provisioningReads = s.provisioning
+		}
+		if body.Contact != nil {
+			s.tenants[body.Name].Contact = body.Contact.merge(contactUpdate{})
+		}
+		writeJSON(w, http.StatusCreated, s.tenants[body.Name].Tenant)
+	case http.MethodPatch:
+		var body struct {
+			Name    string          `json:"tenant"`
+			NewName string          `json:"new_tenant"`
+			Contact json.RawMessage `json:"contact"`
+		}
+		if !decode(w, r, &body) {
+			return
+		}
+		body.Name, body.NewName = strings.ToLower(body.Name), strings.ToLower(body.NewName)
+		stored, ok := s.tenants[body.Name]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("tenant %s not found", body.Name))
+			return
+		}
+		if body.NewName == "" {
+			writeError(w, http.StatusBadRequest, "new_tenant must not be empty")
+			return
+		}
+		if _, ok := s.tenants[body.NewName]; ok && body.NewName != body.Name {
+			writeError(w, http.StatusConflict, fmt.Sprintf("tenant %s already exists", body.NewName))
+			return
+		}
+		if s.dropped["new_tenant"] {
+			body.NewName = body.Name
+		}
+		switch {
+		case len(body.Contact) == 0 || s.dropped["contact"]:
+		case string(body.Contact) == "null":
+			stored.Contact = nil
+		default:
+			var update contactUpdate
+			if err := json.Unmarshal(body.Contact, &update); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("malformed contact: %s", err))
+				return
+			}
+			stored.Contact = stored.Contact.merge(update)
+		}
+		delete(s.tenants, body.Name)
+		stored.Name = body.NewName
+		s.tenants[stored.Name] = stored
+		writeJSON(w, http.StatusOK, stored.Tenant)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// serveTenant serves a single tenant and the roles below it. Tenant names
+// are matched regardless of case.
+func (s *Server) serveTenant(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/tenants/"), "/")
+	stored, ok := s.tenants[strings.ToLower(segments[0])]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("tenant %s not found", segments[0]))
+		return
+	}
+
+	switch {
+	case len(segments) == 1:
+		s.serveTenantObject(w, r, stored)
+	case len(segments) == 2 && segments[1] == "roles":
+		s.serveRoles(w, r, stored)
+	case len(segments) == 3 && segments[1] == "roles":
+		s.serveRole(w, r, stored, segments[2])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) serveTenantObject(w http.ResponseWriter, r *http.Request, stored *tenant) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, stored.Tenant)
+		if stored.Status == TenantProvisioning && stored.provisioningReads > 0 {
+			stored.provisioningReads--
+			if stored.provisioningReads == 0 {
+				stored.Status = TenantActive
+			}
+		}
+	case http.MethodDelete:
+		delete(s.tenants, stored.Name)
+		writeJSON(w, http.StatusOK, stored.Tenant)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// roleRequest is the body roles are created and updated with. Updates
+// renaming the role carry its new name, updates without scopes keep them.
+type roleRequest struct {
+	Name        string    `json:"name"`
+	Scopes      *[]string `json:"scopes"`
+	Permissions *[]string `json:"permissions"`
+	Description string    `json:"description"`
+}
+
+// v2Role is a role as returned by servers advertising RolePermissionsFeature.
+type v2Role struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	Description string   `json:"description,omitempty"`
+}
+
+// roleScopes returns the scopes of body under the name the server uses for
+// them, answering with 400 and returning false when body uses the other
+// one. s.mu must be held.
+func (s *Server) roleScopes(w http.ResponseWriter, body roleRequest) (*[]string, bool) {
+	if s.features[RolePermissionsFeature] {
+		if body.Scopes != nil {
+			writeError(w, http.StatusBadRequest, "scopes was renamed to permissions")
+			return nil, false
+		}
+		return body.Permissions, true
+	}
+	if body.Permissions != nil {
+		writeError(w, http.StatusBadRequest, "unknown field permissions")
+		return nil, false
+	}
+	return body.Scopes, true
+}
+
+// roleResponse returns role the way the server answers with it. s.mu must be
+// held.
+func (s *Server) roleResponse(role Role) interface{} {
+	if s.features[RolePermissionsFeature] {
+		return v2Role{ID: role.ID, Name: role.Name, Permissions: role.Scopes, Description: role.Description}
+	}
+	return role
+}
+
+func (s *Server) serveRoles(w http.ResponseWriter, r *http.Request, stored *tenant) {
+	switch r.Method {
+	case http.MethodGet:
+		roles := []interface{}{}
+		for _, role := range stored.sortedRoles() {
+			roles = append(roles, s.roleResponse(role))
+		}
+		writeJSON(w, http.StatusOK, roles)
+	case http.MethodPost:
+		if stored.Status == TenantProvisioning {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("tenant %s is still provisioning", stored.Name))
+			return
+		}
+		var body roleRequest
+		if !decode(w, r, &body) {
+			return
+		}
+		if body.Name == "" {
+			writeError(w, http.StatusBadRequest, "name must not be empty")
+			return
+		}
+		requested, ok := s.roleScopes(w, body)
+		if !ok {
+			return
+		}
+		if requested == nil {
+			writeError(w, http.StatusBadRequest, "scopes must be set")
+			return
+		}
+		if _, ok := stored.roles[body.Name]; ok {
+			writeError(w, http.StatusConflict, fmt.Sprintf("role %s already exists in tenant %s", body.Name, stored.Name))
+			return
+		}
+		role := Role{ID: s.newID(), Name: body.Name, Scopes: scopes(*requested), Description: body.Description}
+		stored.roles[role.Name] = role
+		s.delayPropagation(stored, role.Name, nil)
+		writeJSON(w, http.StatusCreated, s.roleResponse(role))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) serveRole(w http.ResponseWriter, r *http.Request, stored *tenant, name string) {
+	if stale, ok := stored.stale[name]; ok && r.Method == http.MethodGet {
+		if stale.reads--; stale.reads > 0 {
+			stored.stale[name] = stale
+		} else {
+			delete(stored.stale, name)
+		}
+		if stale.role == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("role %s not found in tenant %s", name, stored.Name))
+			return
+		}
+		writeJSON(w, http.StatusOK, s.roleResponse(*stale.role))
+		return
+	}
+
+	role, ok := stored.roles[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("role %s not found in tenant %s", name, stored.Name))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.roleResponse(role))
+	case http.MethodPatch:
+		var body roleRequest
+		if !decode(w, r, &body) {
+			return
+		}
+		requested, ok := s.roleScopes(w, body)
+		if !ok {
+			return
+		}
+		previous := role
+		if body.Name != "" && body.Name != name && !s.dropped["name"] {
+			if _, ok := stored.roles[body.Name]; ok {
+				writeError(w, http.StatusConflict, fmt.Sprintf("role %s already exists in tenant %s", body.Name, stored.Name))
+				return
+			}
+			delete(stored.roles, name)
+			role.Name = body.Name
+		}
+		if requested != nil && !s.dropped["scopes"] {
+			role.Scopes = scopes(*requested)
+		}
+		if !s.dropped["description"] {
+			role.Description = body.Description
+		}
+		stored.roles[role.Name] = role
+		if role.Name == previous.Name {
+			s.delayPropagation(stored, role.Name, &previous)
+		} else {
+			s.delayPropagation(stored, role.Name, nil)
+		}
+		writeJSON(w, http.StatusOK, s.roleResponse(role))
+	case http.MethodDelete:
+		delete(stored.roles, name)
+		delete(stored.stale, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// validationProblem is a policy violation reported by the validate endpoint.
+type validationProblem struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// serveValidate checks a proposed tenant or role against the policy of the
+// server without storing it, answering with the violations.
+func (s *Server) serveValidate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var body struct {
+		Kind   string `json:"kind"`
+		Object struct {
+			Tenant      string    `json:"tenant"`
+			Name        string    `json:"name"`
+			Scopes      *[]string `json:"scopes"`
+			Permissions *[]string `json:"permissions"`
+		} `json:"object"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+
+	problems := []validationProblem{}
+	reserved := func(field string, name string) {
+		for _, reservedName := range s.policy.ReservedNames {
+			if strings.EqualFold(name, reservedName) {
+				problems = append(problems, validationProblem{Field: field, Message: fmt.Sprintf("the name %s is reserved", name)})
+			}
+		}
+	}
+	switch body.Kind {
+	case "tenant":
+		reserved("tenant", body.Object.Tenant)
+	case "role":
+		reserved("name", body.Object.Name)
+		field, requested := "scopes", body.Object.Scopes
+		if body.Object.Permissions != nil {
+			field, requested = "permissions", body.Object.Permissions
+		}
+		known := make(map[string]bool, len(s.policy.Scopes))
+		for _, scope := range s.policy.Scopes {
+			known[scope] = true
+		}
+		if requested != nil && len(known) > 0 {
+			for _, scope := range *requested {
+				if !known[scope] {
+					problems = append(problems, validationProblem{Field: field, Message: fmt.Sprintf("the scope %s does not exist", scope)})
+				}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"errors": problems})
+}
+
+// delayPropagation makes the next reads of the role named name of stored
+// answer with previous, as configured through SetPropagationReads. s.mu must
+// be held.
+func (s *Server) delayPropagation(stored *tenant, name string, previous *Role) {
+	if s.propagation <= 0 {
+		return
+	}
+	if stored.stale == nil {
+		stored.stale = map[string]staleRole{}
+	}
+	stored.stale[name] = staleRole{role: previous, reads: s.propagation}
+}
+
+// createTenant stores a new tenant named name. s.mu must be held.
+func (s *Server) createTenant(name string) Tenant {
+	stored := &tenant{
+		Tenant: Tenant{ID: s.newID(), Name: name, Status: TenantActive},
+		roles:  map[string]Role{},
+	}
+	s.tenants[name] = stored
+	return stored.Tenant
+}
+
+// newID returns a new unique id in the format of the database uuids of
+// authproxy. s.mu must be held.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID)
+}
+
+func (t *tenant) sortedRoles() []Role {
+	roles := make([]Role, 0, len(t.roles))
+	for _, role := range t.roles {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles
+}
+
+// scopes returns the sorted scopes of a request, an empty list when there
+// are none.
+func scopes(requested []string) []string {
+	sorted := append([]string{}, requested...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// decode decodes the JSON request body into out, answering with 400 and
+// returning false when it is malformed.
+func decode(w http.ResponseWriter, r *http.Request, out interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(out); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("malformed request body: %s", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError answers with status and an error body shaped like the ones of
+// authproxy.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,505 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTB records the errors reported through it instead of failing the
+// test, for tests sending invalid requests on purpose.
+type recordingTB struct {
+	testing.TB
+
+	mu     sync.Mutex
+	errors []string
+}
+
+func (tb *recordingTB) Errorf(format string, args ...interface{}) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestServer(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	server := New(tb)
+	server.CreateTenant("acme")
+	server.PutRole("acme", Role{Name: "viewer", Scopes: []string{"billing:read"}})
+	server.Handle("/tenants/acme/webhooks", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	var authenticated []string
+	for _, tc := range []struct {
+		method string
+		path   string
+		body   string
+		anon   bool
+		status int
+	}{
+		{method: http.MethodGet, path: "/health", anon: true, status: http.StatusOK},
+		{method: http.MethodGet, path: "/tenants/acme", anon: true, status: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/tenants/acme", status: http.StatusOK},
+		{method: http.MethodGet, path: "/tenants/lidl", status: http.StatusNotFound},
+		{method: http.MethodPost, path: "/tenants", body: `{"tenant":"lidl"}`, status: http.StatusCreated},
+		{method: http.MethodPost, path: "/tenants", body: `{"tenant":"lidl"}`, status: http.StatusConflict},
+		{method: http.MethodPost, path: "/tenants", body: `{"tenant":"LIDL"}`, status: http.StatusConflict},
+		{method: http.MethodGet, path: "/tenants/Lidl", status: http.StatusOK},
+		{method: http.MethodPost, path: "/tenants", body: `{`, status: http.StatusBadRequest},
+		{method: http.MethodPatch, path: "/tenants", body: `{"tenant":"lidl","new_tenant":"acme"}`, status: http.StatusConflict},
+		{method: http.MethodPatch, path: "/tenants", body: `{"tenant":"Lidl","new_tenant":"Lidl-EU"}`, status: http.StatusOK},
+		{method: http.MethodGet, path: "/tenants/acme/roles", status: http.StatusOK},
+		{method: http.MethodPost, path: "/tenants/acme/roles", body: `{"name":"viewer","scopes":[]}`, status: http.StatusConflict},
+		{method: http.MethodPost, path: "/tenants/acme/roles", body: `{"name":"editor","scopes":["billing:write"]}`, status: http.StatusCreated},
+		{method: http.MethodPost, path: "/tenants/lidl/roles", body: `{"name":"editor","scopes":[]}`, status: http.StatusNotFound},
+		{method: http.MethodPatch, path: "/tenants/acme/roles/editor", body: `{"name":"viewer","scopes":[]}`, status: http.StatusConflict},
+		{method: http.MethodPatch, path: "/tenants/acme/roles/editor", body: `{"scopes":["billing:read","billing:write"]}`, status: http.StatusOK},
+		{method: http.MethodDelete, path: "/tenants/acme/roles/viewer", status: http.StatusNoContent},
+		{method: http.MethodGet, path: "/tenants/acme/roles/viewer", status: http.StatusNotFound},
+		{method: http.MethodGet, path: "/tenants/acme/webhooks", status: http.StatusTeapot},
+		{method: http.MethodGet, path: "/tenants/acme/webhooks", anon: true, status: http.StatusUnauthorized},
+		{method: http.MethodDelete, path: "/tenants/lidl-eu", status: http.StatusOK},
+		{method: http.MethodDelete, path: "/tenants/lidl-eu", status: http.StatusNotFound},
+	} {
+		var body io.Reader
+		if tc.body != "" {
+			body = strings.NewReader(tc.body)
+		}
+		req, err := http.NewRequest(tc.method, server.URL+tc.path, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if !tc.anon {
+			req.SetBasicAuth(Username, Password)
+			authenticated = append(authenticated, tc.method+" "+tc.path)
+		}
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != tc.status {
+			t.Errorf("%s %s %s: expected status %d, got %d", tc.method, tc.path, tc.body, tc.status, res.StatusCode)
+		}
+	}
+
+	if len(tb.errors) != 1 || !strings.HasPrefix(tb.errors[0], "POST /tenants does not conform") {
+		t.Errorf("expected only the malformed request to fail validation, got %v", tb.errors)
+	}
+
+	var requests []string
+	for _, req := range server.Requests() {
+		requests = append(requests, req.String())
+	}
+	if strings.Join(requests, "\n") != strings.Join(authenticated, "\n") {
+		t.Errorf("expected exactly the authenticated requests to be recorded, got %v", requests)
+	}
+
+	if checks := server.HealthChecks(); checks != 1 {
+		t.Errorf("expected a single health check to be counted, got %d", checks)
+	}
+
+	roles := server.Roles("acme")
+	if len(roles) != 1 || roles[0].Name != "editor" || strings.Join(roles[0].Scopes, ",") != "billing:read,billing:write" {
+		t.Errorf("expected only the updated editor role to be left, got %v", roles)
+	}
+	if tenants := server.Tenants(); len(tenants) != 1 || tenants[0].Name != "acme" {
+		t.Errorf("expected only acme to be left, got %v", tenants)
+	}
+}
+
+func TestServer_features(t *testing.T) {
+	server := New(t)
+	features := map[string]bool{"roles_batch": true}
+	server.SetFeatures(features)
+	features["tenant_usage"] = true
+
+	res, err := server.Client().Get(server.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(body)); got != `{"features":{"roles_batch":true},"status":"ok"}` {
+		t.Errorf("expected only the features set to be advertised, got %s", got)
+	}
+}
+
+func TestServer_contact(t *testing.T) {
+	server := New(t)
+	send := func(method string, body string) {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+"/tenants", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBasicAuth(Username, Password)
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode >= 300 {
+			t.Fatalf("%s %s: unexpected status %d", method, body, res.StatusCode)
+		}
+	}
+	contact := func() Contact {
+		t.Helper()
+		tenant, _ := server.Tenant("acme")
+		if tenant.Contact == nil {
+			return Contact{}
+		}
+		return *tenant.Contact
+	}
+
+	send(http.MethodPost, `{"tenant":"acme","contact":{"email":"ops@acme.example","name":"Ops"}}`)
+	if got := contact(); got != (Contact{Email: "ops@acme.example", Name: "Ops"}) {
+		t.Errorf("expected the contact to be created with the tenant, got %+v", got)
+	}
+
+	// Fields missing from an update are kept, empty ones cleared.
+	send(http.MethodPatch, `{"tenant":"acme","new_tenant":"acme","contact":{"name":"","phone":"+1 555 0100"}}`)
+	if got := contact(); got != (Contact{Email: "ops@acme.example", Phone: "+1 555 0100"}) {
+		t.Errorf("expected the contact to be merged, got %+v", got)
+	}
+
+	send(http.MethodPatch, `{"tenant":"acme","new_tenant":"acme"}`)
+	if got := contact(); got != (Contact{Email: "ops@acme.example", Phone: "+1 555 0100"}) {
+		t.Errorf("expected a rename to keep the contact, got %+v", got)
+	}
+
+	send(http.MethodPatch, `{"tenant":"acme","new_tenant":"acme","contact":null}`)
+	if tenant, _ := server.Tenant("acme"); tenant.Contact != nil {
+		t.Errorf("expected the contact to be cleared, got %+v", tenant.Contact)
+	}
+}
+
+func TestServer_provisioning(t *testing.T) {
+	server := New(t)
+	server.SetProvisioningReads(2)
+	server.CreateTenant("acme")
+	send := func(method string, path string, body string) int {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBasicAuth(Username, Password)
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+	status := func(name string) string {
+		t.Helper()
+		tenant, _ := server.Tenant(name)
+		return tenant.Status
+	}
+
+	// Tenants created outside of the API are active right away.
+	if got := status("acme"); got != TenantActive {
+		t.Errorf("expected acme to be active, got %q", got)
+	}
+
+	send(http.MethodPost, "/tenants", `{"tenant":"lidl"}`)
+	for read := 0; read < 2; read++ {
+		if got := status("lidl"); got != TenantProvisioning {
+			t.Fatalf("expected lidl to be provisioning before read %d, got %q", read+1, got)
+		}
+		if got := send(http.MethodPost, "/tenants/lidl/roles", `{"name":"viewer","scopes":["billing:read"]}`); got != http.StatusUnprocessableEntity {
+			t.Errorf("expected roles to be refused while provisioning, got status %d", got)
+		}
+		send(http.MethodGet, "/tenants/lidl", "")
+	}
+	if got := status("lidl"); got != TenantActive {
+		t.Errorf("expected lidl to be active after two reads, got %q", got)
+	}
+	if got := send(http.MethodPost, "/tenants/lidl/roles", `{"name":"viewer","scopes":["billing:read"]}`); got != http.StatusCreated {
+		t.Errorf("expected roles to be created once active, got status %d", got)
+	}
+
+	server.SetProvisioningReads(-1)
+	send(http.MethodPost, "/tenants", `{"tenant":"aldi"}`)
+	for read := 0; read < 5; read++ {
+		send(http.MethodGet, "/tenants/aldi", "")
+	}
+	if got := status("aldi"); got != TenantProvisioning {
+		t.Errorf("expected aldi to never become active, got %q", got)
+	}
+}
+
+func TestServer_propagation(t *testing.T) {
+	server := New(t)
+	server.CreateTenant("acme")
+	server.SetPropagationReads(2)
+	send := func(method string, path string, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBasicAuth(Username, Password)
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		read, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode, string(read)
+	}
+
+	// New roles are not found until they propagated.
+	send(http.MethodPost, "/tenants/acme/roles", `{"name":"viewer","scopes":["billing:read"]}`)
+	for read := 0; read < 2; read++ {
+		if status, _ := send(http.MethodGet, "/tenants/acme/roles/viewer", ""); status != http.StatusNotFound {
+			t.Errorf("expected read %d of the new role to miss it, got status %d", read+1, status)
+		}
+	}
+	if status, _ := send(http.MethodGet, "/tenants/acme/roles/viewer", ""); status != http.StatusOK {
+		t.Errorf("expected the role to have propagated, got status %d", status)
+	}
+
+	// Updated roles answer with the previous version until then.
+	send(http.MethodPatch, "/tenants/acme/roles/viewer", `{"scopes":["billing:read","billing:write"]}`)
+	for read := 0; read < 2; read++ {
+		if _, body := send(http.MethodGet, "/tenants/acme/roles/viewer", ""); strings.Contains(body, "billing:write") {
+			t.Errorf("expected read %d of the updated role to be stale, got %s", read+1, body)
+		}
+	}
+	if _, body := send(http.MethodGet, "/tenants/acme/roles/viewer", ""); !strings.Contains(body, "billing:write") {
+		t.Errorf("expected the update to have propagated, got %s", body)
+	}
+	if roles := server.Roles("acme"); len(roles) != 1 || len(roles[0].Scopes) != 2 {
+		t.Errorf("expected the stored role to be updated right away, got %v", roles)
+	}
+}
+
+func TestServer_validate(t *testing.T) {
+	server := New(t)
+	server.SetPolicy(Policy{ReservedNames: []string{"system"}, Scopes: []string{"billing:read"}})
+	validate := func(body string) string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/validate", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBasicAuth(Username, Password)
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		read, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(read))
+	}
+
+	for body, expected := range map[string]string{
+		`{"operation":"create","kind":"tenant","object":{"tenant":"acme"}}`:                                                         `{"errors":[]}`,
+		`{"operation":"create","kind":"tenant","object":{"tenant":"System"}}`:                                                       `{"errors":[{"field":"tenant","message":"the name System is reserved"}]}`,
+		`{"operation":"update","kind":"role","tenant":"acme","object":{"name":"viewer","permissions":["billing:read","ops:read"]}}`: `{"errors":[{"field":"permissions","message":"the scope ops:read does not exist"}]}`,
+	} {
+		if got := validate(body); got != expected {
+			t.Errorf("%s: expected %s, got %s", body, expected, got)
+		}
+	}
+	if tenants := server.Tenants(); len(tenants) != 0 {
+		t.Errorf("expected validating to store nothing, got %v", tenants)
+	}
+}
+
+func TestServer_rolePermissions(t *testing.T) {
+	server := New(t)
+	server.CreateTenant("acme")
+	send := func(method string, path string, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBasicAuth(Username, Password)
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		resBody, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode, strings.TrimSpace(string(resBody))
+	}
+
+	if status, _ := send(http.MethodPost, "/tenants/acme/roles", `{"name":"viewer","permissions":["billing:read"]}`); status != http.StatusBadRequest {
+		t.Errorf("expected servers before v2 to reject permissions, got status %d", status)
+	}
+	if status, _ := send(http.MethodPost, "/tenants/acme/roles", `{"name":"viewer","scopes":["billing:read"]}`); status != http.StatusCreated {
+		t.Errorf("expected servers before v2 to accept scopes, got status %d", status)
+	}
+
+	server.SetFeatures(map[string]bool{RolePermissionsFeature: true})
+	if status, _ := send(http.MethodPatch, "/tenants/acme/roles/viewer", `{"scopes":["billing:write"]}`); status != http.StatusBadRequest {
+		t.Errorf("expected v2 servers to reject scopes, got status %d", status)
+	}
+	status, body := send(http.MethodPatch, "/tenants/acme/roles/viewer", `{"permissions":["billing:write"]}`)
+	if status != http.StatusOK || !strings.Contains(body, `"permissions":["billing:write"]`) || strings.Contains(body, "scopes") {
+		t.Errorf("expected v2 servers to answer with permissions, got status %d: %s", status, body)
+	}
+}
+
+func TestServer_dropUpdates(t *testing.T) {
+	server := New(t)
+	server.PutRole("acme", Role{Name: "viewer", Scopes: []string{"billing:read"}, Description: "Reads invoices"})
+	server.DropUpdates("description", "contact")
+	send := func(method string, path string, body string) int {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBasicAuth(Username, Password)
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	// The update succeeds, but only the fields that are not dropped change.
+	if status := send(http.MethodPatch, "/tenants/acme/roles/viewer", `{"scopes":["billing:write"],"description":"Writes invoices"}`); status != http.StatusOK {
+		t.Fatalf("expected the update to be accepted, got status %d", status)
+	}
+	if roles := server.Roles("acme"); len(roles) != 1 || roles[0].Description != "Reads invoices" || roles[0].Scopes[0] != "billing:write" {
+		t.Errorf("expected only the description to be dropped, got %+v", roles)
+	}
+
+	if status := send(http.MethodPatch, "/tenants", `{"tenant":"acme","new_tenant":"lidl","contact":{"email":"ops@lidl.example"}}`); status != http.StatusOK {
+		t.Fatalf("expected the update to be accepted, got status %d", status)
+	}
+	if tenant, ok := server.Tenant("lidl"); !ok || tenant.Contact != nil {
+		t.Errorf("expected only the contact to be dropped, got %+v", tenant)
+	}
+}
+
+func TestServer_chaos(t *testing.T) {
+	statuses := func(seed int64) string {
+		server := New(t)
+		server.CreateTenant("acme")
+		server.SetChaos(Chaos{
+			Seed:    seed,
+			Rate:    0.5,
+			Faults:  []Fault{FaultInternalError, FaultTooManyRequests, FaultConnectionReset, FaultSlow},
+			Methods: []string{http.MethodGet},
+			Delay:   time.Millisecond,
+		})
+
+		var got []string
+		for i := 0; i < 20; i++ {
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/tenants/acme", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.SetBasicAuth(Username, Password)
+			res, err := server.Client().Do(req)
+			if err != nil {
+				got = append(got, "reset")
+				continue
+			}
+			res.Body.Close()
+			if res.StatusCode == http.StatusTooManyRequests && res.Header.Get("Retry-After") != "0" {
+				t.Errorf("expected throttled requests to be retried right away, got Retry-After %q", res.Header.Get("Retry-After"))
+			}
+			got = append(got, res.Status)
+		}
+
+		// Requests of other methods are left alone.
+		res, err := server.Client().Post(server.URL+"/tenants", "application/json", strings.NewReader(`{"tenant":"lidl"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if faults := server.InjectedFaults(); faults == 0 || faults == 20 {
+			t.Errorf("expected some of the requests to fail, got %d faults", faults)
+		}
+		return strings.Join(got, ", ")
+	}
+
+	if first, second := statuses(42), statuses(42); first != second {
+		t.Errorf("expected the same seed to fail the same requests, got %s and %s", first, second)
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	for name, tc := range map[string]struct {
+		method      string
+		path        string
+		body        string
+		contentType string
+		valid       bool
+	}{
+		"valid":                  {method: http.MethodPost, path: "/tenants/acme/roles", body: `{"name":"viewer","scopes":["billing:read"]}`, contentType: "application/json", valid: true},
+		"query":                  {method: http.MethodGet, path: "/tenants?page_size=50&cursor=abc", valid: true},
+		"undocumented path":      {method: http.MethodGet, path: "/tenants/acme/services"},
+		"undocumented method":    {method: http.MethodPut, path: "/tenants/acme"},
+		"missing field":          {method: http.MethodPost, path: "/tenants", body: `{}`, contentType: "application/json"},
+		"unknown field":          {method: http.MethodPost, path: "/tenants", body: `{"tenant":"acme","name":"acme"}`, contentType: "application/json"},
+		"wrong type":             {method: http.MethodPatch, path: "/tenants/acme/roles/viewer", body: `{"scopes":"billing:read"}`, contentType: "application/json"},
+		"missing content type":   {method: http.MethodPost, path: "/tenants", body: `{"tenant":"acme"}`},
+		"parameter out of range": {method: http.MethodGet, path: "/tenants/acme/users?page_size=0"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tb := &recordingTB{TB: t}
+			reached := false
+			handler := ValidateRequests(tb, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if body, _ := io.ReadAll(r.Body); string(body) != tc.body {
+					t.Errorf("expected the body to reach the handler, got %q", body)
+				}
+				reached = true
+			}))
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+
+			if tc.valid && (!reached || len(tb.errors) != 0) {
+				t.Errorf("expected the request to be valid, got %v", tb.errors)
+			}
+			if !tc.valid && (reached || len(tb.errors) != 1 || res.Code != http.StatusBadRequest) {
+				t.Errorf("expected the request to be rejected, got status %d and errors %v", res.Code, tb.errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ReadTimeoutsModel describes the timeouts block of data sources.
+type ReadTimeoutsModel struct {
+	Read types.String `tfsdk:"read"`
+}
+
+// readTimeoutsBlock is the timeouts block shared by data sources. It mirrors
+// the block of the terraform-plugin-framework-timeouts module.
+func readTimeoutsBlock() schema.Block {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Deadlines for the operations of this data source",
+		Attributes: map[string]schema.Attribute{
+			"read": schema.StringAttribute{
+				MarkdownDescription: "How long reading may take in total, such as `\"30s\"` or `\"2m\"`. Defaults to the provider's `request_timeout`",
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+		},
+	}
+}
+
+// CreateTimeoutsModel describes the timeouts block of resources waiting for
+// the objects they create.
+type CreateTimeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+}
+
+// createTimeoutsBlock is the timeouts block of resources waiting for the
+// objects they create, which wait for defaultTimeout unless configured.
+func createTimeoutsBlock(defaultTimeout time.Duration) resourceschema.Block {
+	return resourceschema.SingleNestedBlock{
+		MarkdownDescription: "Deadlines for the operations of this resource",
+		Attributes: map[string]resourceschema.Attribute{
+			"create": resourceschema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("How long creating may take in total, waiting included, such as `\"30s\"` or `\"2m\"`. Defaults to %g minutes", defaultTimeout.Minutes()),
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+		},
+	}
+}
+
+// createTimeout returns the create timeout configured in timeouts, or
+// defaultTimeout.
+func createTimeout(timeouts *CreateTimeoutsModel, defaultTimeout time.Duration) time.Duration {
+	if timeouts != nil && !timeouts.Create.IsNull() {
+		// The value was validated already, ignore the error.
+		if configured, err := time.ParseDuration(timeouts.Create.ValueString()); err == nil {
+			return configured
+		}
+	}
+	return defaultTimeout
+}
+
+// readTimeoutKey holds the readTimeout of a context created by withReadTimeout.
+type readTimeoutKey struct{}
+
+type readTimeout struct {
+	dataSource string
+	timeout    time.Duration
+}
+
+// withReadTimeout derives a context whose deadline is taken from the timeouts
+// block of the data source named dataSource, falling back to the provider's
+// request timeout. Errors of requests made with the context should be
+// reported through addReadError.
+func (p *ProviderData) withReadTimeout(ctx context.Context, dataSource string, timeouts *ReadTimeoutsModel) (context.Context, context.CancelFunc) {
+	timeout := p.requestTimeout
+	if timeouts != nil && !timeouts.Read.IsNull() {
+		// The value was validated already, ignore the error.
+		if configured, err := time.ParseDuration(timeouts.Read.ValueString()); err == nil {
+			timeout = configured
+		}
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	ctx = context.WithValue(ctx, readTimeoutKey{}, readTimeout{dataSource: dataSource, timeout: timeout})
+	return context.WithTimeout(ctx, timeout)
+}
+
+// addReadError reports err like addClientError unless it was caused by the
+// deadline of withReadTimeout, in which case the data source and the timeout
+// are named instead.
+func addReadError(ctx context.Context, diags *diag.Diagnostics, action string, err error) {
+	deadline, ok := ctx.Value(readTimeoutKey{}).(readTimeout)
+	if !ok || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		addClientError(diags, action, err)
+		return
+	}
+
+	diags.AddError(
+		"Read Timeout",
+		fmt.Sprintf("The %s data source did not finish reading within %s. "+
+			"Raise timeouts.read or the provider's request_timeout if authproxy is expected to take this long.", deadline.dataSource, deadline.timeout),
+	)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testSlowServer answers no request until the client gives up.
+func testSlowServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+}
+
+func TestAccUsersDataSource_timeout(t *testing.T) {
+	server := testSlowServer()
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_users" "test" {
+  tenant = "acme"
+
+  timeouts {
+    read = "100ms"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`authproxy_users\s+data\s+source\s+did\s+not\s+finish\s+reading\s+within\s+100ms`),
+			},
+		},
+	})
+}
+
+func TestDataSource_readTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		requestTimeout time.Duration
+		timeouts       tftypes.Value
+		expected       string
+	}{
+		"timeouts block": {
+			requestTimeout: time.Minute,
+			timeouts: tftypes.NewValue(readTimeoutsType, map[string]tftypes.Value{
+				"read": tftypes.NewValue(tftypes.String, "100ms"),
+			}),
+			expected: "within 100ms",
+		},
+		"request timeout": {
+			requestTimeout: 150 * time.Millisecond,
+			timeouts:       tftypes.NewValue(readTimeoutsType, nil),
+			expected:       "within 150ms",
+		},
+		"empty timeouts block": {
+			requestTimeout: 150 * time.Millisecond,
+			timeouts: tftypes.NewValue(readTimeoutsType, map[string]tftypes.Value{
+				"read": tftypes.NewValue(tftypes.String, nil),
+			}),
+			expected: "within 150ms",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := testSlowServer()
+			defer server.Close()
+
+			providerData := testProviderData(server.URL)
+			providerData.requestTimeout = testCase.requestTimeout
+
+			started := time.Now()
+			resp := testDataSourceRead(t, NewTenantDataSource(), providerData, map[string]tftypes.Value{
+				"name":     tftypes.NewValue(tftypes.String, "acme"),
+				"timeouts": testCase.timeouts,
+			})
+			if elapsed := time.Since(started); elapsed > 5*time.Second {
+				t.Errorf("read was not cut off by the timeout, took %s", elapsed)
+			}
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("expected the read to time out")
+			}
+
+			diagnostic := resp.Diagnostics.Errors()[0]
+			if diagnostic.Summary() != "Read Timeout" || !strings.Contains(diagnostic.Detail(), "authproxy_tenant data source") || !strings.Contains(diagnostic.Detail(), testCase.expected) {
+				t.Errorf("unexpected diagnostic: %s: %s", diagnostic.Summary(), diagnostic.Detail())
+			}
+		})
+	}
+}
+
+var readTimeoutsType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{"read": tftypes.String}}
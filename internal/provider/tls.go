@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// tlsTransport returns the transport of the requests to authproxy. It trusts
+// the authorities in caCertPEM in addition to the ones of the system, and
+// does not verify the certificate of authproxy at all when insecure is set.
+func tlsTransport(caCertPEM string, insecure bool) (http.RoundTripper, error) {
+	if caCertPEM == "" && !insecure {
+		return http.DefaultTransport, nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecure, //nolint:gosec // Configured explicitly and warned about.
+	}
+	if caCertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(caCertPEM)) {
+			return nil, errors.New("it holds no PEM encoded certificate")
+		}
+		config.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	return transport, nil
+}
+
+// tlsGuidance explains err when it is a failed TLS handshake with authproxy,
+// naming the host, what is wrong with its certificate and how to fix it. It
+// reports false for other errors.
+func tlsGuidance(err error) (string, bool) {
+	host := "authproxy"
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil && u.Host != "" {
+			host = u.Host
+		}
+	}
+
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostname         x509.HostnameError
+		invalid          x509.CertificateInvalidError
+		recordHeader     tls.RecordHeaderError
+	)
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return fmt.Sprintf("The certificate of %s is signed by an authority this machine does not trust. "+
+			"Set the provider's ca_cert_pem to the certificate of the authority, or add it to the system trust store. "+
+			"For testing only, insecure_skip_verify = true skips verifying the certificate.", host), true
+	case errors.As(err, &hostname):
+		names := "no names"
+		if hostname.Certificate != nil && len(hostname.Certificate.DNSNames) != 0 {
+			names = strings.Join(hostname.Certificate.DNSNames, ", ")
+		}
+		return fmt.Sprintf("The certificate of %s is not valid for the host %s, only for %s. "+
+			"Set the provider's endpoint to a name the certificate covers. "+
+			"For testing only, insecure_skip_verify = true skips verifying the certificate.", host, hostname.Host, names), true
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		validity := ""
+		if invalid.Cert != nil {
+			validity = fmt.Sprintf(", it is valid from %s to %s", invalid.Cert.NotBefore.UTC().Format(time.RFC3339), invalid.Cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		return fmt.Sprintf("The certificate of %s has expired or is not valid yet%s. "+
+			"Renew the certificate authproxy serves, or correct the clock of this machine if it is off.", host, validity), true
+	case errors.As(err, &invalid):
+		return fmt.Sprintf("The certificate of %s is invalid: %s. Fix the certificate authproxy serves.", host, invalid.Error()), true
+	case errors.As(err, &recordHeader):
+		return fmt.Sprintf("%s did not answer with TLS. Use an http endpoint if authproxy does not serve https on that port.", host), true
+	}
+	return "", false
+}
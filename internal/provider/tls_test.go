@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAddClientError_tls(t *testing.T) {
+	certificate := &x509.Certificate{
+		DNSNames:  []string{"authproxy.internal", "auth.example.com"},
+		NotBefore: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:  time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	// handshakeError wraps err the way the client returns failed handshakes.
+	handshakeError := func(err error) error {
+		return &url.Error{
+			Op:  "Post",
+			URL: "https://authproxy.example.com:8443/tenants",
+			Err: &tls.CertificateVerificationError{Err: err},
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		err      error
+		guidance []string
+	}{
+		"unknown authority": {
+			err:      handshakeError(x509.UnknownAuthorityError{Cert: certificate}),
+			guidance: []string{"The certificate of authproxy.example.com:8443 is signed by an authority this machine does not trust", "ca_cert_pem", "insecure_skip_verify = true"},
+		},
+		"hostname mismatch": {
+			err:      handshakeError(x509.HostnameError{Certificate: certificate, Host: "authproxy.example.com"}),
+			guidance: []string{"not valid for the host authproxy.example.com, only for authproxy.internal, auth.example.com", "Set the provider's endpoint", "insecure_skip_verify = true"},
+		},
+		"expired": {
+			err:      handshakeError(x509.CertificateInvalidError{Cert: certificate, Reason: x509.Expired}),
+			guidance: []string{"has expired or is not valid yet, it is valid from 2022-01-01T00:00:00Z to 2023-01-01T00:00:00Z", "clock"},
+		},
+		"invalid": {
+			err:      handshakeError(x509.CertificateInvalidError{Cert: certificate, Reason: x509.NotAuthorizedToSign}),
+			guidance: []string{"The certificate of authproxy.example.com:8443 is invalid: x509: certificate is not authorized to sign other certificates"},
+		},
+		"plain http": {
+			err:      &url.Error{Op: "Post", URL: "https://authproxy.example.com/tenants", Err: tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}},
+			guidance: []string{"authproxy.example.com did not answer with TLS", "Use an http endpoint"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addClientError(&diags, "create tenant", tc.err)
+
+			if diags.ErrorsCount() != 1 || diags[0].Summary() != "TLS Error" {
+				t.Fatalf("expected a TLS error, got %v", diags)
+			}
+			for _, expected := range append(tc.guidance, "Unable to create tenant, got error: "+tc.err.Error()) {
+				if !strings.Contains(diags[0].Detail(), expected) {
+					t.Errorf("expected the detail to contain %q, got %q", expected, diags[0].Detail())
+				}
+			}
+		})
+	}
+
+	if _, ok := tlsGuidance(errors.New("connection refused")); ok {
+		t.Error("expected no guidance for other errors")
+	}
+}
+
+func TestAddClientError_untrustedServer(t *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+
+	_, err := testProviderData(server.URL).do(context.Background(), "GET", "/tenants", nil)
+
+	var diags diag.Diagnostics
+	addClientError(&diags, "read tenant", err)
+	host := strings.TrimPrefix(server.URL, "https://")
+	if diags.ErrorsCount() != 1 || diags[0].Summary() != "TLS Error" || !strings.Contains(diags[0].Detail(), "The certificate of "+host+" is signed by an authority") {
+		t.Errorf("expected the untrusted certificate to be explained, got %v", diags)
+	}
+}
+
+func TestProviderConfigure_tls(t *testing.T) {
+	backend := testserver.New(t)
+	backend.CreateTenant("acme")
+	server := httptest.NewTLSServer(backend.Config.Handler)
+	defer server.Close()
+	caCertPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	str := func(value string) tftypes.Value { return tftypes.NewValue(tftypes.String, value) }
+	for name, tc := range map[string]struct {
+		config    map[string]tftypes.Value
+		configure string
+		read      string
+	}{
+		"system trust store": {
+			read: "TLS Error",
+		},
+		"ca_cert_pem": {
+			config: map[string]tftypes.Value{"ca_cert_pem": str(caCertPEM)},
+		},
+		"insecure_skip_verify": {
+			config:    map[string]tftypes.Value{"insecure_skip_verify": tftypes.NewValue(tftypes.Bool, true)},
+			configure: "Insecure TLS Verification",
+		},
+		"invalid ca_cert_pem": {
+			config:    map[string]tftypes.Value{"ca_cert_pem": str("not a certificate")},
+			configure: "Invalid CA Certificate",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]tftypes.Value{
+				"endpoint": str(server.URL),
+				"username": str(testserver.Username),
+				"password": str(testserver.Password),
+			}
+			for name, value := range tc.config {
+				config[name] = value
+			}
+
+			providerServer, diags := testProviderConfigure(t, config)
+			if tc.configure != "" {
+				if len(diags) != 1 || diags[0].Summary != tc.configure {
+					t.Fatalf("expected a %q diagnostic, got %v", tc.configure, diags)
+				}
+				if diags[0].Severity == tfprotov6.DiagnosticSeverityError {
+					return
+				}
+			} else if len(diags) != 0 {
+				t.Fatalf("unexpected configure diagnostics: %v", diags)
+			}
+
+			diags = testProviderReadTenant(t, providerServer)
+			if tc.read == "" && len(diags) != 0 {
+				t.Errorf("expected the tenant to be read, got %v", diags)
+			}
+			if tc.read != "" && (len(diags) != 1 || diags[0].Summary != tc.read) {
+				t.Errorf("expected a %q error, got %v", tc.read, diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TokenExchangePolicyResource{}
+var _ resource.ResourceWithImportState = &TokenExchangePolicyResource{}
+
+func NewTokenExchangePolicyResource() resource.Resource {
+	return &TokenExchangePolicyResource{}
+}
+
+// TokenExchangePolicyResource defines the resource implementation.
+type TokenExchangePolicyResource struct {
+	BaseResource
+}
+
+// TokenExchangePolicyResourceModel describes the resource data model.
+type TokenExchangePolicyResourceModel struct {
+	ID                 types.String    `tfsdk:"id"`
+	Tenant             tenantNameValue `tfsdk:"tenant"`
+	SubjectClientID    types.String    `tfsdk:"subject_client_id"`
+	AllowedAudiences   types.Set       `tfsdk:"allowed_audiences"`
+	AllowedScopes      types.Set       `tfsdk:"allowed_scopes"`
+	MaxLifetimeSeconds types.Int64     `tfsdk:"max_lifetime_seconds"`
+}
+
+type tokenExchangePolicyRequest struct {
+	SubjectClientID    string   `json:"subject_client_id,omitempty"`
+	AllowedAudiences   []string `json:"allowed_audiences"`
+	AllowedScopes      []string `json:"allowed_scopes"`
+	MaxLifetimeSeconds int64    `json:"max_lifetime_seconds"`
+}
+
+type tokenExchangePolicyResponse struct {
+	ID                 string   `json:"id"`
+	SubjectClientID    string   `json:"subject_client_id"`
+	AllowedAudiences   []string `json:"allowed_audiences"`
+	AllowedScopes      []string `json:"allowed_scopes"`
+	MaxLifetimeSeconds int64    `json:"max_lifetime_seconds"`
+}
+
+// tokenExchangePolicyConflictResponse is the body authproxy answers with when
+// the subject client already has a policy.
+type tokenExchangePolicyConflictResponse struct {
+	ID string `json:"id"`
+}
+
+// tokenExchangeMaxLifetimeSeconds is the longest lifetime authproxy allows
+// for exchanged tokens.
+const tokenExchangeMaxLifetimeSeconds = 24 * 60 * 60
+
+func (r *TokenExchangePolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_token_exchange_policy"
+}
+
+func (r *TokenExchangePolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Allows a client to exchange its tokens for tokens of other audiences following RFC 8693. Every client has at most one policy per tenant",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the policy applies to. Changing it recreates the policy",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subject_client_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the client whose tokens may be exchanged. Changing it recreates the policy",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"allowed_audiences": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Audiences the client may request exchanged tokens for",
+				Required:            true,
+			},
+			"allowed_scopes": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scopes exchanged tokens may carry, the scopes of the subject token when unset",
+				Optional:            true,
+				Validators: []validator.Set{
+					scopeNames(),
+				},
+			},
+			"max_lifetime_seconds": schema.Int64Attribute{
+				MarkdownDescription: "Longest lifetime of exchanged tokens, at most a day. Defaults to `3600`",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(3600),
+				Validators: []validator.Int64{
+					int64Between(1, tokenExchangeMaxLifetimeSeconds),
+				},
+			},
+		},
+	}
+}
+
+// tokenExchangePoliciesPath returns the path of the token exchange policies
+// collection of tenant.
+func tokenExchangePoliciesPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/token-exchange-policies", url.PathEscape(tenant))
+}
+
+// tokenExchangePolicyPath returns the path of the token exchange policy of
+// tenant with the given id.
+func tokenExchangePolicyPath(tenant string, id string) string {
+	return tokenExchangePoliciesPath(tenant) + "/" + url.PathEscape(id)
+}
+
+// request builds the create and update request body from data.
+func (data *TokenExchangePolicyResourceModel) request(ctx context.Context) (tokenExchangePolicyRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	policy := tokenExchangePolicyRequest{
+		AllowedAudiences:   []string{},
+		AllowedScopes:      []string{},
+		MaxLifetimeSeconds: data.MaxLifetimeSeconds.ValueInt64(),
+	}
+	diags.Append(data.AllowedAudiences.ElementsAs(ctx, &policy.AllowedAudiences, false)...)
+	sort.Strings(policy.AllowedAudiences)
+	if !data.AllowedScopes.IsNull() {
+		diags.Append(data.AllowedScopes.ElementsAs(ctx, &policy.AllowedScopes, false)...)
+		sort.Strings(policy.AllowedScopes)
+	}
+
+	return policy, diags
+}
+
+// setPolicy copies the attributes authproxy returned for a token exchange
+// policy into data.
+func (data *TokenExchangePolicyResourceModel) setPolicy(ctx context.Context, policy tokenExchangePolicyResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(policy.ID)
+	data.SubjectClientID = types.StringValue(policy.SubjectClientID)
+	data.MaxLifetimeSeconds = types.Int64Value(policy.MaxLifetimeSeconds)
+
+	if policy.AllowedAudiences == nil {
+		policy.AllowedAudiences = []string{}
+	}
+	audiences, d := types.SetValueFrom(ctx, types.StringType, policy.AllowedAudiences)
+	diags.Append(d...)
+	data.AllowedAudiences = audiences
+
+	// No scopes stay null unless they were configured as an empty set, so
+	// leaving allowed_scopes unset does not produce a diff.
+	if len(policy.AllowedScopes) > 0 || !data.AllowedScopes.IsNull() {
+		if policy.AllowedScopes == nil {
+			policy.AllowedScopes = []string{}
+		}
+		scopes, d := types.SetValueFrom(ctx, types.StringType, policy.AllowedScopes)
+		diags.Append(d...)
+		data.AllowedScopes = scopes
+	}
+
+	return diags
+}
+
+// addConflictError reports a policy authproxy refused to create because the
+// subject client already has one, suggesting to import it. It returns false
+// for any other error.
+func (data *TokenExchangePolicyResourceModel) addConflictError(diags *diag.Diagnostics, err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		return false
+	}
+
+	id := "<id>"
+	var conflict tokenExchangePolicyConflictResponse
+	if json.Unmarshal([]byte(apiErr.Body), &conflict) == nil && conflict.ID != "" {
+		id = conflict.ID
+	}
+	diags.AddAttributeError(
+		path.Root("subject_client_id"),
+		"Token Exchange Policy Already Exists",
+		fmt.Sprintf("The client %q already has a token exchange policy in tenant %q. Import the existing policy instead of creating it:\n\n"+
+			"terraform import authproxy_token_exchange_policy.<name> %s/%s",
+			data.SubjectClientID.ValueString(), data.Tenant.ValueString(), data.Tenant.ValueString(), id),
+	)
+	return true
+}
+
+func (r *TokenExchangePolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *TokenExchangePolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	body.SubjectClientID = data.SubjectClientID.ValueString()
+
+	var policy tokenExchangePolicyResponse
+	err := r.doJSON(ctx, "POST", tokenExchangePoliciesPath(data.Tenant.ValueString()), body, &policy)
+	if data.addConflictError(&resp.Diagnostics, err) {
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create token exchange policy", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setPolicy(ctx, policy)...)
+
+	tflog.Trace(ctx, "created a token exchange policy resource", map[string]interface{}{
+		"id": policy.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TokenExchangePolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *TokenExchangePolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy tokenExchangePolicyResponse
+	err := r.doJSON(ctx, "GET", tokenExchangePolicyPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, &policy)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "token exchange policy no longer exists, removing it from state", map[string]interface{}{
+			"tenant": data.Tenant.ValueString(),
+			"id":     data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read token exchange policy", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setPolicy(ctx, policy)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TokenExchangePolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *TokenExchangePolicyResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, diags := data.request(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy tokenExchangePolicyResponse
+	err := r.doJSON(ctx, "PATCH", tokenExchangePolicyPath(data.Tenant.ValueString(), data.ID.ValueString()), body, &policy)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update token exchange policy", err)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setPolicy(ctx, policy)...)
+
+	tflog.Trace(ctx, "updated a token exchange policy resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TokenExchangePolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *TokenExchangePolicyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", tokenExchangePolicyPath(data.Tenant.ValueString(), data.ID.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete token exchange policy", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a token exchange policy resource")
+}
+
+func (r *TokenExchangePolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, id, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/id, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccTokenExchangePolicyBackend is an in-memory stand-in for the token
+// exchange policy endpoints of the "acme" tenant. Like authproxy it allows
+// a single policy per subject client.
+type testAccTokenExchangePolicyBackend struct {
+	mu       sync.Mutex
+	policies map[string]tokenExchangePolicyResponse
+	nextID   int
+}
+
+func (b *testAccTokenExchangePolicyBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prefix := tokenExchangePoliciesPath("acme")
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	var body tokenExchangePolicyRequest
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		for _, policy := range b.policies {
+			if policy.SubjectClientID == body.SubjectClientID {
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).Encode(tokenExchangePolicyConflictResponse{ID: policy.ID})
+				return
+			}
+		}
+		b.nextID++
+		id = fmt.Sprintf("tep%d", b.nextID)
+		b.policies[id] = tokenExchangePolicyResponse{
+			ID:                 id,
+			SubjectClientID:    body.SubjectClientID,
+			AllowedAudiences:   body.AllowedAudiences,
+			AllowedScopes:      body.AllowedScopes,
+			MaxLifetimeSeconds: body.MaxLifetimeSeconds,
+		}
+		_ = json.NewEncoder(w).Encode(b.policies[id])
+	case id != "" && r.Method == http.MethodGet:
+		policy, ok := b.policies[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(policy)
+	case id != "" && r.Method == http.MethodPatch:
+		policy, ok := b.policies[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		policy.AllowedAudiences = body.AllowedAudiences
+		policy.AllowedScopes = body.AllowedScopes
+		policy.MaxLifetimeSeconds = body.MaxLifetimeSeconds
+		b.policies[id] = policy
+		_ = json.NewEncoder(w).Encode(policy)
+	case id != "" && r.Method == http.MethodDelete:
+		if _, ok := b.policies[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(b.policies, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (b *testAccTokenExchangePolicyBackend) checkDestroy(s *terraform.State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id, policy := range b.policies {
+		if policy.SubjectClientID == "checkout-service" {
+			return fmt.Errorf("expected the token exchange policy %s to be deleted", id)
+		}
+	}
+	return nil
+}
+
+func TestAccTokenExchangePolicyResource(t *testing.T) {
+	backend := &testAccTokenExchangePolicyBackend{policies: map[string]tokenExchangePolicyResponse{}}
+	server := testBackendServer(t, backend, tokenExchangePoliciesPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             backend.checkDestroy,
+		Steps: []resourcetest.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccTokenExchangePolicyResourceConfig(`["https://orders.acme.io"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_token_exchange_policy.test", "id", "tep1"),
+					resourcetest.TestCheckResourceAttr("authproxy_token_exchange_policy.test", "subject_client_id", "checkout-service"),
+					resourcetest.TestCheckResourceAttr("authproxy_token_exchange_policy.test", "allowed_audiences.#", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_token_exchange_policy.test", "allowed_scopes.#", "1"),
+					resourcetest.TestCheckResourceAttr("authproxy_token_exchange_policy.test", "max_lifetime_seconds", "3600"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "authproxy_token_exchange_policy.test",
+				ImportState:       true,
+				ImportStateId:     "acme/tep1",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccTokenExchangePolicyResourceConfig(`["https://orders.acme.io", "https://payments.acme.io"]`),
+				Check: resourcetest.ComposeAggregateTestCheckFunc(
+					resourcetest.TestCheckResourceAttr("authproxy_token_exchange_policy.test", "id", "tep1"),
+					resourcetest.TestCheckResourceAttr("authproxy_token_exchange_policy.test", "allowed_audiences.#", "2"),
+					resourcetest.TestCheckTypeSetElemAttr("authproxy_token_exchange_policy.test", "allowed_audiences.*", "https://payments.acme.io"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						audiences := append([]string(nil), backend.policies["tep1"].AllowedAudiences...)
+						sort.Strings(audiences)
+						if strings.Join(audiences, ",") != "https://orders.acme.io,https://payments.acme.io" {
+							return fmt.Errorf("expected both audiences to be allowed in authproxy, got %v", audiences)
+						}
+						return nil
+					},
+				),
+			},
+			// A policy deleted outside of Terraform is created again
+			{
+				PreConfig: func() {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					delete(backend.policies, "tep1")
+				},
+				Config: testAccProviderConfig(server.URL) + testAccTokenExchangePolicyResourceConfig(`["https://orders.acme.io", "https://payments.acme.io"]`),
+				Check:  resourcetest.TestCheckResourceAttr("authproxy_token_exchange_policy.test", "id", "tep2"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccTokenExchangePolicyResource_conflict(t *testing.T) {
+	backend := &testAccTokenExchangePolicyBackend{
+		policies: map[string]tokenExchangePolicyResponse{
+			"existing": {
+				ID:                 "existing",
+				SubjectClientID:    "checkout-service",
+				AllowedAudiences:   []string{"https://orders.acme.io"},
+				MaxLifetimeSeconds: 600,
+			},
+		},
+	}
+	server := testBackendServer(t, backend, tokenExchangePoliciesPath("acme"))
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resourcetest.TestStep{
+			{
+				Config:      testAccProviderConfig(server.URL) + testAccTokenExchangePolicyResourceConfig(`["https://orders.acme.io"]`),
+				ExpectError: regexp.MustCompile(`terraform import authproxy_token_exchange_policy.<name> acme/existing`),
+			},
+		},
+	})
+}
+
+func testAccTokenExchangePolicyResourceConfig(audiences string) string {
+	return fmt.Sprintf(`
+resource "authproxy_token_exchange_policy" "test" {
+  tenant            = "acme"
+  subject_client_id = "checkout-service"
+  allowed_audiences = %s
+  allowed_scopes    = ["orders:read"]
+}
+`, audiences)
+}
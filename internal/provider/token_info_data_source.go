@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TokenInfoDataSource{}
+
+func NewTokenInfoDataSource() datasource.DataSource {
+	return &TokenInfoDataSource{}
+}
+
+// TokenInfoDataSource defines the data source implementation.
+type TokenInfoDataSource struct {
+	BaseDataSource
+}
+
+// TokenInfoDataSourceModel describes the data source data model.
+type TokenInfoDataSourceModel struct {
+	Token     types.String    `tfsdk:"token"`
+	Active    types.Bool      `tfsdk:"active"`
+	Subject   types.String    `tfsdk:"subject"`
+	Tenant    tenantNameValue `tfsdk:"tenant"`
+	Scopes    types.List      `tfsdk:"scopes"`
+	ExpiresAt types.String    `tfsdk:"expires_at"`
+}
+
+type introspectRequest struct {
+	Token string `json:"token"`
+}
+
+// introspectResponse follows RFC 7662, where scope is a space separated
+// string. Some authproxy releases return a scopes list instead.
+type introspectResponse struct {
+	Active  bool     `json:"active"`
+	Subject string   `json:"sub"`
+	Tenant  string   `json:"tenant"`
+	Scope   string   `json:"scope"`
+	Scopes  []string `json:"scopes"`
+	Expiry  int64    `json:"exp"`
+}
+
+func (d *TokenInfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_token_info"
+}
+
+func (d *TokenInfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Introspects a token issued by authproxy",
+
+		Attributes: map[string]schema.Attribute{
+			"token": schema.StringAttribute{
+				MarkdownDescription: "Token to introspect",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"active": schema.BoolAttribute{
+				MarkdownDescription: "Whether the token is currently active. Expired and revoked tokens are inactive",
+				Computed:            true,
+			},
+			"subject": schema.StringAttribute{
+				MarkdownDescription: "Subject the token was issued to, null for inactive tokens",
+				Computed:            true,
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the token was issued for, null for inactive or proxy-wide tokens",
+				Computed:            true,
+			},
+			"scopes": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scopes carried by the token",
+				Computed:            true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "RFC 3339 expiry of the token, null for inactive tokens or tokens without expiry",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *TokenInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TokenInfoDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	token := data.Token.ValueString()
+	// The server may echo the token back in error responses, keep it out of
+	// the logs written by the client.
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, token)
+	ctx = tflog.MaskMessageStrings(ctx, token)
+
+	var info introspectResponse
+	if err := d.doJSON(ctx, "POST", "/introspect", introspectRequest{Token: token}, &info); err != nil {
+		if token != "" {
+			err = errors.New(strings.ReplaceAll(err.Error(), token, "<redacted>"))
+		}
+		addClientError(&resp.Diagnostics, "introspect token", err)
+		return
+	}
+
+	scopes := info.Scopes
+	if scopes == nil {
+		scopes = strings.Fields(info.Scope)
+	}
+	scopesValue, diags := types.ListValueFrom(ctx, types.StringType, scopes)
+	resp.Diagnostics.Append(diags...)
+
+	data.Active = types.BoolValue(info.Active)
+	data.Scopes = scopesValue
+	data.Subject = optionalString(info.Subject)
+	data.Tenant = tenantNameValue{StringValue: optionalString(info.Tenant)}
+	data.ExpiresAt = types.StringNull()
+	if info.Expiry > 0 {
+		data.ExpiresAt = types.StringValue(time.Unix(info.Expiry, 0).UTC().Format(time.RFC3339))
+	}
+
+	tflog.Trace(ctx, "read token info data source", map[string]interface{}{
+		"active": info.Active,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// optionalString maps the empty string to null.
+func optionalString(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const (
+	testActiveToken    = "eyJhbGciOiJSUzI1NiJ9.active.signature"
+	testExpiredToken   = "eyJhbGciOiJSUzI1NiJ9.expired.signature"
+	testMalformedToken = "not-a-jwt"
+)
+
+// testAccIntrospectionServer implements the introspection endpoint. Like some
+// real deployments it echoes malformed tokens back in its error message.
+func testAccIntrospectionServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(testserver.ValidateRequests(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req introspectRequest
+		if r.URL.Path != "/introspect" || json.NewDecoder(r.Body).Decode(&req) != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Token {
+		case testActiveToken:
+			fmt.Fprint(w, `{"active":true,"sub":"svc-billing","tenant":"acme","scope":"billing:read billing:write","exp":1893456000}`)
+		case testExpiredToken:
+			fmt.Fprint(w, `{"active":false}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":"invalid_request","error_description":"cannot parse token %s"}`, req.Token)
+		}
+	})))
+}
+
+func TestAccTokenInfoDataSource(t *testing.T) {
+	server := testAccIntrospectionServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + fmt.Sprintf(`
+data "authproxy_token_info" "test" {
+  token = %[1]q
+}
+`, testActiveToken),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_token_info.test", "active", "true"),
+					resource.TestCheckResourceAttr("data.authproxy_token_info.test", "subject", "svc-billing"),
+					resource.TestCheckResourceAttr("data.authproxy_token_info.test", "tenant", "acme"),
+					resource.TestCheckResourceAttr("data.authproxy_token_info.test", "scopes.#", "2"),
+					resource.TestCheckResourceAttr("data.authproxy_token_info.test", "expires_at", "2030-01-01T00:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestTokenInfoDataSource_expired(t *testing.T) {
+	server := testAccIntrospectionServer(t)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewTokenInfoDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"token": tftypes.NewValue(tftypes.String, testExpiredToken),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data TokenInfoDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics reading state: %v", resp.Diagnostics)
+	}
+	if data.Active.ValueBool() {
+		t.Error("expected the expired token to be inactive")
+	}
+	if !data.Subject.IsNull() || !data.ExpiresAt.IsNull() {
+		t.Errorf("expected subject and expires_at to be null, got %s and %s", data.Subject, data.ExpiresAt)
+	}
+}
+
+func TestTokenInfoDataSource_malformed(t *testing.T) {
+	server := testAccIntrospectionServer(t)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewTokenInfoDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"token": tftypes.NewValue(tftypes.String, testMalformedToken),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a malformed token")
+	}
+	for _, d := range resp.Diagnostics {
+		if strings.Contains(d.Summary(), testMalformedToken) || strings.Contains(d.Detail(), testMalformedToken) {
+			t.Errorf("diagnostic leaks the token: %s: %s", d.Summary(), d.Detail())
+		}
+	}
+}
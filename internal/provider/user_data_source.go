@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &UserDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &UserDataSource{}
+
+func NewUserDataSource() datasource.DataSource {
+	return &UserDataSource{}
+}
+
+// UserDataSource defines the data source implementation.
+type UserDataSource struct {
+	BaseDataSource
+}
+
+// UserDataSourceModel describes the data source data model.
+type UserDataSourceModel struct {
+	Tenant    tenantNameValue `tfsdk:"tenant"`
+	Username  types.String    `tfsdk:"username"`
+	Email     types.String    `tfsdk:"email"`
+	ID        types.String    `tfsdk:"id"`
+	Enabled   types.Bool      `tfsdk:"enabled"`
+	CreatedAt types.String    `tfsdk:"created_at"`
+}
+
+func (d *UserDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (d *UserDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Looks up a single user of a tenant by username or email",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the user belongs to",
+				Required:            true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Username to look up. Exactly one of `username` and `email` must be set",
+				Optional:            true,
+				Computed:            true,
+			},
+			"email": schema.StringAttribute{
+				MarkdownDescription: "Email address to look up. Exactly one of `username` and `email` must be set",
+				Optional:            true,
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The database uuid",
+				Computed:            true,
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the user is allowed to log in",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Creation timestamp of the user",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *UserDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data UserDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values that are not known yet are validated once they are.
+	if data.Username.IsUnknown() || data.Email.IsUnknown() {
+		return
+	}
+
+	if data.Username.IsNull() == data.Email.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("username"),
+			"Invalid Attribute Combination",
+			"Exactly one of `username` and `email` must be set.",
+		)
+	}
+}
+
+func (d *UserDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data UserDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+
+	tenant := data.Tenant.ValueString()
+	var user userResponse
+	if !data.Username.IsNull() {
+		username := data.Username.ValueString()
+		err := d.doJSON(ctx, "GET", userPath(tenant, username), nil, &user)
+		if isStatus(err, http.StatusNotFound) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("username"),
+				"User Not Found",
+				fmt.Sprintf("No user with username %q exists in tenant %q.", username, tenant),
+			)
+			return
+		}
+		if err != nil {
+			addClientError(&resp.Diagnostics, "read user", err)
+			return
+		}
+	} else {
+		email := data.Email.ValueString()
+		users, err := listAll[userResponse](ctx, d.providerData, usersPath(tenant), url.Values{"email": []string{email}})
+		if err != nil {
+			addClientError(&resp.Diagnostics, "list users", err)
+			return
+		}
+
+		// The email query parameter is a hint, older servers return every user.
+		var matches []userResponse
+		for _, candidate := range users {
+			if strings.EqualFold(candidate.Email, email) {
+				matches = append(matches, candidate)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("email"),
+				"User Not Found",
+				fmt.Sprintf("No user with email %q exists in tenant %q.", email, tenant),
+			)
+			return
+		case 1:
+			user = matches[0]
+		default:
+			usernames := make([]string, 0, len(matches))
+			for _, match := range matches {
+				usernames = append(usernames, fmt.Sprintf("%s (%s)", match.Username, match.ID))
+			}
+			sort.Strings(usernames)
+			resp.Diagnostics.AddAttributeError(
+				path.Root("email"),
+				"Ambiguous User Lookup",
+				fmt.Sprintf("The email %q matches %d users in tenant %q: %s. Look the user up by username instead.", email, len(matches), tenant, strings.Join(usernames, ", ")),
+			)
+			return
+		}
+	}
+
+	data.ID = types.StringValue(user.ID)
+	data.Username = types.StringValue(user.Username)
+	data.Email = types.StringValue(user.Email)
+	data.Enabled = types.BoolValue(user.Enabled)
+	data.CreatedAt = types.StringValue(user.CreatedAt)
+
+	tflog.Trace(ctx, "read user data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
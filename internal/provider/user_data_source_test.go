@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccUserServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants/acme/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Ignores the email filter like older servers do.
+		fmt.Fprint(w, `{"items":[
+			{"id":"u1","username":"alice","email":"alice@acme.io","enabled":true,"created_at":"2023-01-02T03:04:05Z"},
+			{"id":"u2","username":"ops","email":"ops@acme.io","enabled":true,"created_at":"2023-01-02T03:04:05Z"},
+			{"id":"u3","username":"ops-backup","email":"OPS@acme.io","enabled":false,"created_at":"2023-01-02T03:04:05Z"}
+		]}`)
+	})
+	mux.HandleFunc("/tenants/acme/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"u1","username":"alice","email":"alice@acme.io","enabled":true,"created_at":"2023-01-02T03:04:05Z"}`)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestAccUserDataSource_username(t *testing.T) {
+	server := testAccUserServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_user" "test" {
+  tenant   = "acme"
+  username = "alice"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_user.test", "id", "u1"),
+					resource.TestCheckResourceAttr("data.authproxy_user.test", "email", "alice@acme.io"),
+					resource.TestCheckResourceAttr("data.authproxy_user.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("data.authproxy_user.test", "created_at", "2023-01-02T03:04:05Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserDataSource_email(t *testing.T) {
+	server := testAccUserServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_user" "test" {
+  tenant = "acme"
+  email  = "alice@acme.io"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_user.test", "id", "u1"),
+					resource.TestCheckResourceAttr("data.authproxy_user.test", "username", "alice"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserDataSource_notFound(t *testing.T) {
+	server := testAccUserServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_user" "test" {
+  tenant   = "acme"
+  username = "mallory"
+}
+`,
+				ExpectError: regexp.MustCompile(`User Not Found`),
+			},
+		},
+	})
+}
+
+func TestAccUserDataSource_ambiguousEmail(t *testing.T) {
+	server := testAccUserServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_user" "test" {
+  tenant = "acme"
+  email  = "ops@acme.io"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Ambiguous User Lookup.*ops\s+\(u2\),\s+ops-backup\s+\(u3\)`),
+			},
+		},
+	})
+}
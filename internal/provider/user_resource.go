@@ -0,0 +1,264 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/planmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &UserResource{}
+var _ resource.ResourceWithImportState = &UserResource{}
+
+func NewUserResource() resource.Resource {
+	return &UserResource{}
+}
+
+// UserResource defines the resource implementation.
+type UserResource struct {
+	BaseResource
+}
+
+// UserResourceModel describes the resource data model.
+type UserResourceModel struct {
+	ID              types.String    `tfsdk:"id"`
+	Tenant          tenantNameValue `tfsdk:"tenant"`
+	Username        types.String    `tfsdk:"username"`
+	Email           types.String    `tfsdk:"email"`
+	DisplayName     types.String    `tfsdk:"display_name"`
+	Enabled         types.Bool      `tfsdk:"enabled"`
+	InitialPassword types.String    `tfsdk:"initial_password"`
+}
+
+type userCreateRequest struct {
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	DisplayName string `json:"display_name,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	Password    string `json:"password,omitempty"`
+}
+
+type userUpdateRequest struct {
+	Email       string `json:"email"`
+	DisplayName string `json:"display_name"`
+	Enabled     bool   `json:"enabled"`
+}
+
+func (r *UserResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "User of a tenant",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The database uuid",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the user belongs to. Changing it recreates the user",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy cannot move users between tenants"),
+				},
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Username of the user. Changing it recreates the user",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					planmodifiers.ImmutableString("authproxy does not support renaming users"),
+				},
+			},
+			"email": schema.StringAttribute{
+				MarkdownDescription: "Email address of the user",
+				Required:            true,
+			},
+			"display_name": schema.StringAttribute{
+				MarkdownDescription: "Display name of the user, chosen by authproxy when not configured",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the user is allowed to log in, defaults to `true`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"initial_password": schema.StringAttribute{
+				MarkdownDescription: "Password the user is created with. It is only sent when the user is created and never read back, so changing it later has no effect",
+				Optional:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+// usersPath returns the path of the users collection of tenant.
+func usersPath(tenant string) string {
+	return fmt.Sprintf("/tenants/%s/users", url.PathEscape(tenant))
+}
+
+// userPath returns the path of the user of tenant named username.
+func userPath(tenant string, username string) string {
+	return usersPath(tenant) + "/" + url.PathEscape(username)
+}
+
+// setUser copies the attributes authproxy returned for a user into data.
+func (data *UserResourceModel) setUser(user userResponse) {
+	data.ID = types.StringValue(user.ID)
+	data.Username = types.StringValue(user.Username)
+	data.Email = types.StringValue(user.Email)
+	data.DisplayName = types.StringValue(user.DisplayName)
+	data.Enabled = types.BoolValue(user.Enabled)
+}
+
+func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *UserResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var user userResponse
+	err := r.doJSON(ctx, "POST", usersPath(data.Tenant.ValueString()), userCreateRequest{
+		Username:    data.Username.ValueString(),
+		Email:       data.Email.ValueString(),
+		DisplayName: data.DisplayName.ValueString(),
+		Enabled:     data.Enabled.ValueBool(),
+		Password:    data.InitialPassword.ValueString(),
+	}, &user)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create user", err)
+		return
+	}
+
+	data.setUser(user)
+
+	tflog.Trace(ctx, "created a user resource", map[string]interface{}{
+		"id": user.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *UserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var user userResponse
+	err := r.doJSON(ctx, "GET", userPath(data.Tenant.ValueString(), data.Username.ValueString()), nil, &user)
+	if isStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "user no longer exists, removing it from state", map[string]interface{}{
+			"tenant":   data.Tenant.ValueString(),
+			"username": data.Username.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read user", err)
+		return
+	}
+
+	// initial_password is never read back and keeps its prior value.
+	data.setUser(user)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *UserResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var user userResponse
+	err := r.doJSON(ctx, "PATCH", userPath(data.Tenant.ValueString(), data.Username.ValueString()), userUpdateRequest{
+		Email:       data.Email.ValueString(),
+		DisplayName: data.DisplayName.ValueString(),
+		Enabled:     data.Enabled.ValueBool(),
+	}, &user)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "update user", err)
+		return
+	}
+
+	data.setUser(user)
+
+	tflog.Trace(ctx, "updated a user resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *UserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.doJSON(ctx, "DELETE", userPath(data.Tenant.ValueString(), data.Username.ValueString()), nil, nil)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		addClientError(&resp.Diagnostics, "delete user", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a user resource")
+}
+
+func (r *UserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tenant, username, found := strings.Cut(req.ID, "/")
+	if !found || tenant == "" || username == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form tenant/username, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("username"), username)...)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccUserBackend is an in-memory stand-in for the users endpoints of a
+// single tenant.
+type testAccUserBackend struct {
+	mu        sync.Mutex
+	tenant    string
+	users     map[string]userResponse
+	passwords map[string]string
+	nextID    int
+}
+
+func newTestAccUserBackend(tenant string) *testAccUserBackend {
+	return &testAccUserBackend{
+		tenant:    tenant,
+		users:     map[string]userResponse{},
+		passwords: map[string]string{},
+	}
+}
+
+func (b *testAccUserBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prefix := "/tenants/" + b.tenant + "/users"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	username := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case username == "" && r.Method == http.MethodPost:
+		var create userCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := b.users[create.Username]; ok {
+			http.Error(w, "user exists", http.StatusConflict)
+			return
+		}
+		b.nextID++
+		user := userResponse{
+			ID:          fmt.Sprintf("u%d", b.nextID),
+			Username:    create.Username,
+			Email:       create.Email,
+			DisplayName: create.DisplayName,
+			Enabled:     create.Enabled,
+		}
+		if user.DisplayName == "" {
+			user.DisplayName = create.Username
+		}
+		b.users[user.Username] = user
+		b.passwords[user.Username] = create.Password
+		_ = json.NewEncoder(w).Encode(user)
+	case username == "" && r.Method == http.MethodGet:
+		items := make([]userResponse, 0, len(b.users))
+		for _, user := range b.users {
+			items = append(items, user)
+		}
+		_ = json.NewEncoder(w).Encode(page[userResponse]{Items: items})
+	case username != "":
+		user, ok := b.users[username]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(user)
+		case http.MethodPatch:
+			var update userUpdateRequest
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			user.Email = update.Email
+			user.DisplayName = update.DisplayName
+			user.Enabled = update.Enabled
+			b.users[username] = user
+			_ = json.NewEncoder(w).Encode(user)
+		case http.MethodDelete:
+			delete(b.users, username)
+			delete(b.passwords, username)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAccUserResource(t *testing.T) {
+	backend := newTestAccUserBackend("acme")
+	server := testBackendServer(t, backend, usersPath("acme"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			if len(backend.users) != 0 {
+				return fmt.Errorf("expected every user to be deleted, %d left", len(backend.users))
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccUserResourceConfig("alice@acme.io", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_user.test", "id", "u1"),
+					resource.TestCheckResourceAttr("authproxy_user.test", "email", "alice@acme.io"),
+					resource.TestCheckResourceAttr("authproxy_user.test", "display_name", "alice"),
+					resource.TestCheckResourceAttr("authproxy_user.test", "enabled", "true"),
+					func(s *terraform.State) error {
+						backend.mu.Lock()
+						defer backend.mu.Unlock()
+						if backend.passwords["alice"] != "correct-horse" {
+							return fmt.Errorf("expected the initial password to be sent on create")
+						}
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "authproxy_user.test",
+				ImportState:             true,
+				ImportStateId:           "acme/alice",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initial_password"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(server.URL) + testAccUserResourceConfig("alice@acme.com", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("authproxy_user.test", "id", "u1"),
+					resource.TestCheckResourceAttr("authproxy_user.test", "email", "alice@acme.com"),
+					resource.TestCheckResourceAttr("authproxy_user.test", "enabled", "false"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccUserResource_removedOutOfBand(t *testing.T) {
+	backend := newTestAccUserBackend("acme")
+	server := testBackendServer(t, backend, usersPath("acme"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccUserResourceConfig("alice@acme.io", true),
+				Check: func(s *terraform.State) error {
+					backend.mu.Lock()
+					defer backend.mu.Unlock()
+					delete(backend.users, "alice")
+					return nil
+				},
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccUserResourceConfig(email string, enabled bool) string {
+	return fmt.Sprintf(`
+resource "authproxy_user" "test" {
+  tenant           = "acme"
+  username         = "alice"
+  email            = %[1]q
+  enabled          = %[2]t
+  initial_password = "correct-horse"
+}
+`, email, enabled)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &UsersDataSource{}
+
+func NewUsersDataSource() datasource.DataSource {
+	return &UsersDataSource{}
+}
+
+// UsersDataSource defines the data source implementation.
+type UsersDataSource struct {
+	BaseDataSource
+}
+
+// UsersDataSourceModel describes the data source data model.
+type UsersDataSourceModel struct {
+	Tenant      tenantNameValue    `tfsdk:"tenant"`
+	EmailDomain types.String       `tfsdk:"email_domain"`
+	Enabled     types.Bool         `tfsdk:"enabled"`
+	PageSize    types.Int64        `tfsdk:"page_size"`
+	MaxItems    types.Int64        `tfsdk:"max_items"`
+	Users       []UsersUserModel   `tfsdk:"users"`
+	Timeouts    *ReadTimeoutsModel `tfsdk:"timeouts"`
+}
+
+// UsersUserModel describes a single user returned by the data source.
+type UsersUserModel struct {
+	ID       types.String `tfsdk:"id"`
+	Username types.String `tfsdk:"username"`
+	Email    types.String `tfsdk:"email"`
+	Enabled  types.Bool   `tfsdk:"enabled"`
+}
+
+// userResponse is the user representation returned by the users endpoints.
+// Credential material such as password hashes is deliberately not decoded.
+type userResponse struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	DisplayName string `json:"display_name"`
+	Enabled     bool   `json:"enabled"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func (d *UsersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_users"
+}
+
+func (d *UsersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Users of a tenant",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant to list the users of",
+				Required:            true,
+			},
+			"email_domain": schema.StringAttribute{
+				MarkdownDescription: "Only return users whose email address belongs to this domain",
+				Optional:            true,
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Only return users that are enabled (`true`) or disabled (`false`)",
+				Optional:            true,
+			},
+			"page_size": pageSizeAttribute(),
+			"max_items": maxItemsAttribute(),
+			"users": schema.ListNestedAttribute{
+				MarkdownDescription: "Users sorted by username",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The database uuid",
+							Computed:            true,
+						},
+						"username": schema.StringAttribute{
+							MarkdownDescription: "Username of the user",
+							Computed:            true,
+						},
+						"email": schema.StringAttribute{
+							MarkdownDescription: "Email address of the user",
+							Computed:            true,
+						},
+						"enabled": schema.BoolAttribute{
+							MarkdownDescription: "Whether the user is allowed to log in",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *UsersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data UsersDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_users", data.Timeouts)
+	defer cancel()
+
+	users, truncated, err := listPages[userResponse](ctx, d.providerData, fmt.Sprintf("/tenants/%s/users", url.PathEscape(data.Tenant.ValueString())), nil, listOptionsFrom(data.PageSize, data.MaxItems))
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "list users", err)
+		return
+	}
+	if truncated {
+		addTruncationWarning(&resp.Diagnostics, "users", data.MaxItems.ValueInt64())
+	}
+
+	domain := strings.ToLower(strings.TrimPrefix(data.EmailDomain.ValueString(), "@"))
+	data.Users = make([]UsersUserModel, 0, len(users))
+	for _, user := range users {
+		if domain != "" && !strings.HasSuffix(strings.ToLower(user.Email), "@"+domain) {
+			continue
+		}
+		if !data.Enabled.IsNull() && user.Enabled != data.Enabled.ValueBool() {
+			continue
+		}
+		data.Users = append(data.Users, UsersUserModel{
+			ID:       types.StringValue(user.ID),
+			Username: types.StringValue(user.Username),
+			Email:    types.StringValue(user.Email),
+			Enabled:  types.BoolValue(user.Enabled),
+		})
+	}
+	sort.Slice(data.Users, func(i, j int) bool {
+		return data.Users[i].Username.ValueString() < data.Users[j].Username.ValueString()
+	})
+
+	tflog.Trace(ctx, "read users data source", map[string]interface{}{
+		"count": len(data.Users),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccUsersServer serves the users of the "acme" tenant over two pages and
+// an empty "empty" tenant. The fixture includes password hashes to make sure
+// they never end up in state.
+func testAccUsersServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants/acme/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"items":[
+				{"id":"u3","username":"carol","email":"carol@example.com","enabled":true,"password_hash":"$2y$10$abc"},
+				{"id":"u1","username":"alice","email":"alice@acme.io","enabled":true,"password_hash":"$2y$10$def"}
+			],"next_cursor":"2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[
+			{"id":"u2","username":"bob","email":"bob@ACME.io","enabled":false,"password_hash":"$2y$10$ghi"}
+		]}`)
+	})
+	mux.HandleFunc("/tenants/empty/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[]}`)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestAccUsersDataSource(t *testing.T) {
+	server := testAccUsersServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_users" "test" {
+  tenant = "acme"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_users.test", "users.#", "3"),
+					resource.TestCheckResourceAttr("data.authproxy_users.test", "users.0.username", "alice"),
+					resource.TestCheckResourceAttr("data.authproxy_users.test", "users.1.username", "bob"),
+					resource.TestCheckResourceAttr("data.authproxy_users.test", "users.2.username", "carol"),
+					resource.TestCheckNoResourceAttr("data.authproxy_users.test", "users.0.password_hash"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUsersDataSource_filters(t *testing.T) {
+	server := testAccUsersServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_users" "domain" {
+  tenant       = "acme"
+  email_domain = "acme.io"
+}
+
+data "authproxy_users" "enabled" {
+  tenant       = "acme"
+  email_domain = "acme.io"
+  enabled      = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_users.domain", "users.#", "2"),
+					resource.TestCheckResourceAttr("data.authproxy_users.domain", "users.1.username", "bob"),
+					resource.TestCheckResourceAttr("data.authproxy_users.enabled", "users.#", "1"),
+					resource.TestCheckResourceAttr("data.authproxy_users.enabled", "users.0.id", "u1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUsersDataSource_emptyTenant(t *testing.T) {
+	server := testAccUsersServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_users" "test" {
+  tenant = "empty"
+}
+`,
+				Check: resource.TestCheckResourceAttr("data.authproxy_users.test", "users.#", "0"),
+			},
+		},
+	})
+}
@@ -0,0 +1,771 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ validator.String = stringOneOfValidator{}
+
+// stringOneOfValidator validates that a string attribute is one of a fixed
+// set of values.
+type stringOneOfValidator struct {
+	values []string
+}
+
+// stringOneOf returns a validator which ensures the configured value is one
+// of values. Null and unknown values are ignored.
+func stringOneOf(values ...string) validator.String {
+	return stringOneOfValidator{values: values}
+}
+
+func (v stringOneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", strings.Join(v.quoted(), ", "))
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	for _, allowed := range v.values {
+		if value == allowed {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+	)
+}
+
+func (v stringOneOfValidator) quoted() []string {
+	quoted := make([]string, 0, len(v.values))
+	for _, value := range v.values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return quoted
+}
+
+var _ validator.String = stringMatchesValidator{}
+
+// stringMatchesValidator validates that a string attribute matches a regular
+// expression.
+type stringMatchesValidator struct {
+	pattern     *regexp.Regexp
+	description string
+}
+
+func (v stringMatchesValidator) Description(ctx context.Context) string {
+	return v.description
+}
+
+func (v stringMatchesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringMatchesValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !v.pattern.MatchString(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.String = rfc3339Validator{}
+
+// rfc3339Validator validates that a string attribute is an RFC 3339 timestamp.
+type rfc3339Validator struct{}
+
+// rfc3339Timestamp returns a validator which ensures the configured value is
+// an RFC 3339 timestamp. Null and unknown values are ignored.
+func rfc3339Timestamp() validator.String {
+	return rfc3339Validator{}
+}
+
+func (v rfc3339Validator) Description(ctx context.Context) string {
+	return "value must be an RFC 3339 timestamp such as 2006-01-02T15:04:05Z"
+}
+
+func (v rfc3339Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.String = durationValidator{}
+
+// durationValidator validates that a string attribute is a positive Go
+// duration.
+type durationValidator struct{}
+
+// positiveDuration returns a validator which ensures the configured value is
+// a positive duration such as "30s" or "2m". Null and unknown values are
+// ignored.
+func positiveDuration() validator.String {
+	return durationValidator{}
+}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return `value must be a positive duration such as "30s" or "2m"`
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if duration, err := time.ParseDuration(req.ConfigValue.ValueString()); err != nil || duration <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.String = issuerURLValidator{}
+
+// issuerURLValidator validates that a string attribute is an OpenID Connect
+// issuer identifier.
+type issuerURLValidator struct{}
+
+// issuerURL returns a validator which ensures the configured value is an
+// absolute https URL without query or fragment, as OpenID Connect requires of
+// issuers. Whether the issuer is reachable is left to authproxy. Null and
+// unknown values are ignored.
+func issuerURL() validator.String {
+	return issuerURLValidator{}
+}
+
+func (v issuerURLValidator) Description(ctx context.Context) string {
+	return "value must be an https URL without query or fragment such as https://accounts.google.com"
+}
+
+func (v issuerURLValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v issuerURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	issuer, err := url.Parse(req.ConfigValue.ValueString())
+	if err != nil || issuer.Scheme != "https" || issuer.Host == "" || issuer.RawQuery != "" || issuer.ForceQuery || issuer.Fragment != "" {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.String = upstreamURLValidator{}
+
+// upstreamURLValidator validates that a string attribute is the URL of an
+// upstream service.
+type upstreamURLValidator struct{}
+
+// upstreamURL returns a validator which ensures the configured value is an
+// absolute http or https URL without fragment or userinfo. Credentials for
+// upstreams do not belong into URLs, where they would end up in logs. Null
+// and unknown values are ignored.
+func upstreamURL() validator.String {
+	return upstreamURLValidator{}
+}
+
+func (v upstreamURLValidator) Description(ctx context.Context) string {
+	return "value must be an http or https URL without fragment or userinfo such as http://billing.internal:8080"
+}
+
+func (v upstreamURLValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v upstreamURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	upstream, err := url.Parse(req.ConfigValue.ValueString())
+	if err != nil || (upstream.Scheme != "http" && upstream.Scheme != "https") || upstream.Host == "" ||
+		upstream.User != nil || strings.Contains(req.ConfigValue.ValueString(), "#") {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.Set = originsValidator{}
+
+// originsValidator validates that a set attribute only holds CORS origins.
+type originsValidator struct{}
+
+// corsOrigins returns a validator which ensures every element of the
+// configured set is either the wildcard "*" or an origin, a scheme and host
+// with an optional port but no path. Null and unknown values are ignored.
+func corsOrigins() validator.Set {
+	return originsValidator{}
+}
+
+func (v originsValidator) Description(ctx context.Context) string {
+	return `values must be "*" or origins such as https://app.example.com without a path`
+}
+
+func (v originsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v originsValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() || value.ValueString() == "*" {
+			continue
+		}
+
+		origin, err := url.Parse(value.ValueString())
+		if err != nil || (origin.Scheme != "http" && origin.Scheme != "https") || origin.Host == "" || origin.User != nil ||
+			origin.Path != "" || origin.RawQuery != "" || origin.ForceQuery || origin.Fragment != "" {
+			elementPath := req.Path.AtSetValue(value)
+			resp.Diagnostics.AddAttributeError(
+				elementPath,
+				"Invalid Attribute Value",
+				fmt.Sprintf("Attribute %s %s, got: %q", elementPath, v.Description(ctx), value.ValueString()),
+			)
+		}
+	}
+}
+
+var _ validator.String = certificatePEMValidator{}
+
+// certificatePEMValidator validates that a string attribute is a PEM encoded
+// X.509 certificate.
+type certificatePEMValidator struct {
+	expiryWarning time.Duration
+}
+
+// certificatePEM returns a validator which ensures the configured value is a
+// PEM encoded X.509 certificate, warning when it expires within
+// expiryWarning. Null and unknown values are ignored.
+func certificatePEM(expiryWarning time.Duration) validator.String {
+	return certificatePEMValidator{expiryWarning: expiryWarning}
+}
+
+func (v certificatePEMValidator) Description(ctx context.Context) string {
+	return "value must be a PEM encoded X.509 certificate"
+}
+
+func (v certificatePEMValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v certificatePEMValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	certificate, err := parseCertificatePEM(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got error: %s", req.Path, v.Description(ctx), err),
+		)
+		return
+	}
+
+	if remaining := time.Until(certificate.NotAfter); remaining <= 0 {
+		resp.Diagnostics.AddAttributeWarning(
+			req.Path,
+			"Certificate Expired",
+			fmt.Sprintf("The certificate %q expired at %s.", certificate.Subject.String(), certificate.NotAfter.UTC().Format(time.RFC3339)),
+		)
+	} else if remaining < v.expiryWarning {
+		resp.Diagnostics.AddAttributeWarning(
+			req.Path,
+			"Certificate Expires Soon",
+			fmt.Sprintf("The certificate %q expires at %s, in less than %d days.", certificate.Subject.String(), certificate.NotAfter.UTC().Format(time.RFC3339), int(v.expiryWarning.Hours()/24)),
+		)
+	}
+}
+
+var _ validator.Int64 = int64BetweenValidator{}
+
+// int64BetweenValidator validates that an integer attribute lies within an
+// inclusive range.
+type int64BetweenValidator struct {
+	min int64
+	max int64
+}
+
+// int64Between returns a validator which ensures the configured value is
+// between min and max, inclusive. Null and unknown values are ignored.
+func int64Between(min int64, max int64) validator.Int64 {
+	return int64BetweenValidator{min: min, max: max}
+}
+
+func (v int64BetweenValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be between %d and %d", v.min, v.max)
+}
+
+func (v int64BetweenValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64BetweenValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueInt64(); value < v.min || value > v.max {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %d", req.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+var _ validator.String = stringMaxBytesValidator{}
+
+// stringMaxBytesValidator validates that a string attribute does not exceed
+// a size in bytes.
+type stringMaxBytesValidator struct {
+	max int
+}
+
+// stringMaxBytes returns a validator which ensures the configured value is at
+// most max bytes long. Unlike a length in characters this matches the limits
+// authproxy enforces on stored documents. Null and unknown values are
+// ignored.
+func stringMaxBytes(max int) validator.String {
+	return stringMaxBytesValidator{max: max}
+}
+
+func (v stringMaxBytesValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be at most %d bytes long", v.max)
+}
+
+func (v stringMaxBytesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringMaxBytesValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if size := len(req.ConfigValue.ValueString()); size > v.max {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %d bytes", req.Path, v.Description(ctx), size),
+		)
+	}
+}
+
+var _ validator.Set = ipRangesValidator{}
+
+// ipRangesValidator validates that a set attribute only holds IP addresses
+// and CIDR ranges.
+type ipRangesValidator struct{}
+
+// ipRanges returns a validator which ensures every element of the configured
+// set is an IP address such as "203.0.113.7" or a CIDR range such as
+// "10.0.0.0/8". Null and unknown values are ignored.
+func ipRanges() validator.Set {
+	return ipRangesValidator{}
+}
+
+func (v ipRangesValidator) Description(ctx context.Context) string {
+	return `values must be IP addresses or CIDR ranges such as "10.0.0.0/8"`
+}
+
+func (v ipRangesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ipRangesValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		if validators.IsCIDR(value.ValueString()) {
+			continue
+		}
+		if _, err := netip.ParseAddr(value.ValueString()); err == nil {
+			continue
+		}
+		elementPath := req.Path.AtSetValue(value)
+		resp.Diagnostics.AddAttributeError(
+			elementPath,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", elementPath, v.Description(ctx), value.ValueString()),
+		)
+	}
+}
+
+var _ validator.Set = scopeNamesValidator{}
+
+// scopeNamesValidator validates that a set attribute only holds scope names.
+type scopeNamesValidator struct{}
+
+// scopeNames returns a validator which ensures every element of the
+// configured set is a valid scope name, see validators.IsScope. Null and
+// unknown values are ignored.
+func scopeNames() validator.Set {
+	return scopeNamesValidator{}
+}
+
+func (v scopeNamesValidator) Description(ctx context.Context) string {
+	return `values must be scope names such as "billing:read"`
+}
+
+func (v scopeNamesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v scopeNamesValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		if !validators.IsScope(value.ValueString()) {
+			elementPath := req.Path.AtSetValue(value)
+			resp.Diagnostics.AddAttributeError(
+				elementPath,
+				"Invalid Attribute Value",
+				fmt.Sprintf("Attribute %s %s, got: %q", elementPath, v.Description(ctx), value.ValueString()),
+			)
+		}
+	}
+}
+
+var _ validator.Set = httpMethodsValidator{}
+
+// httpMethodsValidator validates that a set attribute only holds HTTP request
+// methods.
+type httpMethodsValidator struct{}
+
+// httpMethods returns a validator which ensures every element of the
+// configured set is an uppercase HTTP request method such as "GET". Null and
+// unknown values are ignored.
+func httpMethods() validator.Set {
+	return httpMethodsValidator{}
+}
+
+// httpMethodNames are the request methods authproxy routes.
+var httpMethodNames = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+func (v httpMethodsValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("values must be HTTP methods, one of %s", strings.Join(httpMethodNames, ", "))
+}
+
+func (v httpMethodsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v httpMethodsValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		known := false
+		for _, method := range httpMethodNames {
+			known = known || value.ValueString() == method
+		}
+		if !known {
+			elementPath := req.Path.AtSetValue(value)
+			resp.Diagnostics.AddAttributeError(
+				elementPath,
+				"Invalid Attribute Value",
+				fmt.Sprintf("Attribute %s %s, got: %q", elementPath, v.Description(ctx), value.ValueString()),
+			)
+		}
+	}
+}
+
+var _ validator.Map = headerNamesValidator{}
+var _ validator.Set = headerNamesValidator{}
+
+// headerNamesValidator validates that the keys of a map attribute or the
+// elements of a set attribute are HTTP header names, none of them forbidden.
+type headerNamesValidator struct {
+	forbidden []string
+}
+
+// headerNamePattern is the format of HTTP header names, tokens as defined by
+// RFC 7230 section 3.2.6.
+var headerNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// headerNames returns a validator which ensures every key of the configured
+// map or element of the configured set is an HTTP header name other than the
+// forbidden ones, which are compared case-insensitively. Null and unknown
+// values are ignored.
+func headerNames(forbidden ...string) headerNamesValidator {
+	return headerNamesValidator{forbidden: forbidden}
+}
+
+func (v headerNamesValidator) Description(ctx context.Context) string {
+	if len(v.forbidden) == 0 {
+		return `values must be HTTP header names such as "X-User-Id"`
+	}
+	return fmt.Sprintf(`values must be HTTP header names such as "X-User-Id" other than %s`, strings.Join(v.forbidden, ", "))
+}
+
+func (v headerNamesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// validate reports name at attributePath unless it is an allowed header name.
+func (v headerNamesValidator) validate(ctx context.Context, attributePath path.Path, name string, diags *diag.Diagnostics) {
+	if !headerNamePattern.MatchString(name) {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s must be an HTTP header name such as \"X-User-Id\", got: %q", attributePath, name),
+		)
+		return
+	}
+
+	for _, forbidden := range v.forbidden {
+		if strings.EqualFold(name, forbidden) {
+			diags.AddAttributeError(
+				attributePath,
+				"Forbidden Header",
+				fmt.Sprintf("Attribute %s must not configure the %s header, authproxy rejects it.", attributePath, forbidden),
+			)
+		}
+	}
+}
+
+func (v headerNamesValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for name := range req.ConfigValue.Elements() {
+		v.validate(ctx, req.Path.AtMapKey(name), name, &resp.Diagnostics)
+	}
+}
+
+func (v headerNamesValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		v.validate(ctx, req.Path.AtSetValue(value), value.ValueString(), &resp.Diagnostics)
+	}
+}
+
+var _ validator.Map = headerValuesValidator{}
+
+// headerValuesValidator validates that the values of a map attribute can be
+// sent as HTTP header values.
+type headerValuesValidator struct{}
+
+// headerValues returns a validator which ensures no value of the configured
+// map contains control characters such as line breaks, which cannot be sent
+// in HTTP headers. Null and unknown values are ignored.
+func headerValues() validator.Map {
+	return headerValuesValidator{}
+}
+
+func (v headerValuesValidator) Description(ctx context.Context) string {
+	return "values must not contain control characters such as line breaks"
+}
+
+func (v headerValuesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v headerValuesValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for key, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		if strings.IndexFunc(value.ValueString(), func(r rune) bool { return r != '\t' && unicode.IsControl(r) }) >= 0 {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtMapKey(key),
+				"Invalid Attribute Value",
+				fmt.Sprintf("Attribute %s must not contain control characters such as line breaks, got: %q", req.Path.AtMapKey(key), value.ValueString()),
+			)
+		}
+	}
+}
+
+var _ resource.ConfigValidator = atLeastOneOfValidator{}
+
+// atLeastOneOfValidator validates that at least one of a group of attributes
+// of a resource is configured.
+type atLeastOneOfValidator struct {
+	attributes []string
+}
+
+// atLeastOneOf returns a resource validator which ensures at least one of the
+// named top-level attributes is configured. Configurations in which any of
+// them is unknown are validated once it is known.
+func atLeastOneOf(attributes ...string) resource.ConfigValidator {
+	return atLeastOneOfValidator{attributes: attributes}
+}
+
+func (v atLeastOneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("at least one of %s must be configured", strings.Join(v.attributes, ", "))
+}
+
+func (v atLeastOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v atLeastOneOfValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	for _, attribute := range v.attributes {
+		var value attr.Value
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(attribute), &value)...)
+		if resp.Diagnostics.HasError() || value.IsUnknown() || !value.IsNull() {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root(v.attributes[0]),
+		"Invalid Attribute Combination",
+		fmt.Sprintf("At least one of the attributes %s must be configured.", strings.Join(v.attributes, ", ")),
+	)
+}
+
+var _ resource.ConfigValidator = atMostOneOfValidator{}
+
+// atMostOneOfValidator validates that at most one of a group of attributes of
+// a resource is configured.
+type atMostOneOfValidator struct {
+	attributes []string
+}
+
+// atMostOneOf returns a resource validator which ensures no more than one of
+// the named top-level attributes is configured, such as an attribute and the
+// deprecated one it replaces. Unknown values count as configured.
+func atMostOneOf(attributes ...string) resource.ConfigValidator {
+	return atMostOneOfValidator{attributes: attributes}
+}
+
+func (v atMostOneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("at most one of %s may be configured", strings.Join(v.attributes, ", "))
+}
+
+func (v atMostOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v atMostOneOfValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var configured []string
+	for _, attribute := range v.attributes {
+		var value attr.Value
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(attribute), &value)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !value.IsNull() {
+			configured = append(configured, attribute)
+		}
+	}
+
+	if len(configured) > 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(configured[1]),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("Only one of the attributes %s may be configured, got: %s.", strings.Join(v.attributes, ", "), strings.Join(configured, ", ")),
+		)
+	}
+}
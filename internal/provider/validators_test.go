@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestSetValidators_everyElement checks that the set validators report every
+// invalid element at once, each at its own path.
+func TestSetValidators_everyElement(t *testing.T) {
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		validator validator.Set
+		valid     []string
+		invalid   []string
+	}{
+		"ip ranges": {
+			validator: ipRanges(),
+			valid:     []string{"10.0.0.0/8", "203.0.113.7", "2001:db8::/32"},
+			invalid:   []string{"10.0.0.0/33", "localhost", "203.0.113", "10.0.0.0-10.0.0.255"},
+		},
+		"scope names": {
+			validator: scopeNames(),
+			valid:     []string{"billing:read", "deployments:*"},
+			invalid:   []string{"billing", "Billing:Read", "ops:", ":read"},
+		},
+		"cors origins": {
+			validator: corsOrigins(),
+			valid:     []string{"*", "https://app.acme.io", "http://localhost:3000"},
+			invalid:   []string{"app.acme.io", "https://app.acme.io/login", "ftp://files.acme.io"},
+		},
+		"http methods": {
+			validator: httpMethods(),
+			valid:     []string{"GET", "POST"},
+			invalid:   []string{"get", "FETCH"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			elements := []attr.Value{}
+			for _, value := range append(append([]string{}, tc.valid...), tc.invalid...) {
+				elements = append(elements, types.StringValue(value))
+			}
+
+			resp := &validator.SetResponse{}
+			tc.validator.ValidateSet(ctx, validator.SetRequest{
+				Path:        path.Root("values"),
+				ConfigValue: types.SetValueMust(types.StringType, elements),
+			}, resp)
+
+			if resp.Diagnostics.ErrorsCount() != len(tc.invalid) {
+				t.Fatalf("expected %d errors, got %v", len(tc.invalid), resp.Diagnostics)
+			}
+			for _, invalid := range tc.invalid {
+				expected := path.Root("values").AtSetValue(types.StringValue(invalid))
+				found := false
+				for _, diagnostic := range resp.Diagnostics {
+					if withPath, ok := diagnostic.(interface{ Path() path.Path }); ok && withPath.Path().Equal(expected) {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("expected an error at %s, got %v", expected, resp.Diagnostics)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &WebhooksDataSource{}
+
+func NewWebhooksDataSource() datasource.DataSource {
+	return &WebhooksDataSource{}
+}
+
+// WebhooksDataSource defines the data source implementation.
+type WebhooksDataSource struct {
+	BaseDataSource
+}
+
+// WebhooksDataSourceModel describes the data source data model.
+type WebhooksDataSourceModel struct {
+	Tenant   tenantNameValue    `tfsdk:"tenant"`
+	Event    types.String       `tfsdk:"event"`
+	PageSize types.Int64        `tfsdk:"page_size"`
+	MaxItems types.Int64        `tfsdk:"max_items"`
+	Webhooks []WebhookModel     `tfsdk:"webhooks"`
+	Timeouts *ReadTimeoutsModel `tfsdk:"timeouts"`
+}
+
+// WebhookModel describes a single webhook.
+type WebhookModel struct {
+	ID      types.String `tfsdk:"id"`
+	URL     types.String `tfsdk:"url"`
+	Events  types.List   `tfsdk:"events"`
+	Enabled types.Bool   `tfsdk:"enabled"`
+}
+
+// webhookSummary only holds the non-secret webhook fields, the signing secret
+// is dropped while decoding.
+type webhookSummary struct {
+	ID      string   `json:"id"`
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`
+	Enabled bool     `json:"enabled"`
+}
+
+// subscribes reports whether the webhook is subscribed to event.
+func (w webhookSummary) subscribes(event string) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *WebhooksDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_webhooks"
+}
+
+func (d *WebhooksDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Webhooks configured on a tenant. Signing secrets are never read",
+
+		Attributes: map[string]schema.Attribute{
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant to list the webhooks of",
+				Required:            true,
+			},
+			"event": schema.StringAttribute{
+				MarkdownDescription: "Only return webhooks subscribed to this event type",
+				Optional:            true,
+			},
+			"page_size": pageSizeAttribute(),
+			"max_items": maxItemsAttribute(),
+			"webhooks": schema.ListNestedAttribute{
+				MarkdownDescription: "Webhooks in the order returned by authproxy",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The database uuid",
+							Computed:            true,
+						},
+						"url": schema.StringAttribute{
+							MarkdownDescription: "URL the events are delivered to",
+							Computed:            true,
+						},
+						"events": schema.ListAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "Event types the webhook is subscribed to",
+							Computed:            true,
+						},
+						"enabled": schema.BoolAttribute{
+							MarkdownDescription: "Whether events are currently delivered",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *WebhooksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data WebhooksDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+	ctx, cancel := d.providerData.withReadTimeout(ctx, "authproxy_webhooks", data.Timeouts)
+	defer cancel()
+
+	webhooks, truncated, err := listPages[webhookSummary](ctx, d.providerData, fmt.Sprintf("/tenants/%s/webhooks", url.PathEscape(data.Tenant.ValueString())), nil, listOptionsFrom(data.PageSize, data.MaxItems))
+	if err != nil {
+		addReadError(ctx, &resp.Diagnostics, "list webhooks", err)
+		return
+	}
+	if truncated {
+		addTruncationWarning(&resp.Diagnostics, "webhooks", data.MaxItems.ValueInt64())
+	}
+
+	data.Webhooks = make([]WebhookModel, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if !data.Event.IsNull() && !webhook.subscribes(data.Event.ValueString()) {
+			continue
+		}
+		if webhook.Events == nil {
+			webhook.Events = []string{}
+		}
+		events, diags := types.ListValueFrom(ctx, types.StringType, webhook.Events)
+		resp.Diagnostics.Append(diags...)
+		data.Webhooks = append(data.Webhooks, WebhookModel{
+			ID:      types.StringValue(webhook.ID),
+			URL:     types.StringValue(webhook.URL),
+			Events:  events,
+			Enabled: types.BoolValue(webhook.Enabled),
+		})
+	}
+
+	tflog.Trace(ctx, "read webhooks data source", map[string]interface{}{
+		"count": len(data.Webhooks),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
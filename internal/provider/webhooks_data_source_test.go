@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccWebhooksServer serves the webhooks of the "acme" tenant over two
+// pages. The fixture includes signing secrets to make sure they never end up
+// in state.
+func testAccWebhooksServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenants/acme/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"items":[
+				{"id":"w1","url":"https://hooks.acme.io/users","events":["user.created","user.deleted"],"enabled":true,"secret":"whsec_one"},
+				{"id":"w2","url":"https://hooks.acme.io/audit","events":["audit.event"],"enabled":false,"signing_secret":"whsec_two"}
+			],"next_cursor":"2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[
+			{"id":"w3","url":"https://siem.acme.io","events":["user.created","audit.event"],"enabled":true,"secret":"whsec_three"}
+		]}`)
+	})
+	return httptest.NewServer(testserver.ValidateRequests(t, mux))
+}
+
+func TestAccWebhooksDataSource(t *testing.T) {
+	server := testAccWebhooksServer(t)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + `
+data "authproxy_webhooks" "all" {
+  tenant = "acme"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_webhooks.all", "webhooks.#", "3"),
+					resource.TestCheckResourceAttr("data.authproxy_webhooks.all", "webhooks.0.events.#", "2"),
+					resource.TestCheckResourceAttr("data.authproxy_webhooks.all", "webhooks.1.enabled", "false"),
+					resource.TestCheckResourceAttr("data.authproxy_webhooks.all", "webhooks.2.url", "https://siem.acme.io"),
+				),
+			},
+		},
+	})
+}
+
+func TestWebhooksDataSource_event(t *testing.T) {
+	server := testAccWebhooksServer(t)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewWebhooksDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"tenant": tftypes.NewValue(tftypes.String, "acme"),
+		"event":  tftypes.NewValue(tftypes.String, "user.created"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data WebhooksDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	var ids []string
+	for _, webhook := range data.Webhooks {
+		ids = append(ids, webhook.ID.ValueString())
+	}
+	if strings.Join(ids, ",") != "w1,w3" {
+		t.Errorf("expected webhooks w1 and w3, got %v", ids)
+	}
+}
+
+func TestWebhooksDataSource_stripsSecrets(t *testing.T) {
+	server := testAccWebhooksServer(t)
+	defer server.Close()
+
+	resp := testDataSourceRead(t, NewWebhooksDataSource(), testProviderData(server.URL), map[string]tftypes.Value{
+		"tenant": tftypes.NewValue(tftypes.String, "acme"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+
+	state := resp.State.Raw.String()
+	if !strings.Contains(state, "siem.acme.io") {
+		t.Fatalf("expected the webhooks in state, got: %s", state)
+	}
+	if strings.Contains(state, "whsec_") {
+		t.Errorf("state contains a signing secret: %s", state)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &WhoamiDataSource{}
+
+func NewWhoamiDataSource() datasource.DataSource {
+	return &WhoamiDataSource{}
+}
+
+// WhoamiDataSource defines the data source implementation.
+type WhoamiDataSource struct {
+	BaseDataSource
+}
+
+// WhoamiDataSourceModel describes the data source data model.
+type WhoamiDataSourceModel struct {
+	Username types.String    `tfsdk:"username"`
+	Tenant   tenantNameValue `tfsdk:"tenant"`
+	Scopes   types.List      `tfsdk:"scopes"`
+}
+
+type whoamiResponse struct {
+	Username string   `json:"username"`
+	Tenant   *string  `json:"tenant"`
+	Scopes   []string `json:"scopes"`
+	// Permissions is what newer servers call scopes.
+	Permissions []string `json:"permissions"`
+}
+
+func (d *WhoamiDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_whoami"
+}
+
+func (d *WhoamiDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Identity of the credentials the provider is configured with",
+
+		Attributes: map[string]schema.Attribute{
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Username of the configured credentials",
+				Computed:            true,
+			},
+			"tenant": schema.StringAttribute{
+				CustomType:          tenantNameType{},
+				MarkdownDescription: "Tenant the credentials are scoped to, null for proxy-wide admin credentials",
+				Computed:            true,
+			},
+			"scopes": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scopes (permissions) granted to the credentials",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *WhoamiDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data WhoamiDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withReadDeduplication(ctx)
+
+	var me whoamiResponse
+	if err := d.doJSON(ctx, "GET", "/me", nil, &me); err != nil {
+		addClientError(&resp.Diagnostics, "read the configured identity", err)
+		return
+	}
+
+	scopes := me.Scopes
+	if scopes == nil {
+		scopes = me.Permissions
+	}
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	data.Username = types.StringValue(me.Username)
+	data.Tenant = tenantNameValue{StringValue: types.StringPointerValue(me.Tenant)}
+	scopesValue, diags := types.ListValueFrom(ctx, types.StringType, scopes)
+	resp.Diagnostics.Append(diags...)
+	data.Scopes = scopesValue
+
+	tflog.Trace(ctx, "read whoami data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
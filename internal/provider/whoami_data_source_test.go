@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/testserver"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccWhoamiServer answers GET /me with body for the admin credentials used
+// by testAccProviderConfig and with 401 for anything else.
+func testAccWhoamiServer(t *testing.T, body string) *httptest.Server {
+	return httptest.NewServer(testserver.ValidateRequests(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "admin" {
+			http.Error(w, `{"error":"invalid credentials"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	})))
+}
+
+func TestAccWhoamiDataSource_admin(t *testing.T) {
+	server := testAccWhoamiServer(t, `{"username":"admin","scopes":["tenants:write","roles:write"]}`)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccWhoamiDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_whoami.test", "username", "admin"),
+					resource.TestCheckNoResourceAttr("data.authproxy_whoami.test", "tenant"),
+					resource.TestCheckResourceAttr("data.authproxy_whoami.test", "scopes.#", "2"),
+					resource.TestCheckResourceAttr("data.authproxy_whoami.test", "scopes.0", "tenants:write"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWhoamiDataSource_tenantScoped(t *testing.T) {
+	server := testAccWhoamiServer(t, `{"username":"ci","tenant":"acme","permissions":["roles:read"]}`)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server.URL) + testAccWhoamiDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.authproxy_whoami.test", "username", "ci"),
+					resource.TestCheckResourceAttr("data.authproxy_whoami.test", "tenant", "acme"),
+					resource.TestCheckResourceAttr("data.authproxy_whoami.test", "scopes.#", "1"),
+					resource.TestCheckResourceAttr("data.authproxy_whoami.test", "scopes.0", "roles:read"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWhoamiDataSource_invalidCredentials(t *testing.T) {
+	server := testAccWhoamiServer(t, `{}`)
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "authproxy" {
+  endpoint = %[1]q
+  username = "admin"
+  password = "wrong"
+}
+`, server.URL) + testAccWhoamiDataSourceConfig,
+				ExpectError: regexp.MustCompile(`rejected\s+the\s+configured\s+credentials`),
+			},
+		},
+	})
+}
+
+const testAccWhoamiDataSourceConfig = `
+data "authproxy_whoami" "test" {}
+`
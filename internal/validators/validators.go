@@ -0,0 +1,266 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package validators provides the attribute validators shared by the
+// resources and data sources of the provider, along with the predicates they
+// are built on so that normalization code can apply the same rules.
+//
+// The validators ignore null and unknown values. Their errors read
+// "Attribute <path> <description>, got: <value>", where the description can
+// be replaced with WithDescription.
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+
+	"github.com/4thel00z/terraform-provider-authproxy/internal/provider/tenantname"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// scopePattern is the format of scope names such as "billing:read", a
+// service followed by colon separated permissions.
+var scopePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*(:[a-z0-9_*-]+)+$`)
+
+// rolePattern is the format of role names such as "viewer" or
+// "billing.Admin_v2".
+var rolePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// IsTenantName reports whether s is a tenant name such as "acme-eu", see
+// tenantname.Valid.
+func IsTenantName(s string) bool {
+	return tenantname.Valid(s)
+}
+
+// IsRoleName reports whether s is a role name of at most 64 letters, digits,
+// dots, underscores and hyphens, starting with a letter or digit.
+func IsRoleName(s string) bool {
+	return rolePattern.MatchString(s)
+}
+
+// IsScope reports whether s is a scope name such as "billing:read".
+func IsScope(s string) bool {
+	return scopePattern.MatchString(s)
+}
+
+// IsHTTPSURL reports whether s is an absolute https URL with a host, such as
+// "https://authproxy.example.com". Plain http URLs are accepted as well when
+// allowHTTP is set.
+func IsHTTPSURL(s string, allowHTTP bool) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "https" || (allowHTTP && u.Scheme == "http")
+}
+
+// IsCIDR reports whether s is a CIDR range such as "10.0.0.0/8" or
+// "2001:db8::/32".
+func IsCIDR(s string) bool {
+	_, err := netip.ParsePrefix(s)
+	return err == nil
+}
+
+// IsEmail reports whether s is a bare email address such as
+// "noreply@example.com", without a display name.
+func IsEmail(s string) bool {
+	address, err := mail.ParseAddress(s)
+	return err == nil && address.Name == "" && address.Address == s
+}
+
+// Option customizes the messages of a validator.
+type Option func(*stringValidator)
+
+// WithDescription replaces the description of the values the validator
+// accepts, such as `value must be a scope name such as "billing:read"`. It is
+// shown in the documentation and the errors of the validator.
+func WithDescription(description string) Option {
+	return func(v *stringValidator) {
+		v.description = description
+	}
+}
+
+// WithSummary replaces the summary of the errors of the validator, "Invalid
+// Attribute Value" by default.
+func WithSummary(summary string) Option {
+	return func(v *stringValidator) {
+		v.summary = summary
+	}
+}
+
+// TenantName returns a validator which ensures the configured value is a
+// tenant name, see IsTenantName.
+func TenantName(options ...Option) validator.String {
+	return newStringValidator(IsTenantName, fmt.Sprintf(`value must be a tenant name of at most %d lowercase letters, digits and hyphens such as "acme-eu"`, tenantname.MaxLength), options)
+}
+
+// RoleName returns a validator which ensures the configured value is a role
+// name, see IsRoleName.
+func RoleName(options ...Option) validator.String {
+	return newStringValidator(IsRoleName, `value must be a role name of at most 64 letters, digits, dots, underscores and hyphens such as "viewer"`, options)
+}
+
+// Scope returns a validator which ensures the configured value is a scope
+// name, see IsScope. Use it with ListOf or SetOf for collections of scopes.
+func Scope(options ...Option) validator.String {
+	return newStringValidator(IsScope, `value must be a scope name such as "billing:read"`, options)
+}
+
+// HTTPSURL returns a validator which ensures the configured value is an
+// https URL, or an http one when allowHTTP is set, see IsHTTPSURL.
+func HTTPSURL(allowHTTP bool, options ...Option) validator.String {
+	description := "value must be an https URL such as https://authproxy.example.com"
+	if allowHTTP {
+		description = "value must be an http or https URL such as https://authproxy.example.com"
+	}
+	return newStringValidator(func(s string) bool { return IsHTTPSURL(s, allowHTTP) }, description, options)
+}
+
+// CIDR returns a validator which ensures the configured value is a CIDR
+// range, see IsCIDR.
+func CIDR(options ...Option) validator.String {
+	return newStringValidator(IsCIDR, `value must be a CIDR range such as "10.0.0.0/8"`, options)
+}
+
+// Email returns a validator which ensures the configured value is a bare
+// email address, see IsEmail.
+func Email(options ...Option) validator.String {
+	return newStringValidator(IsEmail, `value must be an email address such as "noreply@example.com"`, options)
+}
+
+var _ validator.String = &stringValidator{}
+
+// stringValidator validates that a string attribute satisfies a predicate.
+type stringValidator struct {
+	valid       func(string) bool
+	description string
+	summary     string
+}
+
+func newStringValidator(valid func(string) bool, description string, options []Option) *stringValidator {
+	v := &stringValidator{
+		valid:       valid,
+		description: description,
+		summary:     "Invalid Attribute Value",
+	}
+	for _, option := range options {
+		option(v)
+	}
+	return v
+}
+
+func (v *stringValidator) Description(ctx context.Context) string {
+	return v.description
+}
+
+func (v *stringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v *stringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !v.valid(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			v.summary,
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.description, req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.List = listOfValidator{}
+
+// listOfValidator applies a string validator to every element of a list.
+type listOfValidator struct {
+	element validator.String
+}
+
+// ListOf returns a validator which applies element to every element of the
+// configured list of strings, reporting errors at the path of the element.
+func ListOf(element validator.String) validator.List {
+	return listOfValidator{element: element}
+}
+
+func (v listOfValidator) Description(ctx context.Context) string {
+	return "every element: " + v.element.Description(ctx)
+}
+
+func (v listOfValidator) MarkdownDescription(ctx context.Context) string {
+	return "every element: " + v.element.MarkdownDescription(ctx)
+}
+
+func (v listOfValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for i, element := range req.ConfigValue.Elements() {
+		validateElement(ctx, v.element, req.Path.AtListIndex(i), element, req.Config, &resp.Diagnostics)
+	}
+}
+
+var _ validator.Set = setOfValidator{}
+
+// setOfValidator applies a string validator to every element of a set.
+type setOfValidator struct {
+	element validator.String
+}
+
+// SetOf returns a validator which applies element to every element of the
+// configured set of strings, reporting errors at the path of the element.
+func SetOf(element validator.String) validator.Set {
+	return setOfValidator{element: element}
+}
+
+func (v setOfValidator) Description(ctx context.Context) string {
+	return "every element: " + v.element.Description(ctx)
+}
+
+func (v setOfValidator) MarkdownDescription(ctx context.Context) string {
+	return "every element: " + v.element.MarkdownDescription(ctx)
+}
+
+func (v setOfValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, element := range req.ConfigValue.Elements() {
+		validateElement(ctx, v.element, req.Path.AtSetValue(element), element, req.Config, &resp.Diagnostics)
+	}
+}
+
+// validateElement applies the string validator element to the element of a
+// collection at elementPath. Elements that are not strings are skipped.
+func validateElement(ctx context.Context, element validator.String, elementPath path.Path, value attr.Value, config tfsdk.Config, diags *diag.Diagnostics) {
+	valuable, ok := value.(basetypes.StringValuable)
+	if !ok {
+		return
+	}
+	stringValue, valueDiags := valuable.ToStringValue(ctx)
+	diags.Append(valueDiags...)
+	if valueDiags.HasError() {
+		return
+	}
+
+	resp := &validator.StringResponse{}
+	element.ValidateString(ctx, validator.StringRequest{
+		Path:           elementPath,
+		PathExpression: elementPath.Expression(),
+		ConfigValue:    stringValue,
+		Config:         config,
+	}, resp)
+	diags.Append(resp.Diagnostics...)
+}
@@ -0,0 +1,252 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPredicates(t *testing.T) {
+	for name, tc := range map[string]struct {
+		valid   func(string) bool
+		values  []string
+		invalid []string
+	}{
+		"IsTenantName": {
+			valid:   IsTenantName,
+			values:  []string{"acme", "acme-eu", "a1"},
+			invalid: []string{"", "Acme", "acme_eu", "-acme", "acme-", "café", strings.Repeat("a", 64)},
+		},
+		"IsRoleName": {
+			valid:   IsRoleName,
+			values:  []string{"viewer", "billing.Admin_v2", "0", strings.Repeat("a", 64)},
+			invalid: []string{"", ".viewer", "view er", "viewer:read", strings.Repeat("a", 65)},
+		},
+		"IsScope": {
+			valid:   IsScope,
+			values:  []string{"billing:read", "billing:invoices:*", "user-admin:write_all"},
+			invalid: []string{"", "billing", "Billing:read", "billing:", ":read", "billing read"},
+		},
+		"IsHTTPSURL": {
+			valid:   func(s string) bool { return IsHTTPSURL(s, false) },
+			values:  []string{"https://authproxy.example.com", "https://127.0.0.1:8443/api"},
+			invalid: []string{"", "http://authproxy.example.com", "https://", "authproxy.example.com", "ftp://authproxy.example.com", "https://%zz"},
+		},
+		"IsHTTPSURL allowHTTP": {
+			valid:   func(s string) bool { return IsHTTPSURL(s, true) },
+			values:  []string{"https://authproxy.example.com", "http://localhost:8080"},
+			invalid: []string{"", "http://", "ftp://authproxy.example.com"},
+		},
+		"IsCIDR": {
+			valid:   IsCIDR,
+			values:  []string{"10.0.0.0/8", "192.168.1.1/32", "2001:db8::/32"},
+			invalid: []string{"", "10.0.0.0", "10.0.0.0/33", "10.0.0/8", "example.com/8"},
+		},
+		"IsEmail": {
+			valid:   IsEmail,
+			values:  []string{"noreply@example.com", "ops+alerts@mail.example.com"},
+			invalid: []string{"", "noreply", "Authproxy <noreply@example.com>", " noreply@example.com", "noreply@"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, value := range tc.values {
+				if !tc.valid(value) {
+					t.Errorf("expected %q to be valid", value)
+				}
+			}
+			for _, value := range tc.invalid {
+				if tc.valid(value) {
+					t.Errorf("expected %q to be invalid", value)
+				}
+			}
+		})
+	}
+}
+
+func TestStringValidators(t *testing.T) {
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		validator validator.String
+		valid     string
+		invalid   string
+		summary   string
+		detail    string
+	}{
+		"TenantName": {
+			validator: TenantName(),
+			valid:     "acme-eu",
+			invalid:   "acme_eu",
+			summary:   "Invalid Attribute Value",
+			detail:    `Attribute attribute value must be a tenant name of at most 63 lowercase letters, digits and hyphens such as "acme-eu", got: "acme_eu"`,
+		},
+		"RoleName": {
+			validator: RoleName(),
+			valid:     "viewer",
+			invalid:   "view er",
+			summary:   "Invalid Attribute Value",
+			detail:    `Attribute attribute value must be a role name of at most 64 letters, digits, dots, underscores and hyphens such as "viewer", got: "view er"`,
+		},
+		"Scope": {
+			validator: Scope(),
+			valid:     "billing:read",
+			invalid:   "billing",
+			summary:   "Invalid Attribute Value",
+			detail:    `Attribute attribute value must be a scope name such as "billing:read", got: "billing"`,
+		},
+		"HTTPSURL": {
+			validator: HTTPSURL(false),
+			valid:     "https://authproxy.example.com",
+			invalid:   "http://authproxy.example.com",
+			summary:   "Invalid Attribute Value",
+			detail:    `Attribute attribute value must be an https URL such as https://authproxy.example.com, got: "http://authproxy.example.com"`,
+		},
+		"HTTPSURL allowHTTP": {
+			validator: HTTPSURL(true),
+			valid:     "http://authproxy.example.com",
+			invalid:   "authproxy.example.com",
+			summary:   "Invalid Attribute Value",
+			detail:    `Attribute attribute value must be an http or https URL such as https://authproxy.example.com, got: "authproxy.example.com"`,
+		},
+		"CIDR": {
+			validator: CIDR(),
+			valid:     "10.0.0.0/8",
+			invalid:   "10.0.0.1",
+			summary:   "Invalid Attribute Value",
+			detail:    `Attribute attribute value must be a CIDR range such as "10.0.0.0/8", got: "10.0.0.1"`,
+		},
+		"Email": {
+			validator: Email(),
+			valid:     "noreply@example.com",
+			invalid:   "noreply",
+			summary:   "Invalid Attribute Value",
+			detail:    `Attribute attribute value must be an email address such as "noreply@example.com", got: "noreply"`,
+		},
+		"WithDescription": {
+			validator: Scope(WithDescription("value must name a permission of the billing service")),
+			valid:     "billing:read",
+			invalid:   "billing",
+			summary:   "Invalid Attribute Value",
+			detail:    `Attribute attribute value must name a permission of the billing service, got: "billing"`,
+		},
+		"WithSummary": {
+			validator: Email(WithSummary("Invalid Sender Address")),
+			valid:     "noreply@example.com",
+			invalid:   "noreply",
+			summary:   "Invalid Sender Address",
+			detail:    `Attribute attribute value must be an email address such as "noreply@example.com", got: "noreply"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			validate := func(value types.String) *validator.StringResponse {
+				resp := &validator.StringResponse{}
+				tc.validator.ValidateString(ctx, validator.StringRequest{
+					Path:        path.Root("attribute"),
+					ConfigValue: value,
+				}, resp)
+				return resp
+			}
+
+			for _, value := range []types.String{types.StringValue(tc.valid), types.StringNull(), types.StringUnknown()} {
+				if resp := validate(value); resp.Diagnostics.HasError() {
+					t.Errorf("expected %s to be valid, got %v", value, resp.Diagnostics)
+				}
+			}
+
+			resp := validate(types.StringValue(tc.invalid))
+			if resp.Diagnostics.ErrorsCount() != 1 {
+				t.Fatalf("expected a single error, got %v", resp.Diagnostics)
+			}
+			if summary := resp.Diagnostics[0].Summary(); summary != tc.summary {
+				t.Errorf("expected the summary %q, got %q", tc.summary, summary)
+			}
+			if detail := resp.Diagnostics[0].Detail(); detail != tc.detail {
+				t.Errorf("expected the detail %q, got %q", tc.detail, detail)
+			}
+			if description := tc.validator.Description(ctx); !strings.Contains(tc.detail, description) {
+				t.Errorf("expected the description %q to be part of the error", description)
+			}
+		})
+	}
+}
+
+func TestListOf(t *testing.T) {
+	ctx := context.Background()
+	list := func(values ...string) types.List {
+		elements := make([]attr.Value, 0, len(values))
+		for _, value := range values {
+			elements = append(elements, types.StringValue(value))
+		}
+		return types.ListValueMust(types.StringType, elements)
+	}
+
+	for name, tc := range map[string]struct {
+		value types.List
+		paths []path.Path
+	}{
+		"null":    {value: types.ListNull(types.StringType)},
+		"unknown": {value: types.ListUnknown(types.StringType)},
+		"valid":   {value: list("billing:read", "billing:write")},
+		"invalid": {
+			value: list("billing", "billing:read", "users"),
+			paths: []path.Path{path.Root("scopes").AtListIndex(0), path.Root("scopes").AtListIndex(2)},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := &validator.ListResponse{}
+			ListOf(Scope()).ValidateList(ctx, validator.ListRequest{
+				Path:        path.Root("scopes"),
+				ConfigValue: tc.value,
+			}, resp)
+
+			if resp.Diagnostics.ErrorsCount() != len(tc.paths) {
+				t.Fatalf("expected %d errors, got %v", len(tc.paths), resp.Diagnostics)
+			}
+			for i, expected := range tc.paths {
+				if diagnostic, ok := resp.Diagnostics[i].(interface{ Path() path.Path }); !ok || !diagnostic.Path().Equal(expected) {
+					t.Errorf("expected an error at %s, got %v", expected, resp.Diagnostics[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSetOf(t *testing.T) {
+	ctx := context.Background()
+	invalid := []attr.Value{types.StringValue("10.0.0.1"), types.StringValue("10.0.0.0/33")}
+	value := types.SetValueMust(types.StringType, append([]attr.Value{types.StringValue("10.0.0.0/8")}, invalid...))
+
+	resp := &validator.SetResponse{}
+	SetOf(CIDR()).ValidateSet(ctx, validator.SetRequest{
+		Path:        path.Root("allowed_ips"),
+		ConfigValue: value,
+	}, resp)
+
+	if resp.Diagnostics.ErrorsCount() != len(invalid) {
+		t.Fatalf("expected an error for every invalid element, got %v", resp.Diagnostics)
+	}
+	for _, element := range invalid {
+		expected := path.Root("allowed_ips").AtSetValue(element)
+		found := false
+		for _, diagnostic := range resp.Diagnostics {
+			if withPath, ok := diagnostic.(interface{ Path() path.Path }); ok && withPath.Path().Equal(expected) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected an error at %s, got %v", expected, resp.Diagnostics)
+		}
+	}
+
+	if description := SetOf(CIDR()).Description(ctx); description != `every element: value must be a CIDR range such as "10.0.0.0/8"` {
+		t.Errorf("unexpected description %q", description)
+	}
+}